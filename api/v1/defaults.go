@@ -0,0 +1,154 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Defaults of the fields of Backend.
+const (
+	// DefaultS3Region is the region of S3 compatible services, which mostly
+	// ignore it. The region of an AWS bucket is looked up instead.
+	DefaultS3Region = "us-east-1"
+	// DefaultEndpointScheme is added to endpoints without a scheme.
+	DefaultEndpointScheme = "https"
+
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseBackoff = 100 * time.Millisecond
+	DefaultRetryMaxBackoff  = 20 * time.Second
+)
+
+// DefaultRetryOn are the classes of errors that are retried by default.
+var DefaultRetryOn = []RetryErrorClass{RetryOnThrottling, RetryOnServerError, RetryOnTimeout, RetryOnNetwork}
+
+// DefaultObjectStoreCheckInterval is the CheckInterval of an ObjectStore.
+const DefaultObjectStoreCheckInterval = 5 * time.Minute
+
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	return RegisterDefaults(scheme)
+}
+
+// The SetDefaults_<Type> functions follow the conventions of defaulter-gen,
+// so the defaulting functions generated for the kinds that embed a Backend
+// call them. Every function only defaults the fields of its own type.
+
+// SetDefaults sets the defaults of the backend and of its nested specs. A
+// Backend is not a kind, so defaulter-gen generates no function for it. This
+// calls the same functions as the generated defaulters of the kinds that
+// embed a Backend.
+func (backend *Backend) SetDefaults() {
+	if backend.S3 != nil {
+		SetDefaults_S3Spec(backend.S3)
+	}
+	if backend.GCS != nil {
+		SetDefaults_GCSSpec(backend.GCS)
+	}
+	if backend.Azure != nil {
+		SetDefaults_AzureSpec(backend.Azure)
+	}
+	if backend.Swift != nil {
+		SetDefaults_SwiftSpec(backend.Swift)
+	}
+	if backend.B2 != nil {
+		SetDefaults_B2Spec(backend.B2)
+	}
+	if backend.RetryPolicy != nil {
+		SetDefaults_RetryPolicy(backend.RetryPolicy)
+	}
+}
+
+// SetDefaults sets the defaults of the primary, of the mirrors and of the
+// write quorum.
+func (rb *ReplicatedBackend) SetDefaults() {
+	rb.Primary.SetDefaults()
+	for i := range rb.Mirrors {
+		rb.Mirrors[i].SetDefaults()
+	}
+	SetDefaults_ReplicatedBackend(rb)
+}
+
+func SetDefaults_ReplicatedBackend(obj *ReplicatedBackend) {
+	if obj.WriteQuorum == 0 {
+		obj.WriteQuorum = int32(len(obj.Mirrors) + 1)
+	}
+}
+
+func SetDefaults_S3Spec(obj *S3Spec) {
+	obj.Endpoint = defaultScheme(obj.Endpoint)
+	if obj.Region == "" && !obj.IsAWS() {
+		obj.Region = DefaultS3Region
+	}
+	obj.Prefix = cleanPrefix(obj.Prefix)
+}
+
+func SetDefaults_GCSSpec(obj *GCSSpec) {
+	obj.Prefix = cleanPrefix(obj.Prefix)
+}
+
+func SetDefaults_AzureSpec(obj *AzureSpec) {
+	obj.Endpoint = defaultScheme(obj.Endpoint)
+	obj.Prefix = cleanPrefix(obj.Prefix)
+}
+
+func SetDefaults_SwiftSpec(obj *SwiftSpec) {
+	obj.Prefix = cleanPrefix(obj.Prefix)
+}
+
+func SetDefaults_B2Spec(obj *B2Spec) {
+	obj.Prefix = cleanPrefix(obj.Prefix)
+}
+
+func SetDefaults_RetryPolicy(obj *RetryPolicy) {
+	if obj.MaxAttempts == 0 {
+		obj.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if obj.MaxBackoff == nil || obj.MaxBackoff.Duration == 0 {
+		obj.MaxBackoff = &metav1.Duration{Duration: DefaultRetryMaxBackoff}
+	}
+	if obj.BaseBackoff == nil || obj.BaseBackoff.Duration == 0 {
+		// a short maxBackoff is not exceeded by the default
+		obj.BaseBackoff = &metav1.Duration{Duration: min(DefaultRetryBaseBackoff, obj.MaxBackoff.Duration)}
+	}
+	if len(obj.RetryOn) == 0 {
+		obj.RetryOn = append([]RetryErrorClass(nil), DefaultRetryOn...)
+	}
+}
+
+func SetDefaults_ObjectStoreSpec(obj *ObjectStoreSpec) {
+	if obj.CheckInterval == nil || obj.CheckInterval.Duration == 0 {
+		obj.CheckInterval = &metav1.Duration{Duration: DefaultObjectStoreCheckInterval}
+	}
+}
+
+// defaultScheme adds the DefaultEndpointScheme to an endpoint without one.
+func defaultScheme(endpoint string) string {
+	if endpoint == "" || strings.Contains(endpoint, "://") {
+		return endpoint
+	}
+	return DefaultEndpointScheme + "://" + endpoint
+}
+
+// cleanPrefix removes the trailing slashes of a prefix, the keys are joined
+// to it with a slash.
+func cleanPrefix(prefix string) string {
+	return strings.TrimRight(prefix, "/")
+}
@@ -0,0 +1,186 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestSetDefaults(t *testing.T) {
+	cases := []struct {
+		name     string
+		backend  Backend
+		expected Backend
+	}{
+		{
+			name:     "s3",
+			backend:  Backend{S3: &S3Spec{Bucket: "stash", Prefix: "source/data/"}},
+			expected: Backend{S3: &S3Spec{Bucket: "stash", Prefix: "source/data"}},
+		},
+		{
+			name:    "s3 compatible",
+			backend: Backend{S3: &S3Spec{Bucket: "stash", Endpoint: "minio.example.com:9000", MaxConnections: 8}},
+			expected: Backend{S3: &S3Spec{
+				Bucket:         "stash",
+				Endpoint:       "https://minio.example.com:9000",
+				Region:         DefaultS3Region,
+				MaxConnections: 8,
+			}},
+		},
+		{
+			name:    "s3 bare aws host",
+			backend: Backend{S3: &S3Spec{Bucket: "stash", Endpoint: "s3.amazonaws.com"}},
+			expected: Backend{S3: &S3Spec{
+				Bucket:   "stash",
+				Endpoint: "https://s3.amazonaws.com",
+			}},
+		},
+		{
+			name:    "s3 keeps the settings",
+			backend: Backend{S3: &S3Spec{Bucket: "stash", Endpoint: "http://ceph.example.com", Region: "eu-west-1", Prefix: "source"}},
+			expected: Backend{S3: &S3Spec{
+				Bucket:   "stash",
+				Endpoint: "http://ceph.example.com",
+				Region:   "eu-west-1",
+				Prefix:   "source",
+			}},
+		},
+		{
+			name:     "gcs",
+			backend:  Backend{GCS: &GCSSpec{Bucket: "stash", Prefix: "source//"}},
+			expected: Backend{GCS: &GCSSpec{Bucket: "stash", Prefix: "source"}},
+		},
+		{
+			name:    "azure",
+			backend: Backend{Azure: &AzureSpec{Container: "stash", Prefix: "source/", Endpoint: "stash.blob.core.chinacloudapi.cn"}},
+			expected: Backend{Azure: &AzureSpec{
+				Container: "stash",
+				Prefix:    "source",
+				Endpoint:  "https://stash.blob.core.chinacloudapi.cn",
+			}},
+		},
+		{
+			name:     "azurite",
+			backend:  Backend{Azure: &AzureSpec{Container: "stash", Endpoint: "http://127.0.0.1:10000/devstoreaccount1", MaxConnections: 2}},
+			expected: Backend{Azure: &AzureSpec{Container: "stash", Endpoint: "http://127.0.0.1:10000/devstoreaccount1", MaxConnections: 2}},
+		},
+		{
+			name:     "swift",
+			backend:  Backend{Swift: &SwiftSpec{Container: "stash", Prefix: "source/"}},
+			expected: Backend{Swift: &SwiftSpec{Container: "stash", Prefix: "source"}},
+		},
+		{
+			name:     "b2",
+			backend:  Backend{B2: &B2Spec{Bucket: "stash-backup", Prefix: "/"}},
+			expected: Backend{B2: &B2Spec{Bucket: "stash-backup"}},
+		},
+		{
+			name:     "local",
+			backend:  Backend{Local: &LocalSpec{MountPath: "/safe/data/"}},
+			expected: Backend{Local: &LocalSpec{MountPath: "/safe/data/"}},
+		},
+		{
+			name:     "rest",
+			backend:  Backend{Rest: &RestServerSpec{URL: "https://rest.example.com/repo/"}},
+			expected: Backend{Rest: &RestServerSpec{URL: "https://rest.example.com/repo/"}},
+		},
+		{
+			name: "retry policy",
+			backend: Backend{
+				Rest:        &RestServerSpec{URL: "https://rest.example.com/repo"},
+				RetryPolicy: &RetryPolicy{MaxBackoff: &metav1.Duration{Duration: 50 * time.Millisecond}},
+			},
+			expected: Backend{
+				Rest: &RestServerSpec{URL: "https://rest.example.com/repo"},
+				RetryPolicy: &RetryPolicy{
+					MaxAttempts: DefaultRetryMaxAttempts,
+					BaseBackoff: &metav1.Duration{Duration: 50 * time.Millisecond},
+					MaxBackoff:  &metav1.Duration{Duration: 50 * time.Millisecond},
+					RetryOn:     []RetryErrorClass{RetryOnThrottling, RetryOnServerError, RetryOnTimeout, RetryOnNetwork},
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.backend.SetDefaults()
+			if !reflect.DeepEqual(tc.backend, tc.expected) {
+				t.Errorf("Expected backend %+v, got %+v", tc.expected, tc.backend)
+			}
+			if errs := tc.backend.Validate(); len(errs) > 0 {
+				t.Errorf("Expected the defaulted backend to be valid, got %v", errs)
+			}
+			// the defaults are stable
+			again := *tc.backend.DeepCopy()
+			again.SetDefaults()
+			if !reflect.DeepEqual(again, tc.backend) {
+				t.Errorf("Expected defaulting twice to be a no-op, got %+v", again)
+			}
+		})
+	}
+}
+
+func TestObjectStoreDefaults(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	obj := &ObjectStore{
+		Spec: ObjectStoreSpec{
+			Backend: Backend{S3: &S3Spec{Bucket: "stash", Endpoint: "minio.example.com"}},
+		},
+	}
+	scheme.Default(obj)
+
+	expected := ObjectStoreSpec{
+		Backend: Backend{S3: &S3Spec{
+			Bucket:   "stash",
+			Endpoint: "https://minio.example.com",
+			Region:   DefaultS3Region,
+		}},
+		CheckInterval: &metav1.Duration{Duration: DefaultObjectStoreCheckInterval},
+	}
+	if !reflect.DeepEqual(obj.Spec, expected) {
+		t.Errorf("Expected spec %+v, got %+v", expected, obj.Spec)
+	}
+}
+
+func TestReplicatedBackendDefaults(t *testing.T) {
+	rb := ReplicatedBackend{
+		Primary: Backend{S3: &S3Spec{Bucket: "stash", Endpoint: "minio.example.com"}},
+		Mirrors: []Backend{{GCS: &GCSSpec{Bucket: "stash", Prefix: "dr/"}}},
+	}
+	rb.SetDefaults()
+
+	expected := ReplicatedBackend{
+		Primary: Backend{S3: &S3Spec{
+			Bucket:   "stash",
+			Endpoint: "https://minio.example.com",
+			Region:   DefaultS3Region,
+		}},
+		Mirrors:     []Backend{{GCS: &GCSSpec{Bucket: "stash", Prefix: "dr"}}},
+		WriteQuorum: 2,
+	}
+	if !reflect.DeepEqual(rb, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rb)
+	}
+}
@@ -15,6 +15,8 @@ limitations under the License.
 */
 
 // +k8s:deepcopy-gen=package
+// +k8s:defaulter-gen=TypeMeta
 // +k8s:openapi-gen=true
+// +groupName=objectstore.kmodules.xyz
 // +gencrdrefdocs:force=true
 package v1 // import "kmodules.xyz/objectstore-api/api/v1"
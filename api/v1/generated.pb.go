@@ -28,6 +28,9 @@ import (
 	strings "strings"
 
 	proto "github.com/gogo/protobuf/proto"
+	github_com_gogo_protobuf_sortkeys "github.com/gogo/protobuf/sortkeys"
+	v1 "k8s.io/api/core/v1"
+	v11 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -125,10 +128,38 @@ func (m *Backend) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Backend proto.InternalMessageInfo
 
+func (m *CredentialKeySource) Reset()      { *m = CredentialKeySource{} }
+func (*CredentialKeySource) ProtoMessage() {}
+func (*CredentialKeySource) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2461da20a2c3fd4, []int{3}
+}
+func (m *CredentialKeySource) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CredentialKeySource) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *CredentialKeySource) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CredentialKeySource.Merge(m, src)
+}
+func (m *CredentialKeySource) XXX_Size() int {
+	return m.Size()
+}
+func (m *CredentialKeySource) XXX_DiscardUnknown() {
+	xxx_messageInfo_CredentialKeySource.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CredentialKeySource proto.InternalMessageInfo
+
 func (m *GCSSpec) Reset()      { *m = GCSSpec{} }
 func (*GCSSpec) ProtoMessage() {}
 func (*GCSSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c2461da20a2c3fd4, []int{3}
+	return fileDescriptor_c2461da20a2c3fd4, []int{4}
 }
 func (m *GCSSpec) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -156,7 +187,7 @@ var xxx_messageInfo_GCSSpec proto.InternalMessageInfo
 func (m *LocalSpec) Reset()      { *m = LocalSpec{} }
 func (*LocalSpec) ProtoMessage() {}
 func (*LocalSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c2461da20a2c3fd4, []int{4}
+	return fileDescriptor_c2461da20a2c3fd4, []int{5}
 }
 func (m *LocalSpec) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -181,10 +212,206 @@ func (m *LocalSpec) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_LocalSpec proto.InternalMessageInfo
 
+func (m *ObjectStore) Reset()      { *m = ObjectStore{} }
+func (*ObjectStore) ProtoMessage() {}
+func (*ObjectStore) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2461da20a2c3fd4, []int{6}
+}
+func (m *ObjectStore) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ObjectStore) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ObjectStore) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ObjectStore.Merge(m, src)
+}
+func (m *ObjectStore) XXX_Size() int {
+	return m.Size()
+}
+func (m *ObjectStore) XXX_DiscardUnknown() {
+	xxx_messageInfo_ObjectStore.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ObjectStore proto.InternalMessageInfo
+
+func (m *ObjectStoreList) Reset()      { *m = ObjectStoreList{} }
+func (*ObjectStoreList) ProtoMessage() {}
+func (*ObjectStoreList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2461da20a2c3fd4, []int{7}
+}
+func (m *ObjectStoreList) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ObjectStoreList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ObjectStoreList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ObjectStoreList.Merge(m, src)
+}
+func (m *ObjectStoreList) XXX_Size() int {
+	return m.Size()
+}
+func (m *ObjectStoreList) XXX_DiscardUnknown() {
+	xxx_messageInfo_ObjectStoreList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ObjectStoreList proto.InternalMessageInfo
+
+func (m *ObjectStoreSpec) Reset()      { *m = ObjectStoreSpec{} }
+func (*ObjectStoreSpec) ProtoMessage() {}
+func (*ObjectStoreSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2461da20a2c3fd4, []int{8}
+}
+func (m *ObjectStoreSpec) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ObjectStoreSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ObjectStoreSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ObjectStoreSpec.Merge(m, src)
+}
+func (m *ObjectStoreSpec) XXX_Size() int {
+	return m.Size()
+}
+func (m *ObjectStoreSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_ObjectStoreSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ObjectStoreSpec proto.InternalMessageInfo
+
+func (m *ObjectStoreStatus) Reset()      { *m = ObjectStoreStatus{} }
+func (*ObjectStoreStatus) ProtoMessage() {}
+func (*ObjectStoreStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2461da20a2c3fd4, []int{9}
+}
+func (m *ObjectStoreStatus) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ObjectStoreStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ObjectStoreStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ObjectStoreStatus.Merge(m, src)
+}
+func (m *ObjectStoreStatus) XXX_Size() int {
+	return m.Size()
+}
+func (m *ObjectStoreStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ObjectStoreStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ObjectStoreStatus proto.InternalMessageInfo
+
+func (m *ProxyConfig) Reset()      { *m = ProxyConfig{} }
+func (*ProxyConfig) ProtoMessage() {}
+func (*ProxyConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2461da20a2c3fd4, []int{10}
+}
+func (m *ProxyConfig) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ProxyConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ProxyConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProxyConfig.Merge(m, src)
+}
+func (m *ProxyConfig) XXX_Size() int {
+	return m.Size()
+}
+func (m *ProxyConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProxyConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProxyConfig proto.InternalMessageInfo
+
+func (m *RateLimit) Reset()      { *m = RateLimit{} }
+func (*RateLimit) ProtoMessage() {}
+func (*RateLimit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2461da20a2c3fd4, []int{11}
+}
+func (m *RateLimit) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RateLimit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *RateLimit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RateLimit.Merge(m, src)
+}
+func (m *RateLimit) XXX_Size() int {
+	return m.Size()
+}
+func (m *RateLimit) XXX_DiscardUnknown() {
+	xxx_messageInfo_RateLimit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RateLimit proto.InternalMessageInfo
+
+func (m *ReplicatedBackend) Reset()      { *m = ReplicatedBackend{} }
+func (*ReplicatedBackend) ProtoMessage() {}
+func (*ReplicatedBackend) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2461da20a2c3fd4, []int{12}
+}
+func (m *ReplicatedBackend) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReplicatedBackend) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ReplicatedBackend) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicatedBackend.Merge(m, src)
+}
+func (m *ReplicatedBackend) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReplicatedBackend) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicatedBackend.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicatedBackend proto.InternalMessageInfo
+
 func (m *RestServerSpec) Reset()      { *m = RestServerSpec{} }
 func (*RestServerSpec) ProtoMessage() {}
 func (*RestServerSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c2461da20a2c3fd4, []int{5}
+	return fileDescriptor_c2461da20a2c3fd4, []int{13}
 }
 func (m *RestServerSpec) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -209,10 +436,66 @@ func (m *RestServerSpec) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_RestServerSpec proto.InternalMessageInfo
 
+func (m *RetryPolicy) Reset()      { *m = RetryPolicy{} }
+func (*RetryPolicy) ProtoMessage() {}
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2461da20a2c3fd4, []int{14}
+}
+func (m *RetryPolicy) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RetryPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *RetryPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetryPolicy.Merge(m, src)
+}
+func (m *RetryPolicy) XXX_Size() int {
+	return m.Size()
+}
+func (m *RetryPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetryPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetryPolicy proto.InternalMessageInfo
+
+func (m *S3Encryption) Reset()      { *m = S3Encryption{} }
+func (*S3Encryption) ProtoMessage() {}
+func (*S3Encryption) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2461da20a2c3fd4, []int{15}
+}
+func (m *S3Encryption) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *S3Encryption) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *S3Encryption) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_S3Encryption.Merge(m, src)
+}
+func (m *S3Encryption) XXX_Size() int {
+	return m.Size()
+}
+func (m *S3Encryption) XXX_DiscardUnknown() {
+	xxx_messageInfo_S3Encryption.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_S3Encryption proto.InternalMessageInfo
+
 func (m *S3Spec) Reset()      { *m = S3Spec{} }
 func (*S3Spec) ProtoMessage() {}
 func (*S3Spec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c2461da20a2c3fd4, []int{6}
+	return fileDescriptor_c2461da20a2c3fd4, []int{16}
 }
 func (m *S3Spec) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -237,10 +520,38 @@ func (m *S3Spec) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_S3Spec proto.InternalMessageInfo
 
+func (m *StorageSecretReference) Reset()      { *m = StorageSecretReference{} }
+func (*StorageSecretReference) ProtoMessage() {}
+func (*StorageSecretReference) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2461da20a2c3fd4, []int{17}
+}
+func (m *StorageSecretReference) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StorageSecretReference) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *StorageSecretReference) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageSecretReference.Merge(m, src)
+}
+func (m *StorageSecretReference) XXX_Size() int {
+	return m.Size()
+}
+func (m *StorageSecretReference) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageSecretReference.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageSecretReference proto.InternalMessageInfo
+
 func (m *SwiftSpec) Reset()      { *m = SwiftSpec{} }
 func (*SwiftSpec) ProtoMessage() {}
 func (*SwiftSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c2461da20a2c3fd4, []int{7}
+	return fileDescriptor_c2461da20a2c3fd4, []int{18}
 }
 func (m *SwiftSpec) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -265,15 +576,58 @@ func (m *SwiftSpec) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_SwiftSpec proto.InternalMessageInfo
 
+func (m *TLSConfig) Reset()      { *m = TLSConfig{} }
+func (*TLSConfig) ProtoMessage() {}
+func (*TLSConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2461da20a2c3fd4, []int{19}
+}
+func (m *TLSConfig) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TLSConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *TLSConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TLSConfig.Merge(m, src)
+}
+func (m *TLSConfig) XXX_Size() int {
+	return m.Size()
+}
+func (m *TLSConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_TLSConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TLSConfig proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*AzureSpec)(nil), "kmodules.xyz.objectstore_api.api.v1.AzureSpec")
 	proto.RegisterType((*B2Spec)(nil), "kmodules.xyz.objectstore_api.api.v1.B2Spec")
 	proto.RegisterType((*Backend)(nil), "kmodules.xyz.objectstore_api.api.v1.Backend")
+	proto.RegisterType((*CredentialKeySource)(nil), "kmodules.xyz.objectstore_api.api.v1.CredentialKeySource")
 	proto.RegisterType((*GCSSpec)(nil), "kmodules.xyz.objectstore_api.api.v1.GCSSpec")
 	proto.RegisterType((*LocalSpec)(nil), "kmodules.xyz.objectstore_api.api.v1.LocalSpec")
+	proto.RegisterType((*ObjectStore)(nil), "kmodules.xyz.objectstore_api.api.v1.ObjectStore")
+	proto.RegisterType((*ObjectStoreList)(nil), "kmodules.xyz.objectstore_api.api.v1.ObjectStoreList")
+	proto.RegisterType((*ObjectStoreSpec)(nil), "kmodules.xyz.objectstore_api.api.v1.ObjectStoreSpec")
+	proto.RegisterType((*ObjectStoreStatus)(nil), "kmodules.xyz.objectstore_api.api.v1.ObjectStoreStatus")
+	proto.RegisterType((*ProxyConfig)(nil), "kmodules.xyz.objectstore_api.api.v1.ProxyConfig")
+	proto.RegisterType((*RateLimit)(nil), "kmodules.xyz.objectstore_api.api.v1.RateLimit")
+	proto.RegisterType((*ReplicatedBackend)(nil), "kmodules.xyz.objectstore_api.api.v1.ReplicatedBackend")
 	proto.RegisterType((*RestServerSpec)(nil), "kmodules.xyz.objectstore_api.api.v1.RestServerSpec")
+	proto.RegisterType((*RetryPolicy)(nil), "kmodules.xyz.objectstore_api.api.v1.RetryPolicy")
+	proto.RegisterType((*S3Encryption)(nil), "kmodules.xyz.objectstore_api.api.v1.S3Encryption")
+	proto.RegisterMapType((map[string]string)(nil), "kmodules.xyz.objectstore_api.api.v1.S3Encryption.KmsEncryptionContextEntry")
 	proto.RegisterType((*S3Spec)(nil), "kmodules.xyz.objectstore_api.api.v1.S3Spec")
+	proto.RegisterMapType((map[string]string)(nil), "kmodules.xyz.objectstore_api.api.v1.S3Spec.TagsEntry")
+	proto.RegisterType((*StorageSecretReference)(nil), "kmodules.xyz.objectstore_api.api.v1.StorageSecretReference")
+	proto.RegisterMapType((map[string]string)(nil), "kmodules.xyz.objectstore_api.api.v1.StorageSecretReference.KeysEntry")
 	proto.RegisterType((*SwiftSpec)(nil), "kmodules.xyz.objectstore_api.api.v1.SwiftSpec")
+	proto.RegisterType((*TLSConfig)(nil), "kmodules.xyz.objectstore_api.api.v1.TLSConfig")
 }
 
 func init() {
@@ -281,54 +635,155 @@ func init() {
 }
 
 var fileDescriptor_c2461da20a2c3fd4 = []byte{
-	// 739 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x55, 0x4f, 0x6f, 0xd3, 0x48,
-	0x14, 0x8f, 0xe3, 0xd4, 0x49, 0x26, 0x55, 0x76, 0x3b, 0xbb, 0x5a, 0x79, 0x2b, 0xe1, 0x54, 0xa9,
-	0x54, 0x15, 0xd1, 0x3a, 0xaa, 0x23, 0x24, 0x4e, 0x48, 0x38, 0x42, 0x51, 0xa5, 0x16, 0xca, 0x18,
-	0x38, 0xf4, 0x82, 0x6c, 0x67, 0xea, 0x9a, 0x24, 0x9e, 0x68, 0x3c, 0x0e, 0xa5, 0x27, 0x3e, 0x02,
-	0x47, 0xb8, 0xf3, 0x4d, 0xb8, 0xf4, 0xd8, 0x63, 0x4f, 0x11, 0x35, 0xe2, 0xc4, 0x97, 0x40, 0x33,
-	0x9e, 0x24, 0x6e, 0x41, 0x28, 0x3d, 0x80, 0x38, 0x58, 0xf2, 0x7b, 0xef, 0xf7, 0x7e, 0xf3, 0xfe,
-	0xce, 0x80, 0x76, 0x7f, 0x48, 0x7a, 0xc9, 0x00, 0xc7, 0xe6, 0xc9, 0xeb, 0xd3, 0x16, 0xf1, 0x5e,
-	0x62, 0x9f, 0xc5, 0x8c, 0x50, 0xbc, 0xed, 0x8e, 0xc2, 0x16, 0xff, 0xc6, 0x3b, 0xad, 0x00, 0x47,
-	0x98, 0xba, 0x0c, 0xf7, 0xcc, 0x11, 0x25, 0x8c, 0xc0, 0xf5, 0xbc, 0x93, 0x99, 0x73, 0x7a, 0xe1,
-	0x8e, 0x42, 0x93, 0x7f, 0xe3, 0x9d, 0xd5, 0xed, 0x20, 0x64, 0xc7, 0x89, 0x67, 0xfa, 0x64, 0xd8,
-	0x0a, 0x48, 0x40, 0x5a, 0xc2, 0xd7, 0x4b, 0x8e, 0x84, 0x24, 0x04, 0xf1, 0x97, 0x71, 0xae, 0x36,
-	0xfb, 0xf7, 0x62, 0x33, 0x24, 0xe2, 0x48, 0x9f, 0x50, 0xfc, 0x83, 0x73, 0x9b, 0x1f, 0x14, 0x50,
-	0x7d, 0x70, 0x9a, 0x50, 0xec, 0x8c, 0xb0, 0x0f, 0x5b, 0xa0, 0xea, 0x93, 0x88, 0xb9, 0x61, 0x84,
-	0xa9, 0xae, 0xac, 0x29, 0x9b, 0x55, 0x7b, 0xe5, 0x6c, 0xd2, 0x28, 0xa4, 0x93, 0x46, 0xb5, 0x33,
-	0x35, 0xa0, 0x39, 0x06, 0x6e, 0x00, 0x6d, 0x44, 0xf1, 0x51, 0x78, 0xa2, 0x17, 0x05, 0xba, 0x2e,
-	0xd1, 0xda, 0x81, 0xd0, 0x22, 0x69, 0x85, 0xf7, 0x41, 0x7d, 0xe8, 0x9e, 0x74, 0x48, 0x14, 0x61,
-	0x9f, 0x85, 0x24, 0x8a, 0x75, 0x75, 0x4d, 0xd9, 0x54, 0xed, 0xff, 0x24, 0xbe, 0xbe, 0x7f, 0xc5,
-	0x8a, 0xae, 0xa1, 0x9b, 0xef, 0x14, 0xa0, 0xd9, 0x96, 0x88, 0x71, 0x03, 0x68, 0x5e, 0xe2, 0xf7,
-	0x31, 0x93, 0x01, 0xce, 0x8e, 0xb4, 0x85, 0x16, 0x49, 0xeb, 0x6f, 0x0b, 0xed, 0x6b, 0x09, 0x94,
-	0x6d, 0xd7, 0xef, 0xe3, 0xa8, 0x07, 0xbb, 0x60, 0x85, 0x37, 0xcd, 0x0d, 0xb0, 0x83, 0x7d, 0x8a,
-	0xd9, 0x23, 0x77, 0x88, 0x65, 0x98, 0xff, 0x4b, 0xba, 0x15, 0xe7, 0x3a, 0x00, 0x7d, 0xef, 0x03,
-	0x1f, 0x83, 0xa5, 0x01, 0xf1, 0xdd, 0x81, 0x88, 0xbd, 0x66, 0x99, 0xe6, 0x02, 0xe3, 0x61, 0xee,
-	0x71, 0x0f, 0x5e, 0x23, 0xbb, 0x9a, 0x4e, 0x1a, 0x4b, 0x42, 0x44, 0x19, 0x0f, 0xec, 0x80, 0x62,
-	0xdc, 0x16, 0x99, 0xd5, 0xac, 0x3b, 0x0b, 0xb1, 0x39, 0x6d, 0x41, 0xa5, 0xa5, 0x93, 0x46, 0xd1,
-	0x69, 0xa3, 0x62, 0xdc, 0x86, 0x5d, 0xa0, 0x06, 0x7e, 0xac, 0x97, 0x04, 0xcb, 0xd6, 0x42, 0x2c,
-	0xdd, 0x8e, 0x23, 0x68, 0xca, 0xe9, 0xa4, 0xa1, 0x76, 0x3b, 0x0e, 0xe2, 0x0c, 0x3c, 0x3d, 0x97,
-	0x0f, 0x9d, 0xbe, 0x74, 0x83, 0xf4, 0x66, 0x63, 0x9a, 0xa5, 0x27, 0x44, 0x94, 0xf1, 0x70, 0xc2,
-	0xf8, 0x55, 0x78, 0xc4, 0x74, 0xed, 0x06, 0x84, 0x0e, 0xf7, 0x98, 0x13, 0x0a, 0x11, 0x65, 0x3c,
-	0xbc, 0x5e, 0x9e, 0xa5, 0x97, 0x6f, 0x50, 0xaf, 0x6c, 0x3c, 0xb3, 0x7a, 0xd9, 0x16, 0x2a, 0x7a,
-	0x16, 0x7c, 0x02, 0x4a, 0x14, 0xc7, 0x4c, 0xaf, 0x08, 0x9a, 0xf6, 0x42, 0x34, 0x08, 0xc7, 0xcc,
-	0xc1, 0x74, 0x8c, 0xa9, 0xa0, 0xab, 0xa4, 0x93, 0x46, 0x89, 0xeb, 0x90, 0xa0, 0x6a, 0xbe, 0x57,
-	0x40, 0x59, 0xd6, 0xf4, 0x8f, 0xdb, 0x84, 0x8f, 0x0a, 0xa8, 0xce, 0x66, 0x10, 0x1e, 0x82, 0xe5,
-	0x31, 0x19, 0x24, 0x43, 0xec, 0x90, 0x84, 0xfa, 0xd9, 0x1a, 0xd4, 0xac, 0x35, 0x33, 0xbb, 0x94,
-	0x44, 0xba, 0xfc, 0x52, 0xe2, 0x39, 0x3f, 0xcf, 0xe1, 0xec, 0x7f, 0xe5, 0x69, 0xcb, 0x79, 0x2d,
-	0xba, 0xc2, 0xc5, 0xef, 0xa9, 0x21, 0x49, 0x22, 0x76, 0xe0, 0xb2, 0x63, 0x99, 0xd4, 0xec, 0x9e,
-	0xda, 0x9f, 0x1a, 0xd0, 0x1c, 0x03, 0x6f, 0x83, 0x72, 0x9c, 0x78, 0x02, 0xae, 0x0a, 0xf8, 0x5f,
-	0x12, 0x5e, 0x76, 0x32, 0x35, 0x9a, 0xda, 0x9b, 0x2d, 0x50, 0xbf, 0xda, 0x03, 0x78, 0x0b, 0xa8,
-	0x09, 0x1d, 0xc8, 0x22, 0xd7, 0xa4, 0xa3, 0xfa, 0x0c, 0xed, 0x21, 0xae, 0x6f, 0x7e, 0x51, 0x80,
-	0x96, 0x2d, 0x0b, 0xdc, 0x02, 0x15, 0x1c, 0xf5, 0x46, 0x24, 0x8c, 0xa6, 0x3d, 0xf9, 0x5b, 0xc2,
-	0x2b, 0x0f, 0xa5, 0x1e, 0xcd, 0x10, 0xb9, 0xfe, 0x15, 0x17, 0xec, 0x9f, 0xfa, 0xd3, 0xfe, 0x6d,
-	0x00, 0x8d, 0xe2, 0x20, 0x24, 0x91, 0xd8, 0xd0, 0x1c, 0x0e, 0x09, 0x2d, 0x92, 0x56, 0x78, 0x17,
-	0xd4, 0xc2, 0x28, 0xc6, 0x7e, 0x42, 0xf1, 0xd3, 0x3d, 0x47, 0xec, 0x60, 0xc5, 0xfe, 0x47, 0x82,
-	0x6b, 0xbb, 0x73, 0x13, 0xca, 0xe3, 0x9a, 0x3d, 0x50, 0x9d, 0x6d, 0xcc, 0x2f, 0x7b, 0x29, 0xec,
-	0xdd, 0xb3, 0x4b, 0xa3, 0x70, 0x7e, 0x69, 0x14, 0x2e, 0x2e, 0x8d, 0xc2, 0x9b, 0xd4, 0x50, 0xce,
-	0x52, 0x43, 0x39, 0x4f, 0x0d, 0xe5, 0x22, 0x35, 0x94, 0x4f, 0xa9, 0xa1, 0xbc, 0xfd, 0x6c, 0x14,
-	0x0e, 0xd7, 0x17, 0x78, 0x63, 0xbf, 0x05, 0x00, 0x00, 0xff, 0xff, 0xfc, 0xea, 0x65, 0x4f, 0x89,
-	0x07, 0x00, 0x00,
+	// 2367 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0xdf, 0x6f, 0x1b, 0xc7,
+	0xf1, 0x37, 0x7f, 0x89, 0xe2, 0x50, 0x3f, 0xd7, 0x8a, 0xbf, 0x67, 0xe1, 0x5b, 0x51, 0x65, 0x90,
+	0xc0, 0x69, 0x1d, 0x2a, 0x96, 0xec, 0xd6, 0x68, 0x81, 0xa2, 0x22, 0xa5, 0x0a, 0xaa, 0x44, 0x5b,
+	0x9e, 0x93, 0x6d, 0xc0, 0x29, 0x92, 0x1e, 0x8f, 0x2b, 0xfa, 0xc2, 0xe3, 0xdd, 0xf5, 0x76, 0x29,
+	0x8b, 0x79, 0x6a, 0x81, 0xfc, 0x01, 0x7d, 0xec, 0x43, 0x5b, 0xf4, 0xad, 0xff, 0x46, 0x8b, 0x02,
+	0x85, 0x1f, 0x03, 0xf4, 0x25, 0x40, 0x01, 0xa2, 0x66, 0xff, 0x83, 0x3e, 0x0a, 0x45, 0x51, 0xec,
+	0xde, 0xde, 0x2f, 0x8a, 0x4e, 0x48, 0x07, 0x2d, 0xfa, 0x20, 0x40, 0x37, 0xf3, 0x99, 0xcf, 0xee,
+	0xce, 0xee, 0xcc, 0xce, 0x0e, 0x61, 0xa7, 0xdb, 0x73, 0xdb, 0x7d, 0x9b, 0xb2, 0xda, 0xc5, 0xe0,
+	0xd3, 0x2d, 0xb7, 0xf5, 0x09, 0x35, 0x39, 0xe3, 0xae, 0x4f, 0xdf, 0x37, 0x3c, 0x6b, 0x4b, 0xfc,
+	0x9d, 0xdf, 0xd9, 0xea, 0x50, 0x87, 0xfa, 0x06, 0xa7, 0xed, 0x9a, 0xe7, 0xbb, 0xdc, 0x25, 0x6f,
+	0x27, 0x8d, 0x6a, 0x09, 0xa3, 0x8f, 0x0d, 0xcf, 0xaa, 0x89, 0xbf, 0xf3, 0x3b, 0xeb, 0xef, 0x77,
+	0x2c, 0xfe, 0xbc, 0xdf, 0xaa, 0x99, 0x6e, 0x6f, 0xab, 0xe3, 0x76, 0xdc, 0x2d, 0x69, 0xdb, 0xea,
+	0x9f, 0xc9, 0x2f, 0xf9, 0x21, 0xff, 0x0b, 0x38, 0xd7, 0xab, 0xdd, 0xfb, 0xac, 0x66, 0xb9, 0x72,
+	0x48, 0xd3, 0xf5, 0xe9, 0x84, 0x71, 0xd7, 0xef, 0xc6, 0x98, 0x9e, 0x61, 0x3e, 0xb7, 0x1c, 0xea,
+	0x0f, 0xb6, 0xbc, 0x6e, 0x47, 0x08, 0xd8, 0x56, 0x8f, 0x72, 0x63, 0x82, 0x55, 0xf5, 0x0f, 0x59,
+	0x28, 0xed, 0x7e, 0xda, 0xf7, 0xa9, 0xee, 0x51, 0x93, 0x6c, 0x41, 0xc9, 0x74, 0x1d, 0x6e, 0x08,
+	0x63, 0x2d, 0xb3, 0x99, 0xb9, 0x55, 0xaa, 0xaf, 0xbe, 0x1c, 0x56, 0xae, 0x8d, 0x86, 0x95, 0x52,
+	0x23, 0x54, 0x60, 0x8c, 0x21, 0xef, 0xc2, 0x9c, 0xe7, 0xd3, 0x33, 0xeb, 0x42, 0xcb, 0x4a, 0xf4,
+	0x92, 0x42, 0xcf, 0x9d, 0x48, 0x29, 0x2a, 0x2d, 0xf9, 0x01, 0x2c, 0xf5, 0x8c, 0x8b, 0x86, 0xeb,
+	0x38, 0xd4, 0xe4, 0x96, 0xeb, 0x30, 0x2d, 0xb7, 0x99, 0xb9, 0x95, 0xab, 0xdf, 0x50, 0xf8, 0xa5,
+	0x66, 0x4a, 0x8b, 0x63, 0x68, 0x72, 0x1b, 0xe6, 0xa9, 0xd3, 0xf6, 0x5c, 0xcb, 0xe1, 0x5a, 0x5e,
+	0x8e, 0xb4, 0xa2, 0x2c, 0xe7, 0xf7, 0x95, 0x1c, 0x23, 0x84, 0x18, 0x4d, 0x78, 0xdc, 0xe8, 0xd0,
+	0x5d, 0xd3, 0x74, 0xfb, 0x0e, 0xd7, 0x0a, 0xd2, 0x26, 0x1a, 0x4d, 0x4f, 0x69, 0x71, 0x0c, 0x2d,
+	0x46, 0x33, 0x6d, 0x8b, 0x3a, 0xfc, 0x70, 0x4f, 0x9b, 0x4b, 0x8f, 0xd6, 0x50, 0x72, 0x8c, 0x10,
+	0xd5, 0x5f, 0x65, 0x60, 0xae, 0xbe, 0x2d, 0xfd, 0xf7, 0x2e, 0xcc, 0xb5, 0xfa, 0x66, 0x97, 0x72,
+	0x2d, 0x93, 0x76, 0x47, 0x5d, 0x4a, 0x51, 0x69, 0xff, 0x5b, 0x6e, 0xab, 0xfe, 0x71, 0x1e, 0x8a,
+	0x75, 0xc3, 0xec, 0x52, 0xa7, 0x4d, 0x0e, 0x60, 0x55, 0x2d, 0x53, 0xa7, 0xa6, 0x4f, 0xf9, 0x03,
+	0xa3, 0x47, 0xd5, 0x34, 0x6f, 0x2a, 0xba, 0x55, 0x7d, 0x1c, 0x80, 0x57, 0x6d, 0xc8, 0x43, 0x28,
+	0xd8, 0xae, 0x69, 0xd8, 0x72, 0xee, 0xe5, 0xed, 0x5a, 0x6d, 0x8a, 0x03, 0x5f, 0x3b, 0x16, 0x16,
+	0xc2, 0x47, 0xf5, 0xd2, 0x68, 0x58, 0x29, 0xc8, 0x4f, 0x0c, 0x78, 0x48, 0x03, 0xb2, 0x6c, 0x47,
+	0xae, 0xac, 0xbc, 0xfd, 0xed, 0xa9, 0xd8, 0xf4, 0x1d, 0x49, 0x35, 0x37, 0x1a, 0x56, 0xb2, 0xfa,
+	0x0e, 0x66, 0xd9, 0x0e, 0x39, 0x80, 0x5c, 0xc7, 0x64, 0xf2, 0x70, 0x94, 0xb7, 0x6f, 0x4f, 0xc5,
+	0x72, 0xd0, 0xd0, 0x25, 0x4d, 0x71, 0x34, 0xac, 0xe4, 0x0e, 0x1a, 0x3a, 0x0a, 0x06, 0xb1, 0x3c,
+	0x43, 0x04, 0x84, 0x56, 0x98, 0x61, 0x79, 0x51, 0x08, 0x05, 0xcb, 0x93, 0x9f, 0x18, 0xf0, 0x08,
+	0x42, 0xf6, 0xc2, 0x3a, 0xe3, 0xda, 0xdc, 0x0c, 0x84, 0xba, 0xb0, 0x88, 0x09, 0xe5, 0x27, 0x06,
+	0x3c, 0xc2, 0x5f, 0xad, 0x6d, 0xad, 0x38, 0x83, 0xbf, 0xea, 0xdb, 0xb1, 0xbf, 0xea, 0xdb, 0x98,
+	0x6d, 0x6d, 0x93, 0x47, 0x90, 0xf7, 0x29, 0xe3, 0xda, 0xbc, 0xa4, 0xd9, 0x99, 0x8a, 0x06, 0x29,
+	0xe3, 0x3a, 0xf5, 0xcf, 0xa9, 0x2f, 0xe9, 0xe6, 0x47, 0xc3, 0x4a, 0x5e, 0xc8, 0x50, 0x52, 0x11,
+	0x0e, 0x8b, 0xa9, 0xd3, 0xa2, 0x95, 0x24, 0xf7, 0xf7, 0xa7, 0x5b, 0x70, 0xd2, 0x12, 0xe9, 0x19,
+	0xf5, 0xa9, 0x63, 0xd2, 0xfa, 0xea, 0x68, 0x58, 0x59, 0x4c, 0xeb, 0xd2, 0x83, 0x90, 0x43, 0xc8,
+	0x71, 0x9b, 0x69, 0x30, 0x83, 0x73, 0x4f, 0x8f, 0xf5, 0x86, 0xeb, 0x9c, 0x59, 0x9d, 0x60, 0xeb,
+	0x4f, 0x8f, 0x75, 0x14, 0x1c, 0xe4, 0x11, 0x14, 0x3c, 0xdf, 0xbd, 0x18, 0x68, 0x65, 0x49, 0xf6,
+	0xc1, 0x54, 0x64, 0x27, 0xc2, 0x42, 0xd1, 0xc9, 0xbd, 0x92, 0x02, 0x0c, 0x98, 0x88, 0x09, 0x65,
+	0x9f, 0x72, 0x7f, 0x70, 0xe2, 0xda, 0x96, 0x39, 0xd0, 0x16, 0x66, 0x20, 0xc6, 0xd8, 0xae, 0xbe,
+	0x3c, 0x1a, 0x56, 0xca, 0x09, 0x01, 0x26, 0x59, 0xc9, 0x87, 0x50, 0x12, 0x39, 0xfd, 0xd8, 0xea,
+	0x59, 0x5c, 0x5b, 0x9c, 0xc1, 0x11, 0x18, 0x5a, 0xd5, 0x17, 0x45, 0x8a, 0x8f, 0x3e, 0x31, 0xe6,
+	0xab, 0xfe, 0x3a, 0x0b, 0xd7, 0x1b, 0x3e, 0x6d, 0x53, 0x87, 0x5b, 0x86, 0x7d, 0x44, 0x07, 0xba,
+	0xdb, 0xf7, 0x4d, 0x4a, 0xbe, 0x01, 0xb9, 0x2e, 0x1d, 0xa8, 0x0c, 0x52, 0x56, 0x19, 0x24, 0x77,
+	0x44, 0x07, 0x28, 0xe4, 0xe4, 0x43, 0x58, 0x60, 0x72, 0x83, 0x84, 0x84, 0x9e, 0xa9, 0x64, 0xf1,
+	0x4e, 0x2d, 0xb8, 0xa5, 0xe4, 0x04, 0xc4, 0x4d, 0x26, 0xb7, 0x3e, 0xc4, 0xe9, 0xd4, 0xa6, 0x26,
+	0x77, 0xfd, 0xfa, 0xca, 0x68, 0x58, 0x59, 0xd0, 0x13, 0xe6, 0x98, 0x22, 0x23, 0x1d, 0x58, 0x36,
+	0xa5, 0xc7, 0x9b, 0x86, 0xa7, 0xf8, 0x83, 0xf4, 0x71, 0x6b, 0x12, 0x7f, 0x23, 0x01, 0x8d, 0x86,
+	0xb8, 0x3e, 0x1a, 0x56, 0x96, 0x1b, 0x69, 0x12, 0x1c, 0x67, 0x25, 0x9b, 0x90, 0x3f, 0xb3, 0x6c,
+	0xaa, 0xee, 0x9c, 0x05, 0xb5, 0xca, 0xfc, 0x8f, 0x2c, 0x9b, 0xa2, 0xd4, 0x54, 0x7f, 0x9b, 0x85,
+	0xa2, 0x4a, 0x24, 0xff, 0x6b, 0xe9, 0x9f, 0x7c, 0x0c, 0x37, 0xad, 0x9e, 0x47, 0x7d, 0xe6, 0x3a,
+	0x06, 0xa7, 0x22, 0x72, 0x2d, 0x33, 0xba, 0x12, 0x83, 0x25, 0x7d, 0x53, 0x51, 0xdd, 0x3c, 0x7c,
+	0x1d, 0x10, 0x5f, 0xcf, 0x41, 0xaa, 0x30, 0xc7, 0x4c, 0xd7, 0xa3, 0x4c, 0x2b, 0x6c, 0xe6, 0x6e,
+	0x95, 0xea, 0x20, 0x16, 0xa1, 0x4b, 0x09, 0x2a, 0x4d, 0xf5, 0x4f, 0x19, 0x28, 0x45, 0xd9, 0x9f,
+	0x3c, 0x83, 0x85, 0x73, 0xd7, 0xee, 0xf7, 0x68, 0x70, 0x8a, 0xa4, 0xa3, 0xca, 0xdb, 0x9b, 0x93,
+	0xb6, 0xed, 0x49, 0x02, 0x57, 0x5f, 0x53, 0xf3, 0x5c, 0x48, 0x4a, 0x31, 0xc5, 0x25, 0xaa, 0x97,
+	0x9e, 0x98, 0xd6, 0x89, 0xc1, 0x9f, 0x6b, 0xd9, 0x74, 0xf5, 0xd2, 0x0c, 0x15, 0x18, 0x63, 0xc8,
+	0x7b, 0x50, 0x64, 0xfd, 0x96, 0x84, 0xe7, 0x24, 0x7c, 0x59, 0xc1, 0x8b, 0x7a, 0x20, 0xc6, 0x50,
+	0x5f, 0xfd, 0x7d, 0x16, 0xca, 0x0f, 0x65, 0x10, 0x89, 0x64, 0x44, 0xc9, 0x4f, 0x61, 0x5e, 0x94,
+	0x54, 0x6d, 0x83, 0x1b, 0x6a, 0x0d, 0x1f, 0x24, 0xd6, 0x10, 0x15, 0x60, 0x35, 0xaf, 0xdb, 0x11,
+	0x02, 0x56, 0x13, 0x68, 0xb1, 0xaa, 0x80, 0xa4, 0x49, 0xb9, 0x51, 0x27, 0x6a, 0x34, 0x88, 0x65,
+	0x18, 0xb1, 0x92, 0x27, 0x90, 0x67, 0x1e, 0x35, 0x55, 0xe0, 0xdc, 0x9d, 0x2a, 0x9e, 0x13, 0x33,
+	0x94, 0x19, 0x3a, 0x3a, 0xb0, 0xe2, 0x0b, 0x25, 0x1f, 0xf9, 0x08, 0xe6, 0x18, 0x37, 0x78, 0x9f,
+	0xa9, 0x90, 0xf9, 0xce, 0xcc, 0xcc, 0xd2, 0x3a, 0x3e, 0xb4, 0xc1, 0x37, 0x2a, 0xd6, 0xea, 0x9f,
+	0x33, 0xb0, 0x9c, 0x40, 0x1f, 0x5b, 0x8c, 0x93, 0x9f, 0x5c, 0xf1, 0x56, 0x6d, 0x3a, 0x6f, 0x09,
+	0x6b, 0xe9, 0xab, 0xa8, 0x00, 0x0b, 0x25, 0x09, 0x4f, 0x3d, 0x86, 0x82, 0xc5, 0x69, 0x8f, 0x69,
+	0xd9, 0xcd, 0xdc, 0xd4, 0xd9, 0x35, 0x31, 0xc5, 0xfa, 0xa2, 0x22, 0x2f, 0x1c, 0x0a, 0x1a, 0x0c,
+	0xd8, 0xaa, 0x7f, 0x49, 0x2f, 0x44, 0x1e, 0xdf, 0xa7, 0x50, 0x6c, 0x05, 0xf5, 0x94, 0x96, 0x99,
+	0xa1, 0xd2, 0x50, 0x35, 0x58, 0x7c, 0xbe, 0x94, 0x00, 0x43, 0x36, 0xd2, 0x81, 0x45, 0xf3, 0x39,
+	0x35, 0xbb, 0x87, 0x0e, 0xa7, 0xfe, 0x79, 0xb2, 0xb8, 0x9a, 0xc6, 0x4d, 0x7b, 0x7d, 0xdf, 0x10,
+	0x21, 0x1f, 0x5c, 0x97, 0x8d, 0x24, 0x11, 0xa6, 0x79, 0xab, 0xbf, 0xcb, 0xc1, 0xea, 0x95, 0xcd,
+	0x24, 0x3f, 0x06, 0xe2, 0xb6, 0x98, 0xb8, 0xda, 0xdb, 0x07, 0xc1, 0x0b, 0xc1, 0x72, 0x1d, 0xb9,
+	0xc4, 0x5c, 0x7d, 0x5d, 0x4d, 0x9a, 0x3c, 0xbc, 0x82, 0xc0, 0x09, 0x56, 0xc4, 0x84, 0x45, 0xdb,
+	0x60, 0x5c, 0xce, 0xe2, 0xd4, 0xea, 0x51, 0xb5, 0x94, 0x6f, 0x4d, 0xb7, 0x14, 0x61, 0x11, 0x2c,
+	0xe3, 0x38, 0x49, 0x82, 0x69, 0x4e, 0x91, 0x42, 0x7d, 0xda, 0x11, 0x93, 0xcc, 0xa5, 0x53, 0x28,
+	0x4a, 0x29, 0x2a, 0x2d, 0x69, 0xc2, 0x82, 0x69, 0x78, 0x46, 0xcb, 0xb2, 0x2d, 0x6e, 0x51, 0x51,
+	0x1f, 0x8a, 0x3c, 0xf5, 0x9e, 0xc8, 0x24, 0x8d, 0x84, 0xfc, 0x72, 0x58, 0x79, 0x2b, 0xe1, 0x95,
+	0x48, 0x35, 0xc0, 0x94, 0x39, 0x31, 0x01, 0x4c, 0xd7, 0x69, 0x5b, 0x41, 0x36, 0x2e, 0xc8, 0xf3,
+	0xb6, 0x35, 0xdd, 0xc2, 0x1a, 0xa1, 0x5d, 0x1c, 0xf7, 0x91, 0x88, 0x61, 0x82, 0xb6, 0xaa, 0x43,
+	0x39, 0x51, 0x54, 0x88, 0x8b, 0xb6, 0xef, 0xdb, 0xe3, 0x17, 0xed, 0x63, 0x3c, 0x46, 0x21, 0x27,
+	0xef, 0x40, 0xd1, 0x71, 0x25, 0x5e, 0x9e, 0xff, 0x52, 0xbd, 0x2c, 0x0e, 0xd8, 0x83, 0x40, 0x84,
+	0xa1, 0xae, 0xfa, 0xaf, 0x0c, 0xc4, 0xf7, 0x3b, 0x39, 0x81, 0xb5, 0xbe, 0x67, 0xbb, 0x46, 0xbb,
+	0x3e, 0xe0, 0x94, 0x9d, 0x50, 0x5f, 0xa7, 0x62, 0x02, 0x6a, 0xc7, 0xff, 0x5f, 0x0d, 0xb2, 0xf6,
+	0x78, 0x02, 0x06, 0x27, 0x5a, 0x92, 0x27, 0x70, 0xa3, 0xed, 0xbe, 0x70, 0x26, 0x70, 0x66, 0x25,
+	0xe7, 0x86, 0xe2, 0xbc, 0xb1, 0x37, 0x11, 0x85, 0xaf, 0xb1, 0x16, 0xcf, 0x16, 0x9f, 0xfe, 0xac,
+	0x4f, 0x19, 0x4f, 0x50, 0x8a, 0x3d, 0x2f, 0xc4, 0xcf, 0x16, 0x1c, 0x07, 0xe0, 0x55, 0x9b, 0xea,
+	0x3f, 0x33, 0xb0, 0x8a, 0xd4, 0xb3, 0x2d, 0x53, 0x3c, 0x7f, 0xc3, 0x57, 0xd1, 0x53, 0x28, 0x7a,
+	0xbe, 0xd5, 0x33, 0xfc, 0xc1, 0xd7, 0x0b, 0xe8, 0x93, 0x80, 0x04, 0x43, 0x36, 0x41, 0xdc, 0xb3,
+	0x7c, 0xdf, 0xf5, 0xc3, 0xb4, 0xf4, 0x86, 0xc4, 0xcd, 0x80, 0x04, 0x43, 0x36, 0x72, 0x0f, 0xca,
+	0x2f, 0x7c, 0x8b, 0xd3, 0x47, 0x7d, 0xd7, 0xef, 0xf7, 0x94, 0x2b, 0xae, 0x2b, 0x78, 0xf9, 0x69,
+	0xac, 0xc2, 0x24, 0xae, 0xba, 0x05, 0x4b, 0xe9, 0xf2, 0xfd, 0x2b, 0xce, 0x55, 0xf5, 0xb3, 0x3c,
+	0x24, 0x2b, 0x4e, 0x31, 0x6e, 0xcf, 0xb8, 0xd8, 0xe5, 0x9c, 0xf6, 0x3c, 0xce, 0xb4, 0x4c, 0x7a,
+	0xdc, 0x66, 0xac, 0xc2, 0x24, 0x8e, 0x18, 0x50, 0x6e, 0x19, 0x8c, 0x8a, 0x75, 0xb9, 0x67, 0x67,
+	0x6f, 0x98, 0xd6, 0x64, 0xf9, 0x5b, 0x8f, 0x69, 0x30, 0xc9, 0x49, 0x3e, 0x02, 0xe8, 0x19, 0x17,
+	0xe1, 0x08, 0xb9, 0x37, 0x1a, 0x61, 0x49, 0xc4, 0x63, 0x33, 0x62, 0xc1, 0x04, 0x23, 0xf9, 0x04,
+	0x96, 0x8c, 0x60, 0x39, 0x22, 0xf5, 0xb8, 0x7d, 0xae, 0xe5, 0xdf, 0x68, 0x0c, 0x22, 0x4a, 0xb6,
+	0xdd, 0x14, 0x13, 0x8e, 0x31, 0x93, 0xc7, 0x50, 0xe4, 0x6a, 0x90, 0xc2, 0x1b, 0x0d, 0x22, 0xa3,
+	0x3f, 0x64, 0x0f, 0xb9, 0xc8, 0x7d, 0x28, 0xca, 0x07, 0xc3, 0x43, 0x47, 0x9b, 0x93, 0x49, 0x62,
+	0x43, 0xc0, 0x30, 0x10, 0x5d, 0x0e, 0x2b, 0xcb, 0xf2, 0xdf, 0x7d, 0x71, 0xc2, 0x1a, 0xb6, 0xc1,
+	0x18, 0x86, 0xf0, 0xea, 0x5f, 0xb3, 0xb0, 0xa0, 0xef, 0xec, 0x3b, 0xa6, 0x3f, 0xf0, 0x64, 0x7a,
+	0xbf, 0x0b, 0x79, 0x3e, 0xf0, 0xc2, 0xd6, 0xc1, 0x66, 0x58, 0x61, 0x9c, 0x0e, 0x3c, 0x7a, 0x39,
+	0xac, 0xac, 0x24, 0xb1, 0x42, 0x86, 0x12, 0x2d, 0x5a, 0x2a, 0xdd, 0x1e, 0x3b, 0xa2, 0x83, 0xc3,
+	0x3d, 0x55, 0x9a, 0x45, 0x37, 0xfa, 0x51, 0x53, 0x97, 0x72, 0x8c, 0x10, 0xe4, 0x37, 0x19, 0x58,
+	0xeb, 0xf6, 0x58, 0xcc, 0x24, 0x9a, 0x4f, 0xf4, 0x82, 0x6b, 0x39, 0x19, 0x4a, 0x47, 0x53, 0x36,
+	0x09, 0x62, 0xfb, 0xda, 0xd1, 0x04, 0xb6, 0x7d, 0x87, 0xfb, 0x83, 0x38, 0xd9, 0x1d, 0x35, 0xf5,
+	0x2b, 0x10, 0x9c, 0x38, 0x8d, 0xf5, 0x03, 0xb8, 0xf9, 0x5a, 0x42, 0xb2, 0x92, 0x78, 0x18, 0x05,
+	0x6f, 0xa1, 0x35, 0x28, 0x9c, 0x1b, 0x76, 0x3f, 0xb8, 0x09, 0x4b, 0x18, 0x7c, 0x7c, 0x2f, 0x7b,
+	0x3f, 0x53, 0xfd, 0x0c, 0x60, 0x2e, 0x68, 0x66, 0xa4, 0x5a, 0x5c, 0x99, 0xaf, 0x6c, 0x71, 0xc5,
+	0x4f, 0x8d, 0xec, 0x94, 0x4f, 0x8d, 0xdc, 0x97, 0x3e, 0x35, 0xe2, 0xfb, 0x34, 0xff, 0xa5, 0xf7,
+	0xe9, 0x3d, 0x28, 0x5b, 0x0e, 0xa3, 0x66, 0xdf, 0xa7, 0xa7, 0xc7, 0xba, 0x3c, 0xa3, 0xf3, 0x71,
+	0x16, 0x38, 0x8c, 0x55, 0x98, 0xc4, 0x11, 0x03, 0x80, 0x46, 0xde, 0x52, 0x8d, 0x90, 0x3b, 0x33,
+	0xef, 0x62, 0x10, 0xa5, 0xf1, 0x37, 0x26, 0x48, 0xc9, 0x7d, 0x58, 0x50, 0x8d, 0x01, 0x79, 0x82,
+	0x65, 0x7f, 0xa4, 0x14, 0xbf, 0x1b, 0xf4, 0x84, 0x0e, 0x53, 0x48, 0x91, 0x08, 0x0d, 0xd3, 0xd6,
+	0xe6, 0xd3, 0x89, 0x70, 0xb7, 0x71, 0x8c, 0x42, 0x4e, 0x9e, 0x42, 0x9e, 0x1b, 0x1d, 0xa6, 0x95,
+	0xe4, 0xd9, 0xbb, 0x37, 0x43, 0x83, 0xaa, 0x76, 0x6a, 0x74, 0x58, 0x70, 0xca, 0xa2, 0x4a, 0x5c,
+	0x88, 0x50, 0x12, 0x92, 0x27, 0xb0, 0x6c, 0xb4, 0xdb, 0x3e, 0x65, 0xcc, 0x72, 0x3a, 0x3a, 0x1f,
+	0xd8, 0x54, 0x76, 0x31, 0x4a, 0xf5, 0xdb, 0x0a, 0xbc, 0xbc, 0x9b, 0x56, 0x5f, 0x8a, 0x16, 0xdd,
+	0xce, 0x98, 0x10, 0xc7, 0x49, 0xc4, 0x3b, 0xa8, 0xdd, 0x37, 0x6c, 0x9d, 0x1b, 0x66, 0x57, 0xb6,
+	0x32, 0xe6, 0xe3, 0x77, 0xd0, 0x5e, 0xa8, 0xc0, 0x18, 0x13, 0xbc, 0x72, 0x3d, 0x26, 0xbb, 0x13,
+	0xf3, 0x89, 0x57, 0xee, 0xe1, 0x89, 0x8e, 0x52, 0x43, 0xb6, 0x01, 0x0c, 0xd3, 0xa4, 0xb6, 0xec,
+	0x1d, 0xcb, 0x16, 0xc3, 0x7c, 0x5c, 0xc6, 0xec, 0x46, 0x1a, 0x4c, 0xa0, 0xc8, 0x33, 0x58, 0x61,
+	0x56, 0xc7, 0x31, 0x78, 0xdf, 0xa7, 0x4f, 0xa8, 0xcf, 0xc4, 0xce, 0x2f, 0xc9, 0xf5, 0xd5, 0x94,
+	0xe5, 0x8a, 0x3e, 0xa6, 0xbf, 0x1c, 0x56, 0x88, 0xbe, 0x33, 0x2e, 0xc5, 0x2b, 0x3c, 0xe2, 0xe5,
+	0xe6, 0xbb, 0x36, 0xdd, 0xc5, 0x07, 0xda, 0x72, 0xfa, 0xe5, 0x86, 0x81, 0x18, 0x43, 0xbd, 0x98,
+	0x3a, 0xbd, 0xe0, 0xd4, 0x77, 0x0c, 0xfb, 0x70, 0x4f, 0x5b, 0x91, 0xe8, 0x68, 0xea, 0xfb, 0x91,
+	0x06, 0x13, 0x28, 0xb2, 0x0b, 0xcb, 0xc2, 0x5c, 0x17, 0x5e, 0x75, 0x1d, 0xd9, 0x29, 0x5d, 0x95,
+	0x86, 0xff, 0x17, 0xee, 0x0c, 0xa6, 0xd5, 0x38, 0x8e, 0x17, 0x15, 0xd6, 0x0b, 0xda, 0x3a, 0x94,
+	0x6d, 0x13, 0x3e, 0x38, 0x75, 0xbb, 0xd4, 0x11, 0x5d, 0x03, 0x8d, 0x48, 0x9e, 0x28, 0xe9, 0x3c,
+	0x9d, 0x80, 0xc1, 0x89, 0x96, 0x22, 0xf4, 0x18, 0x67, 0x61, 0x2e, 0xd0, 0xae, 0x4b, 0xa2, 0x28,
+	0xf4, 0xf4, 0x53, 0x3d, 0x54, 0x61, 0x12, 0x27, 0x5c, 0xe5, 0xf9, 0xae, 0xec, 0x62, 0xac, 0xa5,
+	0x5d, 0x75, 0x12, 0x88, 0x31, 0xd4, 0x4f, 0xe8, 0x37, 0xbc, 0x35, 0x4b, 0xbf, 0x61, 0xfd, 0xbb,
+	0x50, 0x8a, 0x4e, 0xfc, 0x4c, 0x69, 0xf0, 0x1f, 0x59, 0xb8, 0x31, 0xb9, 0x01, 0x28, 0xce, 0xa6,
+	0x13, 0x77, 0xaa, 0xa3, 0xb3, 0x29, 0x9d, 0x2e, 0x35, 0xa4, 0x03, 0xf9, 0x2e, 0x1d, 0x84, 0x65,
+	0xd6, 0xfe, 0xd7, 0xe8, 0x36, 0xd6, 0x8e, 0xe8, 0x60, 0x3c, 0x5e, 0x85, 0x08, 0xe5, 0x00, 0x22,
+	0xae, 0xc4, 0x80, 0xcc, 0x33, 0x4c, 0xaa, 0xd2, 0x69, 0x14, 0x57, 0x0f, 0x42, 0x05, 0xc6, 0x18,
+	0x62, 0x42, 0x91, 0xc9, 0xd6, 0x44, 0xf0, 0xee, 0x28, 0x6f, 0xdf, 0x9f, 0x6a, 0x72, 0x13, 0xba,
+	0x6d, 0x89, 0xce, 0x44, 0x40, 0x88, 0x21, 0xb3, 0x70, 0x7a, 0x34, 0xed, 0x99, 0x9c, 0xde, 0x86,
+	0x52, 0xd4, 0x65, 0xfe, 0x8f, 0xfd, 0xf2, 0x53, 0xfd, 0x45, 0x06, 0x4a, 0x51, 0xbf, 0x55, 0x04,
+	0xa3, 0x7c, 0x2f, 0xfa, 0x89, 0x5f, 0x1f, 0xa2, 0x60, 0xd4, 0x23, 0x0d, 0x26, 0x50, 0xe4, 0x87,
+	0x00, 0x3d, 0xcb, 0x09, 0x33, 0x48, 0x36, 0x55, 0x76, 0x40, 0x33, 0xd2, 0x5c, 0x0e, 0x2b, 0x70,
+	0x7a, 0xac, 0xab, 0x2f, 0x4c, 0xd8, 0xd4, 0x0f, 0x5f, 0xbe, 0xda, 0xb8, 0xf6, 0xf9, 0xab, 0x8d,
+	0x6b, 0x5f, 0xbc, 0xda, 0xb8, 0xf6, 0xf3, 0xd1, 0x46, 0xe6, 0xe5, 0x68, 0x23, 0xf3, 0xf9, 0x68,
+	0x23, 0xf3, 0xc5, 0x68, 0x23, 0xf3, 0xb7, 0xd1, 0x46, 0xe6, 0x97, 0x7f, 0xdf, 0xb8, 0xf6, 0xec,
+	0xed, 0x29, 0x7e, 0xed, 0xfb, 0xf7, 0x00, 0x08, 0xd9, 0x34, 0x4f, 0x13, 0x1c, 0x00, 0x00,
 }
 
 func (m *AzureSpec) Marshal() (dAtA []byte, err error) {
@@ -351,6 +806,21 @@ func (m *AzureSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	i -= len(m.ClientID)
+	copy(dAtA[i:], m.ClientID)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.ClientID)))
+	i--
+	dAtA[i] = 0x32
+	i -= len(m.StorageAccount)
+	copy(dAtA[i:], m.StorageAccount)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.StorageAccount)))
+	i--
+	dAtA[i] = 0x2a
+	i -= len(m.Endpoint)
+	copy(dAtA[i:], m.Endpoint)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Endpoint)))
+	i--
+	dAtA[i] = 0x22
 	i = encodeVarintGenerated(dAtA, i, uint64(m.MaxConnections))
 	i--
 	dAtA[i] = 0x18
@@ -423,6 +893,66 @@ func (m *Backend) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.RateLimit != nil {
+		{
+			size, err := m.RateLimit.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x6a
+	}
+	if m.RetryPolicy != nil {
+		{
+			size, err := m.RetryPolicy.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x62
+	}
+	if m.Proxy != nil {
+		{
+			size, err := m.Proxy.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x5a
+	}
+	if m.TLS != nil {
+		{
+			size, err := m.TLS.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.StorageSecret != nil {
+		{
+			size, err := m.StorageSecret.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
 	if m.Rest != nil {
 		{
 			size, err := m.Rest.MarshalToSizedBuffer(dAtA[:i])
@@ -515,7 +1045,7 @@ func (m *Backend) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *GCSSpec) Marshal() (dAtA []byte, err error) {
+func (m *CredentialKeySource) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -525,7 +1055,64 @@ func (m *GCSSpec) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *GCSSpec) MarshalTo(dAtA []byte) (int, error) {
+func (m *CredentialKeySource) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CredentialKeySource) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i -= len(m.File)
+	copy(dAtA[i:], m.File)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.File)))
+	i--
+	dAtA[i] = 0x22
+	if m.ConfigMapKeyRef != nil {
+		{
+			size, err := m.ConfigMapKeyRef.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.SecretKeyRef != nil {
+		{
+			size, err := m.SecretKeyRef.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	i -= len(m.Key)
+	copy(dAtA[i:], m.Key)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Key)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *GCSSpec) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GCSSpec) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
@@ -535,6 +1122,20 @@ func (m *GCSSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Scopes) > 0 {
+		for iNdEx := len(m.Scopes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Scopes[iNdEx])
+			copy(dAtA[i:], m.Scopes[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.Scopes[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	i -= len(m.ImpersonateServiceAccount)
+	copy(dAtA[i:], m.ImpersonateServiceAccount)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.ImpersonateServiceAccount)))
+	i--
+	dAtA[i] = 0x22
 	i = encodeVarintGenerated(dAtA, i, uint64(m.MaxConnections))
 	i--
 	dAtA[i] = 0x18
@@ -594,7 +1195,7 @@ func (m *LocalSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *RestServerSpec) Marshal() (dAtA []byte, err error) {
+func (m *ObjectStore) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -604,25 +1205,50 @@ func (m *RestServerSpec) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *RestServerSpec) MarshalTo(dAtA []byte) (int, error) {
+func (m *ObjectStore) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *RestServerSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ObjectStore) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	i -= len(m.URL)
-	copy(dAtA[i:], m.URL)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.URL)))
+	{
+		size, err := m.Status.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	{
+		size, err := m.Spec.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	{
+		size, err := m.ObjectMeta.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
 	i--
 	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func (m *S3Spec) Marshal() (dAtA []byte, err error) {
+func (m *ObjectStoreList) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -632,48 +1258,44 @@ func (m *S3Spec) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *S3Spec) MarshalTo(dAtA []byte) (int, error) {
+func (m *ObjectStoreList) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *S3Spec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ObjectStoreList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	i--
-	if m.InsecureTLS {
-		dAtA[i] = 1
-	} else {
-		dAtA[i] = 0
+	if len(m.Items) > 0 {
+		for iNdEx := len(m.Items) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Items[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	{
+		size, err := m.ListMeta.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
 	}
-	i--
-	dAtA[i] = 0x28
-	i -= len(m.Region)
-	copy(dAtA[i:], m.Region)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Region)))
-	i--
-	dAtA[i] = 0x22
-	i -= len(m.Prefix)
-	copy(dAtA[i:], m.Prefix)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Prefix)))
-	i--
-	dAtA[i] = 0x1a
-	i -= len(m.Bucket)
-	copy(dAtA[i:], m.Bucket)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Bucket)))
-	i--
-	dAtA[i] = 0x12
-	i -= len(m.Endpoint)
-	copy(dAtA[i:], m.Endpoint)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Endpoint)))
 	i--
 	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func (m *SwiftSpec) Marshal() (dAtA []byte, err error) {
+func (m *ObjectStoreSpec) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -683,293 +1305,4178 @@ func (m *SwiftSpec) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SwiftSpec) MarshalTo(dAtA []byte) (int, error) {
+func (m *ObjectStoreSpec) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SwiftSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ObjectStoreSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	i -= len(m.Prefix)
-	copy(dAtA[i:], m.Prefix)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Prefix)))
-	i--
-	dAtA[i] = 0x12
-	i -= len(m.Container)
-	copy(dAtA[i:], m.Container)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Container)))
+	if m.CheckInterval != nil {
+		{
+			size, err := m.CheckInterval.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	{
+		size, err := m.Backend.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
 	i--
 	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintGenerated(dAtA []byte, offset int, v uint64) int {
-	offset -= sovGenerated(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *ObjectStoreStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *AzureSpec) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *ObjectStoreStatus) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ObjectStoreStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Container)
-	n += 1 + l + sovGenerated(uint64(l))
-	l = len(m.Prefix)
-	n += 1 + l + sovGenerated(uint64(l))
-	n += 1 + sovGenerated(uint64(m.MaxConnections))
-	return n
+	if len(m.Conditions) > 0 {
+		for iNdEx := len(m.Conditions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Conditions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.Capabilities) > 0 {
+		for iNdEx := len(m.Capabilities) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Capabilities[iNdEx])
+			copy(dAtA[i:], m.Capabilities[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.Capabilities[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	i -= len(m.Region)
+	copy(dAtA[i:], m.Region)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Region)))
+	i--
+	dAtA[i] = 0x1a
+	if m.LastCheckTime != nil {
+		{
+			size, err := m.LastCheckTime.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	i = encodeVarintGenerated(dAtA, i, uint64(m.ObservedGeneration))
+	i--
+	dAtA[i] = 0x8
+	return len(dAtA) - i, nil
 }
 
-func (m *B2Spec) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ProxyConfig) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *ProxyConfig) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ProxyConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Bucket)
-	n += 1 + l + sovGenerated(uint64(l))
-	l = len(m.Prefix)
-	n += 1 + l + sovGenerated(uint64(l))
-	n += 1 + sovGenerated(uint64(m.MaxConnections))
-	return n
+	if len(m.NoProxy) > 0 {
+		for iNdEx := len(m.NoProxy) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.NoProxy[iNdEx])
+			copy(dAtA[i:], m.NoProxy[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.NoProxy[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	i -= len(m.URL)
+	copy(dAtA[i:], m.URL)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.URL)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
 
-func (m *Backend) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *RateLimit) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *RateLimit) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RateLimit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.StorageSecretName)
-	n += 1 + l + sovGenerated(uint64(l))
-	if m.Local != nil {
-		l = m.Local.Size()
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	if m.S3 != nil {
-		l = m.S3.Size()
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	if m.GCS != nil {
-		l = m.GCS.Size()
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	if m.Azure != nil {
-		l = m.Azure.Size()
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	if m.Swift != nil {
-		l = m.Swift.Size()
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	if m.B2 != nil {
-		l = m.B2.Size()
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	if m.Rest != nil {
-		l = m.Rest.Size()
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	return n
+	i = encodeVarintGenerated(dAtA, i, uint64(m.RequestsPerSecond))
+	i--
+	dAtA[i] = 0x18
+	i = encodeVarintGenerated(dAtA, i, uint64(m.DownloadBytesPerSecond))
+	i--
+	dAtA[i] = 0x10
+	i = encodeVarintGenerated(dAtA, i, uint64(m.UploadBytesPerSecond))
+	i--
+	dAtA[i] = 0x8
+	return len(dAtA) - i, nil
 }
 
-func (m *GCSSpec) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ReplicatedBackend) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	l = len(m.Bucket)
-	n += 1 + l + sovGenerated(uint64(l))
-	l = len(m.Prefix)
-	n += 1 + l + sovGenerated(uint64(l))
-	n += 1 + sovGenerated(uint64(m.MaxConnections))
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *LocalSpec) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = m.VolumeSource.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	l = len(m.MountPath)
-	n += 1 + l + sovGenerated(uint64(l))
-	l = len(m.SubPath)
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
+func (m *ReplicatedBackend) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *RestServerSpec) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *ReplicatedBackend) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.URL)
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
+	i = encodeVarintGenerated(dAtA, i, uint64(m.WriteQuorum))
+	i--
+	dAtA[i] = 0x18
+	if len(m.Mirrors) > 0 {
+		for iNdEx := len(m.Mirrors) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Mirrors[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	{
+		size, err := m.Primary.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
 
-func (m *S3Spec) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *RestServerSpec) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	l = len(m.Endpoint)
-	n += 1 + l + sovGenerated(uint64(l))
-	l = len(m.Bucket)
-	n += 1 + l + sovGenerated(uint64(l))
-	l = len(m.Prefix)
-	n += 1 + l + sovGenerated(uint64(l))
-	l = len(m.Region)
-	n += 1 + l + sovGenerated(uint64(l))
-	n += 2
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *SwiftSpec) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *RestServerSpec) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RestServerSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Container)
-	n += 1 + l + sovGenerated(uint64(l))
-	l = len(m.Prefix)
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
+	i -= len(m.URL)
+	copy(dAtA[i:], m.URL)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.URL)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
 
-func sovGenerated(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
+func (m *RetryPolicy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
 }
-func sozGenerated(x uint64) (n int) {
-	return sovGenerated(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+
+func (m *RetryPolicy) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
-func (this *AzureSpec) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *RetryPolicy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.RetryOn) > 0 {
+		for iNdEx := len(m.RetryOn) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.RetryOn[iNdEx])
+			copy(dAtA[i:], m.RetryOn[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.RetryOn[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
 	}
-	s := strings.Join([]string{`&AzureSpec{`,
-		`Container:` + fmt.Sprintf("%v", this.Container) + `,`,
-		`Prefix:` + fmt.Sprintf("%v", this.Prefix) + `,`,
-		`MaxConnections:` + fmt.Sprintf("%v", this.MaxConnections) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *B2Spec) String() string {
-	if this == nil {
-		return "nil"
+	if m.Timeout != nil {
+		{
+			size, err := m.Timeout.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
 	}
-	s := strings.Join([]string{`&B2Spec{`,
-		`Bucket:` + fmt.Sprintf("%v", this.Bucket) + `,`,
-		`Prefix:` + fmt.Sprintf("%v", this.Prefix) + `,`,
-		`MaxConnections:` + fmt.Sprintf("%v", this.MaxConnections) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Backend) String() string {
-	if this == nil {
-		return "nil"
+	if m.AttemptTimeout != nil {
+		{
+			size, err := m.AttemptTimeout.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
 	}
-	s := strings.Join([]string{`&Backend{`,
-		`StorageSecretName:` + fmt.Sprintf("%v", this.StorageSecretName) + `,`,
-		`Local:` + strings.Replace(this.Local.String(), "LocalSpec", "LocalSpec", 1) + `,`,
-		`S3:` + strings.Replace(this.S3.String(), "S3Spec", "S3Spec", 1) + `,`,
-		`GCS:` + strings.Replace(this.GCS.String(), "GCSSpec", "GCSSpec", 1) + `,`,
-		`Azure:` + strings.Replace(this.Azure.String(), "AzureSpec", "AzureSpec", 1) + `,`,
-		`Swift:` + strings.Replace(this.Swift.String(), "SwiftSpec", "SwiftSpec", 1) + `,`,
-		`B2:` + strings.Replace(this.B2.String(), "B2Spec", "B2Spec", 1) + `,`,
-		`Rest:` + strings.Replace(this.Rest.String(), "RestServerSpec", "RestServerSpec", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *GCSSpec) String() string {
-	if this == nil {
-		return "nil"
+	if m.MaxBackoff != nil {
+		{
+			size, err := m.MaxBackoff.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
 	}
-	s := strings.Join([]string{`&GCSSpec{`,
-		`Bucket:` + fmt.Sprintf("%v", this.Bucket) + `,`,
-		`Prefix:` + fmt.Sprintf("%v", this.Prefix) + `,`,
-		`MaxConnections:` + fmt.Sprintf("%v", this.MaxConnections) + `,`,
-		`}`,
-	}, "")
-	return s
+	if m.BaseBackoff != nil {
+		{
+			size, err := m.BaseBackoff.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	i = encodeVarintGenerated(dAtA, i, uint64(m.MaxAttempts))
+	i--
+	dAtA[i] = 0x8
+	return len(dAtA) - i, nil
 }
-func (this *LocalSpec) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *S3Encryption) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	s := strings.Join([]string{`&LocalSpec{`,
-		`VolumeSource:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.VolumeSource), "VolumeSource", "v1.VolumeSource", 1), `&`, ``, 1) + `,`,
-		`MountPath:` + fmt.Sprintf("%v", this.MountPath) + `,`,
-		`SubPath:` + fmt.Sprintf("%v", this.SubPath) + `,`,
-		`}`,
-	}, "")
-	return s
+	return dAtA[:n], nil
+}
+
+func (m *S3Encryption) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *S3Encryption) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.KMSEncryptionContext) > 0 {
+		keysForKMSEncryptionContext := make([]string, 0, len(m.KMSEncryptionContext))
+		for k := range m.KMSEncryptionContext {
+			keysForKMSEncryptionContext = append(keysForKMSEncryptionContext, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForKMSEncryptionContext)
+		for iNdEx := len(keysForKMSEncryptionContext) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.KMSEncryptionContext[string(keysForKMSEncryptionContext[iNdEx])]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintGenerated(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForKMSEncryptionContext[iNdEx])
+			copy(dAtA[i:], keysForKMSEncryptionContext[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(keysForKMSEncryptionContext[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintGenerated(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	i -= len(m.KMSKeyID)
+	copy(dAtA[i:], m.KMSKeyID)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.KMSKeyID)))
+	i--
+	dAtA[i] = 0x12
+	i -= len(m.Type)
+	copy(dAtA[i:], m.Type)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Type)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *S3Spec) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *S3Spec) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *S3Spec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i = encodeVarintGenerated(dAtA, i, uint64(m.MaxConnections))
+	i--
+	dAtA[i] = 0x1
+	i--
+	dAtA[i] = 0xa8
+	i -= len(m.Profile)
+	copy(dAtA[i:], m.Profile)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Profile)))
+	i--
+	dAtA[i] = 0x1
+	i--
+	dAtA[i] = 0xa2
+	i -= len(m.STSEndpoint)
+	copy(dAtA[i:], m.STSEndpoint)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.STSEndpoint)))
+	i--
+	dAtA[i] = 0x1
+	i--
+	dAtA[i] = 0x9a
+	i -= len(m.WebIdentityTokenFile)
+	copy(dAtA[i:], m.WebIdentityTokenFile)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.WebIdentityTokenFile)))
+	i--
+	dAtA[i] = 0x1
+	i--
+	dAtA[i] = 0x92
+	i -= len(m.RoleSessionName)
+	copy(dAtA[i:], m.RoleSessionName)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.RoleSessionName)))
+	i--
+	dAtA[i] = 0x1
+	i--
+	dAtA[i] = 0x8a
+	i -= len(m.ExternalID)
+	copy(dAtA[i:], m.ExternalID)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.ExternalID)))
+	i--
+	dAtA[i] = 0x1
+	i--
+	dAtA[i] = 0x82
+	i -= len(m.RoleARN)
+	copy(dAtA[i:], m.RoleARN)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.RoleARN)))
+	i--
+	dAtA[i] = 0x7a
+	i -= len(m.SignatureVersion)
+	copy(dAtA[i:], m.SignatureVersion)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.SignatureVersion)))
+	i--
+	dAtA[i] = 0x72
+	i--
+	if m.Accelerate {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x68
+	i--
+	if m.FIPS {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x60
+	i--
+	if m.DualStack {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x58
+	i -= len(m.AddressingStyle)
+	copy(dAtA[i:], m.AddressingStyle)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.AddressingStyle)))
+	i--
+	dAtA[i] = 0x52
+	if len(m.Tags) > 0 {
+		keysForTags := make([]string, 0, len(m.Tags))
+		for k := range m.Tags {
+			keysForTags = append(keysForTags, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForTags)
+		for iNdEx := len(keysForTags) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Tags[string(keysForTags[iNdEx])]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintGenerated(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForTags[iNdEx])
+			copy(dAtA[i:], keysForTags[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(keysForTags[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintGenerated(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	i -= len(m.ACL)
+	copy(dAtA[i:], m.ACL)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.ACL)))
+	i--
+	dAtA[i] = 0x42
+	i -= len(m.StorageClass)
+	copy(dAtA[i:], m.StorageClass)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.StorageClass)))
+	i--
+	dAtA[i] = 0x3a
+	if m.Encryption != nil {
+		{
+			size, err := m.Encryption.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	i--
+	if m.InsecureTLS {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x28
+	i -= len(m.Region)
+	copy(dAtA[i:], m.Region)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Region)))
+	i--
+	dAtA[i] = 0x22
+	i -= len(m.Prefix)
+	copy(dAtA[i:], m.Prefix)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Prefix)))
+	i--
+	dAtA[i] = 0x1a
+	i -= len(m.Bucket)
+	copy(dAtA[i:], m.Bucket)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Bucket)))
+	i--
+	dAtA[i] = 0x12
+	i -= len(m.Endpoint)
+	copy(dAtA[i:], m.Endpoint)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Endpoint)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *StorageSecretReference) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StorageSecretReference) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StorageSecretReference) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Sources) > 0 {
+		for iNdEx := len(m.Sources) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Sources[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	i -= len(m.Namespace)
+	copy(dAtA[i:], m.Namespace)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Namespace)))
+	i--
+	dAtA[i] = 0x1a
+	if len(m.Keys) > 0 {
+		keysForKeys := make([]string, 0, len(m.Keys))
+		for k := range m.Keys {
+			keysForKeys = append(keysForKeys, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForKeys)
+		for iNdEx := len(keysForKeys) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Keys[string(keysForKeys[iNdEx])]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintGenerated(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForKeys[iNdEx])
+			copy(dAtA[i:], keysForKeys[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(keysForKeys[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintGenerated(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	i -= len(m.Name)
+	copy(dAtA[i:], m.Name)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *SwiftSpec) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SwiftSpec) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SwiftSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i -= len(m.Prefix)
+	copy(dAtA[i:], m.Prefix)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Prefix)))
+	i--
+	dAtA[i] = 0x12
+	i -= len(m.Container)
+	copy(dAtA[i:], m.Container)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Container)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *TLSConfig) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TLSConfig) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TLSConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i -= len(m.MinVersion)
+	copy(dAtA[i:], m.MinVersion)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.MinVersion)))
+	i--
+	dAtA[i] = 0x12
+	i -= len(m.ServerName)
+	copy(dAtA[i:], m.ServerName)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.ServerName)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintGenerated(dAtA []byte, offset int, v uint64) int {
+	offset -= sovGenerated(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *AzureSpec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Container)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Prefix)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.MaxConnections))
+	l = len(m.Endpoint)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.StorageAccount)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.ClientID)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *B2Spec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Bucket)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Prefix)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.MaxConnections))
+	return n
+}
+
+func (m *Backend) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.StorageSecretName)
+	n += 1 + l + sovGenerated(uint64(l))
+	if m.Local != nil {
+		l = m.Local.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.S3 != nil {
+		l = m.S3.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.GCS != nil {
+		l = m.GCS.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.Azure != nil {
+		l = m.Azure.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.Swift != nil {
+		l = m.Swift.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.B2 != nil {
+		l = m.B2.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.Rest != nil {
+		l = m.Rest.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.StorageSecret != nil {
+		l = m.StorageSecret.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.TLS != nil {
+		l = m.TLS.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.Proxy != nil {
+		l = m.Proxy.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.RetryPolicy != nil {
+		l = m.RetryPolicy.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.RateLimit != nil {
+		l = m.RateLimit.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	return n
+}
+
+func (m *CredentialKeySource) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	n += 1 + l + sovGenerated(uint64(l))
+	if m.SecretKeyRef != nil {
+		l = m.SecretKeyRef.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.ConfigMapKeyRef != nil {
+		l = m.ConfigMapKeyRef.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	l = len(m.File)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *GCSSpec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Bucket)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Prefix)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.MaxConnections))
+	l = len(m.ImpersonateServiceAccount)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Scopes) > 0 {
+		for _, s := range m.Scopes {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *LocalSpec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.VolumeSource.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.MountPath)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.SubPath)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *ObjectStore) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ObjectMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.Spec.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.Status.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *ObjectStoreList) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ListMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Items) > 0 {
+		for _, e := range m.Items {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ObjectStoreSpec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Backend.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if m.CheckInterval != nil {
+		l = m.CheckInterval.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	return n
+}
+
+func (m *ObjectStoreStatus) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.ObservedGeneration))
+	if m.LastCheckTime != nil {
+		l = m.LastCheckTime.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	l = len(m.Region)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Capabilities) > 0 {
+		for _, s := range m.Capabilities {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.Conditions) > 0 {
+		for _, e := range m.Conditions {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ProxyConfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.URL)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.NoProxy) > 0 {
+		for _, s := range m.NoProxy {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *RateLimit) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.UploadBytesPerSecond))
+	n += 1 + sovGenerated(uint64(m.DownloadBytesPerSecond))
+	n += 1 + sovGenerated(uint64(m.RequestsPerSecond))
+	return n
+}
+
+func (m *ReplicatedBackend) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Primary.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Mirrors) > 0 {
+		for _, e := range m.Mirrors {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	n += 1 + sovGenerated(uint64(m.WriteQuorum))
+	return n
+}
+
+func (m *RestServerSpec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.URL)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *RetryPolicy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.MaxAttempts))
+	if m.BaseBackoff != nil {
+		l = m.BaseBackoff.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.MaxBackoff != nil {
+		l = m.MaxBackoff.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.AttemptTimeout != nil {
+		l = m.AttemptTimeout.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.Timeout != nil {
+		l = m.Timeout.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if len(m.RetryOn) > 0 {
+		for _, s := range m.RetryOn {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *S3Encryption) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Type)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.KMSKeyID)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.KMSEncryptionContext) > 0 {
+		for k, v := range m.KMSEncryptionContext {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + len(v) + sovGenerated(uint64(len(v)))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *S3Spec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Endpoint)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Bucket)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Prefix)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Region)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 2
+	if m.Encryption != nil {
+		l = m.Encryption.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	l = len(m.StorageClass)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.ACL)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Tags) > 0 {
+		for k, v := range m.Tags {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + len(v) + sovGenerated(uint64(len(v)))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
+	l = len(m.AddressingStyle)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 2
+	n += 2
+	n += 2
+	l = len(m.SignatureVersion)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.RoleARN)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.ExternalID)
+	n += 2 + l + sovGenerated(uint64(l))
+	l = len(m.RoleSessionName)
+	n += 2 + l + sovGenerated(uint64(l))
+	l = len(m.WebIdentityTokenFile)
+	n += 2 + l + sovGenerated(uint64(l))
+	l = len(m.STSEndpoint)
+	n += 2 + l + sovGenerated(uint64(l))
+	l = len(m.Profile)
+	n += 2 + l + sovGenerated(uint64(l))
+	n += 2 + sovGenerated(uint64(m.MaxConnections))
+	return n
+}
+
+func (m *StorageSecretReference) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Keys) > 0 {
+		for k, v := range m.Keys {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + len(v) + sovGenerated(uint64(len(v)))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
+	l = len(m.Namespace)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Sources) > 0 {
+		for _, e := range m.Sources {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SwiftSpec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Container)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Prefix)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *TLSConfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ServerName)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.MinVersion)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func sovGenerated(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozGenerated(x uint64) (n int) {
+	return sovGenerated(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *AzureSpec) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AzureSpec{`,
+		`Container:` + fmt.Sprintf("%v", this.Container) + `,`,
+		`Prefix:` + fmt.Sprintf("%v", this.Prefix) + `,`,
+		`MaxConnections:` + fmt.Sprintf("%v", this.MaxConnections) + `,`,
+		`Endpoint:` + fmt.Sprintf("%v", this.Endpoint) + `,`,
+		`StorageAccount:` + fmt.Sprintf("%v", this.StorageAccount) + `,`,
+		`ClientID:` + fmt.Sprintf("%v", this.ClientID) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *B2Spec) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&B2Spec{`,
+		`Bucket:` + fmt.Sprintf("%v", this.Bucket) + `,`,
+		`Prefix:` + fmt.Sprintf("%v", this.Prefix) + `,`,
+		`MaxConnections:` + fmt.Sprintf("%v", this.MaxConnections) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Backend) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Backend{`,
+		`StorageSecretName:` + fmt.Sprintf("%v", this.StorageSecretName) + `,`,
+		`Local:` + strings.Replace(this.Local.String(), "LocalSpec", "LocalSpec", 1) + `,`,
+		`S3:` + strings.Replace(this.S3.String(), "S3Spec", "S3Spec", 1) + `,`,
+		`GCS:` + strings.Replace(this.GCS.String(), "GCSSpec", "GCSSpec", 1) + `,`,
+		`Azure:` + strings.Replace(this.Azure.String(), "AzureSpec", "AzureSpec", 1) + `,`,
+		`Swift:` + strings.Replace(this.Swift.String(), "SwiftSpec", "SwiftSpec", 1) + `,`,
+		`B2:` + strings.Replace(this.B2.String(), "B2Spec", "B2Spec", 1) + `,`,
+		`Rest:` + strings.Replace(this.Rest.String(), "RestServerSpec", "RestServerSpec", 1) + `,`,
+		`StorageSecret:` + strings.Replace(this.StorageSecret.String(), "StorageSecretReference", "StorageSecretReference", 1) + `,`,
+		`TLS:` + strings.Replace(this.TLS.String(), "TLSConfig", "TLSConfig", 1) + `,`,
+		`Proxy:` + strings.Replace(this.Proxy.String(), "ProxyConfig", "ProxyConfig", 1) + `,`,
+		`RetryPolicy:` + strings.Replace(this.RetryPolicy.String(), "RetryPolicy", "RetryPolicy", 1) + `,`,
+		`RateLimit:` + strings.Replace(this.RateLimit.String(), "RateLimit", "RateLimit", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CredentialKeySource) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CredentialKeySource{`,
+		`Key:` + fmt.Sprintf("%v", this.Key) + `,`,
+		`SecretKeyRef:` + strings.Replace(fmt.Sprintf("%v", this.SecretKeyRef), "SecretKeySelector", "v1.SecretKeySelector", 1) + `,`,
+		`ConfigMapKeyRef:` + strings.Replace(fmt.Sprintf("%v", this.ConfigMapKeyRef), "ConfigMapKeySelector", "v1.ConfigMapKeySelector", 1) + `,`,
+		`File:` + fmt.Sprintf("%v", this.File) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GCSSpec) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GCSSpec{`,
+		`Bucket:` + fmt.Sprintf("%v", this.Bucket) + `,`,
+		`Prefix:` + fmt.Sprintf("%v", this.Prefix) + `,`,
+		`MaxConnections:` + fmt.Sprintf("%v", this.MaxConnections) + `,`,
+		`ImpersonateServiceAccount:` + fmt.Sprintf("%v", this.ImpersonateServiceAccount) + `,`,
+		`Scopes:` + fmt.Sprintf("%v", this.Scopes) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LocalSpec) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LocalSpec{`,
+		`VolumeSource:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.VolumeSource), "VolumeSource", "v1.VolumeSource", 1), `&`, ``, 1) + `,`,
+		`MountPath:` + fmt.Sprintf("%v", this.MountPath) + `,`,
+		`SubPath:` + fmt.Sprintf("%v", this.SubPath) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ObjectStore) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ObjectStore{`,
+		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v11.ObjectMeta", 1), `&`, ``, 1) + `,`,
+		`Spec:` + strings.Replace(strings.Replace(this.Spec.String(), "ObjectStoreSpec", "ObjectStoreSpec", 1), `&`, ``, 1) + `,`,
+		`Status:` + strings.Replace(strings.Replace(this.Status.String(), "ObjectStoreStatus", "ObjectStoreStatus", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ObjectStoreList) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForItems := "[]ObjectStore{"
+	for _, f := range this.Items {
+		repeatedStringForItems += strings.Replace(strings.Replace(f.String(), "ObjectStore", "ObjectStore", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForItems += "}"
+	s := strings.Join([]string{`&ObjectStoreList{`,
+		`ListMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ListMeta), "ListMeta", "v11.ListMeta", 1), `&`, ``, 1) + `,`,
+		`Items:` + repeatedStringForItems + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ObjectStoreSpec) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ObjectStoreSpec{`,
+		`Backend:` + strings.Replace(strings.Replace(this.Backend.String(), "Backend", "Backend", 1), `&`, ``, 1) + `,`,
+		`CheckInterval:` + strings.Replace(fmt.Sprintf("%v", this.CheckInterval), "Duration", "v11.Duration", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ObjectStoreStatus) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForConditions := "[]Condition{"
+	for _, f := range this.Conditions {
+		repeatedStringForConditions += fmt.Sprintf("%v", f) + ","
+	}
+	repeatedStringForConditions += "}"
+	s := strings.Join([]string{`&ObjectStoreStatus{`,
+		`ObservedGeneration:` + fmt.Sprintf("%v", this.ObservedGeneration) + `,`,
+		`LastCheckTime:` + strings.Replace(fmt.Sprintf("%v", this.LastCheckTime), "Time", "v11.Time", 1) + `,`,
+		`Region:` + fmt.Sprintf("%v", this.Region) + `,`,
+		`Capabilities:` + fmt.Sprintf("%v", this.Capabilities) + `,`,
+		`Conditions:` + repeatedStringForConditions + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ProxyConfig) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ProxyConfig{`,
+		`URL:` + fmt.Sprintf("%v", this.URL) + `,`,
+		`NoProxy:` + fmt.Sprintf("%v", this.NoProxy) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *RateLimit) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&RateLimit{`,
+		`UploadBytesPerSecond:` + fmt.Sprintf("%v", this.UploadBytesPerSecond) + `,`,
+		`DownloadBytesPerSecond:` + fmt.Sprintf("%v", this.DownloadBytesPerSecond) + `,`,
+		`RequestsPerSecond:` + fmt.Sprintf("%v", this.RequestsPerSecond) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ReplicatedBackend) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForMirrors := "[]Backend{"
+	for _, f := range this.Mirrors {
+		repeatedStringForMirrors += strings.Replace(strings.Replace(f.String(), "Backend", "Backend", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForMirrors += "}"
+	s := strings.Join([]string{`&ReplicatedBackend{`,
+		`Primary:` + strings.Replace(strings.Replace(this.Primary.String(), "Backend", "Backend", 1), `&`, ``, 1) + `,`,
+		`Mirrors:` + repeatedStringForMirrors + `,`,
+		`WriteQuorum:` + fmt.Sprintf("%v", this.WriteQuorum) + `,`,
+		`}`,
+	}, "")
+	return s
 }
 func (this *RestServerSpec) String() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&RestServerSpec{`,
-		`URL:` + fmt.Sprintf("%v", this.URL) + `,`,
-		`}`,
-	}, "")
-	return s
+	s := strings.Join([]string{`&RestServerSpec{`,
+		`URL:` + fmt.Sprintf("%v", this.URL) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *RetryPolicy) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&RetryPolicy{`,
+		`MaxAttempts:` + fmt.Sprintf("%v", this.MaxAttempts) + `,`,
+		`BaseBackoff:` + strings.Replace(fmt.Sprintf("%v", this.BaseBackoff), "Duration", "v11.Duration", 1) + `,`,
+		`MaxBackoff:` + strings.Replace(fmt.Sprintf("%v", this.MaxBackoff), "Duration", "v11.Duration", 1) + `,`,
+		`AttemptTimeout:` + strings.Replace(fmt.Sprintf("%v", this.AttemptTimeout), "Duration", "v11.Duration", 1) + `,`,
+		`Timeout:` + strings.Replace(fmt.Sprintf("%v", this.Timeout), "Duration", "v11.Duration", 1) + `,`,
+		`RetryOn:` + fmt.Sprintf("%v", this.RetryOn) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *S3Encryption) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForKMSEncryptionContext := make([]string, 0, len(this.KMSEncryptionContext))
+	for k, _ := range this.KMSEncryptionContext {
+		keysForKMSEncryptionContext = append(keysForKMSEncryptionContext, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForKMSEncryptionContext)
+	mapStringForKMSEncryptionContext := "map[string]string{"
+	for _, k := range keysForKMSEncryptionContext {
+		mapStringForKMSEncryptionContext += fmt.Sprintf("%v: %v,", k, this.KMSEncryptionContext[k])
+	}
+	mapStringForKMSEncryptionContext += "}"
+	s := strings.Join([]string{`&S3Encryption{`,
+		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
+		`KMSKeyID:` + fmt.Sprintf("%v", this.KMSKeyID) + `,`,
+		`KMSEncryptionContext:` + mapStringForKMSEncryptionContext + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *S3Spec) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForTags := make([]string, 0, len(this.Tags))
+	for k, _ := range this.Tags {
+		keysForTags = append(keysForTags, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForTags)
+	mapStringForTags := "map[string]string{"
+	for _, k := range keysForTags {
+		mapStringForTags += fmt.Sprintf("%v: %v,", k, this.Tags[k])
+	}
+	mapStringForTags += "}"
+	s := strings.Join([]string{`&S3Spec{`,
+		`Endpoint:` + fmt.Sprintf("%v", this.Endpoint) + `,`,
+		`Bucket:` + fmt.Sprintf("%v", this.Bucket) + `,`,
+		`Prefix:` + fmt.Sprintf("%v", this.Prefix) + `,`,
+		`Region:` + fmt.Sprintf("%v", this.Region) + `,`,
+		`InsecureTLS:` + fmt.Sprintf("%v", this.InsecureTLS) + `,`,
+		`Encryption:` + strings.Replace(this.Encryption.String(), "S3Encryption", "S3Encryption", 1) + `,`,
+		`StorageClass:` + fmt.Sprintf("%v", this.StorageClass) + `,`,
+		`ACL:` + fmt.Sprintf("%v", this.ACL) + `,`,
+		`Tags:` + mapStringForTags + `,`,
+		`AddressingStyle:` + fmt.Sprintf("%v", this.AddressingStyle) + `,`,
+		`DualStack:` + fmt.Sprintf("%v", this.DualStack) + `,`,
+		`FIPS:` + fmt.Sprintf("%v", this.FIPS) + `,`,
+		`Accelerate:` + fmt.Sprintf("%v", this.Accelerate) + `,`,
+		`SignatureVersion:` + fmt.Sprintf("%v", this.SignatureVersion) + `,`,
+		`RoleARN:` + fmt.Sprintf("%v", this.RoleARN) + `,`,
+		`ExternalID:` + fmt.Sprintf("%v", this.ExternalID) + `,`,
+		`RoleSessionName:` + fmt.Sprintf("%v", this.RoleSessionName) + `,`,
+		`WebIdentityTokenFile:` + fmt.Sprintf("%v", this.WebIdentityTokenFile) + `,`,
+		`STSEndpoint:` + fmt.Sprintf("%v", this.STSEndpoint) + `,`,
+		`Profile:` + fmt.Sprintf("%v", this.Profile) + `,`,
+		`MaxConnections:` + fmt.Sprintf("%v", this.MaxConnections) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StorageSecretReference) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForSources := "[]CredentialKeySource{"
+	for _, f := range this.Sources {
+		repeatedStringForSources += strings.Replace(strings.Replace(f.String(), "CredentialKeySource", "CredentialKeySource", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForSources += "}"
+	keysForKeys := make([]string, 0, len(this.Keys))
+	for k, _ := range this.Keys {
+		keysForKeys = append(keysForKeys, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForKeys)
+	mapStringForKeys := "map[string]string{"
+	for _, k := range keysForKeys {
+		mapStringForKeys += fmt.Sprintf("%v: %v,", k, this.Keys[k])
+	}
+	mapStringForKeys += "}"
+	s := strings.Join([]string{`&StorageSecretReference{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Keys:` + mapStringForKeys + `,`,
+		`Namespace:` + fmt.Sprintf("%v", this.Namespace) + `,`,
+		`Sources:` + repeatedStringForSources + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SwiftSpec) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SwiftSpec{`,
+		`Container:` + fmt.Sprintf("%v", this.Container) + `,`,
+		`Prefix:` + fmt.Sprintf("%v", this.Prefix) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TLSConfig) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TLSConfig{`,
+		`ServerName:` + fmt.Sprintf("%v", this.ServerName) + `,`,
+		`MinVersion:` + fmt.Sprintf("%v", this.MinVersion) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringGenerated(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *AzureSpec) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AzureSpec: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AzureSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Container", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Container = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Prefix = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxConnections", wireType)
+			}
+			m.MaxConnections = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxConnections |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Endpoint", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Endpoint = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StorageAccount", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StorageAccount = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *B2Spec) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: B2Spec: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: B2Spec: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bucket", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Bucket = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Prefix = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxConnections", wireType)
+			}
+			m.MaxConnections = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxConnections |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Backend) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Backend: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Backend: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StorageSecretName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StorageSecretName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Local", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Local == nil {
+				m.Local = &LocalSpec{}
+			}
+			if err := m.Local.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field S3", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.S3 == nil {
+				m.S3 = &S3Spec{}
+			}
+			if err := m.S3.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GCS", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.GCS == nil {
+				m.GCS = &GCSSpec{}
+			}
+			if err := m.GCS.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Azure", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Azure == nil {
+				m.Azure = &AzureSpec{}
+			}
+			if err := m.Azure.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Swift", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Swift == nil {
+				m.Swift = &SwiftSpec{}
+			}
+			if err := m.Swift.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field B2", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.B2 == nil {
+				m.B2 = &B2Spec{}
+			}
+			if err := m.B2.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rest", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Rest == nil {
+				m.Rest = &RestServerSpec{}
+			}
+			if err := m.Rest.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StorageSecret", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StorageSecret == nil {
+				m.StorageSecret = &StorageSecretReference{}
+			}
+			if err := m.StorageSecret.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TLS", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TLS == nil {
+				m.TLS = &TLSConfig{}
+			}
+			if err := m.TLS.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Proxy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Proxy == nil {
+				m.Proxy = &ProxyConfig{}
+			}
+			if err := m.Proxy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetryPolicy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RetryPolicy == nil {
+				m.RetryPolicy = &RetryPolicy{}
+			}
+			if err := m.RetryPolicy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RateLimit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RateLimit == nil {
+				m.RateLimit = &RateLimit{}
+			}
+			if err := m.RateLimit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CredentialKeySource) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CredentialKeySource: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CredentialKeySource: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SecretKeyRef", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SecretKeyRef == nil {
+				m.SecretKeyRef = &v1.SecretKeySelector{}
+			}
+			if err := m.SecretKeyRef.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConfigMapKeyRef", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ConfigMapKeyRef == nil {
+				m.ConfigMapKeyRef = &v1.ConfigMapKeySelector{}
+			}
+			if err := m.ConfigMapKeyRef.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.File = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GCSSpec) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GCSSpec: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GCSSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bucket", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Bucket = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Prefix = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxConnections", wireType)
+			}
+			m.MaxConnections = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxConnections |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ImpersonateServiceAccount", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ImpersonateServiceAccount = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scopes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Scopes = append(m.Scopes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LocalSpec) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LocalSpec: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LocalSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VolumeSource", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.VolumeSource.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MountPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MountPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SubPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SubPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ObjectStore) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ObjectStore: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ObjectStore: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectMeta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ObjectMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spec", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Spec.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Status.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ObjectStoreList) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ObjectStoreList: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ObjectStoreList: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ListMeta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ListMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Items", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Items = append(m.Items, ObjectStore{})
+			if err := m.Items[len(m.Items)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ObjectStoreSpec) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ObjectStoreSpec: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ObjectStoreSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Backend", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Backend.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CheckInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CheckInterval == nil {
+				m.CheckInterval = &v11.Duration{}
+			}
+			if err := m.CheckInterval.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ObjectStoreStatus) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ObjectStoreStatus: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ObjectStoreStatus: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ObservedGeneration", wireType)
+			}
+			m.ObservedGeneration = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ObservedGeneration |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastCheckTime", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LastCheckTime == nil {
+				m.LastCheckTime = &v11.Time{}
+			}
+			if err := m.LastCheckTime.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Region", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Region = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Capabilities = append(m.Capabilities, ObjectStoreCapability(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Conditions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Conditions = append(m.Conditions, v11.Condition{})
+			if err := m.Conditions[len(m.Conditions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ProxyConfig) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ProxyConfig: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ProxyConfig: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field URL", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.URL = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NoProxy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NoProxy = append(m.NoProxy, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RateLimit) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RateLimit: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RateLimit: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UploadBytesPerSecond", wireType)
+			}
+			m.UploadBytesPerSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UploadBytesPerSecond |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DownloadBytesPerSecond", wireType)
+			}
+			m.DownloadBytesPerSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DownloadBytesPerSecond |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestsPerSecond", wireType)
+			}
+			m.RequestsPerSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RequestsPerSecond |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReplicatedBackend) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReplicatedBackend: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReplicatedBackend: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Primary", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Primary.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mirrors", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Mirrors = append(m.Mirrors, Backend{})
+			if err := m.Mirrors[len(m.Mirrors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WriteQuorum", wireType)
+			}
+			m.WriteQuorum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WriteQuorum |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (this *S3Spec) String() string {
-	if this == nil {
-		return "nil"
+func (m *RestServerSpec) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RestServerSpec: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RestServerSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field URL", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.URL = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	s := strings.Join([]string{`&S3Spec{`,
-		`Endpoint:` + fmt.Sprintf("%v", this.Endpoint) + `,`,
-		`Bucket:` + fmt.Sprintf("%v", this.Bucket) + `,`,
-		`Prefix:` + fmt.Sprintf("%v", this.Prefix) + `,`,
-		`Region:` + fmt.Sprintf("%v", this.Region) + `,`,
-		`InsecureTLS:` + fmt.Sprintf("%v", this.InsecureTLS) + `,`,
-		`}`,
-	}, "")
-	return s
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (this *SwiftSpec) String() string {
-	if this == nil {
-		return "nil"
+func (m *RetryPolicy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RetryPolicy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RetryPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxAttempts", wireType)
+			}
+			m.MaxAttempts = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxAttempts |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BaseBackoff", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.BaseBackoff == nil {
+				m.BaseBackoff = &v11.Duration{}
+			}
+			if err := m.BaseBackoff.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxBackoff", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxBackoff == nil {
+				m.MaxBackoff = &v11.Duration{}
+			}
+			if err := m.MaxBackoff.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AttemptTimeout", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AttemptTimeout == nil {
+				m.AttemptTimeout = &v11.Duration{}
+			}
+			if err := m.AttemptTimeout.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Timeout == nil {
+				m.Timeout = &v11.Duration{}
+			}
+			if err := m.Timeout.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetryOn", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RetryOn = append(m.RetryOn, RetryErrorClass(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	s := strings.Join([]string{`&SwiftSpec{`,
-		`Container:` + fmt.Sprintf("%v", this.Container) + `,`,
-		`Prefix:` + fmt.Sprintf("%v", this.Prefix) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringGenerated(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *AzureSpec) Unmarshal(dAtA []byte) error {
+func (m *S3Encryption) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -992,15 +5499,15 @@ func (m *AzureSpec) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: AzureSpec: wiretype end group for non-group")
+			return fmt.Errorf("proto: S3Encryption: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: AzureSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: S3Encryption: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Container", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1028,11 +5535,11 @@ func (m *AzureSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Container = string(dAtA[iNdEx:postIndex])
+			m.Type = S3EncryptionType(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field KMSKeyID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1060,13 +5567,13 @@ func (m *AzureSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Prefix = string(dAtA[iNdEx:postIndex])
+			m.KMSKeyID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MaxConnections", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KMSEncryptionContext", wireType)
 			}
-			m.MaxConnections = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1076,11 +5583,119 @@ func (m *AzureSpec) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.MaxConnections |= int64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.KMSEncryptionContext == nil {
+				m.KMSEncryptionContext = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.KMSEncryptionContext[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -1102,7 +5717,7 @@ func (m *AzureSpec) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *B2Spec) Unmarshal(dAtA []byte) error {
+func (m *S3Spec) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1125,17 +5740,165 @@ func (m *B2Spec) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: B2Spec: wiretype end group for non-group")
+			return fmt.Errorf("proto: S3Spec: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: B2Spec: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: S3Spec: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Bucket", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Endpoint", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Endpoint = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bucket", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Bucket = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Prefix = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Region", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Region = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InsecureTLS", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.InsecureTLS = bool(v != 0)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Encryption", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1145,27 +5908,31 @@ func (m *B2Spec) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Bucket = string(dAtA[iNdEx:postIndex])
+			if m.Encryption == nil {
+				m.Encryption = &S3Encryption{}
+			}
+			if err := m.Encryption.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 2:
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StorageClass", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1193,80 +5960,11 @@ func (m *B2Spec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Prefix = string(dAtA[iNdEx:postIndex])
+			m.StorageClass = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MaxConnections", wireType)
-			}
-			m.MaxConnections = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowGenerated
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.MaxConnections |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipGenerated(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Backend) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowGenerated
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Backend: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Backend: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StorageSecretName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ACL", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1294,11 +5992,11 @@ func (m *Backend) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.StorageSecretName = string(dAtA[iNdEx:postIndex])
+			m.ACL = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Local", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1325,18 +6023,109 @@ func (m *Backend) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Local == nil {
-				m.Local = &LocalSpec{}
-			}
-			if err := m.Local.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			if m.Tags == nil {
+				m.Tags = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
 			}
+			m.Tags[mapkey] = mapvalue
 			iNdEx = postIndex
-		case 3:
+		case 10:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field S3", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AddressingStyle", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1346,33 +6135,29 @@ func (m *Backend) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.S3 == nil {
-				m.S3 = &S3Spec{}
-			}
-			if err := m.S3.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.AddressingStyle = S3AddressingStyle(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GCS", wireType)
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DualStack", wireType)
 			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1382,33 +6167,57 @@ func (m *Backend) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthGenerated
+			m.DualStack = bool(v != 0)
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FIPS", wireType)
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if m.GCS == nil {
-				m.GCS = &GCSSpec{}
+			m.FIPS = bool(v != 0)
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Accelerate", wireType)
 			}
-			if err := m.GCS.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 5:
+			m.Accelerate = bool(v != 0)
+		case 14:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Azure", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SignatureVersion", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1418,33 +6227,29 @@ func (m *Backend) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Azure == nil {
-				m.Azure = &AzureSpec{}
-			}
-			if err := m.Azure.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.SignatureVersion = S3SignatureVersion(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
+		case 15:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Swift", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RoleARN", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1454,33 +6259,29 @@ func (m *Backend) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Swift == nil {
-				m.Swift = &SwiftSpec{}
-			}
-			if err := m.Swift.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.RoleARN = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 7:
+		case 16:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field B2", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ExternalID", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1490,33 +6291,29 @@ func (m *Backend) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.B2 == nil {
-				m.B2 = &B2Spec{}
-			}
-			if err := m.B2.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.ExternalID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 8:
+		case 17:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Rest", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RoleSessionName", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1526,81 +6323,59 @@ func (m *Backend) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Rest == nil {
-				m.Rest = &RestServerSpec{}
-			}
-			if err := m.Rest.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.RoleSessionName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipGenerated(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WebIdentityTokenFile", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *GCSSpec) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowGenerated
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
 			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: GCSSpec: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GCSSpec: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.WebIdentityTokenFile = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 19:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Bucket", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field STSEndpoint", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1628,11 +6403,11 @@ func (m *GCSSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Bucket = string(dAtA[iNdEx:postIndex])
+			m.STSEndpoint = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 20:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Profile", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1660,9 +6435,9 @@ func (m *GCSSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Prefix = string(dAtA[iNdEx:postIndex])
+			m.Profile = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 21:
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field MaxConnections", wireType)
 			}
@@ -1702,7 +6477,7 @@ func (m *GCSSpec) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LocalSpec) Unmarshal(dAtA []byte) error {
+func (m *StorageSecretReference) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1725,17 +6500,17 @@ func (m *LocalSpec) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LocalSpec: wiretype end group for non-group")
+			return fmt.Errorf("proto: StorageSecretReference: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LocalSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StorageSecretReference: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field VolumeSource", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1745,30 +6520,29 @@ func (m *LocalSpec) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.VolumeSource.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MountPath", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Keys", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1778,27 +6552,122 @@ func (m *LocalSpec) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.MountPath = string(dAtA[iNdEx:postIndex])
+			if m.Keys == nil {
+				m.Keys = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Keys[mapkey] = mapvalue
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SubPath", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1826,63 +6695,13 @@ func (m *LocalSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.SubPath = string(dAtA[iNdEx:postIndex])
+			m.Namespace = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipGenerated(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *RestServerSpec) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowGenerated
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: RestServerSpec: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RestServerSpec: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field URL", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Sources", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1892,23 +6711,25 @@ func (m *RestServerSpec) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.URL = string(dAtA[iNdEx:postIndex])
+			m.Sources = append(m.Sources, CredentialKeySource{})
+			if err := m.Sources[len(m.Sources)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1931,7 +6752,7 @@ func (m *RestServerSpec) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *S3Spec) Unmarshal(dAtA []byte) error {
+func (m *SwiftSpec) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1954,15 +6775,15 @@ func (m *S3Spec) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: S3Spec: wiretype end group for non-group")
+			return fmt.Errorf("proto: SwiftSpec: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: S3Spec: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SwiftSpec: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Endpoint", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Container", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1990,41 +6811,9 @@ func (m *S3Spec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Endpoint = string(dAtA[iNdEx:postIndex])
+			m.Container = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Bucket", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowGenerated
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Bucket = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
 			}
@@ -2056,58 +6845,6 @@ func (m *S3Spec) Unmarshal(dAtA []byte) error {
 			}
 			m.Prefix = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Region", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowGenerated
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Region = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field InsecureTLS", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowGenerated
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.InsecureTLS = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -2129,7 +6866,7 @@ func (m *S3Spec) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SwiftSpec) Unmarshal(dAtA []byte) error {
+func (m *TLSConfig) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2152,15 +6889,15 @@ func (m *SwiftSpec) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SwiftSpec: wiretype end group for non-group")
+			return fmt.Errorf("proto: TLSConfig: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SwiftSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TLSConfig: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Container", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ServerName", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2188,11 +6925,11 @@ func (m *SwiftSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Container = string(dAtA[iNdEx:postIndex])
+			m.ServerName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MinVersion", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2220,7 +6957,7 @@ func (m *SwiftSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Prefix = string(dAtA[iNdEx:postIndex])
+			m.MinVersion = TLSVersion(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -17,7 +17,10 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
 	"net/url"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	core "k8s.io/api/core/v1"
@@ -71,10 +74,47 @@ func (backend Backend) Location() (string, error) {
 		return "swift:" + backend.Swift.Container, nil
 	} else if backend.B2 != nil {
 		return "b2:" + backend.B2.Bucket, nil
+	} else if backend.Rest != nil {
+		return "rest:" + backend.Rest.URL, nil
 	}
 	return "", errors.New("no storage provider is configured")
 }
 
+// SecretName returns the name of the storage secret, or "" if the backend has none
+func (backend Backend) SecretName() string {
+	if backend.StorageSecret != nil && backend.StorageSecret.Name != "" {
+		return backend.StorageSecret.Name
+	}
+	return backend.StorageSecretName
+}
+
+// SecretData returns the data of the storage secret with the credential keys
+// mapped by StorageSecret filled in. It fails when a mapped key is missing.
+func (backend Backend) SecretData(secret *core.Secret) (map[string][]byte, error) {
+	if backend.StorageSecret == nil || len(backend.StorageSecret.Keys) == 0 {
+		return secret.Data, nil
+	}
+	keys := backend.StorageSecret.Keys
+	data := make(map[string][]byte, len(secret.Data)+len(keys))
+	for k, v := range secret.Data {
+		data[k] = v
+	}
+	var missing []string
+	for cred, key := range keys {
+		v, ok := secret.Data[key]
+		if !ok {
+			missing = append(missing, fmt.Sprintf("%q for %s", key, cred))
+			continue
+		}
+		data[cred] = v
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, errors.Errorf("storage secret %s/%s is missing key %s", secret.Namespace, secret.Name, strings.Join(missing, ", key "))
+	}
+	return data, nil
+}
+
 // ToVolumeAndMount returns volumes and mounts for local backend
 func (l LocalSpec) ToVolumeAndMount(volName string) (core.Volume, core.VolumeMount) {
 	vol := core.Volume{
@@ -136,7 +176,9 @@ func (backend Backend) Provider() (string, error) {
 // MaxConnections returns maximum parallel connection to use to connect with the backend
 // returns 0 if not specified
 func (backend Backend) MaxConnections() int64 {
-	if backend.GCS != nil {
+	if backend.S3 != nil {
+		return backend.S3.MaxConnections
+	} else if backend.GCS != nil {
 		return backend.GCS.MaxConnections
 	} else if backend.Azure != nil {
 		return backend.Azure.MaxConnections
@@ -171,3 +213,29 @@ func (backend Backend) InsecureTLS() bool {
 	}
 	return false
 }
+
+// IsAWS returns true if the S3 backend is AWS S3 and not an S3 compatible service
+func (s S3Spec) IsAWS() bool {
+	if s.Endpoint == "" {
+		return true
+	}
+	host := s.Endpoint
+	if u, err := url.Parse(s.Endpoint); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	return strings.HasSuffix(host, ".amazonaws.com")
+}
+
+// UsePathStyle returns true if the bucket name goes into the url path instead of the host name
+func (s S3Spec) UsePathStyle() bool {
+	if s.SignatureVersion == S3SignatureV2 {
+		return true
+	}
+	switch s.AddressingStyle {
+	case S3AddressingStylePath:
+		return true
+	case S3AddressingStyleVirtual:
+		return false
+	}
+	return !s.IsAWS()
+}
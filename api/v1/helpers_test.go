@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type test struct {
@@ -99,10 +100,11 @@ var testCases = func() []test {
 			name: ProviderS3 + "_with_region",
 			backend: Backend{
 				S3: &S3Spec{
-					Bucket:   "stash-backup",
-					Prefix:   "/source/data",
-					Endpoint: "s3.amazonaws.com",
-					Region:   "my.custom.region",
+					Bucket:         "stash-backup",
+					Prefix:         "/source/data",
+					Endpoint:       "s3.amazonaws.com",
+					Region:         "my.custom.region",
+					MaxConnections: 4,
 				},
 				StorageSecretName: "s3-secret",
 			},
@@ -110,7 +112,7 @@ var testCases = func() []test {
 			expectedLocation:      fmt.Sprintf("%s:%s", ProviderS3, "stash-backup"),
 			expectedPrefix:        "/source/data",
 			expectedProvider:      ProviderS3,
-			expectedMaxConnection: 0,
+			expectedMaxConnection: 4,
 			expectedEndpoint:      "s3.amazonaws.com",
 			expectedRegion:        "my.custom.region",
 		},
@@ -176,7 +178,7 @@ var testCases = func() []test {
 				StorageSecretName: "rest-secret",
 			},
 			expectedContainer:     "rest-server.demo.svc:8000",
-			expectedLocation:      "",
+			expectedLocation:      fmt.Sprintf("%s:%s", ProviderRest, "http://rest-server.demo.svc:8000/stash-backup"),
 			expectedPrefix:        "/stash-backup",
 			expectedProvider:      ProviderRest,
 			expectedMaxConnection: 0,
@@ -205,7 +207,7 @@ func TestBackend_Location(t *testing.T) {
 	for _, tt := range testCases() {
 		t.Run(tt.name, func(t *testing.T) {
 			location, err := tt.backend.Location()
-			if err != nil && tt.backend.Rest == nil {
+			if err != nil {
 				t.Errorf("fail to get location, reason: %v", err)
 				return
 			}
@@ -288,3 +290,70 @@ func TestBackend_Region(t *testing.T) {
 		})
 	}
 }
+
+func TestS3Spec_UsePathStyle(t *testing.T) {
+	cases := []struct {
+		spec      S3Spec
+		isAWS     bool
+		pathStyle bool
+	}{
+		{spec: S3Spec{}, isAWS: true, pathStyle: false},
+		{spec: S3Spec{Endpoint: "https://s3.us-east-2.amazonaws.com/"}, isAWS: true, pathStyle: false},
+		{spec: S3Spec{Endpoint: "s3.amazonaws.com"}, isAWS: true, pathStyle: false},
+		{spec: S3Spec{AddressingStyle: S3AddressingStylePath}, isAWS: true, pathStyle: true},
+		{spec: S3Spec{Endpoint: "http://minio:9000"}, isAWS: false, pathStyle: true},
+		{spec: S3Spec{Endpoint: "https://minio.amazonaws.com.example.com"}, isAWS: false, pathStyle: true},
+		{spec: S3Spec{Endpoint: "http://minio:9000", AddressingStyle: S3AddressingStyleVirtual}, isAWS: false, pathStyle: false},
+		{spec: S3Spec{SignatureVersion: S3SignatureV2}, isAWS: true, pathStyle: true},
+	}
+	for _, tc := range cases {
+		if isAWS := tc.spec.IsAWS(); isAWS != tc.isAWS {
+			t.Errorf("%+v: expected IsAWS %v, found %v", tc.spec, tc.isAWS, isAWS)
+		}
+		if pathStyle := tc.spec.UsePathStyle(); pathStyle != tc.pathStyle {
+			t.Errorf("%+v: expected UsePathStyle %v, found %v", tc.spec, tc.pathStyle, pathStyle)
+		}
+	}
+}
+
+func TestBackend_SecretData(t *testing.T) {
+	secret := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bucket-creds", Namespace: "demo"},
+		Data: map[string][]byte{
+			"accessKey": []byte("id"),
+			"secretKey": []byte("key"),
+		},
+	}
+
+	backend := Backend{StorageSecretName: "bucket-creds", S3: &S3Spec{Bucket: "stash"}}
+	if name := backend.SecretName(); name != "bucket-creds" {
+		t.Errorf("expected secret name bucket-creds, found %q", name)
+	}
+	data, err := backend.SecretData(secret)
+	if err != nil || len(data) != 2 || data[AWS_ACCESS_KEY_ID] != nil {
+		t.Errorf("expected unmapped secret data, found %v, %v", data, err)
+	}
+
+	backend.StorageSecret = &StorageSecretReference{
+		Name: "bucket-creds",
+		Keys: map[string]string{AWS_ACCESS_KEY_ID: "accessKey", AWS_SECRET_ACCESS_KEY: "secretKey"},
+	}
+	data, err = backend.SecretData(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data[AWS_ACCESS_KEY_ID]) != "id" || string(data[AWS_SECRET_ACCESS_KEY]) != "key" {
+		t.Errorf("expected mapped credentials, found %v", data)
+	}
+	if _, ok := secret.Data[AWS_ACCESS_KEY_ID]; ok {
+		t.Errorf("secret data must not be modified")
+	}
+
+	backend.StorageSecret.Keys[CA_CERT_DATA] = "ca.crt"
+	backend.StorageSecret.Keys[AWS_SECRET_ACCESS_KEY] = "secret"
+	_, err = backend.SecretData(secret)
+	expected := `storage secret demo/bucket-creds is missing key "ca.crt" for CA_CERT_DATA, key "secret" for AWS_SECRET_ACCESS_KEY`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, found %v", expected, err)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ResourceKindObjectStore     = "ObjectStore"
+	ResourceSingularObjectStore = "objectstore"
+	ResourcePluralObjectStore   = "objectstores"
+)
+
+// ObjectStore is a backend whose bucket is checked periodically by the
+// objectstore controller, so that its users learn about unreachable buckets
+// and lost permissions before they need them.
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=objectstores,singular=objectstore,scope=Cluster,categories={storage,appscode}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Region",type="string",JSONPath=".status.region"
+// +kubebuilder:printcolumn:name="Last Check",type="date",JSONPath=".status.lastCheckTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type ObjectStore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec   ObjectStoreSpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	Status ObjectStoreStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+type ObjectStoreSpec struct {
+	// Backend is the bucket that is checked. As an ObjectStore has no namespace,
+	// the storage secret is read from the namespace of the controller, unless
+	// Backend.StorageSecret names another one.
+	Backend Backend `json:"backend" protobuf:"bytes,1,opt,name=backend"`
+
+	// CheckInterval is the time between two checks of the bucket. Defaults to 5m.
+	// +optional
+	CheckInterval *metav1.Duration `json:"checkInterval,omitempty" protobuf:"bytes,2,opt,name=checkInterval"`
+}
+
+type ObjectStoreStatus struct {
+	// ObservedGeneration is the generation of the spec that was checked last.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" protobuf:"varint,1,opt,name=observedGeneration"`
+
+	// LastCheckTime is the time of the last check of the bucket.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty" protobuf:"bytes,2,opt,name=lastCheckTime"`
+
+	// Region of the bucket, as configured or looked up for AWS buckets. It is empty
+	// for the providers without regions.
+	// +optional
+	Region string `json:"region,omitempty" protobuf:"bytes,3,opt,name=region"`
+
+	// Capabilities are the operations on the bucket that the credentials were
+	// allowed by the last check.
+	// +optional
+	Capabilities []ObjectStoreCapability `json:"capabilities,omitempty" protobuf:"bytes,4,rep,name=capabilities,casttype=ObjectStoreCapability"`
+
+	// Conditions of the object store. The Ready condition is true when the last
+	// check found the bucket and could write to it.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,5,rep,name=conditions"`
+}
+
+// ObjectStoreCapability is an operation on the bucket of an ObjectStore.
+type ObjectStoreCapability string
+
+const (
+	// CapabilityRead is reported when an object can be looked up.
+	CapabilityRead ObjectStoreCapability = "Read"
+	// CapabilityList is reported when the objects can be listed.
+	CapabilityList ObjectStoreCapability = "List"
+	// CapabilityWrite is reported when an object can be written and removed.
+	CapabilityWrite ObjectStoreCapability = "Write"
+)
+
+// Conditions and reasons of an ObjectStore
+const (
+	ObjectStoreConditionReady = "Ready"
+
+	// ObjectStoreReasonAvailable is the reason of a successful check.
+	ObjectStoreReasonAvailable = "BucketAvailable"
+	// ObjectStoreReasonInvalidSpec is the reason of a spec that is not checked,
+	// because it does not pass validation.
+	ObjectStoreReasonInvalidSpec = "InvalidSpec"
+	// ObjectStoreReasonCheckFailed is the reason of a check that could not
+	// reach the bucket or write to it.
+	ObjectStoreReasonCheckFailed = "CheckFailed"
+)
+
+// ObjectStoreList is a list of ObjectStores
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+type ObjectStoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []ObjectStore `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
@@ -30,14 +30,26 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"kmodules.xyz/objectstore-api/api/v1.AzureSpec":      schema_kmodulesxyz_objectstore_api_api_v1_AzureSpec(ref),
-		"kmodules.xyz/objectstore-api/api/v1.B2Spec":         schema_kmodulesxyz_objectstore_api_api_v1_B2Spec(ref),
-		"kmodules.xyz/objectstore-api/api/v1.Backend":        schema_kmodulesxyz_objectstore_api_api_v1_Backend(ref),
-		"kmodules.xyz/objectstore-api/api/v1.GCSSpec":        schema_kmodulesxyz_objectstore_api_api_v1_GCSSpec(ref),
-		"kmodules.xyz/objectstore-api/api/v1.LocalSpec":      schema_kmodulesxyz_objectstore_api_api_v1_LocalSpec(ref),
-		"kmodules.xyz/objectstore-api/api/v1.RestServerSpec": schema_kmodulesxyz_objectstore_api_api_v1_RestServerSpec(ref),
-		"kmodules.xyz/objectstore-api/api/v1.S3Spec":         schema_kmodulesxyz_objectstore_api_api_v1_S3Spec(ref),
-		"kmodules.xyz/objectstore-api/api/v1.SwiftSpec":      schema_kmodulesxyz_objectstore_api_api_v1_SwiftSpec(ref),
+		"kmodules.xyz/objectstore-api/api/v1.AzureSpec":              schema_kmodulesxyz_objectstore_api_api_v1_AzureSpec(ref),
+		"kmodules.xyz/objectstore-api/api/v1.B2Spec":                 schema_kmodulesxyz_objectstore_api_api_v1_B2Spec(ref),
+		"kmodules.xyz/objectstore-api/api/v1.Backend":                schema_kmodulesxyz_objectstore_api_api_v1_Backend(ref),
+		"kmodules.xyz/objectstore-api/api/v1.CredentialKeySource":    schema_kmodulesxyz_objectstore_api_api_v1_CredentialKeySource(ref),
+		"kmodules.xyz/objectstore-api/api/v1.GCSSpec":                schema_kmodulesxyz_objectstore_api_api_v1_GCSSpec(ref),
+		"kmodules.xyz/objectstore-api/api/v1.LocalSpec":              schema_kmodulesxyz_objectstore_api_api_v1_LocalSpec(ref),
+		"kmodules.xyz/objectstore-api/api/v1.ObjectStore":            schema_kmodulesxyz_objectstore_api_api_v1_ObjectStore(ref),
+		"kmodules.xyz/objectstore-api/api/v1.ObjectStoreList":        schema_kmodulesxyz_objectstore_api_api_v1_ObjectStoreList(ref),
+		"kmodules.xyz/objectstore-api/api/v1.ObjectStoreSpec":        schema_kmodulesxyz_objectstore_api_api_v1_ObjectStoreSpec(ref),
+		"kmodules.xyz/objectstore-api/api/v1.ObjectStoreStatus":      schema_kmodulesxyz_objectstore_api_api_v1_ObjectStoreStatus(ref),
+		"kmodules.xyz/objectstore-api/api/v1.ProxyConfig":            schema_kmodulesxyz_objectstore_api_api_v1_ProxyConfig(ref),
+		"kmodules.xyz/objectstore-api/api/v1.RateLimit":              schema_kmodulesxyz_objectstore_api_api_v1_RateLimit(ref),
+		"kmodules.xyz/objectstore-api/api/v1.ReplicatedBackend":      schema_kmodulesxyz_objectstore_api_api_v1_ReplicatedBackend(ref),
+		"kmodules.xyz/objectstore-api/api/v1.RestServerSpec":         schema_kmodulesxyz_objectstore_api_api_v1_RestServerSpec(ref),
+		"kmodules.xyz/objectstore-api/api/v1.RetryPolicy":            schema_kmodulesxyz_objectstore_api_api_v1_RetryPolicy(ref),
+		"kmodules.xyz/objectstore-api/api/v1.S3Encryption":           schema_kmodulesxyz_objectstore_api_api_v1_S3Encryption(ref),
+		"kmodules.xyz/objectstore-api/api/v1.S3Spec":                 schema_kmodulesxyz_objectstore_api_api_v1_S3Spec(ref),
+		"kmodules.xyz/objectstore-api/api/v1.StorageSecretReference": schema_kmodulesxyz_objectstore_api_api_v1_StorageSecretReference(ref),
+		"kmodules.xyz/objectstore-api/api/v1.SwiftSpec":              schema_kmodulesxyz_objectstore_api_api_v1_SwiftSpec(ref),
+		"kmodules.xyz/objectstore-api/api/v1.TLSConfig":              schema_kmodulesxyz_objectstore_api_api_v1_TLSConfig(ref),
 	}
 }
 
@@ -66,6 +78,27 @@ func schema_kmodulesxyz_objectstore_api_api_v1_AzureSpec(ref common.ReferenceCal
 							Format: "int64",
 						},
 					},
+					"endpoint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Endpoint is the url of the blob service of the storage account, eg. https://<account>.blob.core.chinacloudapi.cn for a sovereign cloud or http://127.0.0.1:10000/<account> for Azurite. Defaults to https://<account>.blob.core.windows.net",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"storageAccount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StorageAccount is the name of the storage account. It is used when the storage secret has no AZURE_ACCOUNT_NAME, eg. with workload or managed identity.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clientID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClientID selects a user-assigned managed identity or the application of a workload identity. It is only used when the storage secret has neither AZURE_ACCOUNT_KEY nor AZURE_ACCOUNT_SAS.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"container"},
 			},
@@ -152,11 +185,84 @@ func schema_kmodulesxyz_objectstore_api_api_v1_Backend(ref common.ReferenceCallb
 							Ref: ref("kmodules.xyz/objectstore-api/api/v1.RestServerSpec"),
 						},
 					},
+					"storageSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StorageSecret references the storage secret like StorageSecretName, and maps the credential keys to the keys of secrets that are created by other tools, eg. external-secrets, Crossplane or bucket provisioners.",
+							Ref:         ref("kmodules.xyz/objectstore-api/api/v1.StorageSecretReference"),
+						},
+					},
+					"tls": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TLS configures the connections to the endpoint of any provider. The CA bundle and the client certificate are read from the CA_CERT_DATA, TLS_CLIENT_CERT_DATA and TLS_CLIENT_KEY_DATA keys of the storage secret.",
+							Ref:         ref("kmodules.xyz/objectstore-api/api/v1.TLSConfig"),
+						},
+					},
+					"proxy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Proxy routes the connections to the endpoint of any provider through an http proxy, instead of the proxy of the environment of the process.",
+							Ref:         ref("kmodules.xyz/objectstore-api/api/v1.ProxyConfig"),
+						},
+					},
+					"retryPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RetryPolicy configures how the requests to the backend are retried. Without it, the defaults of the provider sdk are used.",
+							Ref:         ref("kmodules.xyz/objectstore-api/api/v1.RetryPolicy"),
+						},
+					},
+					"rateLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RateLimit limits the bandwidth and the request rate to the backend. The limits are shared by every user of the backend within the process.",
+							Ref:         ref("kmodules.xyz/objectstore-api/api/v1.RateLimit"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"kmodules.xyz/objectstore-api/api/v1.AzureSpec", "kmodules.xyz/objectstore-api/api/v1.B2Spec", "kmodules.xyz/objectstore-api/api/v1.GCSSpec", "kmodules.xyz/objectstore-api/api/v1.LocalSpec", "kmodules.xyz/objectstore-api/api/v1.RestServerSpec", "kmodules.xyz/objectstore-api/api/v1.S3Spec", "kmodules.xyz/objectstore-api/api/v1.SwiftSpec"},
+			"kmodules.xyz/objectstore-api/api/v1.AzureSpec", "kmodules.xyz/objectstore-api/api/v1.B2Spec", "kmodules.xyz/objectstore-api/api/v1.GCSSpec", "kmodules.xyz/objectstore-api/api/v1.LocalSpec", "kmodules.xyz/objectstore-api/api/v1.ProxyConfig", "kmodules.xyz/objectstore-api/api/v1.RateLimit", "kmodules.xyz/objectstore-api/api/v1.RestServerSpec", "kmodules.xyz/objectstore-api/api/v1.RetryPolicy", "kmodules.xyz/objectstore-api/api/v1.S3Spec", "kmodules.xyz/objectstore-api/api/v1.StorageSecretReference", "kmodules.xyz/objectstore-api/api/v1.SwiftSpec", "kmodules.xyz/objectstore-api/api/v1.TLSConfig"},
+	}
+}
+
+func schema_kmodulesxyz_objectstore_api_api_v1_CredentialKeySource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CredentialKeySource sets a credential key from exactly one of a secret key, a config map key or a file.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"key": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Key is the credential key that is set, eg. CA_CERT_DATA",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secretKeyRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretKeyRef selects a key of a secret",
+							Ref:         ref("k8s.io/api/core/v1.SecretKeySelector"),
+						},
+					},
+					"configMapKeyRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigMapKeyRef selects a key of a config map, eg. a CA bundle",
+							Ref:         ref("k8s.io/api/core/v1.ConfigMapKeySelector"),
+						},
+					},
+					"file": {
+						SchemaProps: spec.SchemaProps{
+							Description: "File is the absolute path of a file that is mounted in the pod",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"key"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.ConfigMapKeySelector", "k8s.io/api/core/v1.SecretKeySelector"},
 	}
 }
 
@@ -185,6 +291,28 @@ func schema_kmodulesxyz_objectstore_api_api_v1_GCSSpec(ref common.ReferenceCallb
 							Format: "int64",
 						},
 					},
+					"impersonateServiceAccount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImpersonateServiceAccount is the email of a service account impersonated with the credentials of the storage secret, or with the application default credentials when there is no secret.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"scopes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Scopes of the access token, eg. https://www.googleapis.com/auth/devstorage.read_only. Defaults to https://www.googleapis.com/auth/devstorage.read_write",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"bucket"},
 			},
@@ -400,6 +528,318 @@ func schema_kmodulesxyz_objectstore_api_api_v1_LocalSpec(ref common.ReferenceCal
 	}
 }
 
+func schema_kmodulesxyz_objectstore_api_api_v1_ObjectStore(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ObjectStore is a backend whose bucket is checked periodically by the objectstore controller, so that its users learn about unreachable buckets and lost permissions before they need them.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kmodules.xyz/objectstore-api/api/v1.ObjectStoreSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kmodules.xyz/objectstore-api/api/v1.ObjectStoreStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kmodules.xyz/objectstore-api/api/v1.ObjectStoreSpec", "kmodules.xyz/objectstore-api/api/v1.ObjectStoreStatus"},
+	}
+}
+
+func schema_kmodulesxyz_objectstore_api_api_v1_ObjectStoreList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ObjectStoreList is a list of ObjectStores",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kmodules.xyz/objectstore-api/api/v1.ObjectStore"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kmodules.xyz/objectstore-api/api/v1.ObjectStore"},
+	}
+}
+
+func schema_kmodulesxyz_objectstore_api_api_v1_ObjectStoreSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"backend": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backend is the bucket that is checked. As an ObjectStore has no namespace, the storage secret is read from the namespace of the controller, unless Backend.StorageSecret names another one.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("kmodules.xyz/objectstore-api/api/v1.Backend"),
+						},
+					},
+					"checkInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CheckInterval is the time between two checks of the bucket. Defaults to 5m.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"backend"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "kmodules.xyz/objectstore-api/api/v1.Backend"},
+	}
+}
+
+func schema_kmodulesxyz_objectstore_api_api_v1_ObjectStoreStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObservedGeneration is the generation of the spec that was checked last.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"lastCheckTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastCheckTime is the time of the last check of the bucket.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"region": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Region of the bucket, as configured or looked up for AWS buckets. It is empty for the providers without regions.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"capabilities": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Capabilities are the operations on the bucket that the credentials were allowed by the last check.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"type",
+								},
+								"x-kubernetes-list-type":       "map",
+								"x-kubernetes-patch-merge-key": "type",
+								"x-kubernetes-patch-strategy":  "merge",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions of the object store. The Ready condition is true when the last check found the bucket and could write to it.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Condition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_kmodulesxyz_objectstore_api_api_v1_ProxyConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ProxyConfig configures the proxy of a backend.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL of the proxy, eg. http://proxy.example.com:3128. The credentials are read from the PROXY_USERNAME and PROXY_PASSWORD keys of the storage secret.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"noProxy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NoProxy lists the hosts that are connected to directly: host names, which also match their sub domains, ip addresses and CIDRs, optionally with a port, or *.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"url"},
+			},
+		},
+	}
+}
+
+func schema_kmodulesxyz_objectstore_api_api_v1_RateLimit(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RateLimit limits the traffic to a backend. A limit of 0 is no limit.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"uploadBytesPerSecond": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UploadBytesPerSecond limits the bytes sent to the backend.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"downloadBytesPerSecond": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DownloadBytesPerSecond limits the bytes read from the backend.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"requestsPerSecond": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RequestsPerSecond limits the requests sent to the backend.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kmodulesxyz_objectstore_api_api_v1_ReplicatedBackend(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ReplicatedBackend is a primary backend with mirrors that hold copies of its objects. Writes go to every backend, reads go to the primary first and to the mirrors when it fails or misses the object.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"primary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Primary is the backend that is read first.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("kmodules.xyz/objectstore-api/api/v1.Backend"),
+						},
+					},
+					"mirrors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mirrors are read in order when the primary fails.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kmodules.xyz/objectstore-api/api/v1.Backend"),
+									},
+								},
+							},
+						},
+					},
+					"writeQuorum": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WriteQuorum is the number of backends, the primary included, that must store an object for a write to succeed. Defaults to all of them.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"primary", "mirrors"},
+			},
+		},
+		Dependencies: []string{
+			"kmodules.xyz/objectstore-api/api/v1.Backend"},
+	}
+}
+
 func schema_kmodulesxyz_objectstore_api_api_v1_RestServerSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -418,6 +858,110 @@ func schema_kmodulesxyz_objectstore_api_api_v1_RestServerSpec(ref common.Referen
 	}
 }
 
+func schema_kmodulesxyz_objectstore_api_api_v1_RetryPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RetryPolicy configures the retries and timeouts of the requests to a backend.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxAttempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxAttempts is the number of attempts of a request, including the first one. Defaults to 3.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"baseBackoff": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BaseBackoff is the upper bound of the wait before the first retry. It doubles with every retry, the wait is picked at random below it. Defaults to 100ms.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"maxBackoff": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxBackoff caps the wait between two attempts. Defaults to 20s.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"attemptTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AttemptTimeout limits every attempt, including reading the response.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"timeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timeout limits an operation, including all of its attempts and waits.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"retryOn": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RetryOn lists the classes of errors that are retried. Defaults to all of them.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_kmodulesxyz_objectstore_api_api_v1_S3Encryption(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"kmsKeyID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KMSKeyID is the id, alias or arn of the KMS key used for SSE-KMS. The AWS managed key of the bucket is used when it is empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"kmsEncryptionContext": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KMSEncryptionContext is the encryption context used for SSE-KMS",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"type"},
+			},
+		},
+	}
+}
+
 func schema_kmodulesxyz_objectstore_api_api_v1_S3Spec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -456,10 +1000,189 @@ func schema_kmodulesxyz_objectstore_api_api_v1_S3Spec(ref common.ReferenceCallba
 							Format: "",
 						},
 					},
+					"encryption": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kmodules.xyz/objectstore-api/api/v1.S3Encryption"),
+						},
+					},
+					"storageClass": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StorageClass of the objects written to the bucket, eg. STANDARD_IA or GLACIER_IR. The bucket default is used when it is empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"acl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ACL is the canned ACL of the objects written to the bucket, eg. bucket-owner-full-control",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tags": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tags are added to every object written to the bucket",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"addressingStyle": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AddressingStyle selects between path-style and virtual-hosted-style urls. By default, AWS buckets are virtual-hosted and buckets of other services use path-style urls.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"dualStack": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DualStack uses the IPv4/IPv6 dual-stack endpoint of AWS",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"fips": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FIPS uses the FIPS 140 validated endpoint of AWS",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"accelerate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Accelerate uses the transfer acceleration endpoint of AWS",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"signatureVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SignatureVersion is the version of the request signature, v4 by default. v2 is only meant for legacy S3 compatible appliances and implies path-style urls.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"roleARN": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RoleARN is assumed through STS. The access keys of the storage secret, or the default credentials when there are none, are used to call AssumeRole.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"externalID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExternalID is passed to AssumeRole, when the trust policy of the role requires it",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"roleSessionName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RoleSessionName identifies the session of the assumed role in CloudTrail. A name is generated when it is empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"webIdentityTokenFile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WebIdentityTokenFile is the path of a projected service account token. The role is assumed with AssumeRoleWithWebIdentity instead of AssumeRole when it is set.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"stsEndpoint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "STSEndpoint is the url of the STS service, eg. of an S3 compatible service that implements it. Defaults to the STS endpoint of the AWS region.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"profile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Profile selects the profile of the AWS_SHARED_CREDENTIALS and AWS_CONFIG files of the storage secret. Defaults to the default profile.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maxConnections": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxConnections limits the requests that are sent to the bucket at the same time, including the parts of multipart uploads. 0 means no limit.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
 				},
 				Required: []string{"endpoint", "bucket"},
 			},
 		},
+		Dependencies: []string{
+			"kmodules.xyz/objectstore-api/api/v1.S3Encryption"},
+	}
+}
+
+func schema_kmodulesxyz_objectstore_api_api_v1_StorageSecretReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the secret. Defaults to StorageSecretName.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"keys": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Keys maps credential keys, eg. AWS_ACCESS_KEY_ID, to the keys of the secret that hold them. Credential keys that are not mapped are read from the secret as is.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the secret and of the secrets and config maps of Sources. Defaults to the namespace of the backend. Other namespaces are only read when the namespace policy of the caller allows it.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"sources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sources set single credential keys from other secrets, config maps or files mounted in the pod. They override the keys of the secret.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kmodules.xyz/objectstore-api/api/v1.CredentialKeySource"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kmodules.xyz/objectstore-api/api/v1.CredentialKeySource"},
 	}
 }
 
@@ -488,3 +1211,30 @@ func schema_kmodulesxyz_objectstore_api_api_v1_SwiftSpec(ref common.ReferenceCal
 		},
 	}
 }
+
+func schema_kmodulesxyz_objectstore_api_api_v1_TLSConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TLSConfig configures the TLS connections to a backend.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"serverName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServerName overrides the host name that is used to verify the certificate of the server, eg. when the endpoint is addressed by ip.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"minVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MinVersion is the minimum TLS version, one of 1.0, 1.1, 1.2 or 1.3. Defaults to the minimum of the Go runtime.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
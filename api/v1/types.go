@@ -18,6 +18,7 @@ package v1
 
 import (
 	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -27,9 +28,17 @@ const (
 	AWS_SECRET_ACCESS_KEY = "AWS_SECRET_ACCESS_KEY"
 	// Deprecated: Use kmodules.xyz/constants/aws
 	CA_CERT_DATA = "CA_CERT_DATA"
+	// TLS_CLIENT_CERT_DATA is the PEM encoded client certificate used for mutual TLS
+	TLS_CLIENT_CERT_DATA = "TLS_CLIENT_CERT_DATA"
+	// TLS_CLIENT_KEY_DATA is the PEM encoded private key of TLS_CLIENT_CERT_DATA
+	TLS_CLIENT_KEY_DATA = "TLS_CLIENT_KEY_DATA"
 
 	// Deprecated: Use kmodules.xyz/constants/google
 	GOOGLE_PROJECT_ID = "GOOGLE_PROJECT_ID"
+	// GOOGLE_SERVICE_ACCOUNT_JSON_KEY holds a service_account key, an external_account
+	// (workload identity federation) or any other credential file understood by
+	// Google's client libraries.
+	//
 	// Deprecated: Use kmodules.xyz/constants/google
 	GOOGLE_SERVICE_ACCOUNT_JSON_KEY = "GOOGLE_SERVICE_ACCOUNT_JSON_KEY"
 	// Deprecated: Use kmodules.xyz/constants/google
@@ -39,6 +48,8 @@ const (
 	AZURE_ACCOUNT_NAME = "AZURE_ACCOUNT_NAME"
 	// Deprecated: Use kmodules.xyz/constants/azure
 	AZURE_ACCOUNT_KEY = "AZURE_ACCOUNT_KEY"
+	// AZURE_ACCOUNT_SAS is a shared access signature used instead of the account key
+	AZURE_ACCOUNT_SAS = "AZURE_ACCOUNT_SAS"
 
 	// swift
 	// Deprecated: Use kmodules.xyz/constants/openstack
@@ -77,6 +88,32 @@ const (
 	OS_STORAGE_URL = "OS_STORAGE_URL"
 	// Deprecated: Use kmodules.xyz/constants/openstack
 	OS_AUTH_TOKEN = "OS_AUTH_TOKEN"
+
+	// b2
+	B2_ACCOUNT_ID  = "B2_ACCOUNT_ID"
+	B2_ACCOUNT_KEY = "B2_ACCOUNT_KEY"
+	// B2_AUTH_URL is optional and overrides https://api.backblazeb2.com
+	B2_AUTH_URL = "B2_AUTH_URL"
+
+	// AWS_SSE_CUSTOMER_KEY holds the 256 bit key used for SSE-C, either as raw
+	// bytes or base64 encoded
+	AWS_SSE_CUSTOMER_KEY = "AWS_SSE_CUSTOMER_KEY"
+	// AWS_SESSION_TOKEN is the session token of temporary access keys
+	AWS_SESSION_TOKEN = "AWS_SESSION_TOKEN"
+	// AWS_CREDENTIAL_EXPIRATION is the RFC 3339 expiry time of temporary access keys
+	AWS_CREDENTIAL_EXPIRATION = "AWS_CREDENTIAL_EXPIRATION"
+	// AWS_SHARED_CREDENTIALS holds a shared credentials file, like ~/.aws/credentials
+	AWS_SHARED_CREDENTIALS = "AWS_SHARED_CREDENTIALS"
+	// AWS_CONFIG holds a shared config file, like ~/.aws/config
+	AWS_CONFIG = "AWS_CONFIG"
+
+	// rest server
+	REST_SERVER_USERNAME = "REST_SERVER_USERNAME"
+	REST_SERVER_PASSWORD = "REST_SERVER_PASSWORD"
+
+	// proxy of Backend.Proxy
+	PROXY_USERNAME = "PROXY_USERNAME"
+	PROXY_PASSWORD = "PROXY_PASSWORD"
 )
 
 type Backend struct {
@@ -89,6 +126,140 @@ type Backend struct {
 	Swift *SwiftSpec      `json:"swift,omitempty" protobuf:"bytes,6,opt,name=swift"`
 	B2    *B2Spec         `json:"b2,omitempty" protobuf:"bytes,7,opt,name=b2"`
 	Rest  *RestServerSpec `json:"rest,omitempty" protobuf:"bytes,8,opt,name=rest"`
+
+	// StorageSecret references the storage secret like StorageSecretName, and maps
+	// the credential keys to the keys of secrets that are created by other tools,
+	// eg. external-secrets, Crossplane or bucket provisioners.
+	StorageSecret *StorageSecretReference `json:"storageSecret,omitempty" protobuf:"bytes,9,opt,name=storageSecret"`
+
+	// TLS configures the connections to the endpoint of any provider. The CA bundle and
+	// the client certificate are read from the CA_CERT_DATA, TLS_CLIENT_CERT_DATA and
+	// TLS_CLIENT_KEY_DATA keys of the storage secret.
+	TLS *TLSConfig `json:"tls,omitempty" protobuf:"bytes,10,opt,name=tls"`
+
+	// Proxy routes the connections to the endpoint of any provider through an http
+	// proxy, instead of the proxy of the environment of the process.
+	Proxy *ProxyConfig `json:"proxy,omitempty" protobuf:"bytes,11,opt,name=proxy"`
+
+	// RetryPolicy configures how the requests to the backend are retried. Without it,
+	// the defaults of the provider sdk are used.
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty" protobuf:"bytes,12,opt,name=retryPolicy"`
+
+	// RateLimit limits the bandwidth and the request rate to the backend. The
+	// limits are shared by every user of the backend within the process.
+	RateLimit *RateLimit `json:"rateLimit,omitempty" protobuf:"bytes,13,opt,name=rateLimit"`
+}
+
+// ReplicatedBackend is a primary backend with mirrors that hold copies of its
+// objects. Writes go to every backend, reads go to the primary first and to
+// the mirrors when it fails or misses the object.
+type ReplicatedBackend struct {
+	// Primary is the backend that is read first.
+	Primary Backend `json:"primary" protobuf:"bytes,1,opt,name=primary"`
+	// Mirrors are read in order when the primary fails.
+	Mirrors []Backend `json:"mirrors" protobuf:"bytes,2,rep,name=mirrors"`
+	// WriteQuorum is the number of backends, the primary included, that must
+	// store an object for a write to succeed. Defaults to all of them.
+	WriteQuorum int32 `json:"writeQuorum,omitempty" protobuf:"varint,3,opt,name=writeQuorum"`
+}
+
+// RateLimit limits the traffic to a backend. A limit of 0 is no limit.
+type RateLimit struct {
+	// UploadBytesPerSecond limits the bytes sent to the backend.
+	UploadBytesPerSecond int64 `json:"uploadBytesPerSecond,omitempty" protobuf:"varint,1,opt,name=uploadBytesPerSecond"`
+	// DownloadBytesPerSecond limits the bytes read from the backend.
+	DownloadBytesPerSecond int64 `json:"downloadBytesPerSecond,omitempty" protobuf:"varint,2,opt,name=downloadBytesPerSecond"`
+	// RequestsPerSecond limits the requests sent to the backend.
+	RequestsPerSecond int32 `json:"requestsPerSecond,omitempty" protobuf:"varint,3,opt,name=requestsPerSecond"`
+}
+
+// RetryPolicy configures the retries and timeouts of the requests to a backend.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts of a request, including the first one.
+	// Defaults to 3.
+	MaxAttempts int32 `json:"maxAttempts,omitempty" protobuf:"varint,1,opt,name=maxAttempts"`
+	// BaseBackoff is the upper bound of the wait before the first retry. It doubles
+	// with every retry, the wait is picked at random below it. Defaults to 100ms.
+	BaseBackoff *metav1.Duration `json:"baseBackoff,omitempty" protobuf:"bytes,2,opt,name=baseBackoff"`
+	// MaxBackoff caps the wait between two attempts. Defaults to 20s.
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty" protobuf:"bytes,3,opt,name=maxBackoff"`
+	// AttemptTimeout limits every attempt, including reading the response.
+	AttemptTimeout *metav1.Duration `json:"attemptTimeout,omitempty" protobuf:"bytes,4,opt,name=attemptTimeout"`
+	// Timeout limits an operation, including all of its attempts and waits.
+	Timeout *metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,5,opt,name=timeout"`
+	// RetryOn lists the classes of errors that are retried. Defaults to all of them.
+	RetryOn []RetryErrorClass `json:"retryOn,omitempty" protobuf:"bytes,6,rep,name=retryOn,casttype=RetryErrorClass"`
+}
+
+// RetryErrorClass is a class of errors that can be retried.
+type RetryErrorClass string
+
+const (
+	// RetryOnThrottling retries 429 and 503 responses.
+	RetryOnThrottling RetryErrorClass = "Throttling"
+	// RetryOnServerError retries the other 5xx responses.
+	RetryOnServerError RetryErrorClass = "ServerError"
+	// RetryOnTimeout retries 408 responses and attempts that timed out.
+	RetryOnTimeout RetryErrorClass = "Timeout"
+	// RetryOnNetwork retries refused, reset and otherwise failed connections.
+	RetryOnNetwork RetryErrorClass = "Network"
+)
+
+// ProxyConfig configures the proxy of a backend.
+type ProxyConfig struct {
+	// URL of the proxy, eg. http://proxy.example.com:3128. The credentials are read
+	// from the PROXY_USERNAME and PROXY_PASSWORD keys of the storage secret.
+	URL string `json:"url" protobuf:"bytes,1,opt,name=url"`
+	// NoProxy lists the hosts that are connected to directly: host names, which also
+	// match their sub domains, ip addresses and CIDRs, optionally with a port, or *.
+	NoProxy []string `json:"noProxy,omitempty" protobuf:"bytes,2,rep,name=noProxy"`
+}
+
+// TLSConfig configures the TLS connections to a backend.
+type TLSConfig struct {
+	// ServerName overrides the host name that is used to verify the certificate of
+	// the server, eg. when the endpoint is addressed by ip.
+	ServerName string `json:"serverName,omitempty" protobuf:"bytes,1,opt,name=serverName"`
+	// MinVersion is the minimum TLS version, one of 1.0, 1.1, 1.2 or 1.3. Defaults to
+	// the minimum of the Go runtime.
+	MinVersion TLSVersion `json:"minVersion,omitempty" protobuf:"bytes,2,opt,name=minVersion,casttype=TLSVersion"`
+}
+
+type TLSVersion string
+
+const (
+	TLSVersion10 TLSVersion = "1.0"
+	TLSVersion11 TLSVersion = "1.1"
+	TLSVersion12 TLSVersion = "1.2"
+	TLSVersion13 TLSVersion = "1.3"
+)
+
+type StorageSecretReference struct {
+	// Name of the secret. Defaults to StorageSecretName.
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// Keys maps credential keys, eg. AWS_ACCESS_KEY_ID, to the keys of the secret that hold them.
+	// Credential keys that are not mapped are read from the secret as is.
+	Keys map[string]string `json:"keys,omitempty" protobuf:"bytes,2,rep,name=keys"`
+	// Namespace of the secret and of the secrets and config maps of Sources.
+	// Defaults to the namespace of the backend. Other namespaces are only read
+	// when the namespace policy of the caller allows it.
+	Namespace string `json:"namespace,omitempty" protobuf:"bytes,3,opt,name=namespace"`
+	// Sources set single credential keys from other secrets, config maps or
+	// files mounted in the pod. They override the keys of the secret.
+	Sources []CredentialKeySource `json:"sources,omitempty" protobuf:"bytes,4,rep,name=sources"`
+}
+
+// CredentialKeySource sets a credential key from exactly one of a secret key, a
+// config map key or a file.
+type CredentialKeySource struct {
+	// Key is the credential key that is set, eg. CA_CERT_DATA
+	Key string `json:"key" protobuf:"bytes,1,opt,name=key"`
+	// SecretKeyRef selects a key of a secret
+	SecretKeyRef *core.SecretKeySelector `json:"secretKeyRef,omitempty" protobuf:"bytes,2,opt,name=secretKeyRef"`
+	// ConfigMapKeyRef selects a key of a config map, eg. a CA bundle
+	ConfigMapKeyRef *core.ConfigMapKeySelector `json:"configMapKeyRef,omitempty" protobuf:"bytes,3,opt,name=configMapKeyRef"`
+	// File is the absolute path of a file that is mounted in the pod
+	File string `json:"file,omitempty" protobuf:"bytes,4,opt,name=file"`
 }
 
 type LocalSpec struct {
@@ -103,18 +274,118 @@ type S3Spec struct {
 	Prefix      string `json:"prefix,omitempty" protobuf:"bytes,3,opt,name=prefix"`
 	Region      string `json:"region,omitempty" protobuf:"bytes,4,opt,name=region"`
 	InsecureTLS bool   `json:"insecureTLS,omitempty" protobuf:"varint,5,opt,name=insecureTLS"`
+
+	Encryption *S3Encryption `json:"encryption,omitempty" protobuf:"bytes,6,opt,name=encryption"`
+
+	// StorageClass of the objects written to the bucket, eg. STANDARD_IA or GLACIER_IR.
+	// The bucket default is used when it is empty.
+	StorageClass string `json:"storageClass,omitempty" protobuf:"bytes,7,opt,name=storageClass"`
+	// ACL is the canned ACL of the objects written to the bucket, eg. bucket-owner-full-control
+	ACL string `json:"acl,omitempty" protobuf:"bytes,8,opt,name=acl"`
+	// Tags are added to every object written to the bucket
+	Tags map[string]string `json:"tags,omitempty" protobuf:"bytes,9,rep,name=tags"`
+
+	// AddressingStyle selects between path-style and virtual-hosted-style urls.
+	// By default, AWS buckets are virtual-hosted and buckets of other services use path-style urls.
+	AddressingStyle S3AddressingStyle `json:"addressingStyle,omitempty" protobuf:"bytes,10,opt,name=addressingStyle,casttype=S3AddressingStyle"`
+	// DualStack uses the IPv4/IPv6 dual-stack endpoint of AWS
+	DualStack bool `json:"dualStack,omitempty" protobuf:"varint,11,opt,name=dualStack"`
+	// FIPS uses the FIPS 140 validated endpoint of AWS
+	FIPS bool `json:"fips,omitempty" protobuf:"varint,12,opt,name=fips"`
+	// Accelerate uses the transfer acceleration endpoint of AWS
+	Accelerate bool `json:"accelerate,omitempty" protobuf:"varint,13,opt,name=accelerate"`
+	// SignatureVersion is the version of the request signature, v4 by default.
+	// v2 is only meant for legacy S3 compatible appliances and implies path-style urls.
+	SignatureVersion S3SignatureVersion `json:"signatureVersion,omitempty" protobuf:"bytes,14,opt,name=signatureVersion,casttype=S3SignatureVersion"`
+
+	// RoleARN is assumed through STS. The access keys of the storage secret, or the
+	// default credentials when there are none, are used to call AssumeRole.
+	RoleARN string `json:"roleARN,omitempty" protobuf:"bytes,15,opt,name=roleARN"`
+	// ExternalID is passed to AssumeRole, when the trust policy of the role requires it
+	ExternalID string `json:"externalID,omitempty" protobuf:"bytes,16,opt,name=externalID"`
+	// RoleSessionName identifies the session of the assumed role in CloudTrail.
+	// A name is generated when it is empty.
+	RoleSessionName string `json:"roleSessionName,omitempty" protobuf:"bytes,17,opt,name=roleSessionName"`
+	// WebIdentityTokenFile is the path of a projected service account token. The role is
+	// assumed with AssumeRoleWithWebIdentity instead of AssumeRole when it is set.
+	WebIdentityTokenFile string `json:"webIdentityTokenFile,omitempty" protobuf:"bytes,18,opt,name=webIdentityTokenFile"`
+	// STSEndpoint is the url of the STS service, eg. of an S3 compatible service that
+	// implements it. Defaults to the STS endpoint of the AWS region.
+	STSEndpoint string `json:"stsEndpoint,omitempty" protobuf:"bytes,19,opt,name=stsEndpoint"`
+	// Profile selects the profile of the AWS_SHARED_CREDENTIALS and AWS_CONFIG files of
+	// the storage secret. Defaults to the default profile.
+	Profile string `json:"profile,omitempty" protobuf:"bytes,20,opt,name=profile"`
+	// MaxConnections limits the requests that are sent to the bucket at the same
+	// time, including the parts of multipart uploads. 0 means no limit.
+	MaxConnections int64 `json:"maxConnections,omitempty" protobuf:"varint,21,opt,name=maxConnections"`
+}
+
+type S3AddressingStyle string
+
+const (
+	S3AddressingStylePath    S3AddressingStyle = "path"
+	S3AddressingStyleVirtual S3AddressingStyle = "virtual"
+)
+
+type S3SignatureVersion string
+
+const (
+	S3SignatureV2 S3SignatureVersion = "v2"
+	S3SignatureV4 S3SignatureVersion = "v4"
+)
+
+type S3EncryptionType string
+
+const (
+	// S3EncryptionSSES3 encrypts objects with keys managed by S3
+	S3EncryptionSSES3 S3EncryptionType = "SSE-S3"
+	// S3EncryptionSSEKMS encrypts objects with a key stored in AWS KMS
+	S3EncryptionSSEKMS S3EncryptionType = "SSE-KMS"
+	// S3EncryptionSSEC encrypts objects with the AWS_SSE_CUSTOMER_KEY of the storage secret.
+	// The same key is required to read them back.
+	S3EncryptionSSEC S3EncryptionType = "SSE-C"
+)
+
+type S3Encryption struct {
+	Type S3EncryptionType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=S3EncryptionType"`
+	// KMSKeyID is the id, alias or arn of the KMS key used for SSE-KMS.
+	// The AWS managed key of the bucket is used when it is empty.
+	KMSKeyID string `json:"kmsKeyID,omitempty" protobuf:"bytes,2,opt,name=kmsKeyID"`
+	// KMSEncryptionContext is the encryption context used for SSE-KMS
+	KMSEncryptionContext map[string]string `json:"kmsEncryptionContext,omitempty" protobuf:"bytes,3,rep,name=kmsEncryptionContext"`
 }
 
 type GCSSpec struct {
 	Bucket         string `json:"bucket" protobuf:"bytes,1,opt,name=bucket"`
 	Prefix         string `json:"prefix,omitempty" protobuf:"bytes,2,opt,name=prefix"`
 	MaxConnections int64  `json:"maxConnections,omitempty" protobuf:"varint,3,opt,name=maxConnections"`
+
+	// ImpersonateServiceAccount is the email of a service account impersonated with the
+	// credentials of the storage secret, or with the application default credentials
+	// when there is no secret.
+	ImpersonateServiceAccount string `json:"impersonateServiceAccount,omitempty" protobuf:"bytes,4,opt,name=impersonateServiceAccount"`
+	// Scopes of the access token, eg. https://www.googleapis.com/auth/devstorage.read_only.
+	// Defaults to https://www.googleapis.com/auth/devstorage.read_write
+	Scopes []string `json:"scopes,omitempty" protobuf:"bytes,5,rep,name=scopes"`
 }
 
 type AzureSpec struct {
 	Container      string `json:"container" protobuf:"bytes,1,opt,name=container"`
 	Prefix         string `json:"prefix,omitempty" protobuf:"bytes,2,opt,name=prefix"`
 	MaxConnections int64  `json:"maxConnections,omitempty" protobuf:"varint,3,opt,name=maxConnections"`
+
+	// Endpoint is the url of the blob service of the storage account, eg.
+	// https://<account>.blob.core.chinacloudapi.cn for a sovereign cloud or
+	// http://127.0.0.1:10000/<account> for Azurite.
+	// Defaults to https://<account>.blob.core.windows.net
+	Endpoint string `json:"endpoint,omitempty" protobuf:"bytes,4,opt,name=endpoint"`
+	// StorageAccount is the name of the storage account. It is used when the storage
+	// secret has no AZURE_ACCOUNT_NAME, eg. with workload or managed identity.
+	StorageAccount string `json:"storageAccount,omitempty" protobuf:"bytes,5,opt,name=storageAccount"`
+	// ClientID selects a user-assigned managed identity or the application of a
+	// workload identity. It is only used when the storage secret has neither
+	// AZURE_ACCOUNT_KEY nor AZURE_ACCOUNT_SAS.
+	ClientID string `json:"clientID,omitempty" protobuf:"bytes,6,opt,name=clientID"`
 }
 
 type SwiftSpec struct {
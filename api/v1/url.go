@@ -0,0 +1,572 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// URL schemes used by Backend.URL and ParseBackendURL
+const (
+	SchemeLocal = "file"
+	SchemeS3    = "s3"
+	SchemeGCS   = "gs"
+	SchemeAzure = "azblob"
+	SchemeSwift = "swift"
+	SchemeB2    = "b2"
+	SchemeRest  = "rest"
+)
+
+// query parameters used by Backend.URL and ParseBackendURL. The parameters of
+// nested fields are prefixed with the name of their parent, the entries of a
+// map with the name of the map, eg. tags.team=db.
+const (
+	urlParamEndpoint       = "endpoint"
+	urlParamRegion         = "region"
+	urlParamInsecureTLS    = "insecureTLS"
+	urlParamMaxConnections = "maxConnections"
+	urlParamSubPath        = "subPath"
+	urlParamStorageAccount = "storageAccount"
+
+	urlParamEncryptionType         = "encryption.type"
+	urlParamEncryptionKMSKeyID     = "encryption.kmsKeyID"
+	urlParamEncryptionKMSContext   = "encryption.kmsEncryptionContext"
+	urlParamStorageClass           = "storageClass"
+	urlParamACL                    = "acl"
+	urlParamTags                   = "tags"
+	urlParamAddressingStyle        = "addressingStyle"
+	urlParamDualStack              = "dualStack"
+	urlParamFIPS                   = "fips"
+	urlParamAccelerate             = "accelerate"
+	urlParamSignatureVersion       = "signatureVersion"
+	urlParamRoleARN                = "roleARN"
+	urlParamExternalID             = "externalID"
+	urlParamRoleSessionName        = "roleSessionName"
+	urlParamWebIdentityTokenFile   = "webIdentityTokenFile"
+	urlParamSTSEndpoint            = "stsEndpoint"
+	urlParamProfile                = "profile"
+	urlParamImpersonateSA          = "impersonateServiceAccount"
+	urlParamScopes                 = "scopes"
+	urlParamClientID               = "clientID"
+	urlParamTLSServerName          = "tls.serverName"
+	urlParamTLSMinVersion          = "tls.minVersion"
+	urlParamProxyURL               = "proxy.url"
+	urlParamProxyNoProxy           = "proxy.noProxy"
+	urlParamRetryMaxAttempts       = "retryPolicy.maxAttempts"
+	urlParamRetryBaseBackoff       = "retryPolicy.baseBackoff"
+	urlParamRetryMaxBackoff        = "retryPolicy.maxBackoff"
+	urlParamRetryAttemptTimeout    = "retryPolicy.attemptTimeout"
+	urlParamRetryTimeout           = "retryPolicy.timeout"
+	urlParamRetryOn                = "retryPolicy.retryOn"
+	urlParamUploadBytesPerSecond   = "rateLimit.uploadBytesPerSecond"
+	urlParamDownloadBytesPerSecond = "rateLimit.downloadBytesPerSecond"
+	urlParamRequestsPerSecond      = "rateLimit.requestsPerSecond"
+)
+
+// URL returns the canonical url of the backend. It has the form
+// <scheme>://<bucket>/<prefix>?<options>, eg:
+//
+//	s3://bucket/prefix?endpoint=https://minio:9000&region=us-east-1
+//	gs://bucket/prefix
+//	azblob://container/prefix?maxConnections=4
+//	swift://container/prefix
+//	b2://bucket/prefix
+//	file:///mnt/x?subPath=backup
+//	rest:https://rest.example.com/repo#tls.serverName=rest
+//
+// Every setting of the spec is an option, lists are comma separated. As the
+// url of a rest server may have a query, its options are in the fragment.
+// ParseBackendURL converts the url back to a Backend. The storage secret and
+// the volume source of a local backend are not part of the url.
+func (backend Backend) URL() (string, error) {
+	q := map[string]string{}
+	setTransportParams(q, backend)
+	switch {
+	case backend.Local != nil:
+		if !strings.HasPrefix(backend.Local.MountPath, "/") {
+			return "", errors.Errorf("local mountPath %q must be an absolute path", backend.Local.MountPath)
+		}
+		setString(q, urlParamSubPath, backend.Local.SubPath)
+		return SchemeLocal + "://" + escapePath(backend.Local.MountPath) + encodeQuery(q), nil
+	case backend.S3 != nil:
+		setS3Params(q, backend.S3)
+		return bucketURL(SchemeS3, backend.S3.Bucket, backend.S3.Prefix, q)
+	case backend.GCS != nil:
+		setInt(q, urlParamMaxConnections, backend.GCS.MaxConnections)
+		setString(q, urlParamImpersonateSA, backend.GCS.ImpersonateServiceAccount)
+		setList(q, urlParamScopes, backend.GCS.Scopes)
+		return bucketURL(SchemeGCS, backend.GCS.Bucket, backend.GCS.Prefix, q)
+	case backend.Azure != nil:
+		setString(q, urlParamEndpoint, backend.Azure.Endpoint)
+		setString(q, urlParamStorageAccount, backend.Azure.StorageAccount)
+		setString(q, urlParamClientID, backend.Azure.ClientID)
+		setInt(q, urlParamMaxConnections, backend.Azure.MaxConnections)
+		return bucketURL(SchemeAzure, backend.Azure.Container, backend.Azure.Prefix, q)
+	case backend.Swift != nil:
+		return bucketURL(SchemeSwift, backend.Swift.Container, backend.Swift.Prefix, q)
+	case backend.B2 != nil:
+		setInt(q, urlParamMaxConnections, backend.B2.MaxConnections)
+		return bucketURL(SchemeB2, backend.B2.Bucket, backend.B2.Prefix, q)
+	case backend.Rest != nil:
+		if backend.Rest.URL == "" {
+			return "", errors.New("rest url is empty")
+		}
+		u := SchemeRest + ":" + backend.Rest.URL
+		if len(q) > 0 {
+			u += "#" + strings.TrimPrefix(encodeQuery(q), "?")
+		}
+		return u, nil
+	}
+	return "", errors.New("no storage provider is configured")
+}
+
+// ParseBackendURL parses a url returned by Backend.URL.
+func ParseBackendURL(s string) (*Backend, error) {
+	if rest, ok := strings.CutPrefix(s, SchemeRest+":"); ok {
+		rest, fragment, _ := strings.Cut(rest, "#")
+		u, err := url.Parse(rest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid rest url %q", s)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, errors.Errorf("invalid rest url %q: scheme must be http or https", s)
+		}
+		query, err := url.ParseQuery(fragment)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid rest url %q", s)
+		}
+		q := queryParams{query}
+		backend := Backend{Rest: &RestServerSpec{URL: rest}}
+		if err := q.parseTransport(&backend); err != nil {
+			return nil, errors.Wrapf(err, "invalid rest url %q", s)
+		}
+		if err := q.done(s); err != nil {
+			return nil, err
+		}
+		return &backend, nil
+	}
+
+	scheme, rest, ok := strings.Cut(s, "://")
+	if !ok {
+		return nil, errors.Errorf("invalid backend url %q: missing scheme", s)
+	}
+	rest, rawQuery, _ := strings.Cut(rest, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid backend url %q", s)
+	}
+	q := queryParams{query}
+
+	var backend Backend
+	if err := q.parseTransport(&backend); err != nil {
+		return nil, errors.Wrapf(err, "invalid backend url %q", s)
+	}
+	if scheme == SchemeLocal {
+		if !strings.HasPrefix(rest, "/") {
+			return nil, errors.Errorf("invalid backend url %q: expected %s:///<mountPath>", s, SchemeLocal)
+		}
+		mountPath, err := url.PathUnescape(rest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid backend url %q", s)
+		}
+		backend.Local = &LocalSpec{
+			MountPath: mountPath,
+			SubPath:   q.get(urlParamSubPath),
+		}
+		if err := q.done(s); err != nil {
+			return nil, err
+		}
+		return &backend, nil
+	}
+
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket, err = url.PathUnescape(bucket); err != nil {
+		return nil, errors.Wrapf(err, "invalid backend url %q", s)
+	}
+	if prefix, err = url.PathUnescape(prefix); err != nil {
+		return nil, errors.Wrapf(err, "invalid backend url %q", s)
+	}
+	if bucket == "" {
+		return nil, errors.Errorf("invalid backend url %q: missing bucket", s)
+	}
+
+	switch scheme {
+	case SchemeS3:
+		backend.S3 = &S3Spec{Bucket: bucket, Prefix: prefix}
+		err = q.parseS3(backend.S3)
+	case SchemeGCS:
+		backend.GCS = &GCSSpec{
+			Bucket:                    bucket,
+			Prefix:                    prefix,
+			ImpersonateServiceAccount: q.get(urlParamImpersonateSA),
+			Scopes:                    q.getList(urlParamScopes),
+		}
+		backend.GCS.MaxConnections, err = q.getInt(urlParamMaxConnections)
+	case SchemeAzure:
+		backend.Azure = &AzureSpec{
+			Container:      bucket,
+			Prefix:         prefix,
+			Endpoint:       q.get(urlParamEndpoint),
+			StorageAccount: q.get(urlParamStorageAccount),
+			ClientID:       q.get(urlParamClientID),
+		}
+		backend.Azure.MaxConnections, err = q.getInt(urlParamMaxConnections)
+	case SchemeSwift:
+		backend.Swift = &SwiftSpec{Container: bucket, Prefix: prefix}
+	case SchemeB2:
+		backend.B2 = &B2Spec{Bucket: bucket, Prefix: prefix}
+		backend.B2.MaxConnections, err = q.getInt(urlParamMaxConnections)
+	default:
+		return nil, errors.Errorf("invalid backend url %q: unknown scheme %q", s, scheme)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid backend url %q", s)
+	}
+	if err := q.done(s); err != nil {
+		return nil, err
+	}
+	return &backend, nil
+}
+
+func setS3Params(q map[string]string, spec *S3Spec) {
+	setString(q, urlParamEndpoint, spec.Endpoint)
+	setString(q, urlParamRegion, spec.Region)
+	setBool(q, urlParamInsecureTLS, spec.InsecureTLS)
+	if spec.Encryption != nil {
+		// the type is always set, so that an empty encryption round-trips
+		q[urlParamEncryptionType] = string(spec.Encryption.Type)
+		setString(q, urlParamEncryptionKMSKeyID, spec.Encryption.KMSKeyID)
+		setMap(q, urlParamEncryptionKMSContext, spec.Encryption.KMSEncryptionContext)
+	}
+	setString(q, urlParamStorageClass, spec.StorageClass)
+	setString(q, urlParamACL, spec.ACL)
+	setMap(q, urlParamTags, spec.Tags)
+	setString(q, urlParamAddressingStyle, string(spec.AddressingStyle))
+	setBool(q, urlParamDualStack, spec.DualStack)
+	setBool(q, urlParamFIPS, spec.FIPS)
+	setBool(q, urlParamAccelerate, spec.Accelerate)
+	setString(q, urlParamSignatureVersion, string(spec.SignatureVersion))
+	setString(q, urlParamRoleARN, spec.RoleARN)
+	setString(q, urlParamExternalID, spec.ExternalID)
+	setString(q, urlParamRoleSessionName, spec.RoleSessionName)
+	setString(q, urlParamWebIdentityTokenFile, spec.WebIdentityTokenFile)
+	setString(q, urlParamSTSEndpoint, spec.STSEndpoint)
+	setString(q, urlParamProfile, spec.Profile)
+	setInt(q, urlParamMaxConnections, spec.MaxConnections)
+}
+
+func (q queryParams) parseS3(spec *S3Spec) error {
+	var err error
+	spec.Endpoint = q.get(urlParamEndpoint)
+	spec.Region = q.get(urlParamRegion)
+	if spec.InsecureTLS, err = q.getBool(urlParamInsecureTLS); err != nil {
+		return err
+	}
+	if _, ok := q.Values[urlParamEncryptionType]; ok {
+		spec.Encryption = &S3Encryption{
+			Type:                 S3EncryptionType(q.get(urlParamEncryptionType)),
+			KMSKeyID:             q.get(urlParamEncryptionKMSKeyID),
+			KMSEncryptionContext: q.getMap(urlParamEncryptionKMSContext),
+		}
+	}
+	spec.StorageClass = q.get(urlParamStorageClass)
+	spec.ACL = q.get(urlParamACL)
+	spec.Tags = q.getMap(urlParamTags)
+	spec.AddressingStyle = S3AddressingStyle(q.get(urlParamAddressingStyle))
+	if spec.DualStack, err = q.getBool(urlParamDualStack); err != nil {
+		return err
+	}
+	if spec.FIPS, err = q.getBool(urlParamFIPS); err != nil {
+		return err
+	}
+	if spec.Accelerate, err = q.getBool(urlParamAccelerate); err != nil {
+		return err
+	}
+	spec.SignatureVersion = S3SignatureVersion(q.get(urlParamSignatureVersion))
+	spec.RoleARN = q.get(urlParamRoleARN)
+	spec.ExternalID = q.get(urlParamExternalID)
+	spec.RoleSessionName = q.get(urlParamRoleSessionName)
+	spec.WebIdentityTokenFile = q.get(urlParamWebIdentityTokenFile)
+	spec.STSEndpoint = q.get(urlParamSTSEndpoint)
+	spec.Profile = q.get(urlParamProfile)
+	spec.MaxConnections, err = q.getInt(urlParamMaxConnections)
+	return err
+}
+
+// setTransportParams sets the options of the settings that every provider
+// shares.
+func setTransportParams(q map[string]string, backend Backend) {
+	if tls := backend.TLS; tls != nil {
+		setString(q, urlParamTLSServerName, tls.ServerName)
+		setString(q, urlParamTLSMinVersion, string(tls.MinVersion))
+	}
+	if proxy := backend.Proxy; proxy != nil {
+		// the url is always set, so that an empty proxy round-trips
+		q[urlParamProxyURL] = proxy.URL
+		setList(q, urlParamProxyNoProxy, proxy.NoProxy)
+	}
+	if p := backend.RetryPolicy; p != nil {
+		setInt(q, urlParamRetryMaxAttempts, int64(p.MaxAttempts))
+		setDuration(q, urlParamRetryBaseBackoff, p.BaseBackoff)
+		setDuration(q, urlParamRetryMaxBackoff, p.MaxBackoff)
+		setDuration(q, urlParamRetryAttemptTimeout, p.AttemptTimeout)
+		setDuration(q, urlParamRetryTimeout, p.Timeout)
+		retryOn := make([]string, len(p.RetryOn))
+		for i, c := range p.RetryOn {
+			retryOn[i] = string(c)
+		}
+		setList(q, urlParamRetryOn, retryOn)
+	}
+	if l := backend.RateLimit; l != nil {
+		setInt(q, urlParamUploadBytesPerSecond, l.UploadBytesPerSecond)
+		setInt(q, urlParamDownloadBytesPerSecond, l.DownloadBytesPerSecond)
+		setInt(q, urlParamRequestsPerSecond, int64(l.RequestsPerSecond))
+	}
+}
+
+func (q queryParams) parseTransport(backend *Backend) error {
+	if q.has("tls.") {
+		backend.TLS = &TLSConfig{
+			ServerName: q.get(urlParamTLSServerName),
+			MinVersion: TLSVersion(q.get(urlParamTLSMinVersion)),
+		}
+	}
+	if q.has("proxy.") {
+		backend.Proxy = &ProxyConfig{
+			URL:     q.get(urlParamProxyURL),
+			NoProxy: q.getList(urlParamProxyNoProxy),
+		}
+	}
+	if q.has("retryPolicy.") {
+		p := &RetryPolicy{}
+		var err error
+		if p.MaxAttempts, err = q.getInt32(urlParamRetryMaxAttempts); err != nil {
+			return err
+		}
+		for key, d := range map[string]**metav1.Duration{
+			urlParamRetryBaseBackoff:    &p.BaseBackoff,
+			urlParamRetryMaxBackoff:     &p.MaxBackoff,
+			urlParamRetryAttemptTimeout: &p.AttemptTimeout,
+			urlParamRetryTimeout:        &p.Timeout,
+		} {
+			if *d, err = q.getDuration(key); err != nil {
+				return err
+			}
+		}
+		for _, c := range q.getList(urlParamRetryOn) {
+			p.RetryOn = append(p.RetryOn, RetryErrorClass(c))
+		}
+		backend.RetryPolicy = p
+	}
+	if q.has("rateLimit.") {
+		l := &RateLimit{}
+		var err error
+		if l.UploadBytesPerSecond, err = q.getInt(urlParamUploadBytesPerSecond); err != nil {
+			return err
+		}
+		if l.DownloadBytesPerSecond, err = q.getInt(urlParamDownloadBytesPerSecond); err != nil {
+			return err
+		}
+		if l.RequestsPerSecond, err = q.getInt32(urlParamRequestsPerSecond); err != nil {
+			return err
+		}
+		backend.RateLimit = l
+	}
+	return nil
+}
+
+func bucketURL(scheme, bucket, prefix string, q map[string]string) (string, error) {
+	if bucket == "" {
+		return "", errors.Errorf("%s bucket is empty", scheme)
+	}
+	u := scheme + "://" + url.PathEscape(bucket)
+	if prefix != "" {
+		u += "/" + escapePath(prefix)
+	}
+	return u + encodeQuery(q), nil
+}
+
+func setString(q map[string]string, key, v string) {
+	if v != "" {
+		q[key] = v
+	}
+}
+
+func setBool(q map[string]string, key string, v bool) {
+	if v {
+		q[key] = "true"
+	}
+}
+
+func setInt(q map[string]string, key string, n int64) {
+	if n != 0 {
+		q[key] = strconv.FormatInt(n, 10)
+	}
+}
+
+func setList(q map[string]string, key string, list []string) {
+	if len(list) > 0 {
+		q[key] = strings.Join(list, ",")
+	}
+}
+
+func setMap(q map[string]string, key string, m map[string]string) {
+	for k, v := range m {
+		q[key+"."+k] = v
+	}
+}
+
+func setDuration(q map[string]string, key string, d *metav1.Duration) {
+	if d != nil {
+		q[key] = d.Duration.String()
+	}
+}
+
+// escapePath escapes each segment of p and keeps the separators.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+// encodeQuery is url.Values.Encode, except that ':' and '/' are kept as is,
+// so that endpoints stay readable.
+func encodeQuery(q map[string]string) string {
+	if len(q) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for i, k := range keys {
+		if i == 0 {
+			sb.WriteByte('?')
+		} else {
+			sb.WriteByte('&')
+		}
+		v := url.QueryEscape(q[k])
+		v = strings.NewReplacer("%3A", ":", "%2F", "/").Replace(v)
+		sb.WriteString(url.QueryEscape(k) + "=" + v)
+	}
+	return sb.String()
+}
+
+// queryParams tracks which query parameters were used, so that unknown or
+// misspelled ones are reported instead of silently ignored.
+type queryParams struct {
+	url.Values
+}
+
+func (q queryParams) get(key string) string {
+	v := q.Values.Get(key)
+	q.Values.Del(key)
+	return v
+}
+
+func (q queryParams) getBool(key string) (bool, error) {
+	v := q.get(key)
+	if v == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(v)
+}
+
+func (q queryParams) getInt(key string) (int64, error) {
+	v := q.get(key)
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+func (q queryParams) getInt32(key string) (int32, error) {
+	v := q.get(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 32)
+	return int32(n), err
+}
+
+func (q queryParams) getList(key string) []string {
+	v := q.get(key)
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// getMap returns the entries of the map key, nil if it has none.
+func (q queryParams) getMap(key string) map[string]string {
+	var m map[string]string
+	for k := range q.Values {
+		if name, ok := strings.CutPrefix(k, key+"."); ok {
+			if m == nil {
+				m = map[string]string{}
+			}
+			m[name] = q.get(k)
+		}
+	}
+	return m
+}
+
+func (q queryParams) getDuration(key string) (*metav1.Duration, error) {
+	v := q.get(key)
+	if v == "" {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return nil, err
+	}
+	return &metav1.Duration{Duration: d}, nil
+}
+
+// has returns true if a parameter starts with prefix.
+func (q queryParams) has(prefix string) bool {
+	for k := range q.Values {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (q queryParams) done(s string) error {
+	if len(q.Values) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(q.Values))
+	for k := range q.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return errors.Errorf("invalid backend url %q: unsupported query parameters %s", s, strings.Join(keys, ", "))
+}
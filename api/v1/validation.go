@@ -0,0 +1,712 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var (
+	s3BucketRegex     = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]*[a-z0-9]$`)
+	gcsBucketRegex    = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*[a-z0-9]$`)
+	azureContainerRe  = regexp.MustCompile(`^[a-z0-9]([a-z0-9]|-[a-z0-9])*$`)
+	azureAccountRe    = regexp.MustCompile(`^[a-z0-9]{3,24}$`)
+	roleSessionNameRe = regexp.MustCompile(`^[\w+=,.@-]{2,64}$`)
+	b2BucketRegex     = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+)
+
+// Validate checks that exactly one provider is configured and that its
+// fields are usable. Field paths are relative to the backend, use
+// ValidateWithPath when the backend is embedded in another object.
+func (backend Backend) Validate() field.ErrorList {
+	return backend.ValidateWithPath(nil)
+}
+
+// ValidateWithPath is Validate with field paths rooted at fldPath.
+func (backend Backend) ValidateWithPath(fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	var provider string
+	for _, p := range []struct {
+		name string
+		set  bool
+	}{
+		{"local", backend.Local != nil},
+		{"s3", backend.S3 != nil},
+		{"gcs", backend.GCS != nil},
+		{"azure", backend.Azure != nil},
+		{"swift", backend.Swift != nil},
+		{"b2", backend.B2 != nil},
+		{"rest", backend.Rest != nil},
+	} {
+		if !p.set {
+			continue
+		}
+		if provider != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child(p.name), fmt.Sprintf("may not be set together with %s", provider)))
+			continue
+		}
+		provider = p.name
+	}
+	if provider == "" {
+		return append(allErrs, field.Required(fldPath, "one of local, s3, gcs, azure, swift, b2 or rest must be set"))
+	}
+
+	switch {
+	case backend.Local != nil:
+		allErrs = append(allErrs, validateLocal(backend.Local, fldPath.Child("local"))...)
+	case backend.S3 != nil:
+		allErrs = append(allErrs, validateS3(backend.S3, fldPath.Child("s3"))...)
+	case backend.GCS != nil:
+		fp := fldPath.Child("gcs")
+		allErrs = append(allErrs, validateGCSBucket(backend.GCS.Bucket, fp.Child("bucket"))...)
+		allErrs = append(allErrs, validatePrefix(backend.GCS.Prefix, fp.Child("prefix"))...)
+		allErrs = append(allErrs, validateMaxConnections(backend.GCS.MaxConnections, fp.Child("maxConnections"))...)
+		allErrs = append(allErrs, validateGCSCredentials(backend.GCS, fp)...)
+	case backend.Azure != nil:
+		fp := fldPath.Child("azure")
+		allErrs = append(allErrs, validateAzureContainer(backend.Azure.Container, fp.Child("container"))...)
+		allErrs = append(allErrs, validatePrefix(backend.Azure.Prefix, fp.Child("prefix"))...)
+		allErrs = append(allErrs, validateMaxConnections(backend.Azure.MaxConnections, fp.Child("maxConnections"))...)
+		if backend.Azure.Endpoint != "" {
+			allErrs = append(allErrs, validateURL(backend.Azure.Endpoint, fp.Child("endpoint"))...)
+		}
+		if backend.Azure.StorageAccount != "" {
+			allErrs = append(allErrs, validateAzureStorageAccount(backend.Azure.StorageAccount, fp.Child("storageAccount"))...)
+		}
+	case backend.Swift != nil:
+		fp := fldPath.Child("swift")
+		allErrs = append(allErrs, validateSwiftContainer(backend.Swift.Container, fp.Child("container"))...)
+		allErrs = append(allErrs, validatePrefix(backend.Swift.Prefix, fp.Child("prefix"))...)
+	case backend.B2 != nil:
+		fp := fldPath.Child("b2")
+		allErrs = append(allErrs, validateB2Bucket(backend.B2.Bucket, fp.Child("bucket"))...)
+		allErrs = append(allErrs, validatePrefix(backend.B2.Prefix, fp.Child("prefix"))...)
+		allErrs = append(allErrs, validateMaxConnections(backend.B2.MaxConnections, fp.Child("maxConnections"))...)
+	case backend.Rest != nil:
+		fp := fldPath.Child("rest")
+		if backend.Rest.URL == "" {
+			allErrs = append(allErrs, field.Required(fp.Child("url"), ""))
+		} else {
+			allErrs = append(allErrs, validateURL(backend.Rest.URL, fp.Child("url"))...)
+		}
+	}
+	if backend.StorageSecret != nil {
+		allErrs = append(allErrs, validateStorageSecret(backend.StorageSecretName, backend.StorageSecret, fldPath.Child("storageSecret"))...)
+	}
+	if backend.TLS != nil {
+		if backend.Local != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("tls"), "may not be set for local backends"))
+		} else {
+			allErrs = append(allErrs, validateTLS(backend.TLS, fldPath.Child("tls"))...)
+		}
+	}
+	if backend.Proxy != nil {
+		if backend.Local != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("proxy"), "may not be set for local backends"))
+		} else {
+			allErrs = append(allErrs, validateProxy(backend.Proxy, fldPath.Child("proxy"))...)
+		}
+	}
+	if backend.RetryPolicy != nil {
+		if backend.Local != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("retryPolicy"), "may not be set for local backends"))
+		} else {
+			allErrs = append(allErrs, validateRetryPolicy(backend.RetryPolicy, fldPath.Child("retryPolicy"))...)
+		}
+	}
+	if backend.RateLimit != nil {
+		if backend.Local != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("rateLimit"), "may not be set for local backends"))
+		} else {
+			allErrs = append(allErrs, validateRateLimit(backend.RateLimit, fldPath.Child("rateLimit"))...)
+		}
+	}
+	return allErrs
+}
+
+// ValidateWithPath checks the backend of the object store and its check
+// interval. Local backends are rejected, the controller can not reach the
+// volumes of the workloads.
+func (spec ObjectStoreSpec) ValidateWithPath(fldPath *field.Path) field.ErrorList {
+	allErrs := spec.Backend.ValidateWithPath(fldPath.Child("backend"))
+	if spec.Backend.Local != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("backend", "local"), "local backends can not be checked"))
+	}
+	if spec.CheckInterval != nil && spec.CheckInterval.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("checkInterval"), spec.CheckInterval.Duration.String(), "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
+
+// Validate checks the primary and the mirrors, which must be distinct
+// backends, and the write quorum.
+func (rb ReplicatedBackend) Validate() field.ErrorList {
+	return rb.ValidateWithPath(nil)
+}
+
+// ValidateWithPath is Validate with field paths rooted at fldPath.
+func (rb ReplicatedBackend) ValidateWithPath(fldPath *field.Path) field.ErrorList {
+	allErrs := rb.Primary.ValidateWithPath(fldPath.Child("primary"))
+	if len(rb.Mirrors) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("mirrors"), "at least one mirror must be set"))
+	}
+	seen := map[string]bool{}
+	if u, err := rb.Primary.URL(); err == nil {
+		seen[u] = true
+	}
+	for i, mirror := range rb.Mirrors {
+		fp := fldPath.Child("mirrors").Index(i)
+		allErrs = append(allErrs, mirror.ValidateWithPath(fp)...)
+		if u, err := mirror.URL(); err == nil {
+			if seen[u] {
+				allErrs = append(allErrs, field.Duplicate(fp, u))
+			}
+			seen[u] = true
+		}
+	}
+	if n := len(rb.Mirrors) + 1; rb.WriteQuorum < 0 || int(rb.WriteQuorum) > n {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("writeQuorum"), rb.WriteQuorum, fmt.Sprintf("must be between 1 and %d", n)))
+	}
+	return allErrs
+}
+
+func validateRateLimit(spec *RateLimit, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.UploadBytesPerSecond < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("uploadBytesPerSecond"), spec.UploadBytesPerSecond, "must be greater than or equal to 0"))
+	}
+	if spec.DownloadBytesPerSecond < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("downloadBytesPerSecond"), spec.DownloadBytesPerSecond, "must be greater than or equal to 0"))
+	}
+	if spec.RequestsPerSecond < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("requestsPerSecond"), spec.RequestsPerSecond, "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
+
+func validateRetryPolicy(spec *RetryPolicy, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.MaxAttempts < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxAttempts"), spec.MaxAttempts, "must be greater than or equal to 0"))
+	}
+	for _, d := range []struct {
+		name  string
+		value *metav1.Duration
+	}{
+		{"baseBackoff", spec.BaseBackoff},
+		{"maxBackoff", spec.MaxBackoff},
+		{"attemptTimeout", spec.AttemptTimeout},
+		{"timeout", spec.Timeout},
+	} {
+		if d.value != nil && d.value.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(d.name), d.value.Duration.String(), "must be greater than or equal to 0"))
+		}
+	}
+	if spec.BaseBackoff != nil && spec.MaxBackoff != nil && spec.MaxBackoff.Duration > 0 && spec.BaseBackoff.Duration > spec.MaxBackoff.Duration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("baseBackoff"), spec.BaseBackoff.Duration.String(), "must not be greater than maxBackoff"))
+	}
+	classes := []RetryErrorClass{RetryOnThrottling, RetryOnServerError, RetryOnTimeout, RetryOnNetwork}
+	for i, c := range spec.RetryOn {
+		switch c {
+		case RetryOnThrottling, RetryOnServerError, RetryOnTimeout, RetryOnNetwork:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("retryOn").Index(i), c, classes))
+		}
+	}
+	return allErrs
+}
+
+func validateProxy(spec *ProxyConfig, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.URL == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("url"), ""))
+	} else {
+		allErrs = append(allErrs, validateURL(spec.URL, fldPath.Child("url"))...)
+		if u, err := url.Parse(spec.URL); err == nil && u.User != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), spec.URL,
+				fmt.Sprintf("must not include credentials, use the %s and %s keys of the storage secret", PROXY_USERNAME, PROXY_PASSWORD)))
+		}
+	}
+	for i, host := range spec.NoProxy {
+		// the osm config keeps the hosts as a comma separated list
+		if host == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("noProxy").Index(i), ""))
+		} else if strings.ContainsAny(host, ", ") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("noProxy").Index(i), host, "must not contain commas or spaces"))
+		}
+	}
+	return allErrs
+}
+
+func validateTLS(spec *TLSConfig, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.ServerName != "" {
+		allErrs = append(allErrs, invalid(spec.ServerName, fldPath.Child("serverName"), validation.IsDNS1123Subdomain(spec.ServerName))...)
+	}
+	switch spec.MinVersion {
+	case "", TLSVersion10, TLSVersion11, TLSVersion12, TLSVersion13:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("minVersion"), spec.MinVersion,
+			[]TLSVersion{TLSVersion10, TLSVersion11, TLSVersion12, TLSVersion13}))
+	}
+	return allErrs
+}
+
+func validateStorageSecret(name string, ref *StorageSecretReference, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch {
+	case ref.Name == "" && name == "" && len(ref.Sources) == 0:
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "either storageSecretName, storageSecret.name or storageSecret.sources must be set"))
+	case ref.Name != "" && name != "" && ref.Name != name:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), ref.Name, "must match storageSecretName"))
+	}
+	if ref.Namespace != "" {
+		allErrs = append(allErrs, invalid(ref.Namespace, fldPath.Child("namespace"), validation.IsDNS1123Label(ref.Namespace))...)
+	}
+	creds := make([]string, 0, len(ref.Keys))
+	for cred := range ref.Keys {
+		creds = append(creds, cred)
+	}
+	sort.Strings(creds)
+	for _, cred := range creds {
+		fp := fldPath.Child("keys").Key(cred)
+		allErrs = append(allErrs, invalid(cred, fp, validation.IsConfigMapKey(cred))...)
+		allErrs = append(allErrs, invalid(ref.Keys[cred], fp, validation.IsConfigMapKey(ref.Keys[cred]))...)
+	}
+	for i, src := range ref.Sources {
+		allErrs = append(allErrs, validateCredentialKeySource(src, fldPath.Child("sources").Index(i))...)
+	}
+	return allErrs
+}
+
+func validateCredentialKeySource(src CredentialKeySource, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if src.Key == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("key"), ""))
+	} else {
+		allErrs = append(allErrs, invalid(src.Key, fldPath.Child("key"), validation.IsConfigMapKey(src.Key))...)
+	}
+
+	var n int
+	if src.SecretKeyRef != nil {
+		n++
+		allErrs = append(allErrs, validateKeySelector(src.SecretKeyRef.Name, src.SecretKeyRef.Key, fldPath.Child("secretKeyRef"))...)
+	}
+	if src.ConfigMapKeyRef != nil {
+		n++
+		allErrs = append(allErrs, validateKeySelector(src.ConfigMapKeyRef.Name, src.ConfigMapKeyRef.Key, fldPath.Child("configMapKeyRef"))...)
+	}
+	if src.File != "" {
+		n++
+		if !path.IsAbs(src.File) || path.Clean(src.File) != src.File {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("file"), src.File, "must be a clean absolute path"))
+		}
+	}
+	if n != 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath, src.Key, "exactly one of secretKeyRef, configMapKeyRef or file must be set"))
+	}
+	return allErrs
+}
+
+func validateKeySelector(name, key string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), ""))
+	}
+	if key == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("key"), ""))
+	} else {
+		allErrs = append(allErrs, invalid(key, fldPath.Child("key"), validation.IsConfigMapKey(key))...)
+	}
+	return allErrs
+}
+
+func validateLocal(spec *LocalSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.MountPath == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("mountPath"), ""))
+	} else if !path.IsAbs(spec.MountPath) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("mountPath"), spec.MountPath, "must be an absolute path"))
+	}
+	// subPath is joined with mountPath, so a leading slash is harmless
+	if hasDotDot(spec.SubPath) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subPath"), spec.SubPath, "must not contain '..'"))
+	}
+	return allErrs
+}
+
+func validateS3(spec *S3Spec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateS3Bucket(spec.Bucket, fldPath.Child("bucket"))...)
+	allErrs = append(allErrs, validatePrefix(spec.Prefix, fldPath.Child("prefix"))...)
+	allErrs = append(allErrs, validateMaxConnections(spec.MaxConnections, fldPath.Child("maxConnections"))...)
+	// a bare AWS host is accepted, NewOSMContext treats it like an empty endpoint
+	if spec.Endpoint != "" && !(strings.HasSuffix(spec.Endpoint, ".amazonaws.com") && !strings.Contains(spec.Endpoint, "://")) {
+		allErrs = append(allErrs, validateURL(spec.Endpoint, fldPath.Child("endpoint"))...)
+	}
+	if spec.Encryption != nil {
+		allErrs = append(allErrs, validateS3Encryption(spec.Encryption, fldPath.Child("encryption"))...)
+	}
+	if spec.ACL != "" && !stringIn(spec.ACL, S3CannedACLs) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("acl"), spec.ACL, S3CannedACLs))
+	}
+	allErrs = append(allErrs, validateS3Tags(spec.Tags, fldPath.Child("tags"))...)
+	allErrs = append(allErrs, validateS3Addressing(spec, fldPath)...)
+	allErrs = append(allErrs, validateS3Role(spec, fldPath)...)
+	return allErrs
+}
+
+func validateS3Role(spec *S3Spec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.RoleARN == "" {
+		for _, f := range []struct {
+			name string
+			set  bool
+		}{
+			{"externalID", spec.ExternalID != ""},
+			{"roleSessionName", spec.RoleSessionName != ""},
+			{"webIdentityTokenFile", spec.WebIdentityTokenFile != ""},
+			{"stsEndpoint", spec.STSEndpoint != ""},
+		} {
+			if f.set {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child(f.name), "requires roleARN"))
+			}
+		}
+		return allErrs
+	}
+
+	// arn:partition:service:region:account-id:resource, S3 compatible services
+	// leave region and account empty
+	if parts := strings.SplitN(spec.RoleARN, ":", 6); len(parts) != 6 || parts[0] != "arn" || parts[1] == "" || parts[5] == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("roleARN"), spec.RoleARN, "must be an ARN of the form arn:partition:service:region:account-id:resource"))
+	}
+	if spec.ExternalID != "" && spec.WebIdentityTokenFile != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("externalID"), "may not be used together with webIdentityTokenFile"))
+	}
+	if spec.RoleSessionName != "" && !roleSessionNameRe.MatchString(spec.RoleSessionName) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("roleSessionName"), spec.RoleSessionName, "must be 2 to 64 characters of letters, digits and =,.@_-"))
+	}
+	if spec.WebIdentityTokenFile != "" && !path.IsAbs(spec.WebIdentityTokenFile) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("webIdentityTokenFile"), spec.WebIdentityTokenFile, "must be an absolute path"))
+	}
+	if spec.STSEndpoint != "" {
+		allErrs = append(allErrs, validateURL(spec.STSEndpoint, fldPath.Child("stsEndpoint"))...)
+	}
+	return allErrs
+}
+
+func validateS3Addressing(spec *S3Spec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch spec.AddressingStyle {
+	case "", S3AddressingStylePath, S3AddressingStyleVirtual:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("addressingStyle"), spec.AddressingStyle,
+			[]S3AddressingStyle{S3AddressingStylePath, S3AddressingStyleVirtual}))
+	}
+	switch spec.SignatureVersion {
+	case "", S3SignatureV4:
+	case S3SignatureV2:
+		if spec.AddressingStyle == S3AddressingStyleVirtual {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("addressingStyle"), "v2 signatures require path-style addressing"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("signatureVersion"), spec.SignatureVersion,
+			[]S3SignatureVersion{S3SignatureV2, S3SignatureV4}))
+	}
+
+	if !spec.IsAWS() {
+		for _, f := range []struct {
+			name string
+			set  bool
+		}{
+			{"dualStack", spec.DualStack},
+			{"fips", spec.FIPS},
+			{"accelerate", spec.Accelerate},
+		} {
+			if f.set {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child(f.name), "only supported by AWS endpoints"))
+			}
+		}
+	}
+	if spec.Accelerate {
+		if spec.UsePathStyle() {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("accelerate"), "requires virtual-hosted-style addressing"))
+		}
+		if spec.FIPS {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("accelerate"), "may not be used together with fips"))
+		}
+	}
+	return allErrs
+}
+
+// S3CannedACLs are the canned ACLs accepted by S3Spec.ACL
+//
+// ref: https://docs.aws.amazon.com/AmazonS3/latest/userguide/acl-overview.html#canned-acl
+var S3CannedACLs = []string{
+	"private",
+	"public-read",
+	"public-read-write",
+	"authenticated-read",
+	"aws-exec-read",
+	"bucket-owner-read",
+	"bucket-owner-full-control",
+}
+
+// https://docs.aws.amazon.com/AmazonS3/latest/userguide/object-tagging.html
+func validateS3Tags(tags map[string]string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(tags) > 10 {
+		allErrs = append(allErrs, field.TooMany(fldPath, len(tags), 10))
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := tags[k]
+		if k == "" || len(k) > 128 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(k), k, "tag keys must be between 1 and 128 characters"))
+		}
+		if len(v) > 256 {
+			allErrs = append(allErrs, field.TooLong(fldPath.Key(k), v, 256))
+		}
+	}
+	return allErrs
+}
+
+func stringIn(s string, list []string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func validateS3Encryption(enc *S3Encryption, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch enc.Type {
+	case S3EncryptionSSES3, S3EncryptionSSEKMS, S3EncryptionSSEC:
+	case "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("type"), ""))
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), enc.Type,
+			[]S3EncryptionType{S3EncryptionSSES3, S3EncryptionSSEKMS, S3EncryptionSSEC}))
+	}
+	if enc.Type != S3EncryptionSSEKMS {
+		if enc.KMSKeyID != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("kmsKeyID"), "may only be set for SSE-KMS"))
+		}
+		if len(enc.KMSEncryptionContext) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("kmsEncryptionContext"), "may only be set for SSE-KMS"))
+		}
+	}
+	return allErrs
+}
+
+// https://docs.aws.amazon.com/AmazonS3/latest/userguide/bucketnamingrules.html
+func validateS3Bucket(bucket string, fldPath *field.Path) field.ErrorList {
+	if bucket == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	var msgs []string
+	if len(bucket) < 3 || len(bucket) > 63 {
+		msgs = append(msgs, "must be between 3 and 63 characters")
+	}
+	if !s3BucketRegex.MatchString(bucket) {
+		msgs = append(msgs, "must consist of lower case letters, numbers, dots and hyphens, and start and end with a letter or number")
+	}
+	if strings.Contains(bucket, "..") {
+		msgs = append(msgs, "must not contain two adjacent periods")
+	}
+	if net.ParseIP(bucket) != nil {
+		msgs = append(msgs, "must not be formatted as an IP address")
+	}
+	if strings.HasPrefix(bucket, "xn--") || strings.HasPrefix(bucket, "sthree-") {
+		msgs = append(msgs, "must not start with xn-- or sthree-")
+	}
+	if strings.HasSuffix(bucket, "-s3alias") || strings.HasSuffix(bucket, "--ol-s3") {
+		msgs = append(msgs, "must not end with -s3alias or --ol-s3")
+	}
+	return invalid(bucket, fldPath, msgs)
+}
+
+// https://cloud.google.com/storage/docs/buckets#naming
+func validateGCSBucket(bucket string, fldPath *field.Path) field.ErrorList {
+	if bucket == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	var msgs []string
+	maxLen := 63
+	if strings.Contains(bucket, ".") {
+		maxLen = 222
+		for _, part := range strings.Split(bucket, ".") {
+			if len(part) > 63 {
+				msgs = append(msgs, "dot-separated components must be no longer than 63 characters")
+				break
+			}
+		}
+	}
+	if len(bucket) < 3 || len(bucket) > maxLen {
+		msgs = append(msgs, fmt.Sprintf("must be between 3 and %d characters", maxLen))
+	}
+	if !gcsBucketRegex.MatchString(bucket) {
+		msgs = append(msgs, "must consist of lower case letters, numbers, dots, hyphens and underscores, and start and end with a letter or number")
+	}
+	if net.ParseIP(bucket) != nil {
+		msgs = append(msgs, "must not be formatted as an IP address")
+	}
+	if strings.HasPrefix(bucket, "goog") || strings.Contains(bucket, "google") {
+		msgs = append(msgs, `must not start with "goog" or contain "google"`)
+	}
+	return invalid(bucket, fldPath, msgs)
+}
+
+func validateGCSCredentials(spec *GCSSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if sa := spec.ImpersonateServiceAccount; sa != "" {
+		if i := strings.Index(sa, "@"); i <= 0 || i == len(sa)-1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("impersonateServiceAccount"), sa, "must be the email of a service account"))
+		}
+	}
+	for i, scope := range spec.Scopes {
+		// the osm config keeps the scopes as a comma separated list
+		if scope == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("scopes").Index(i), ""))
+		} else if strings.ContainsAny(scope, ", ") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("scopes").Index(i), scope, "must not contain commas or spaces"))
+		}
+	}
+	return allErrs
+}
+
+// https://learn.microsoft.com/en-us/rest/api/storageservices/naming-and-referencing-containers--blobs--and-metadata#container-names
+func validateAzureContainer(container string, fldPath *field.Path) field.ErrorList {
+	if container == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	var msgs []string
+	if len(container) < 3 || len(container) > 63 {
+		msgs = append(msgs, "must be between 3 and 63 characters")
+	}
+	if !azureContainerRe.MatchString(container) {
+		msgs = append(msgs, "must consist of lower case letters, numbers and single hyphens, and start and end with a letter or number")
+	}
+	return invalid(container, fldPath, msgs)
+}
+
+// https://learn.microsoft.com/en-us/azure/storage/common/storage-account-overview#storage-account-name
+func validateAzureStorageAccount(account string, fldPath *field.Path) field.ErrorList {
+	if !azureAccountRe.MatchString(account) {
+		return field.ErrorList{field.Invalid(fldPath, account, "must be between 3 and 24 characters and consist of lower case letters and numbers")}
+	}
+	return nil
+}
+
+// https://docs.openstack.org/swift/latest/api/object_api_v1_overview.html
+func validateSwiftContainer(container string, fldPath *field.Path) field.ErrorList {
+	if container == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	var msgs []string
+	if len(container) > 256 {
+		msgs = append(msgs, "must be no more than 256 bytes")
+	}
+	if strings.Contains(container, "/") {
+		msgs = append(msgs, "must not contain '/'")
+	}
+	return invalid(container, fldPath, msgs)
+}
+
+// https://www.backblaze.com/docs/cloud-storage-buckets#bucket-names
+func validateB2Bucket(bucket string, fldPath *field.Path) field.ErrorList {
+	if bucket == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	var msgs []string
+	if len(bucket) < 6 || len(bucket) > 63 {
+		msgs = append(msgs, "must be between 6 and 63 characters")
+	}
+	if !b2BucketRegex.MatchString(bucket) {
+		msgs = append(msgs, "must consist of letters, numbers and hyphens")
+	}
+	if strings.HasPrefix(strings.ToLower(bucket), "b2-") {
+		msgs = append(msgs, "must not start with b2-")
+	}
+	return invalid(bucket, fldPath, msgs)
+}
+
+func validatePrefix(prefix string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if strings.HasPrefix(prefix, "/") {
+		allErrs = append(allErrs, field.Invalid(fldPath, prefix, "must be a relative path"))
+	}
+	if hasDotDot(prefix) {
+		allErrs = append(allErrs, field.Invalid(fldPath, prefix, "must not contain '..'"))
+	}
+	return allErrs
+}
+
+func hasDotDot(p string) bool {
+	for _, elem := range strings.Split(p, "/") {
+		if elem == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+func validateURL(s string, fldPath *field.Path) field.ErrorList {
+	u, err := url.Parse(s)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, s, err.Error())}
+	}
+	var allErrs field.ErrorList
+	if u.Scheme != "http" && u.Scheme != "https" {
+		allErrs = append(allErrs, field.NotSupported(fldPath, u.Scheme, []string{"http", "https"}))
+	}
+	if u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath, s, "must include a host"))
+	}
+	return allErrs
+}
+
+func validateMaxConnections(n int64, fldPath *field.Path) field.ErrorList {
+	if n < 0 {
+		return field.ErrorList{field.Invalid(fldPath, n, "must be greater than or equal to 0")}
+	}
+	return nil
+}
+
+func invalid(value string, fldPath *field.Path, msgs []string) field.ErrorList {
+	if len(msgs) == 0 {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(fldPath, value, strings.Join(msgs, ", "))}
+}
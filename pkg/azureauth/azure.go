@@ -0,0 +1,176 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package azureauth resolves the storage account, endpoint and credentials
+// of an Azure backend from its spec and storage secret, so that the blob
+// driver and the osm config authenticate the same way.
+package azureauth // import "kmodules.xyz/objectstore-api/pkg/azureauth"
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	azconst "kmodules.xyz/constants/azure"
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+)
+
+// DefaultDomain is the domain of the blob service in the public cloud
+const DefaultDomain = "blob.core.windows.net"
+
+// Config is the resolved authentication of an Azure backend. The account
+// key wins over the SAS token, and the token credential of a workload or
+// managed identity is used when there is neither.
+type Config struct {
+	AccountName string
+	AccountKey  string
+	// SASToken is the query string of a shared access signature, without
+	// the leading '?'
+	SASToken string
+	ClientID string
+	// Endpoint is the url of the blob service of the account
+	Endpoint string
+	// HTTPClient sends the requests of the container clients, eg. with the
+	// TLS settings of the backend. Defaults to the client of the sdk.
+	HTTPClient *http.Client
+	// DisableRetries turns the retries of the sdk off, for callers that
+	// retry the operations themselves.
+	DisableRetries bool
+}
+
+// ConfigFromSecret reads a Config from spec and secret data. The account
+// name of the secret wins over the one of the spec.
+func ConfigFromSecret(spec *api.AzureSpec, data map[string][]byte) (*Config, error) {
+	cfg := &Config{
+		AccountName: string(data[azconst.AZURE_ACCOUNT_NAME]),
+		AccountKey:  string(data[azconst.AZURE_ACCOUNT_KEY]),
+		SASToken:    strings.TrimPrefix(string(data[api.AZURE_ACCOUNT_SAS]), "?"),
+		ClientID:    spec.ClientID,
+		Endpoint:    strings.TrimSuffix(spec.Endpoint, "/"),
+	}
+	if cfg.AccountName == "" {
+		cfg.AccountName = spec.StorageAccount
+	}
+	if cfg.AccountName == "" {
+		return nil, fmt.Errorf("missing %s key in storage secret and storageAccount in azure spec", azconst.AZURE_ACCOUNT_NAME)
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://" + cfg.AccountName + "." + DefaultDomain
+	}
+	return cfg, nil
+}
+
+// NewContainerClient returns a client of a container of the blob service at
+// serviceURL.
+func (c *Config) NewContainerClient(serviceURL, containerName string) (*container.Client, error) {
+	containerURL, err := url.JoinPath(serviceURL, containerName)
+	if err != nil {
+		return nil, err
+	}
+	opts := &container.ClientOptions{}
+	if c.HTTPClient != nil {
+		opts.Transport = c.HTTPClient
+	}
+	if c.DisableRetries {
+		opts.Retry.MaxRetries = -1
+	}
+	switch {
+	case c.AccountKey != "":
+		cred, err := azblob.NewSharedKeyCredential(c.AccountName, c.AccountKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", azconst.AZURE_ACCOUNT_KEY, err)
+		}
+		return container.NewClientWithSharedKeyCredential(containerURL, cred, opts)
+	case c.SASToken != "":
+		return container.NewClientWithNoCredential(containerURL+"?"+c.SASToken, opts)
+	default:
+		cred, err := c.TokenCredential()
+		if err != nil {
+			return nil, err
+		}
+		// emulators are served over http
+		opts.InsecureAllowCredentialWithHTTP = strings.HasPrefix(containerURL, "http://")
+		return container.NewClient(containerURL, cred, opts)
+	}
+}
+
+// NewServiceClient returns a client of the blob service at the Endpoint. Its
+// container clients authenticate the same way.
+func (c *Config) NewServiceClient() (*service.Client, error) {
+	opts := &service.ClientOptions{}
+	if c.HTTPClient != nil {
+		opts.Transport = c.HTTPClient
+	}
+	if c.DisableRetries {
+		opts.Retry.MaxRetries = -1
+	}
+	switch {
+	case c.AccountKey != "":
+		cred, err := azblob.NewSharedKeyCredential(c.AccountName, c.AccountKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", azconst.AZURE_ACCOUNT_KEY, err)
+		}
+		return service.NewClientWithSharedKeyCredential(c.Endpoint, cred, opts)
+	case c.SASToken != "":
+		return service.NewClientWithNoCredential(c.Endpoint+"?"+c.SASToken, opts)
+	default:
+		cred, err := c.TokenCredential()
+		if err != nil {
+			return nil, err
+		}
+		opts.InsecureAllowCredentialWithHTTP = strings.HasPrefix(c.Endpoint, "http://")
+		return service.NewClient(c.Endpoint, cred, opts)
+	}
+}
+
+// TokenCredential returns the credential of the workload or managed identity
+// of the pod. Without a ClientID, the identity is picked by
+// azidentity.DefaultAzureCredential from the environment.
+func (c *Config) TokenCredential() (azcore.TokenCredential, error) {
+	if c.ClientID == "" {
+		return azidentity.NewDefaultAzureCredential(nil)
+	}
+	var creds []azcore.TokenCredential
+	// set by the workload identity webhook
+	if os.Getenv("AZURE_FEDERATED_TOKEN_FILE") != "" {
+		wi, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientID: c.ClientID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		creds = append(creds, wi)
+	}
+	mi, err := azidentity.NewManagedIdentityCredential(&azidentity.ManagedIdentityCredentialOptions{
+		ID: azidentity.ClientID(c.ClientID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	creds = append(creds, mi)
+	if len(creds) == 1 {
+		return creds[0], nil
+	}
+	return azidentity.NewChainedTokenCredential(creds, nil)
+}
@@ -0,0 +1,578 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package azuretest provides an in-memory stand-in for the Azure blob
+// service, addressed like the Azurite emulator, so that Azure backends can
+// be tested without network access. It also serves managed identity tokens
+// the way the App Service identity endpoint does.
+package azuretest // import "kmodules.xyz/objectstore-api/pkg/azuretest"
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AccountName and AccountKey are the well known credentials of Azurite
+	AccountName = "devstoreaccount1"
+	AccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+
+	// IdentityHeader is the secret the identity endpoint expects in the
+	// X-IDENTITY-HEADER header, like the IDENTITY_HEADER env of App Service
+	IdentityHeader = "identity-secret"
+	// IdentityPath is the path of the identity endpoint
+	IdentityPath = "/msi/token"
+)
+
+// Request is a request received by the Server.
+type Request struct {
+	Method    string
+	Container string
+	Blob      string
+	Query     url.Values
+	Header    http.Header
+}
+
+// Blob is a blob stored by the Server.
+type Blob struct {
+	Data        []byte
+	ContentType string
+	// Metadata holds the x-ms-meta-* headers, keyed by their lowercase
+	// name without the prefix
+	Metadata map[string]string
+	ModTime  time.Time
+}
+
+// Server is an Azure blob service stand-in backed by memory. Requests are
+// authorized by a valid shared key signature, the SAS token or a bearer token
+// issued by the identity endpoint.
+type Server struct {
+	*httptest.Server
+
+	// SASToken is accepted when all of its parameters are sent
+	SASToken string
+	// ClientID is the only managed identity the identity endpoint issues
+	// tokens for. Any identity is accepted when it is empty.
+	ClientID string
+
+	mu         sync.Mutex
+	containers map[string]map[string]*Blob
+	blocks     map[string][]byte
+	tokens     map[string]bool
+	requests   []Request
+}
+
+// NewServer starts a plain http Server.
+func NewServer() *Server {
+	s := &Server{
+		SASToken:   "sv=2022-11-02&ss=b&srt=co&sp=rwdlac&se=2099-01-01T00:00:00Z&sig=c2lnbmF0dXJl",
+		containers: map[string]map[string]*Blob{},
+		blocks:     map[string][]byte{},
+		tokens:     map[string]bool{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Endpoint returns the url of the blob service of AccountName.
+func (s *Server) Endpoint() string {
+	return s.URL + "/" + AccountName
+}
+
+// CreateContainer creates an empty container.
+func (s *Server) CreateContainer(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.containers[name]; !ok {
+		s.containers[name] = map[string]*Blob{}
+	}
+}
+
+// Blob returns a blob of a container.
+func (s *Server) Blob(container, name string) (*Blob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.containers[container][name]
+	return b, ok
+}
+
+// Names returns the sorted blob names of a container.
+func (s *Server) Names(container string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0)
+	for k := range s.containers[container] {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Requests returns the blob service requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == IdentityPath {
+		s.serveIdentity(w, r)
+		return
+	}
+
+	// /<account>/<container>/<blob>
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 3)
+	if parts[0] != AccountName {
+		writeError(w, http.StatusBadRequest, "InvalidUri")
+		return
+	}
+	req := Request{Method: r.Method, Query: r.URL.Query(), Header: r.Header.Clone()}
+	if len(parts) > 1 {
+		req.Container = parts[1]
+	}
+	if len(parts) == 3 {
+		req.Blob = parts[2]
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+
+	if !s.authorized(r) {
+		writeError(w, http.StatusForbidden, "AuthenticationFailed")
+		return
+	}
+
+	if req.Container == "" {
+		if r.Method == http.MethodGet && req.Query.Get("comp") == "list" {
+			s.listContainers(w, req.Query)
+			return
+		}
+		writeError(w, http.StatusNotImplemented, "NotImplemented")
+		return
+	}
+	if req.Blob == "" && req.Query.Get("restype") == "container" && req.Query.Get("comp") == "" {
+		s.serveContainer(w, r.Method, req.Container)
+		return
+	}
+
+	s.mu.Lock()
+	blobs, ok := s.containers[req.Container]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "ContainerNotFound")
+		return
+	}
+	if req.Blob == "" {
+		if r.Method == http.MethodGet && req.Query.Get("comp") == "list" {
+			s.list(w, req.Container, req.Query)
+			return
+		}
+		writeError(w, http.StatusNotImplemented, "NotImplemented")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		s.put(w, r, req)
+	case http.MethodGet, http.MethodHead:
+		s.mu.Lock()
+		b, ok := blobs[req.Blob]
+		s.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		writeBlob(w, r, b)
+	case http.MethodDelete:
+		s.mu.Lock()
+		_, ok := blobs[req.Blob]
+		delete(blobs, req.Blob)
+		s.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+// serveContainer handles Create Container, Delete Container and Get
+// Container Properties.
+func (s *Server) serveContainer(w http.ResponseWriter, method, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.containers[name]
+	switch {
+	case method == http.MethodPut && ok:
+		writeError(w, http.StatusConflict, "ContainerAlreadyExists")
+	case method == http.MethodPut:
+		s.containers[name] = map[string]*Blob{}
+		w.WriteHeader(http.StatusCreated)
+	case !ok:
+		writeError(w, http.StatusNotFound, "ContainerNotFound")
+	case method == http.MethodDelete:
+		delete(s.containers, name)
+		w.WriteHeader(http.StatusAccepted)
+	case method == http.MethodGet || method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+// put handles Put Blob, Put Block and Put Block List.
+func (s *Server) put(w http.ResponseWriter, r *http.Request, req Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidInput")
+		return
+	}
+	blockPrefix := req.Container + "/" + req.Blob + "/"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch req.Query.Get("comp") {
+	case "block":
+		s.blocks[blockPrefix+req.Query.Get("blockid")] = data
+		w.WriteHeader(http.StatusCreated)
+		return
+	case "blocklist":
+		var list struct {
+			Blocks []string `xml:",any"`
+		}
+		if err := xml.Unmarshal(data, &list); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidXmlDocument")
+			return
+		}
+		var content []byte
+		for _, id := range list.Blocks {
+			block, ok := s.blocks[blockPrefix+id]
+			if !ok {
+				writeError(w, http.StatusBadRequest, "InvalidBlockList")
+				return
+			}
+			content = append(content, block...)
+		}
+		for k := range s.blocks {
+			if strings.HasPrefix(k, blockPrefix) {
+				delete(s.blocks, k)
+			}
+		}
+		data = content
+	case "":
+		if r.Header.Get("X-Ms-Blob-Type") != "BlockBlob" {
+			writeError(w, http.StatusBadRequest, "InvalidBlobType")
+			return
+		}
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented")
+		return
+	}
+	b := &Blob{
+		Data:        data,
+		ContentType: r.Header.Get("X-Ms-Blob-Content-Type"),
+		Metadata:    map[string]string{},
+		ModTime:     time.Now().UTC().Truncate(time.Second),
+	}
+	if b.ContentType == "" {
+		b.ContentType = r.Header.Get("Content-Type")
+	}
+	for k, v := range r.Header {
+		if name, ok := strings.CutPrefix(strings.ToLower(k), "x-ms-meta-"); ok {
+			b.Metadata[name] = strings.Join(v, ",")
+		}
+	}
+	s.containers[req.Container][req.Blob] = b
+	w.Header().Set("ETag", b.etag())
+	w.Header().Set("Last-Modified", b.ModTime.Format(http.TimeFormat))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func writeBlob(w http.ResponseWriter, r *http.Request, b *Blob) {
+	data := b.Data
+	status := http.StatusOK
+	if rng := r.Header.Get("X-Ms-Range"); rng != "" {
+		var start, end int
+		n, _ := fmt.Sscanf(rng, "bytes=%d-%d", &start, &end)
+		if n < 2 || end >= len(data) {
+			end = len(data) - 1
+		}
+		if start > end {
+			writeError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange")
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		data = data[start : end+1]
+		status = http.StatusPartialContent
+	}
+	w.Header().Set("Content-Type", b.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("ETag", b.etag())
+	for k, v := range b.Metadata {
+		w.Header().Set("X-Ms-Meta-"+k, v)
+	}
+	w.Header().Set("Last-Modified", b.ModTime.Format(http.TimeFormat))
+	w.Header().Set("X-Ms-Creation-Time", b.ModTime.Format(http.TimeFormat))
+	w.Header().Set("X-Ms-Blob-Type", "BlockBlob")
+	w.WriteHeader(status)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(data)
+	}
+}
+
+func (b *Blob) etag() string {
+	return strconv.Quote(strconv.FormatInt(b.ModTime.UnixNano(), 16))
+}
+
+type enumerationResults struct {
+	XMLName    xml.Name     `xml:"EnumerationResults"`
+	Prefix     string       `xml:"Prefix"`
+	Delimiter  string       `xml:"Delimiter,omitempty"`
+	Blobs      []blobItem   `xml:"Blobs>Blob"`
+	Prefixes   []blobPrefix `xml:"Blobs>BlobPrefix"`
+	NextMarker string       `xml:"NextMarker,omitempty"`
+}
+
+type blobItem struct {
+	Name       string `xml:"Name"`
+	Properties struct {
+		LastModified  string `xml:"Last-Modified"`
+		Etag          string `xml:"Etag"`
+		ContentLength int    `xml:"Content-Length"`
+		ContentType   string `xml:"Content-Type"`
+		BlobType      string `xml:"BlobType"`
+	} `xml:"Properties"`
+}
+
+type blobPrefix struct {
+	Name string `xml:"Name"`
+}
+
+// list handles List Blobs. Markers are the name of the first blob of the
+// next page.
+func (s *Server) list(w http.ResponseWriter, container string, q url.Values) {
+	prefix, delimiter, marker := q.Get("prefix"), q.Get("delimiter"), q.Get("marker")
+	maxResults, _ := strconv.Atoi(q.Get("maxresults"))
+	if maxResults <= 0 {
+		maxResults = 5000
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0)
+	for name := range s.containers[container] {
+		if strings.HasPrefix(name, prefix) && name >= marker {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := enumerationResults{Prefix: prefix, Delimiter: delimiter}
+	seen := map[string]bool{}
+	for i, name := range names {
+		if len(out.Blobs)+len(out.Prefixes) == maxResults {
+			out.NextMarker = names[i]
+			break
+		}
+		if delimiter != "" {
+			if j := strings.Index(name[len(prefix):], delimiter); j >= 0 {
+				p := name[:len(prefix)+j+len(delimiter)]
+				if !seen[p] {
+					seen[p] = true
+					out.Prefixes = append(out.Prefixes, blobPrefix{Name: p})
+				}
+				continue
+			}
+		}
+		b := s.containers[container][name]
+		item := blobItem{Name: name}
+		item.Properties.LastModified = b.ModTime.Format(http.TimeFormat)
+		item.Properties.Etag = b.etag()
+		item.Properties.ContentLength = len(b.Data)
+		item.Properties.ContentType = b.ContentType
+		item.Properties.BlobType = "BlockBlob"
+		out.Blobs = append(out.Blobs, item)
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(out)
+}
+
+type containerEnumerationResults struct {
+	XMLName    xml.Name        `xml:"EnumerationResults"`
+	Prefix     string          `xml:"Prefix"`
+	Containers []containerItem `xml:"Containers>Container"`
+	NextMarker string          `xml:"NextMarker,omitempty"`
+}
+
+type containerItem struct {
+	Name       string `xml:"Name"`
+	Properties struct {
+		LastModified string `xml:"Last-Modified"`
+		Etag         string `xml:"Etag"`
+	} `xml:"Properties"`
+}
+
+// listContainers handles List Containers. Markers are the name of the first
+// container of the next page.
+func (s *Server) listContainers(w http.ResponseWriter, q url.Values) {
+	prefix, marker := q.Get("prefix"), q.Get("marker")
+	maxResults, _ := strconv.Atoi(q.Get("maxresults"))
+	if maxResults <= 0 {
+		maxResults = 5000
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0)
+	for name := range s.containers {
+		if strings.HasPrefix(name, prefix) && name >= marker {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := containerEnumerationResults{Prefix: prefix}
+	for i, name := range names {
+		if i == maxResults {
+			out.NextMarker = name
+			break
+		}
+		item := containerItem{Name: name}
+		item.Properties.LastModified = time.Unix(0, 0).UTC().Format(http.TimeFormat)
+		item.Properties.Etag = strconv.Quote("0")
+		out.Containers = append(out.Containers, item)
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(out)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "SharedKey "+AccountName+":"); ok {
+		return hmac.Equal([]byte(auth), []byte(signSharedKey(r)))
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.tokens[token]
+	}
+	sas, _ := url.ParseQuery(s.SASToken)
+	q := r.URL.Query()
+	for k := range sas {
+		if q.Get(k) != sas.Get(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// signSharedKey signs r with AccountKey.
+//
+// ref: https://learn.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func signSharedKey(r *http.Request) string {
+	contentLength := ""
+	if r.ContentLength > 0 {
+		contentLength = strconv.FormatInt(r.ContentLength, 10)
+	}
+	var headers []string
+	for k, v := range r.Header {
+		k = strings.ToLower(k)
+		if strings.HasPrefix(k, "x-ms-") {
+			headers = append(headers, k+":"+strings.Join(v, ","))
+		}
+	}
+	// the service ignores hyphens when sorting, which does not matter for
+	// the headers sent by the sdk
+	sort.Strings(headers)
+
+	resource := "/" + AccountName + r.URL.EscapedPath()
+	q := r.URL.Query()
+	params := make([]string, 0, len(q))
+	for k := range q {
+		params = append(params, k)
+	}
+	sort.Strings(params)
+	for _, k := range params {
+		values := q[k]
+		sort.Strings(values)
+		resource += "\n" + strings.ToLower(k) + ":" + strings.Join(values, ",")
+	}
+
+	stringToSign := strings.Join([]string{
+		r.Method,
+		r.Header.Get("Content-Encoding"),
+		r.Header.Get("Content-Language"),
+		contentLength,
+		r.Header.Get("Content-Md5"),
+		r.Header.Get("Content-Type"),
+		"",
+		r.Header.Get("If-Modified-Since"),
+		r.Header.Get("If-Match"),
+		r.Header.Get("If-None-Match"),
+		r.Header.Get("If-Unmodified-Since"),
+		r.Header.Get("Range"),
+		strings.Join(headers, "\n"),
+		resource,
+	}, "\n")
+	key, _ := base64.StdEncoding.DecodeString(AccountKey)
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// serveIdentity issues tokens like the App Service identity endpoint.
+//
+// ref: https://learn.microsoft.com/en-us/azure/app-service/overview-managed-identity#rest-endpoint-reference
+func (s *Server) serveIdentity(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Identity-Header") != IdentityHeader {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if s.ClientID != "" && r.URL.Query().Get("client_id") != s.ClientID {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	token := fmt.Sprintf("token-%d", len(s.tokens))
+	s.tokens[token] = true
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = fmt.Fprintf(w, `{"access_token":%q,"expires_on":"%d","resource":%q,"token_type":"Bearer"}`,
+		token, time.Now().Add(time.Hour).Unix(), r.URL.Query().Get("resource"))
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "%s<Error><Code>%s</Code><Message>%s</Message></Error>", xml.Header, code, code)
+}
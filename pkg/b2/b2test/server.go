@@ -0,0 +1,703 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package b2test provides an in-memory stand-in for the B2 native API so
+// that B2 backends can be tested without network access.
+package b2test // import "kmodules.xyz/objectstore-api/pkg/b2/b2test"
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const apiPrefix = "/b2api/v2/"
+
+// Server is a B2 api stand-in backed by memory. It implements the subset of
+// the api used by kmodules.xyz/objectstore-api/pkg/b2.
+type Server struct {
+	*httptest.Server
+
+	AccountID      string
+	ApplicationKey string
+	// PartSize is reported as both the recommended and the minimum part
+	// size so that tests can exercise large files with little data.
+	PartSize int64
+	// Latency is added to every request to make concurrency observable.
+	Latency time.Duration
+
+	inflight    atomic.Int64
+	maxInflight atomic.Int64
+
+	mu         sync.Mutex
+	seq        int
+	tokens     map[string]bool
+	buckets    map[string]*bucket // by id
+	largeFiles map[string]*largeFile
+}
+
+type bucket struct {
+	id, name, typ string
+	// versions of every file, newest last
+	files map[string][]*file
+}
+
+type file struct {
+	id          string
+	name        string
+	action      string
+	data        []byte
+	contentType string
+	info        map[string]string
+	timestamp   int64
+}
+
+type largeFile struct {
+	bucket *bucket
+	file   *file
+	parts  map[int][]byte
+}
+
+// NewServer starts a stand-in that accepts the given credentials.
+func NewServer(accountID, applicationKey string) *Server {
+	s := &Server{
+		AccountID:      accountID,
+		ApplicationKey: applicationKey,
+		PartSize:       100 * 1000 * 1000,
+		tokens:         map[string]bool{},
+		buckets:        map[string]*bucket{},
+		largeFiles:     map[string]*largeFile{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// CreateBucket adds an empty private bucket.
+func (s *Server) CreateBucket(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.createBucket(name, "allPrivate")
+}
+
+// ExpireTokens invalidates every token issued so far.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]bool{}
+}
+
+// Object returns the content of the latest version of a file.
+func (s *Server) Object(bucketName, name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.bucketByName(bucketName)
+	if b == nil {
+		return nil, false
+	}
+	f := b.latest(name)
+	if f == nil {
+		return nil, false
+	}
+	return f.data, true
+}
+
+// Keys returns the names of the visible files of a bucket in order.
+func (s *Server) Keys(bucketName string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.bucketByName(bucketName)
+	if b == nil {
+		return nil
+	}
+	return b.names()
+}
+
+// MaxConcurrentRequests returns the highest number of requests that were
+// served at the same time.
+func (s *Server) MaxConcurrentRequests() int {
+	return int(s.maxInflight.Load())
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	n := s.inflight.Add(1)
+	defer s.inflight.Add(-1)
+	for {
+		m := s.maxInflight.Load()
+		if n <= m || s.maxInflight.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	if s.Latency > 0 {
+		time.Sleep(s.Latency)
+	}
+
+	switch {
+	case r.URL.Path == apiPrefix+"b2_authorize_account":
+		s.authorizeAccount(w, r)
+	case strings.HasPrefix(r.URL.Path, apiPrefix):
+		if !s.checkToken(w, r) {
+			return
+		}
+		s.api(w, r, strings.TrimPrefix(r.URL.Path, apiPrefix))
+	case strings.HasPrefix(r.URL.Path, "/upload/"):
+		if !s.checkToken(w, r) {
+			return
+		}
+		s.upload(w, r, strings.TrimPrefix(r.URL.Path, "/upload/"))
+	case strings.HasPrefix(r.URL.Path, "/upload_part/"):
+		if !s.checkToken(w, r) {
+			return
+		}
+		s.uploadPart(w, r, strings.TrimPrefix(r.URL.Path, "/upload_part/"))
+	case strings.HasPrefix(r.URL.Path, "/file/"):
+		if !s.checkToken(w, r) {
+			return
+		}
+		s.download(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "not_found", r.URL.Path)
+	}
+}
+
+func (s *Server) authorizeAccount(w http.ResponseWriter, r *http.Request) {
+	id, key, ok := r.BasicAuth()
+	if !ok || id != s.AccountID || key != s.ApplicationKey {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "invalid application key")
+		return
+	}
+	s.mu.Lock()
+	token := s.nextID("token")
+	s.tokens[token] = true
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]any{
+		"accountId":               s.AccountID,
+		"authorizationToken":      token,
+		"apiUrl":                  s.URL,
+		"downloadUrl":             s.URL,
+		"recommendedPartSize":     s.PartSize,
+		"absoluteMinimumPartSize": s.PartSize,
+	})
+}
+
+func (s *Server) checkToken(w http.ResponseWriter, r *http.Request) bool {
+	s.mu.Lock()
+	ok := s.tokens[r.Header.Get("Authorization")]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "expired_auth_token", "authorization token has expired")
+	}
+	return ok
+}
+
+func (s *Server) api(w http.ResponseWriter, r *http.Request, method string) {
+	var in struct {
+		AccountID         string            `json:"accountId"`
+		BucketID          string            `json:"bucketId"`
+		BucketName        string            `json:"bucketName"`
+		BucketType        string            `json:"bucketType"`
+		FileID            string            `json:"fileId"`
+		FileName          string            `json:"fileName"`
+		ContentType       string            `json:"contentType"`
+		FileInfo          map[string]string `json:"fileInfo"`
+		Prefix            string            `json:"prefix"`
+		Delimiter         string            `json:"delimiter"`
+		StartFileName     string            `json:"startFileName"`
+		StartFileID       string            `json:"startFileId"`
+		MaxFileCount      int               `json:"maxFileCount"`
+		SourceFileID      string            `json:"sourceFileId"`
+		PartSHA1Array     []string          `json:"partSha1Array"`
+		MetadataDirective string            `json:"metadataDirective"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch method {
+	case "b2_list_buckets":
+		var out []map[string]any
+		for _, b := range s.sortedBuckets() {
+			if in.BucketName == "" || in.BucketName == b.name {
+				out = append(out, b.json())
+			}
+		}
+		writeJSON(w, map[string]any{"buckets": out})
+	case "b2_create_bucket":
+		if s.bucketByName(in.BucketName) != nil {
+			writeError(w, http.StatusBadRequest, "duplicate_bucket_name", "bucket name is already in use")
+			return
+		}
+		writeJSON(w, s.createBucket(in.BucketName, in.BucketType).json())
+	case "b2_delete_bucket":
+		b, ok := s.buckets[in.BucketID]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "bad_bucket_id", in.BucketID)
+			return
+		}
+		if len(b.files) > 0 {
+			writeError(w, http.StatusBadRequest, "cannot_delete_non_empty_bucket", b.name)
+			return
+		}
+		delete(s.buckets, in.BucketID)
+		writeJSON(w, b.json())
+	case "b2_get_upload_url":
+		if _, ok := s.buckets[in.BucketID]; !ok {
+			writeError(w, http.StatusBadRequest, "bad_bucket_id", in.BucketID)
+			return
+		}
+		writeJSON(w, map[string]any{
+			"bucketId":           in.BucketID,
+			"uploadUrl":          s.URL + "/upload/" + in.BucketID,
+			"authorizationToken": r.Header.Get("Authorization"),
+		})
+	case "b2_list_file_names":
+		b, ok := s.buckets[in.BucketID]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "bad_bucket_id", in.BucketID)
+			return
+		}
+		s.listFileNames(w, b, in.Prefix, in.Delimiter, in.StartFileName, in.MaxFileCount)
+	case "b2_list_file_versions":
+		b, ok := s.buckets[in.BucketID]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "bad_bucket_id", in.BucketID)
+			return
+		}
+		s.listFileVersions(w, b, in.Prefix, in.StartFileName, in.StartFileID, in.MaxFileCount)
+	case "b2_delete_file_version":
+		for _, b := range s.buckets {
+			versions := b.files[in.FileName]
+			for i, f := range versions {
+				if f.id == in.FileID {
+					b.files[in.FileName] = append(versions[:i:i], versions[i+1:]...)
+					if len(b.files[in.FileName]) == 0 {
+						delete(b.files, in.FileName)
+					}
+					writeJSON(w, map[string]any{"fileId": f.id, "fileName": f.name})
+					return
+				}
+			}
+		}
+		writeError(w, http.StatusBadRequest, "file_not_present", in.FileName)
+	case "b2_copy_file":
+		for _, b := range s.buckets {
+			for _, versions := range b.files {
+				for _, f := range versions {
+					if f.id == in.SourceFileID {
+						cp := *f
+						cp.id = s.nextID("file")
+						cp.name = in.FileName
+						cp.timestamp = time.Now().UnixMilli()
+						b.files[cp.name] = append(b.files[cp.name], &cp)
+						writeJSON(w, cp.json())
+						return
+					}
+				}
+			}
+		}
+		writeError(w, http.StatusNotFound, "not_found", in.SourceFileID)
+	case "b2_start_large_file":
+		b, ok := s.buckets[in.BucketID]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "bad_bucket_id", in.BucketID)
+			return
+		}
+		f := &file{
+			id:          s.nextID("file"),
+			name:        in.FileName,
+			action:      "start",
+			contentType: in.ContentType,
+			info:        in.FileInfo,
+			timestamp:   time.Now().UnixMilli(),
+		}
+		s.largeFiles[f.id] = &largeFile{bucket: b, file: f, parts: map[int][]byte{}}
+		writeJSON(w, f.json())
+	case "b2_get_upload_part_url":
+		if _, ok := s.largeFiles[in.FileID]; !ok {
+			writeError(w, http.StatusBadRequest, "bad_request", "no such large file "+in.FileID)
+			return
+		}
+		writeJSON(w, map[string]any{
+			"fileId":             in.FileID,
+			"uploadUrl":          s.URL + "/upload_part/" + in.FileID,
+			"authorizationToken": r.Header.Get("Authorization"),
+		})
+	case "b2_finish_large_file":
+		lf, ok := s.largeFiles[in.FileID]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "bad_request", "no such large file "+in.FileID)
+			return
+		}
+		if len(in.PartSHA1Array) < 2 || len(in.PartSHA1Array) != len(lf.parts) {
+			writeError(w, http.StatusBadRequest, "bad_request", "part sha1 array does not match uploaded parts")
+			return
+		}
+		var data []byte
+		for i, sum := range in.PartSHA1Array {
+			part, ok := lf.parts[i+1]
+			if !ok || sha1Hex(part) != sum {
+				writeError(w, http.StatusBadRequest, "bad_request", fmt.Sprintf("part %d does not match", i+1))
+				return
+			}
+			if i < len(in.PartSHA1Array)-1 && int64(len(part)) < s.PartSize {
+				writeError(w, http.StatusBadRequest, "bad_request", fmt.Sprintf("part %d is too small", i+1))
+				return
+			}
+			data = append(data, part...)
+		}
+		delete(s.largeFiles, in.FileID)
+		f := lf.file
+		f.action = "upload"
+		f.data = data
+		lf.bucket.files[f.name] = append(lf.bucket.files[f.name], f)
+		writeJSON(w, f.json())
+	case "b2_cancel_large_file":
+		lf, ok := s.largeFiles[in.FileID]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "bad_request", "no such large file "+in.FileID)
+			return
+		}
+		delete(s.largeFiles, in.FileID)
+		writeJSON(w, map[string]any{"fileId": lf.file.id, "fileName": lf.file.name})
+	default:
+		writeError(w, http.StatusNotFound, "not_found", method)
+	}
+}
+
+func (s *Server) listFileNames(w http.ResponseWriter, b *bucket, prefix, delimiter, start string, limit int) {
+	if limit <= 0 {
+		limit = 100
+	}
+	var out []map[string]any
+	next := ""
+	lastFolder := ""
+	for _, name := range b.names() {
+		if !strings.HasPrefix(name, prefix) || name < start {
+			continue
+		}
+		entry := b.latest(name).json()
+		if delimiter != "" {
+			if i := strings.Index(name[len(prefix):], delimiter); i >= 0 {
+				folder := name[:len(prefix)+i+len(delimiter)]
+				if folder == lastFolder || folder < start {
+					continue
+				}
+				lastFolder = folder
+				entry = map[string]any{"fileName": folder, "action": "folder", "fileId": nil}
+			}
+		}
+		if len(out) == limit {
+			next = entry["fileName"].(string)
+			break
+		}
+		out = append(out, entry)
+	}
+	resp := map[string]any{"files": out, "nextFileName": nil}
+	if next != "" {
+		resp["nextFileName"] = next
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) listFileVersions(w http.ResponseWriter, b *bucket, prefix, startName, startID string, limit int) {
+	if limit <= 0 {
+		limit = 100
+	}
+	var all []*file
+	for _, name := range b.allNames() {
+		if !strings.HasPrefix(name, prefix) || name < startName {
+			continue
+		}
+		versions := b.files[name]
+		// newest first
+		for i := len(versions) - 1; i >= 0; i-- {
+			all = append(all, versions[i])
+		}
+	}
+	if startID != "" {
+		for i, f := range all {
+			if f.id == startID {
+				all = all[i:]
+				break
+			}
+		}
+	}
+	resp := map[string]any{"files": []map[string]any{}, "nextFileName": nil, "nextFileId": nil}
+	var out []map[string]any
+	for i, f := range all {
+		if i == limit {
+			resp["nextFileName"] = f.name
+			resp["nextFileId"] = f.id
+			break
+		}
+		out = append(out, f.json())
+	}
+	if out != nil {
+		resp["files"] = out
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) upload(w http.ResponseWriter, r *http.Request, bucketID string) {
+	data, ok := readVerified(w, r)
+	if !ok {
+		return
+	}
+	name, err := url.PathUnescape(r.Header.Get("X-Bz-File-Name"))
+	if err != nil || name == "" {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid file name")
+		return
+	}
+	info := map[string]string{}
+	for k, v := range r.Header {
+		if strings.HasPrefix(k, "X-Bz-Info-") {
+			val, _ := url.PathUnescape(v[0])
+			info[strings.ToLower(strings.TrimPrefix(k, "X-Bz-Info-"))] = val
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[bucketID]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "bad_bucket_id", bucketID)
+		return
+	}
+	ct := r.Header.Get("Content-Type")
+	if ct == "b2/x-auto" {
+		ct = "application/octet-stream"
+	}
+	f := &file{
+		id:          s.nextID("file"),
+		name:        name,
+		action:      "upload",
+		data:        data,
+		contentType: ct,
+		info:        info,
+		timestamp:   time.Now().UnixMilli(),
+	}
+	b.files[name] = append(b.files[name], f)
+	writeJSON(w, f.json())
+}
+
+func (s *Server) uploadPart(w http.ResponseWriter, r *http.Request, fileID string) {
+	data, ok := readVerified(w, r)
+	if !ok {
+		return
+	}
+	n, err := strconv.Atoi(r.Header.Get("X-Bz-Part-Number"))
+	if err != nil || n < 1 || n > 10000 {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid part number")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lf, ok := s.largeFiles[fileID]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "bad_request", "no such large file "+fileID)
+		return
+	}
+	lf.parts[n] = data
+	writeJSON(w, map[string]any{
+		"fileId":        fileID,
+		"partNumber":    n,
+		"contentLength": len(data),
+		"contentSha1":   sha1Hex(data),
+	})
+}
+
+func (s *Server) download(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimPrefix(r.URL.EscapedPath(), "/file/")
+	i := strings.IndexByte(p, '/')
+	if i < 0 {
+		writeError(w, http.StatusBadRequest, "bad_request", "missing file name")
+		return
+	}
+	bucketName, err1 := url.PathUnescape(p[:i])
+	name, err2 := url.PathUnescape(p[i+1:])
+	if err1 != nil || err2 != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid file name")
+		return
+	}
+
+	s.mu.Lock()
+	var f *file
+	if b := s.bucketByName(bucketName); b != nil {
+		f = b.latest(name)
+	}
+	s.mu.Unlock()
+	if f == nil {
+		writeError(w, http.StatusNotFound, "not_found", "file not present: "+name)
+		return
+	}
+
+	h := w.Header()
+	h.Set("X-Bz-File-Id", f.id)
+	h.Set("X-Bz-File-Name", url.PathEscape(f.name))
+	h.Set("X-Bz-Content-Sha1", sha1Hex(f.data))
+	h.Set("X-Bz-Upload-Timestamp", strconv.FormatInt(f.timestamp, 10))
+	for k, v := range f.info {
+		h.Set("X-Bz-Info-"+k, url.PathEscape(v))
+	}
+	h.Set("Content-Type", f.contentType)
+	http.ServeContent(w, r, "", time.Time{}, strings.NewReader(string(f.data)))
+}
+
+func readVerified(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return nil, false
+	}
+	if sum := r.Header.Get("X-Bz-Content-Sha1"); sum != sha1Hex(data) {
+		writeError(w, http.StatusBadRequest, "bad_request", "sha1 checksum mismatch")
+		return nil, false
+	}
+	return data, true
+}
+
+func (s *Server) nextID(kind string) string {
+	s.seq++
+	return fmt.Sprintf("%s_%08d", kind, s.seq)
+}
+
+func (s *Server) createBucket(name, typ string) *bucket {
+	if typ == "" {
+		typ = "allPrivate"
+	}
+	b := &bucket{
+		id:    s.nextID("bucket"),
+		name:  name,
+		typ:   typ,
+		files: map[string][]*file{},
+	}
+	s.buckets[b.id] = b
+	return b
+}
+
+func (s *Server) bucketByName(name string) *bucket {
+	for _, b := range s.buckets {
+		if b.name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+func (s *Server) sortedBuckets() []*bucket {
+	out := make([]*bucket, 0, len(s.buckets))
+	for _, b := range s.buckets {
+		out = append(out, b)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+func (b *bucket) json() map[string]any {
+	return map[string]any{
+		"accountId":  "",
+		"bucketId":   b.id,
+		"bucketName": b.name,
+		"bucketType": b.typ,
+	}
+}
+
+// latest returns the newest version of name unless it is hidden.
+func (b *bucket) latest(name string) *file {
+	versions := b.files[name]
+	if len(versions) == 0 {
+		return nil
+	}
+	f := versions[len(versions)-1]
+	if f.action != "upload" {
+		return nil
+	}
+	return f
+}
+
+// names returns the sorted names of the visible files.
+func (b *bucket) names() []string {
+	var out []string
+	for name := range b.files {
+		if b.latest(name) != nil {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (b *bucket) allNames() []string {
+	out := make([]string, 0, len(b.files))
+	for name := range b.files {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (f *file) json() map[string]any {
+	info := f.info
+	if info == nil {
+		info = map[string]string{}
+	}
+	return map[string]any{
+		"fileId":          f.id,
+		"fileName":        f.name,
+		"action":          f.action,
+		"contentLength":   len(f.data),
+		"contentSha1":     sha1Hex(f.data),
+		"contentType":     f.contentType,
+		"fileInfo":        info,
+		"uploadTimestamp": f.timestamp,
+	}
+}
+
+func sha1Hex(data []byte) string {
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status":  status,
+		"code":    code,
+		"message": message,
+	})
+}
@@ -0,0 +1,418 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package b2
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	BucketTypePrivate = "allPrivate"
+	BucketTypePublic  = "allPublic"
+
+	// ActionUpload marks a file version that holds data, ActionFolder marks
+	// a common prefix returned when listing with a delimiter.
+	ActionUpload = "upload"
+	ActionFolder = "folder"
+	ActionHide   = "hide"
+	ActionStart  = "start"
+
+	autoContentType  = "b2/x-auto"
+	infoHeaderPrefix = "X-Bz-Info-"
+)
+
+// BucketInfo describes a bucket as returned by b2_list_buckets.
+type BucketInfo struct {
+	BucketID   string `json:"bucketId"`
+	BucketName string `json:"bucketName"`
+	BucketType string `json:"bucketType"`
+}
+
+// Bucket performs file operations on a single bucket.
+type Bucket struct {
+	c    *Client
+	info BucketInfo
+
+	mu         sync.Mutex
+	uploadURLs []*uploadURL
+}
+
+// FileInfo describes a file version.
+type FileInfo struct {
+	FileID          string            `json:"fileId"`
+	FileName        string            `json:"fileName"`
+	Action          string            `json:"action"`
+	ContentLength   int64             `json:"contentLength"`
+	ContentSHA1     string            `json:"contentSha1"`
+	ContentType     string            `json:"contentType"`
+	Info            map[string]string `json:"fileInfo"`
+	UploadTimestamp int64             `json:"uploadTimestamp"`
+}
+
+// ModTime returns the upload time of the file version.
+func (f *FileInfo) ModTime() time.Time {
+	return time.UnixMilli(f.UploadTimestamp)
+}
+
+// ListOptions controls b2_list_file_names.
+type ListOptions struct {
+	Prefix        string
+	Delimiter     string
+	StartFileName string
+	MaxFileCount  int
+}
+
+// ListResult is a page of files. NextFileName is empty on the last page.
+type ListResult struct {
+	Files        []FileInfo `json:"files"`
+	NextFileName string     `json:"nextFileName"`
+}
+
+// Bucket returns the named bucket. Lookups are cached for the lifetime of
+// the client.
+func (c *Client) Bucket(ctx context.Context, name string) (*Bucket, error) {
+	c.mu.Lock()
+	b, ok := c.buckets[name]
+	c.mu.Unlock()
+	if ok {
+		return b, nil
+	}
+
+	buckets, err := c.ListBuckets(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range buckets {
+		if b.Name() == name {
+			return b, nil
+		}
+	}
+	return nil, notFound("bucket %s does not exist", name)
+}
+
+// ListBuckets lists the buckets of the account. If name is not empty only
+// the bucket with that name is returned.
+func (c *Client) ListBuckets(ctx context.Context, name string) ([]*Bucket, error) {
+	in := struct {
+		AccountID  string `json:"accountId"`
+		BucketName string `json:"bucketName,omitempty"`
+	}{
+		AccountID:  c.authorization().AccountID,
+		BucketName: name,
+	}
+	var out struct {
+		Buckets []BucketInfo `json:"buckets"`
+	}
+	if err := c.call(ctx, "b2_list_buckets", in, &out); err != nil {
+		return nil, err
+	}
+	buckets := make([]*Bucket, 0, len(out.Buckets))
+	for _, info := range out.Buckets {
+		buckets = append(buckets, c.cacheBucket(info))
+	}
+	return buckets, nil
+}
+
+// CreateBucket creates a bucket of the given type.
+func (c *Client) CreateBucket(ctx context.Context, name, bucketType string) (*Bucket, error) {
+	if bucketType == "" {
+		bucketType = BucketTypePrivate
+	}
+	in := struct {
+		AccountID  string `json:"accountId"`
+		BucketName string `json:"bucketName"`
+		BucketType string `json:"bucketType"`
+	}{
+		AccountID:  c.authorization().AccountID,
+		BucketName: name,
+		BucketType: bucketType,
+	}
+	var out BucketInfo
+	if err := c.call(ctx, "b2_create_bucket", in, &out); err != nil {
+		return nil, err
+	}
+	return c.cacheBucket(out), nil
+}
+
+func (c *Client) cacheBucket(info BucketInfo) *Bucket {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if b, ok := c.buckets[info.BucketName]; ok && b.info.BucketID == info.BucketID {
+		return b
+	}
+	b := &Bucket{c: c, info: info}
+	c.buckets[info.BucketName] = b
+	return b
+}
+
+// Name returns the bucket name.
+func (b *Bucket) Name() string {
+	return b.info.BucketName
+}
+
+// ID returns the bucket id.
+func (b *Bucket) ID() string {
+	return b.info.BucketID
+}
+
+// Delete removes the bucket. The bucket must be empty.
+func (b *Bucket) Delete(ctx context.Context) error {
+	in := struct {
+		AccountID string `json:"accountId"`
+		BucketID  string `json:"bucketId"`
+	}{
+		AccountID: b.c.authorization().AccountID,
+		BucketID:  b.info.BucketID,
+	}
+	if err := b.c.call(ctx, "b2_delete_bucket", in, nil); err != nil {
+		return err
+	}
+	b.c.mu.Lock()
+	delete(b.c.buckets, b.info.BucketName)
+	b.c.mu.Unlock()
+	return nil
+}
+
+// ListFileNames lists the latest version of the files in the bucket in
+// lexicographical order.
+func (b *Bucket) ListFileNames(ctx context.Context, opts ListOptions) (*ListResult, error) {
+	in := struct {
+		BucketID      string `json:"bucketId"`
+		StartFileName string `json:"startFileName,omitempty"`
+		MaxFileCount  int    `json:"maxFileCount,omitempty"`
+		Prefix        string `json:"prefix,omitempty"`
+		Delimiter     string `json:"delimiter,omitempty"`
+	}{
+		BucketID:      b.info.BucketID,
+		StartFileName: opts.StartFileName,
+		MaxFileCount:  opts.MaxFileCount,
+		Prefix:        opts.Prefix,
+		Delimiter:     opts.Delimiter,
+	}
+	var out ListResult
+	if err := b.c.call(ctx, "b2_list_file_names", in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteFile removes every version of the named file.
+func (b *Bucket) DeleteFile(ctx context.Context, name string) error {
+	deleted := 0
+	startName, startID := name, ""
+	for {
+		in := struct {
+			BucketID      string `json:"bucketId"`
+			StartFileName string `json:"startFileName"`
+			StartFileID   string `json:"startFileId,omitempty"`
+			Prefix        string `json:"prefix"`
+			MaxFileCount  int    `json:"maxFileCount"`
+		}{
+			BucketID:      b.info.BucketID,
+			StartFileName: startName,
+			StartFileID:   startID,
+			Prefix:        name,
+			MaxFileCount:  100,
+		}
+		var out struct {
+			Files        []FileInfo `json:"files"`
+			NextFileName string     `json:"nextFileName"`
+			NextFileID   string     `json:"nextFileId"`
+		}
+		if err := b.c.call(ctx, "b2_list_file_versions", in, &out); err != nil {
+			return err
+		}
+		for _, f := range out.Files {
+			if f.FileName != name {
+				break
+			}
+			if f.Action == ActionStart {
+				// unfinished large files can only be cancelled
+				continue
+			}
+			if err := b.deleteFileVersion(ctx, f.FileName, f.FileID); err != nil {
+				return err
+			}
+			if f.Action == ActionUpload {
+				deleted++
+			}
+		}
+		if out.NextFileName != name {
+			break
+		}
+		startName, startID = out.NextFileName, out.NextFileID
+	}
+	if deleted == 0 {
+		return notFound("file %s does not exist in bucket %s", name, b.info.BucketName)
+	}
+	return nil
+}
+
+func (b *Bucket) deleteFileVersion(ctx context.Context, name, id string) error {
+	in := struct {
+		FileName string `json:"fileName"`
+		FileID   string `json:"fileId"`
+	}{
+		FileName: name,
+		FileID:   id,
+	}
+	return b.c.call(ctx, "b2_delete_file_version", in, nil)
+}
+
+// Copy copies the latest version of src to dst within the bucket.
+func (b *Bucket) Copy(ctx context.Context, dst, src string) (*FileInfo, error) {
+	info, err := b.Head(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	in := struct {
+		SourceFileID      string `json:"sourceFileId"`
+		FileName          string `json:"fileName"`
+		MetadataDirective string `json:"metadataDirective"`
+	}{
+		SourceFileID:      info.FileID,
+		FileName:          dst,
+		MetadataDirective: "COPY",
+	}
+	var out FileInfo
+	if err := b.c.call(ctx, "b2_copy_file", in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Head returns the latest version of the named file without its content.
+func (b *Bucket) Head(ctx context.Context, name string) (*FileInfo, error) {
+	resp, err := b.download(ctx, http.MethodHead, name, "")
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	return fileInfoFromHeader(resp), nil
+}
+
+// Download reads length bytes of the named file starting at offset. A
+// negative length reads until the end of the file. ContentLength of the
+// returned FileInfo is the size of the whole file.
+func (b *Bucket) Download(ctx context.Context, name string, offset, length int64) (io.ReadCloser, *FileInfo, error) {
+	if length == 0 {
+		info, err := b.Head(ctx, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return io.NopCloser(strings.NewReader("")), info, nil
+	}
+
+	var byteRange string
+	if offset > 0 && length < 0 {
+		byteRange = fmt.Sprintf("bytes=%d-", offset)
+	} else if length > 0 {
+		byteRange = fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	}
+	resp, err := b.download(ctx, http.MethodGet, name, byteRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Body, fileInfoFromHeader(resp), nil
+}
+
+func (b *Bucket) download(ctx context.Context, method, name, byteRange string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		auth := b.c.authorization()
+		u := fmt.Sprintf("%s/file/%s/%s", auth.DownloadURL, escapeName(b.info.BucketName), escapeName(name))
+		req, err := http.NewRequestWithContext(ctx, method, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", auth.AuthorizationToken)
+		if byteRange != "" {
+			req.Header.Set("Range", byteRange)
+		}
+		resp, err := b.c.hc.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+			return resp, nil
+		}
+		err = decodeError(resp)
+		_ = resp.Body.Close()
+		if attempt == 0 && isExpiredToken(err) {
+			if err := b.c.reauthorize(ctx, auth.AuthorizationToken); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return nil, err
+	}
+}
+
+func fileInfoFromHeader(resp *http.Response) *FileInfo {
+	h := resp.Header
+	info := &FileInfo{
+		FileID:        h.Get("X-Bz-File-Id"),
+		ContentSHA1:   h.Get("X-Bz-Content-Sha1"),
+		ContentType:   h.Get("Content-Type"),
+		ContentLength: resp.ContentLength,
+		Action:        ActionUpload,
+	}
+	if name, err := url.PathUnescape(h.Get("X-Bz-File-Name")); err == nil {
+		info.FileName = name
+	}
+	if ts, err := strconv.ParseInt(h.Get("X-Bz-Upload-Timestamp"), 10, 64); err == nil {
+		info.UploadTimestamp = ts
+	}
+	// Content-Range: bytes 0-9/100
+	if cr := h.Get("Content-Range"); cr != "" {
+		if i := strings.LastIndexByte(cr, '/'); i >= 0 {
+			if size, err := strconv.ParseInt(cr[i+1:], 10, 64); err == nil {
+				info.ContentLength = size
+			}
+		}
+	}
+	for k, v := range h {
+		if !strings.HasPrefix(k, infoHeaderPrefix) || len(v) == 0 {
+			continue
+		}
+		if info.Info == nil {
+			info.Info = map[string]string{}
+		}
+		val, err := url.PathUnescape(v[0])
+		if err != nil {
+			val = v[0]
+		}
+		info.Info[strings.ToLower(strings.TrimPrefix(k, infoHeaderPrefix))] = val
+	}
+	return info
+}
+
+// escapeName percent-encodes a file or bucket name, keeping "/" intact.
+func escapeName(name string) string {
+	parts := strings.Split(name, "/")
+	for i := range parts {
+		parts[i] = url.PathEscape(parts[i])
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,258 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package b2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultAuthURL is the endpoint used to authorize an account when
+	// Options.AuthURL is not set.
+	DefaultAuthURL = "https://api.backblazeb2.com"
+
+	apiPrefix = "/b2api/v2/"
+)
+
+// Options configures a Client.
+type Options struct {
+	// AccountID is the account ID or the application key ID.
+	AccountID string
+	// ApplicationKey is the secret part of the application key.
+	ApplicationKey string
+	// AuthURL overrides DefaultAuthURL.
+	AuthURL string
+	// MaxConnections limits the parts of a large file that are uploaded at
+	// the same time. Zero means defaultUploadConcurrency. The requests in
+	// flight are limited by the transport of HTTPClient.
+	MaxConnections int
+	// PartSize overrides the part size recommended by the server for
+	// large file uploads.
+	PartSize int64
+	// HTTPClient is used to send requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Client talks to the B2 native API on behalf of a single account.
+// It is safe for concurrent use.
+type Client struct {
+	opts Options
+	hc   *http.Client
+
+	mu      sync.Mutex
+	auth    authorization
+	buckets map[string]*Bucket
+}
+
+type authorization struct {
+	AccountID               string `json:"accountId"`
+	AuthorizationToken      string `json:"authorizationToken"`
+	APIURL                  string `json:"apiUrl"`
+	DownloadURL             string `json:"downloadUrl"`
+	RecommendedPartSize     int64  `json:"recommendedPartSize"`
+	AbsoluteMinimumPartSize int64  `json:"absoluteMinimumPartSize"`
+}
+
+// NewClient authorizes the account and returns a client for it.
+func NewClient(ctx context.Context, opts Options) (*Client, error) {
+	if opts.AccountID == "" || opts.ApplicationKey == "" {
+		return nil, errors.New("b2: account id and application key are required")
+	}
+	if opts.AuthURL == "" {
+		opts.AuthURL = DefaultAuthURL
+	}
+	c := &Client{
+		opts:    opts,
+		hc:      opts.HTTPClient,
+		buckets: map[string]*Bucket{},
+	}
+	if c.hc == nil {
+		c.hc = http.DefaultClient
+	}
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// MaxConnections returns the configured upload concurrency limit.
+func (c *Client) MaxConnections() int {
+	return c.opts.MaxConnections
+}
+
+func (c *Client) authorize(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.opts.AuthURL, "/")+apiPrefix+"b2_authorize_account", nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.opts.AccountID, c.opts.ApplicationKey)
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	var auth authorization
+	if err := decodeResponse(resp, &auth); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.auth = auth
+	c.mu.Unlock()
+	return nil
+}
+
+// reauthorize refreshes the account token unless another caller already
+// replaced the stale one.
+func (c *Client) reauthorize(ctx context.Context, stale string) error {
+	c.mu.Lock()
+	current := c.auth.AuthorizationToken
+	c.mu.Unlock()
+	if current != stale {
+		return nil
+	}
+	return c.authorize(ctx)
+}
+
+func (c *Client) authorization() authorization {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.auth
+}
+
+func (c *Client) partSize() int64 {
+	auth := c.authorization()
+	size := c.opts.PartSize
+	if size <= 0 {
+		size = auth.RecommendedPartSize
+	}
+	if size < auth.AbsoluteMinimumPartSize {
+		size = auth.AbsoluteMinimumPartSize
+	}
+	return size
+}
+
+// call invokes a JSON api method. The request is retried once with a fresh
+// account token if the current one has expired.
+func (c *Client) call(ctx context.Context, method string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	for attempt := 0; ; attempt++ {
+		auth := c.authorization()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, auth.APIURL+apiPrefix+method, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", auth.AuthorizationToken)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := c.hc.Do(req)
+		if err != nil {
+			return err
+		}
+		err = decodeResponse(resp, out)
+		if attempt == 0 && isExpiredToken(err) {
+			if err := c.reauthorize(ctx, auth.AuthorizationToken); err != nil {
+				return err
+			}
+			continue
+		}
+		return err
+	}
+}
+
+func decodeResponse(resp *http.Response, out any) error {
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodeError(resp)
+	}
+	if out == nil {
+		_, err := io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func decodeError(resp *http.Response) error {
+	e := &Error{}
+	if data, err := io.ReadAll(resp.Body); err == nil && len(data) > 0 {
+		_ = json.Unmarshal(data, e)
+	}
+	if e.Status == 0 {
+		e.Status = resp.StatusCode
+	}
+	if e.Code == "" {
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			e.Code = "not_found"
+		case http.StatusUnauthorized:
+			// HEAD responses carry no body, the token is the usual suspect.
+			e.Code = "expired_auth_token"
+		default:
+			e.Code = strings.ToLower(strings.ReplaceAll(http.StatusText(resp.StatusCode), " ", "_"))
+		}
+	}
+	return e
+}
+
+// Error is returned by the B2 api for failed requests.
+type Error struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("b2: %d %s", e.Status, e.Code)
+	}
+	return fmt.Sprintf("b2: %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// IsNotFound returns true if err reports a missing bucket or file.
+func IsNotFound(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.Status == http.StatusNotFound || e.Code == "not_found" || e.Code == "no_such_file" || e.Code == "file_not_present"
+}
+
+func isExpiredToken(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.Status == http.StatusUnauthorized && (e.Code == "expired_auth_token" || e.Code == "bad_auth_token")
+}
+
+func notFound(format string, args ...any) error {
+	return &Error{
+		Status:  http.StatusNotFound,
+		Code:    "not_found",
+		Message: fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package b2_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"kmodules.xyz/objectstore-api/pkg/b2"
+	"kmodules.xyz/objectstore-api/pkg/b2/b2test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newClient(t *testing.T, srv *b2test.Server, maxConnections int) *b2.Client {
+	t.Helper()
+	c, err := b2.NewClient(context.Background(), b2.Options{
+		AccountID:      srv.AccountID,
+		ApplicationKey: srv.ApplicationKey,
+		AuthURL:        srv.URL,
+		MaxConnections: maxConnections,
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return c
+}
+
+func TestInvalidCredentials(t *testing.T) {
+	srv := b2test.NewServer("id", "key")
+	defer srv.Close()
+
+	_, err := b2.NewClient(context.Background(), b2.Options{
+		AccountID:      "id",
+		ApplicationKey: "wrong",
+		AuthURL:        srv.URL,
+	})
+	assert.ErrorContains(t, err, "401 unauthorized")
+}
+
+func TestBucketNotFound(t *testing.T) {
+	srv := b2test.NewServer("id", "key")
+	defer srv.Close()
+
+	_, err := newClient(t, srv, 0).Bucket(context.Background(), "missing")
+	assert.True(t, b2.IsNotFound(err))
+}
+
+func TestLargeFileUpload(t *testing.T) {
+	srv := b2test.NewServer("id", "key")
+	defer srv.Close()
+	srv.PartSize = 1024
+	srv.Latency = 10 * time.Millisecond
+	srv.CreateBucket("bucket")
+
+	c := newClient(t, srv, 3)
+	ctx := context.Background()
+	b, err := c.Bucket(ctx, "bucket")
+	assert.Nil(t, err)
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1024) // 16 parts
+	w := b.NewWriter(ctx, "large", b2.UploadOptions{ContentType: "application/octet-stream"})
+	_, err = w.Write(data)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+
+	stored, ok := srv.Object("bucket", "large")
+	assert.True(t, ok)
+	assert.Equal(t, data, stored)
+	assert.LessOrEqual(t, srv.MaxConcurrentRequests(), 3)
+
+	r, info, err := b.Download(ctx, "large", 1000, 100)
+	assert.Nil(t, err)
+	got, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Nil(t, r.Close())
+	assert.Equal(t, data[1000:1100], got)
+	assert.Equal(t, int64(len(data)), info.ContentLength)
+}
+
+func TestListFileNamesPaging(t *testing.T) {
+	srv := b2test.NewServer("id", "key")
+	defer srv.Close()
+	srv.CreateBucket("bucket")
+
+	c := newClient(t, srv, 0)
+	ctx := context.Background()
+	b, err := c.Bucket(ctx, "bucket")
+	assert.Nil(t, err)
+	for i := 0; i < 5; i++ {
+		_, err := b.Upload(ctx, fmt.Sprintf("dir/file-%d", i), []byte("x"), b2.UploadOptions{})
+		assert.Nil(t, err)
+	}
+	_, err = b.Upload(ctx, "dir/sub/file", []byte("x"), b2.UploadOptions{})
+	assert.Nil(t, err)
+
+	var names []string
+	opts := b2.ListOptions{Prefix: "dir/", Delimiter: "/", MaxFileCount: 2}
+	for {
+		out, err := b.ListFileNames(ctx, opts)
+		if !assert.Nil(t, err) {
+			return
+		}
+		for _, f := range out.Files {
+			names = append(names, f.Action+":"+f.FileName)
+		}
+		if out.NextFileName == "" {
+			break
+		}
+		opts.StartFileName = out.NextFileName
+	}
+	assert.Equal(t, []string{
+		"upload:dir/file-0",
+		"upload:dir/file-1",
+		"upload:dir/file-2",
+		"upload:dir/file-3",
+		"upload:dir/file-4",
+		"folder:dir/sub/",
+	}, names)
+}
@@ -0,0 +1,23 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package b2 is a minimal client for the Backblaze B2 native API.
+// It covers the calls needed by the blob driver and the stow location:
+// account authorization, bucket lookup, listing, simple and large file
+// uploads, downloads by name and deletion of every version of a file.
+//
+// ref: https://www.backblaze.com/apidocs/introduction-to-the-b2-native-api
+package b2 // import "kmodules.xyz/objectstore-api/pkg/b2"
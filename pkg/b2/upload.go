@@ -0,0 +1,382 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package b2
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultUploadConcurrency is the number of parts a Writer uploads in
+// parallel when the client has no connection limit.
+const defaultUploadConcurrency = 4
+
+// UploadOptions sets the attributes of an uploaded file.
+type UploadOptions struct {
+	// ContentType of the file. B2 detects it from the file name when empty.
+	ContentType string
+	// Info holds custom metadata stored with the file.
+	Info map[string]string
+}
+
+type uploadURL struct {
+	UploadURL          string `json:"uploadUrl"`
+	AuthorizationToken string `json:"authorizationToken"`
+}
+
+// Upload stores data under name in a single request.
+func (b *Bucket) Upload(ctx context.Context, name string, data []byte, opts UploadOptions) (*FileInfo, error) {
+	var out FileInfo
+	err := b.withUploadURL(ctx, func(u *uploadURL) error {
+		req, err := newUploadRequest(ctx, u, data)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("X-Bz-File-Name", escapeName(name))
+		req.Header.Set("Content-Type", contentType(opts.ContentType))
+		for k, v := range opts.Info {
+			req.Header.Set(infoHeaderPrefix+k, url.PathEscape(v))
+		}
+		resp, err := b.c.hc.Do(req)
+		if err != nil {
+			return err
+		}
+		return decodeResponse(resp, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// withUploadURL runs fn with a pooled upload url. Upload urls can not be
+// shared by concurrent uploads, and B2 asks clients to get a new one when an
+// upload is rejected because the pod behind the url is busy or the token
+// expired.
+func (b *Bucket) withUploadURL(ctx context.Context, fn func(u *uploadURL) error) error {
+	for attempt := 0; ; attempt++ {
+		u, err := b.getUploadURL(ctx)
+		if err != nil {
+			return err
+		}
+		err = fn(u)
+		if err == nil {
+			b.putUploadURL(u)
+			return nil
+		}
+		if attempt == 0 && isRetryableUpload(err) {
+			continue
+		}
+		return err
+	}
+}
+
+func (b *Bucket) getUploadURL(ctx context.Context) (*uploadURL, error) {
+	b.mu.Lock()
+	if n := len(b.uploadURLs); n > 0 {
+		u := b.uploadURLs[n-1]
+		b.uploadURLs = b.uploadURLs[:n-1]
+		b.mu.Unlock()
+		return u, nil
+	}
+	b.mu.Unlock()
+
+	in := struct {
+		BucketID string `json:"bucketId"`
+	}{
+		BucketID: b.info.BucketID,
+	}
+	var out uploadURL
+	if err := b.c.call(ctx, "b2_get_upload_url", in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (b *Bucket) putUploadURL(u *uploadURL) {
+	b.mu.Lock()
+	b.uploadURLs = append(b.uploadURLs, u)
+	b.mu.Unlock()
+}
+
+func isRetryableUpload(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.Status == http.StatusUnauthorized || e.Status == http.StatusRequestTimeout || e.Status == http.StatusServiceUnavailable
+}
+
+func newUploadRequest(ctx context.Context, u *uploadURL, data []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.UploadURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum(data)
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Authorization", u.AuthorizationToken)
+	req.Header.Set("X-Bz-Content-Sha1", hex.EncodeToString(sum[:]))
+	return req, nil
+}
+
+func contentType(ct string) string {
+	if ct == "" {
+		return autoContentType
+	}
+	return ct
+}
+
+// Writer uploads a file of unknown size. Content that fits in a single part
+// is stored with one upload request, anything larger becomes a large file
+// whose parts are uploaded concurrently.
+type Writer struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	b      *Bucket
+	name   string
+	opts   UploadOptions
+
+	partSize int64
+	buf      []byte
+
+	fileID string
+	g      *errgroup.Group
+	gctx   context.Context
+	mu     sync.Mutex
+	sha1s  []string
+	parts  []*uploadURL
+	closed bool
+	info   *FileInfo
+}
+
+// NewWriter returns a Writer for name. Close must be called to finish the
+// upload.
+func (b *Bucket) NewWriter(ctx context.Context, name string, opts UploadOptions) *Writer {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Writer{
+		ctx:      ctx,
+		cancel:   cancel,
+		b:        b,
+		name:     name,
+		opts:     opts,
+		partSize: b.c.partSize(),
+	}
+}
+
+// Write buffers p and uploads every full part.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("b2: write on closed writer")
+	}
+	w.buf = append(w.buf, p...)
+	// keep at least one byte buffered so that Close always has a last part
+	for int64(len(w.buf)) > w.partSize {
+		if err := w.uploadPart(w.buf[:w.partSize]); err != nil {
+			return 0, err
+		}
+		w.buf = append([]byte(nil), w.buf[w.partSize:]...)
+	}
+	return len(p), nil
+}
+
+func (w *Writer) uploadPart(data []byte) error {
+	if w.fileID == "" {
+		if err := w.startLargeFile(); err != nil {
+			return err
+		}
+	}
+	if err := w.gctx.Err(); err != nil {
+		// a previous part failed
+		return w.g.Wait()
+	}
+
+	w.mu.Lock()
+	w.sha1s = append(w.sha1s, "")
+	partNumber := len(w.sha1s)
+	w.mu.Unlock()
+
+	w.g.Go(func() error {
+		sum, err := w.putPart(partNumber, data)
+		if err != nil {
+			return err
+		}
+		w.mu.Lock()
+		w.sha1s[partNumber-1] = sum
+		w.mu.Unlock()
+		return nil
+	})
+	return nil
+}
+
+func (w *Writer) startLargeFile() error {
+	in := struct {
+		BucketID    string            `json:"bucketId"`
+		FileName    string            `json:"fileName"`
+		ContentType string            `json:"contentType"`
+		FileInfo    map[string]string `json:"fileInfo,omitempty"`
+	}{
+		BucketID:    w.b.info.BucketID,
+		FileName:    w.name,
+		ContentType: contentType(w.opts.ContentType),
+		FileInfo:    w.opts.Info,
+	}
+	var out FileInfo
+	if err := w.b.c.call(w.ctx, "b2_start_large_file", in, &out); err != nil {
+		return err
+	}
+	w.fileID = out.FileID
+
+	limit := w.b.c.MaxConnections()
+	if limit <= 0 {
+		limit = defaultUploadConcurrency
+	}
+	w.g, w.gctx = errgroup.WithContext(w.ctx)
+	w.g.SetLimit(limit)
+	return nil
+}
+
+func (w *Writer) putPart(partNumber int, data []byte) (string, error) {
+	for attempt := 0; ; attempt++ {
+		u, err := w.getPartURL()
+		if err != nil {
+			return "", err
+		}
+		req, err := newUploadRequest(w.gctx, u, data)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("X-Bz-Part-Number", strconv.Itoa(partNumber))
+		resp, err := w.b.c.hc.Do(req)
+		if err != nil {
+			return "", err
+		}
+		var out struct {
+			ContentSHA1 string `json:"contentSha1"`
+		}
+		err = decodeResponse(resp, &out)
+		if err == nil {
+			w.mu.Lock()
+			w.parts = append(w.parts, u)
+			w.mu.Unlock()
+			return out.ContentSHA1, nil
+		}
+		if attempt == 0 && isRetryableUpload(err) {
+			continue
+		}
+		return "", err
+	}
+}
+
+func (w *Writer) getPartURL() (*uploadURL, error) {
+	w.mu.Lock()
+	if n := len(w.parts); n > 0 {
+		u := w.parts[n-1]
+		w.parts = w.parts[:n-1]
+		w.mu.Unlock()
+		return u, nil
+	}
+	w.mu.Unlock()
+
+	in := struct {
+		FileID string `json:"fileId"`
+	}{
+		FileID: w.fileID,
+	}
+	var out uploadURL
+	if err := w.b.c.call(w.gctx, "b2_get_upload_part_url", in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Close uploads the buffered content and finishes the file.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	defer w.cancel()
+
+	if w.fileID == "" {
+		info, err := w.b.Upload(w.ctx, w.name, w.buf, w.opts)
+		if err != nil {
+			return err
+		}
+		w.info = info
+		return nil
+	}
+
+	if err := w.uploadPart(w.buf); err != nil {
+		return w.cancelLargeFile(err)
+	}
+	if err := w.g.Wait(); err != nil {
+		return w.cancelLargeFile(err)
+	}
+	in := struct {
+		FileID        string   `json:"fileId"`
+		PartSHA1Array []string `json:"partSha1Array"`
+	}{
+		FileID:        w.fileID,
+		PartSHA1Array: w.sha1s,
+	}
+	var out FileInfo
+	if err := w.b.c.call(w.ctx, "b2_finish_large_file", in, &out); err != nil {
+		return w.cancelLargeFile(err)
+	}
+	w.info = &out
+	return nil
+}
+
+// Abort stops the upload and discards any uploaded part.
+func (w *Writer) Abort(err error) error {
+	w.closed = true
+	defer w.cancel()
+	if w.fileID == "" {
+		return err
+	}
+	if w.g != nil {
+		_ = w.g.Wait()
+	}
+	return w.cancelLargeFile(err)
+}
+
+func (w *Writer) cancelLargeFile(cause error) error {
+	in := struct {
+		FileID string `json:"fileId"`
+	}{
+		FileID: w.fileID,
+	}
+	// the caller's context may already be done
+	if err := w.b.c.call(context.WithoutCancel(w.ctx), "b2_cancel_large_file", in, nil); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+// Info returns the stored file after a successful Close.
+func (w *Writer) Info() *FileInfo {
+	return w.info
+}
@@ -0,0 +1,65 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob
+
+import (
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	aws2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// defaultSTSRegion is used to sign STS calls when the backend has no region,
+// the global STS endpoint lives in us-east-1.
+const defaultSTSRegion = "us-east-1"
+
+// assumeRoleProvider returns the credentials of the role of spec. The
+// credentials of cfg sign the AssumeRole calls, AssumeRoleWithWebIdentity
+// calls are not signed. The provider is cached, so the role is assumed again
+// shortly before the credentials expire.
+func assumeRoleProvider(cfg aws2.Config, spec *api.S3Spec) aws2.CredentialsProvider {
+	client := sts.NewFromConfig(cfg, func(o *sts.Options) {
+		// the base endpoint of cfg is the one of the bucket
+		o.BaseEndpoint = nil
+		if spec.STSEndpoint != "" {
+			o.BaseEndpoint = aws2.String(spec.STSEndpoint)
+		}
+		if o.Region == "" {
+			o.Region = defaultSTSRegion
+		}
+	})
+
+	var provider aws2.CredentialsProvider
+	if spec.WebIdentityTokenFile != "" {
+		provider = stscreds.NewWebIdentityRoleProvider(client, spec.RoleARN,
+			stscreds.IdentityTokenFile(spec.WebIdentityTokenFile),
+			func(o *stscreds.WebIdentityRoleOptions) {
+				o.RoleSessionName = spec.RoleSessionName
+			})
+	} else {
+		provider = stscreds.NewAssumeRoleProvider(client, spec.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			if spec.ExternalID != "" {
+				o.ExternalID = aws2.String(spec.ExternalID)
+			}
+			if spec.RoleSessionName != "" {
+				o.RoleSessionName = spec.RoleSessionName
+			}
+		})
+	}
+	return aws2.NewCredentialsCache(provider)
+}
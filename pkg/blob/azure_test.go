@@ -0,0 +1,170 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/azuretest"
+	"kmodules.xyz/objectstore-api/pkg/blob"
+
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtc "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const azureContainer = "stash"
+
+func newAzureServer(t *testing.T) *azuretest.Server {
+	srv := azuretest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.CreateContainer(azureContainer)
+	return srv
+}
+
+func newAzureStorage(t *testing.T, spec *api.AzureSpec, data map[string][]byte) (*blob.Blob, error) {
+	t.Helper()
+	var objs []rtc.Object
+	backend := &api.Backend{Azure: spec}
+	if data != nil {
+		objs = append(objs, &core.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "azure-secret", Namespace: "db"},
+			Data:       data,
+		})
+		backend.StorageSecretName = "azure-secret"
+	}
+	fakeClient, err := getFakeClient(objs...)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	spec.Container = azureContainer
+	return blob.NewBlob(context.Background(), fakeClient, "db", backend)
+}
+
+func TestAzureAuth(t *testing.T) {
+	srv := newAzureServer(t)
+	t.Setenv("IDENTITY_ENDPOINT", srv.URL+azuretest.IdentityPath)
+	t.Setenv("IDENTITY_HEADER", azuretest.IdentityHeader)
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+	srv.ClientID = "backup-identity"
+
+	cases := []struct {
+		name string
+		spec api.AzureSpec
+		data map[string][]byte
+		// prefix of the Authorization header of every request, none for sas
+		auth string
+	}{
+		{
+			name: "account key",
+			spec: api.AzureSpec{Endpoint: srv.Endpoint()},
+			data: map[string][]byte{
+				api.AZURE_ACCOUNT_NAME: []byte(azuretest.AccountName),
+				api.AZURE_ACCOUNT_KEY:  []byte(azuretest.AccountKey),
+			},
+			auth: "SharedKey " + azuretest.AccountName + ":",
+		},
+		{
+			name: "sas token",
+			spec: api.AzureSpec{Endpoint: srv.Endpoint()},
+			data: map[string][]byte{
+				api.AZURE_ACCOUNT_NAME: []byte(azuretest.AccountName),
+				api.AZURE_ACCOUNT_SAS:  []byte("?" + srv.SASToken),
+			},
+		},
+		{
+			name: "managed identity",
+			spec: api.AzureSpec{
+				Endpoint:       srv.Endpoint(),
+				StorageAccount: azuretest.AccountName,
+				ClientID:       "backup-identity",
+			},
+			auth: "Bearer ",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := tc.spec
+			storage, err := newAzureStorage(t, &spec, tc.data)
+			if !assert.Nil(t, err) {
+				return
+			}
+			seen := len(srv.Requests())
+			ctx := context.Background()
+			key := filepath.Join(testPath, tc.name, sampleFile)
+
+			assert.Nil(t, storage.Upload(ctx, key, []byte(sampleData), "text/plain"))
+			exists, err := storage.Exists(ctx, key)
+			assert.Nil(t, err)
+			assert.True(t, exists)
+			data, err := storage.Get(ctx, key)
+			if assert.Nil(t, err) {
+				assert.Equal(t, sampleData, string(data))
+			}
+			items, err := storage.List(ctx, filepath.Join(testPath, tc.name))
+			if assert.Nil(t, err) {
+				assert.Equal(t, [][]byte{[]byte(sampleData)}, items)
+			}
+			b, ok := srv.Blob(azureContainer, key)
+			if assert.True(t, ok) {
+				assert.Equal(t, "text/plain", b.ContentType)
+			}
+			assert.Nil(t, storage.Delete(ctx, key, false))
+			exists, err = storage.Exists(ctx, key)
+			assert.Nil(t, err)
+			assert.False(t, exists)
+
+			for _, req := range srv.Requests()[seen:] {
+				auth := req.Header.Get("Authorization")
+				if tc.auth == "" {
+					assert.Empty(t, auth)
+					assert.Equal(t, "c2lnbmF0dXJl", req.Query.Get("sig"))
+				} else {
+					assert.True(t, strings.HasPrefix(auth, tc.auth), auth)
+				}
+			}
+		})
+	}
+
+}
+
+func TestAzureWrongAccountKey(t *testing.T) {
+	srv := newAzureServer(t)
+	storage, err := newAzureStorage(t, &api.AzureSpec{Endpoint: srv.Endpoint()}, map[string][]byte{
+		api.AZURE_ACCOUNT_NAME: []byte(azuretest.AccountName),
+		api.AZURE_ACCOUNT_KEY:  []byte("d3Jvbmcga2V5"),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.NotNil(t, storage.Upload(context.Background(), sampleFile, []byte(sampleData), ""))
+	assert.Empty(t, srv.Names(azureContainer))
+}
+
+func TestAzureMissingAccount(t *testing.T) {
+	_, err := newAzureStorage(t, &api.AzureSpec{}, map[string][]byte{
+		api.AZURE_ACCOUNT_SAS: []byte("sig=abc"),
+	})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), api.AZURE_ACCOUNT_NAME)
+	}
+}
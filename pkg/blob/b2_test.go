@@ -0,0 +1,201 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/b2/b2test"
+	"kmodules.xyz/objectstore-api/pkg/blob"
+
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	b2AccountID  = "b2-account"
+	b2AccountKey = "b2-key"
+	b2Bucket     = "cold-backups"
+	b2SecretName = "b2-secret"
+)
+
+func newB2Storage(t *testing.T, srv *b2test.Server, transformFuncs ...func(bConfig *api.Backend)) *blob.Blob {
+	t.Helper()
+	secret := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b2SecretName,
+			Namespace: "db",
+		},
+		Data: map[string][]byte{
+			api.B2_ACCOUNT_ID:  []byte(b2AccountID),
+			api.B2_ACCOUNT_KEY: []byte(b2AccountKey),
+			api.B2_AUTH_URL:    []byte(srv.URL),
+		},
+	}
+	fakeClient, err := getFakeClient(secret)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	bConfig := &api.Backend{
+		StorageSecretName: b2SecretName,
+		B2: &api.B2Spec{
+			Bucket: b2Bucket,
+			Prefix: prefix,
+		},
+	}
+	for _, fn := range transformFuncs {
+		fn(bConfig)
+	}
+	storage, err := blob.NewBlob(context.Background(), fakeClient, "db", bConfig)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return storage
+}
+
+func newB2Server(t *testing.T) *b2test.Server {
+	srv := b2test.NewServer(b2AccountID, b2AccountKey)
+	t.Cleanup(srv.Close)
+	srv.CreateBucket(b2Bucket)
+	return srv
+}
+
+func TestB2UploadGetDelete(t *testing.T) {
+	srv := newB2Server(t)
+	storage := newB2Storage(t, srv)
+	ctx := context.Background()
+	key := filepath.Join(testPath, sampleFile)
+
+	exists, err := storage.Exists(ctx, key)
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	err = storage.Upload(ctx, key, []byte(sampleData), "text/plain")
+	assert.Nil(t, err)
+	data, ok := srv.Object(b2Bucket, prefix+"/"+key)
+	assert.True(t, ok, "object must be stored under the prefix")
+	assert.Equal(t, sampleData, string(data))
+
+	exists, err = storage.Exists(ctx, key)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	d, err := storage.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, sampleData, string(d))
+
+	err = storage.Delete(ctx, key, false)
+	assert.Nil(t, err)
+	_, err = storage.Get(ctx, key)
+	assert.True(t, isNotFound(err))
+	err = storage.Delete(ctx, key, false)
+	assert.True(t, isNotFound(err))
+}
+
+func TestB2ListAndDeleteDir(t *testing.T) {
+	srv := newB2Server(t)
+	storage := newB2Storage(t, srv)
+	ctx := context.Background()
+
+	paths := []string{"sample1.txt", "sample2.txt", "sample3.txt"}
+	for i, p := range paths {
+		assert.Nil(t, storage.Upload(ctx, filepath.Join(testPath, p), []byte(fmt.Sprintf("sample data %d", i+1)), ""))
+	}
+	assert.Nil(t, storage.Upload(ctx, filepath.Join(testPath, "snapshots", "s1", "meta.json"), []byte("{}"), ""))
+
+	objects, err := storage.List(ctx, testPath)
+	assert.Nil(t, err)
+	if assert.Len(t, objects, 4) {
+		for i := range paths {
+			assert.Equal(t, fmt.Sprintf("sample data %d", i+1), string(objects[i]))
+		}
+	}
+
+	dirs, err := storage.ListDirN(ctx, "", -1)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("data/"), []byte("data/snapshots/"), []byte("data/snapshots/s1/")}, dirs)
+
+	assert.Nil(t, storage.Delete(ctx, testPath, true))
+	assert.Empty(t, srv.Keys(b2Bucket))
+}
+
+func TestB2MaxConnections(t *testing.T) {
+	srv := newB2Server(t)
+	srv.Latency = 20 * time.Millisecond
+	storage := newB2Storage(t, srv, func(bConfig *api.Backend) {
+		bConfig.B2.MaxConnections = 2
+	})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- storage.Upload(ctx, filepath.Join(testPath, fmt.Sprintf("file-%d", i)), []byte(sampleData), "")
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.Nil(t, err)
+	}
+	assert.Len(t, srv.Keys(b2Bucket), 10)
+	assert.LessOrEqual(t, srv.MaxConcurrentRequests(), 2)
+}
+
+func TestB2ExpiredToken(t *testing.T) {
+	srv := newB2Server(t)
+	storage := newB2Storage(t, srv)
+	ctx := context.Background()
+	key := filepath.Join(testPath, sampleFile)
+
+	assert.Nil(t, storage.Upload(ctx, key, []byte(sampleData), ""))
+	srv.ExpireTokens()
+	d, err := storage.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, sampleData, string(d))
+	srv.ExpireTokens()
+	assert.Nil(t, storage.Upload(ctx, key, []byte("updated"), ""))
+}
+
+func TestB2MissingCredentials(t *testing.T) {
+	secret := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b2SecretName,
+			Namespace: "db",
+		},
+		Data: map[string][]byte{
+			api.B2_ACCOUNT_ID: []byte(b2AccountID),
+		},
+	}
+	fakeClient, err := getFakeClient(secret)
+	assert.Nil(t, err)
+	_, err = blob.NewBlob(context.Background(), fakeClient, "db", &api.Backend{
+		StorageSecretName: b2SecretName,
+		B2:                &api.B2Spec{Bucket: b2Bucket},
+	})
+	assert.EqualError(t, err, "storage secret db/b2-secret missing B2_ACCOUNT_KEY key")
+}
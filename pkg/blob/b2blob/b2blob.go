@@ -0,0 +1,301 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package b2blob provides a gocloud.dev/blob driver for Backblaze B2.
+package b2blob // import "kmodules.xyz/objectstore-api/pkg/blob/b2blob"
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"kmodules.xyz/objectstore-api/pkg/b2"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/driver"
+	"gocloud.dev/gcerrors"
+)
+
+const defaultPageSize = 1000
+
+// OpenBucket returns a *blob.Bucket for the named B2 bucket.
+func OpenBucket(ctx context.Context, client *b2.Client, bucketName string) (*blob.Bucket, error) {
+	if client == nil {
+		return nil, errors.New("b2blob.OpenBucket: client is required")
+	}
+	if bucketName == "" {
+		return nil, errors.New("b2blob.OpenBucket: bucketName is required")
+	}
+	b, err := client.Bucket(ctx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return blob.NewBucket(&bucket{b: b}), nil
+}
+
+type bucket struct {
+	b *b2.Bucket
+}
+
+func (b *bucket) ErrorCode(err error) gcerrors.ErrorCode {
+	if errors.Is(err, errNotImplemented) {
+		return gcerrors.Unimplemented
+	}
+	if b2.IsNotFound(err) {
+		return gcerrors.NotFound
+	}
+	var e *b2.Error
+	if errors.As(err, &e) {
+		switch e.Status {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return gcerrors.PermissionDenied
+		case http.StatusBadRequest:
+			return gcerrors.InvalidArgument
+		case http.StatusTooManyRequests:
+			return gcerrors.ResourceExhausted
+		}
+	}
+	return gcerrors.Unknown
+}
+
+func (b *bucket) As(i any) bool {
+	p, ok := i.(**b2.Bucket)
+	if !ok {
+		return false
+	}
+	*p = b.b
+	return true
+}
+
+func (b *bucket) ErrorAs(err error, i any) bool {
+	return errors.As(err, i)
+}
+
+func (b *bucket) Attributes(ctx context.Context, key string) (*driver.Attributes, error) {
+	info, err := b.b.Head(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return attributes(info), nil
+}
+
+func attributes(info *b2.FileInfo) *driver.Attributes {
+	attrs := &driver.Attributes{
+		ContentType: info.ContentType,
+		Metadata:    info.Info,
+		ModTime:     info.ModTime(),
+		Size:        info.ContentLength,
+		ETag:        info.FileID,
+		AsFunc: func(i any) bool {
+			p, ok := i.(*b2.FileInfo)
+			if !ok {
+				return false
+			}
+			*p = *info
+			return true
+		},
+	}
+	if attrs.Metadata == nil {
+		attrs.Metadata = map[string]string{}
+	}
+	return attrs
+}
+
+func (b *bucket) ListPaged(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	pageSize := opts.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	in := b2.ListOptions{
+		Prefix:        opts.Prefix,
+		Delimiter:     opts.Delimiter,
+		StartFileName: string(opts.PageToken),
+		MaxFileCount:  pageSize,
+	}
+	if opts.BeforeList != nil {
+		asFunc := func(i any) bool {
+			p, ok := i.(**b2.ListOptions)
+			if !ok {
+				return false
+			}
+			*p = &in
+			return true
+		}
+		if err := opts.BeforeList(asFunc); err != nil {
+			return nil, err
+		}
+	}
+	out, err := b.b.ListFileNames(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &driver.ListPage{}
+	if out.NextFileName != "" {
+		page.NextPageToken = []byte(out.NextFileName)
+	}
+	for i := range out.Files {
+		f := out.Files[i]
+		if f.Action == b2.ActionFolder {
+			page.Objects = append(page.Objects, &driver.ListObject{
+				Key:   f.FileName,
+				IsDir: true,
+			})
+			continue
+		}
+		obj := &driver.ListObject{
+			Key:     f.FileName,
+			ModTime: f.ModTime(),
+			Size:    f.ContentLength,
+			AsFunc: func(i any) bool {
+				p, ok := i.(*b2.FileInfo)
+				if !ok {
+					return false
+				}
+				*p = f
+				return true
+			},
+		}
+		page.Objects = append(page.Objects, obj)
+	}
+	return page, nil
+}
+
+func (b *bucket) NewRangeReader(ctx context.Context, key string, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
+	body, info, err := b.b.Download(ctx, key, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	if opts.BeforeRead != nil {
+		asFunc := func(i any) bool {
+			p, ok := i.(*b2.FileInfo)
+			if !ok {
+				return false
+			}
+			*p = *info
+			return true
+		}
+		if err := opts.BeforeRead(asFunc); err != nil {
+			_ = body.Close()
+			return nil, err
+		}
+	}
+	return &reader{
+		body: body,
+		info: info,
+		attrs: driver.ReaderAttributes{
+			ContentType: info.ContentType,
+			ModTime:     info.ModTime(),
+			Size:        info.ContentLength,
+		},
+	}, nil
+}
+
+type reader struct {
+	body  io.ReadCloser
+	info  *b2.FileInfo
+	attrs driver.ReaderAttributes
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	return r.body.Read(p)
+}
+
+func (r *reader) Close() error {
+	return r.body.Close()
+}
+
+func (r *reader) Attributes() *driver.ReaderAttributes {
+	return &r.attrs
+}
+
+func (r *reader) As(i any) bool {
+	p, ok := i.(*b2.FileInfo)
+	if !ok {
+		return false
+	}
+	*p = *r.info
+	return true
+}
+
+func (b *bucket) NewTypedWriter(ctx context.Context, key, contentType string, opts *driver.WriterOptions) (driver.Writer, error) {
+	uo := b2.UploadOptions{
+		ContentType: contentType,
+		Info:        map[string]string{},
+	}
+	for k, v := range opts.Metadata {
+		uo.Info[k] = v
+	}
+	if len(opts.ContentMD5) > 0 {
+		uo.Info["md5"] = hex.EncodeToString(opts.ContentMD5)
+	}
+	if opts.BeforeWrite != nil {
+		asFunc := func(i any) bool {
+			p, ok := i.(**b2.UploadOptions)
+			if !ok {
+				return false
+			}
+			*p = &uo
+			return true
+		}
+		if err := opts.BeforeWrite(asFunc); err != nil {
+			return nil, err
+		}
+	}
+	return &writer{ctx: ctx, w: b.b.NewWriter(ctx, key, uo)}, nil
+}
+
+type writer struct {
+	ctx context.Context
+	w   *b2.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	return w.w.Write(p)
+}
+
+func (w *writer) Close() error {
+	if err := w.ctx.Err(); err != nil {
+		return w.w.Abort(err)
+	}
+	return w.w.Close()
+}
+
+func (b *bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *driver.CopyOptions) error {
+	if opts.BeforeCopy != nil {
+		if err := opts.BeforeCopy(func(any) bool { return false }); err != nil {
+			return err
+		}
+	}
+	_, err := b.b.Copy(ctx, dstKey, srcKey)
+	return err
+}
+
+func (b *bucket) Delete(ctx context.Context, key string) error {
+	return b.b.DeleteFile(ctx, key)
+}
+
+func (b *bucket) SignedURL(ctx context.Context, key string, opts *driver.SignedURLOptions) (string, error) {
+	return "", errNotImplemented
+}
+
+func (b *bucket) Close() error {
+	return nil
+}
+
+var errNotImplemented = errors.New("b2blob: not implemented")
@@ -19,29 +19,51 @@ package blob
 import (
 	"context"
 	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"strings"
+	"sync"
 
+	osconst "kmodules.xyz/constants/openstack"
 	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/azureauth"
+	"kmodules.xyz/objectstore-api/pkg/b2"
+	"kmodules.xyz/objectstore-api/pkg/blob/b2blob"
+	"kmodules.xyz/objectstore-api/pkg/blob/restblob"
+	"kmodules.xyz/objectstore-api/pkg/blob/swiftblob"
+	"kmodules.xyz/objectstore-api/pkg/credsource"
+	"kmodules.xyz/objectstore-api/pkg/gcsauth"
+	"kmodules.xyz/objectstore-api/pkg/httpproxy"
+	"kmodules.xyz/objectstore-api/pkg/openstack"
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+	"kmodules.xyz/objectstore-api/pkg/rest"
+	"kmodules.xyz/objectstore-api/pkg/retry"
+	"kmodules.xyz/objectstore-api/pkg/s3auth"
+	"kmodules.xyz/objectstore-api/pkg/s3sse"
+	"kmodules.xyz/objectstore-api/pkg/tlsconfig"
 
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	aws2 "github.com/aws/aws-sdk-go-v2/aws"
 	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/ncw/swift"
 	"gocloud.dev/blob"
-	_ "gocloud.dev/blob/azureblob"
+	"gocloud.dev/blob/azureblob"
 	_ "gocloud.dev/blob/fileblob"
-	_ "gocloud.dev/blob/gcsblob"
+	"gocloud.dev/blob/gcsblob"
 	"gocloud.dev/blob/s3blob"
+	"gocloud.dev/gcp"
+	"golang.org/x/oauth2"
 	core "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -49,161 +71,497 @@ import (
 )
 
 const (
-	gcsPrefix                    = "gs://"
-	azurePrefix                  = "azblob://"
-	localPrefix                  = "file:///"
-	credentialsDir               = "/tmp/credentials"
-	azureStorageAccount          = "AZURE_STORAGE_ACCOUNT"
-	azureStorageKey              = "AZURE_ACCOUNT_KEY"
-	googleServiceAccountJsonKey  = "GOOGLE_SERVICE_ACCOUNT_JSON_KEY"
-	googleApplicationCredentials = "GOOGLE_APPLICATION_CREDENTIALS"
-	azureAccountKey              = "AZURE_ACCOUNT_KEY"
-	azureAccountName             = "AZURE_ACCOUNT_NAME"
-	caCertData                   = "CA_CERT_DATA"
-	awsAccessKeyId               = "AWS_ACCESS_KEY_ID"
-	awsSecretAccessKey           = "AWS_SECRET_ACCESS_KEY"
+	azurePrefix                 = "azblob://"
+	localPrefix                 = "file:///"
+	googleServiceAccountJsonKey = "GOOGLE_SERVICE_ACCOUNT_JSON_KEY"
+	awsAccessKeyId              = "AWS_ACCESS_KEY_ID"
+	awsSecretAccessKey          = "AWS_SECRET_ACCESS_KEY"
 )
 
+// Blob reads and writes the objects of a backend. The provider clients are
+// built with the credentials of the Blob, it never sets environment
+// variables, so Blobs of different tenants can be used side by side.
 type Blob struct {
 	prefix     string
 	storageURL string
 	secret     *core.Secret
 	bConfig    *api.Backend
+
+	// credsMu guards the credentials and the clients built from them. Buckets
+	// are opened under the read lock, the write lock swaps the credentials
+	// when they are rotated.
+	credsMu sync.RWMutex
+	// getter, namespace and credOpts resolve the credentials again when they
+	// are rotated
+	getter    credsource.Getter
+	namespace string
+	credOpts  []credsource.Option
+
+	// gcs clients cache their access token, so they are shared by every
+	// bucket opened by this Blob.
+	gcsMu     sync.Mutex
+	gcsClient *gcp.HTTPClient
+
+	// azure container clients cache the token of a workload or managed
+	// identity, so they are shared the same way.
+	azure       *azureauth.Config
+	azureMu     sync.Mutex
+	azureClient *container.Client
+
+	// the credentials of an assumed s3 role are cached until they expire,
+	// so the role is not assumed again by every bucket opened by this Blob.
+	s3RoleMu    sync.Mutex
+	s3RoleCreds aws2.CredentialsProvider
+
+	// s3 http clients hold the connection pool, so they are shared by every
+	// bucket opened by this Blob as well.
+	s3ClientMu sync.Mutex
+	s3Client   aws2.HTTPClient
+
+	// b2 clients hold an account token, so they are authorized once and
+	// shared by every bucket opened by this Blob.
+	b2Mu     sync.Mutex
+	b2Client *b2.Client
+
+	// swift connections cache their token and re-authenticate when it
+	// expires, so they are shared the same way.
+	swiftMu   sync.Mutex
+	swiftConn *swift.Connection
+
+	restClient *rest.Client
+
+	// transport holds the TLS, proxy and connection settings and the rate
+	// limits of the backend, nil when the defaults of the provider sdk are
+	// used.
+	transport *transportConfig
+
+	// sse is the server side encryption of s3 backends
+	sse *s3sse.Config
+
+	// replicas are the backends of a Blob made by NewReplicatedBlob, which
+	// delegates every operation to them.
+	replicas *replicas
 }
 
-func NewBlob(ctx context.Context, c client.Client, namespace string, bConfig *api.Backend) (*Blob, error) {
+// NewBlob returns a Blob of the backend. The credentials are resolved the same
+// way as by osm.NewOSMContext, opts allow reading them from other namespaces.
+// With credsource.WithSource, the credentials are read from the given source
+// and c may be nil. The Blob uses a copy of bConfig with its defaults applied.
+func NewBlob(ctx context.Context, c client.Client, namespace string, bConfig *api.Backend, opts ...credsource.Option) (*Blob, error) {
+	bConfig = bConfig.DeepCopy()
+	bConfig.SetDefaults()
 	provider, err := bConfig.Provider()
 	if err != nil {
 		return nil, err
 	}
-	var secret *core.Secret
-	if bConfig.StorageSecretName != "" {
-		secret, err = getStorageSecret(ctx, c, types.NamespacedName{
+	var g credsource.Getter
+	if c != nil {
+		g = credsource.NewClientGetter(c)
+	}
+	data, err := credsource.Resolve(ctx, g, namespace, *bConfig, opts...)
+	if err != nil {
+		return nil, err
+	}
+	b, err := newBlob(provider, credentialsSecret(namespace, bConfig, data), bConfig)
+	if err != nil {
+		return nil, err
+	}
+	b.getter, b.namespace, b.credOpts = g, namespace, opts
+	return b, nil
+}
+
+// credentialsSecret holds the resolved credentials, the providers read them
+// under their usual keys.
+func credentialsSecret(namespace string, bConfig *api.Backend, data map[string][]byte) *core.Secret {
+	if data == nil {
+		return nil
+	}
+	return &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{
 			Namespace: namespace,
-			Name:      bConfig.StorageSecretName,
-		})
-		if err != nil {
-			return nil, err
-		}
+			Name:      bConfig.SecretName(),
+		},
+		Data: data,
 	}
+}
 
+func newBlob(provider string, secret *core.Secret, bConfig *api.Backend) (*Blob, error) {
 	switch provider {
 	case api.ProviderS3:
-		return s3Blob(secret, bConfig), nil
+		return s3Blob(secret, bConfig)
 	case api.ProviderGCS:
 		return gcsBlob(secret, bConfig)
 	case api.ProviderAzure:
 		return azureBlob(secret, bConfig)
 	case api.ProviderLocal:
 		return localBlob(bConfig)
+	case api.ProviderB2:
+		return b2Blob(secret, bConfig)
+	case api.ProviderSwift:
+		return swiftBlob(secret, bConfig)
+	case api.ProviderRest:
+		return restBlob(secret, bConfig)
 	default:
 		return nil, fmt.Errorf("unknown provider: %s", provider)
 	}
 }
 
-func s3Blob(secret *core.Secret, bConfig *api.Backend) *Blob {
-	return &Blob{
-		secret:  secret,
-		bConfig: bConfig,
-		prefix:  bConfig.S3.Prefix,
+// Close releases the limiter that the Blob shares with the other Blobs and
+// the osm locations of its backend. The Blob must not be used afterwards.
+func (b *Blob) Close() error {
+	if b.replicas != nil {
+		for _, rb := range b.replicas.backends {
+			_ = rb.Close()
+		}
+		return nil
+	}
+	b.credsMu.Lock()
+	defer b.credsMu.Unlock()
+	b.transport.release()
+	return nil
+}
+
+// WatchCredentials keeps the credentials of the Blob in sync with the secrets
+// and config maps they are read from until ctx is done. Rotated credentials
+// are used by the operations that start afterwards. The client passed to
+// NewBlob must be able to watch, eg. one made by client.NewWithWatch.
+// onRotate may be nil.
+func (b *Blob) WatchCredentials(ctx context.Context, onRotate credsource.RotationHook) error {
+	if b.replicas != nil {
+		return b.replicas.watchCredentials(ctx, onRotate)
+	}
+	if b.getter == nil {
+		return fmt.Errorf("credentials can not be watched without a kubernetes client")
+	}
+	return credsource.Watch(ctx, b.getter, b.namespace, *b.bConfig, func(data map[string][]byte, err error) {
+		if err == nil {
+			err = b.setCredentials(data)
+		}
+		if onRotate != nil {
+			onRotate(err)
+		}
+	}, b.credOpts...)
+}
+
+// setCredentials replaces the credentials of the Blob and drops the clients
+// that were built with the previous ones.
+func (b *Blob) setCredentials(data map[string][]byte) error {
+	provider, err := b.bConfig.Provider()
+	if err != nil {
+		return err
+	}
+	secret := credentialsSecret(b.namespace, b.bConfig, data)
+	nb, err := newBlob(provider, secret, b.bConfig)
+	if err != nil {
+		return err
+	}
+	if provider == api.ProviderS3 {
+		// the s3 credentials are otherwise only checked when a bucket is opened
+		if _, err := s3Credentials(secret, b.bConfig.S3); err != nil {
+			nb.transport.release()
+			return err
+		}
+	}
+
+	b.credsMu.Lock()
+	defer b.credsMu.Unlock()
+	b.secret = nb.secret
+	b.storageURL = nb.storageURL
+	b.azure = nb.azure
+	b.restClient = nb.restClient
+	b.sse = nb.sse
+	// the clients of the calls in flight keep the limiter they hold
+	b.transport.release()
+	b.transport = nb.transport
+	b.gcsClient = nil
+	b.azureClient = nil
+	b.s3RoleCreds = nil
+	b.s3Client = nil
+	b.b2Client = nil
+	b.swiftConn = nil
+	return nil
+}
+
+func s3Blob(secret *core.Secret, bConfig *api.Backend) (*Blob, error) {
+	var data map[string][]byte
+	if secret != nil {
+		data = secret.Data
+	}
+	sse, err := s3sse.ConfigFromSpec(bConfig.S3.Encryption, data)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := backendTransport(secret, bConfig)
+	if err != nil {
+		return nil, err
 	}
+	return &Blob{
+		secret:    secret,
+		bConfig:   bConfig,
+		prefix:    bConfig.S3.Prefix,
+		sse:       sse,
+		transport: transport,
+	}, nil
 }
 
+// gcsBlob authenticates with the credential file of the storage secret or,
+// without one, with the application default credentials.
 func gcsBlob(secret *core.Secret, bConfig *api.Backend) (*Blob, error) {
 	if secret != nil {
-		if err := setGcsCredentialsToEnv(secret); err != nil {
-			return nil, err
+		if data, ok := secret.Data[googleServiceAccountJsonKey]; ok {
+			if _, err := gcsauth.CredentialsType(data); err != nil {
+				return nil, fmt.Errorf("storage secret %s/%s has invalid %s: %w", secret.Namespace, secret.Name, googleServiceAccountJsonKey, err)
+			}
 		}
 	}
+	transport, err := backendTransport(secret, bConfig)
+	if err != nil {
+		return nil, err
+	}
 	return &Blob{
-		bConfig:    bConfig,
-		prefix:     bConfig.GCS.Prefix,
-		storageURL: fmt.Sprintf("%s%s", gcsPrefix, bConfig.GCS.Bucket),
+		secret:    secret,
+		bConfig:   bConfig,
+		prefix:    bConfig.GCS.Prefix,
+		transport: transport,
 	}, nil
 }
 
+// azureBlob opens the container through an azblob:// url, but with the
+// credentials of this Blob instead of those of the environment.
 func azureBlob(secret *core.Secret, bConfig *api.Backend) (*Blob, error) {
+	var data map[string][]byte
 	if secret != nil {
-		if err := setAzureCredentialsToEnv(secret); err != nil {
-			return nil, err
-		}
+		data = secret.Data
+	}
+	cfg, err := azureauth.ConfigFromSecret(bConfig.Azure, data)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := backendTransport(secret, bConfig)
+	if err != nil {
+		return nil, err
+	}
+	cfg.HTTPClient = transport.client()
+	// the operations are retried by the Blob
+	cfg.DisableRetries = bConfig.RetryPolicy != nil
+	storageURL, err := azureBucketURL(cfg, bConfig.Azure.Container)
+	if err != nil {
+		transport.release()
+		return nil, err
 	}
 	return &Blob{
+		secret:     secret,
+		bConfig:    bConfig,
 		prefix:     bConfig.Azure.Prefix,
-		storageURL: fmt.Sprintf("%s%s", azurePrefix, bConfig.Azure.Container),
+		storageURL: storageURL,
+		azure:      cfg,
+		transport:  transport,
 	}, nil
 }
 
-func localBlob(bConfig *api.Backend) (*Blob, error) {
+// azureBucketURL expresses the endpoint of cfg with the query parameters of
+// azblob:// urls.
+func azureBucketURL(cfg *azureauth.Config, containerName string) (string, error) {
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid azure endpoint %q: %w", cfg.Endpoint, err)
+	}
+	q := url.Values{}
+	q.Set("protocol", u.Scheme)
+	q.Set("storage_account", cfg.AccountName)
+	p := strings.TrimSuffix(u.EscapedPath(), "/")
+	switch {
+	case strings.HasPrefix(u.Host, "127.0.0.1") || strings.HasPrefix(u.Host, "localhost"):
+		// emulators address the account in the path
+		q.Set("domain", u.Host+strings.TrimSuffix(p, "/"+cfg.AccountName))
+		q.Set("localemu", "true")
+	case p == "" && strings.HasPrefix(u.Host, cfg.AccountName+"."):
+		q.Set("domain", strings.TrimPrefix(u.Host, cfg.AccountName+"."))
+	default:
+		// any other endpoint is used as is
+		q.Set("domain", u.Host+p)
+		q.Set("cdn", "true")
+	}
+	return azurePrefix + containerName + "?" + q.Encode(), nil
+}
+
+func b2Blob(secret *core.Secret, bConfig *api.Backend) (*Blob, error) {
+	if secret == nil {
+		return nil, fmt.Errorf("storage secret is required for provider %s", api.ProviderB2)
+	}
+	for _, key := range []string{api.B2_ACCOUNT_ID, api.B2_ACCOUNT_KEY} {
+		if _, ok := secret.Data[key]; !ok {
+			return nil, fmt.Errorf("storage secret %s/%s missing %s key", secret.Namespace, secret.Name, key)
+		}
+	}
+	transport, err := backendTransport(secret, bConfig)
+	if err != nil {
+		return nil, err
+	}
 	return &Blob{
-		storageURL: fmt.Sprintf("%s%s?no_tmp_dir=true", localPrefix, bConfig.Local.MountPath),
+		secret:    secret,
+		bConfig:   bConfig,
+		prefix:    bConfig.B2.Prefix,
+		transport: transport,
 	}, nil
 }
 
-func getStorageSecret(ctx context.Context, c client.Client, nsName types.NamespacedName) (*core.Secret, error) {
-	secret := &core.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: nsName.Namespace,
-			Name:      nsName.Name,
-		},
+func swiftBlob(secret *core.Secret, bConfig *api.Backend) (*Blob, error) {
+	if secret == nil {
+		return nil, fmt.Errorf("storage secret is required for provider %s", api.ProviderSwift)
 	}
-	if err := c.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+	cfg := openstack.SwiftConfigFromSecret(secret.Data)
+	if !cfg.ManualAuth() && cfg.AuthURL == "" {
+		return nil, fmt.Errorf("storage secret %s/%s missing %s or %s/%s keys",
+			secret.Namespace, secret.Name, osconst.OS_AUTH_URL, osconst.OS_STORAGE_URL, osconst.OS_AUTH_TOKEN)
+	}
+	transport, err := backendTransport(secret, bConfig)
+	if err != nil {
 		return nil, err
 	}
-	return secret, nil
+	return &Blob{
+		secret:    secret,
+		bConfig:   bConfig,
+		prefix:    bConfig.Swift.Prefix,
+		transport: transport,
+	}, nil
 }
 
-func setGcsCredentialsToEnv(secret *core.Secret) error {
-	if val, ok := secret.Data[googleServiceAccountJsonKey]; !ok {
-		return fmt.Errorf("storage secret missing %s key", googleServiceAccountJsonKey)
-	} else {
-		filePath := path.Join(credentialsDir, googleServiceAccountJsonKey)
-		if err := writeDataIntoFile(filePath, val); err != nil {
-			return err
-		}
-		if err := os.Setenv(googleApplicationCredentials, filePath); err != nil {
-			return err
-		}
+// restBlob keeps the repository path in the client url, so no prefix is
+// added to the keys.
+func restBlob(secret *core.Secret, bConfig *api.Backend) (*Blob, error) {
+	transport, err := backendTransport(secret, bConfig)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	opts := rest.Options{
+		URL:        bConfig.Rest.URL,
+		HTTPClient: transport.client(),
+	}
+	if secret != nil {
+		opts.Username = string(secret.Data[api.REST_SERVER_USERNAME])
+		opts.Password = string(secret.Data[api.REST_SERVER_PASSWORD])
+	}
+	client, err := rest.NewClient(opts)
+	if err != nil {
+		transport.release()
+		return nil, err
+	}
+	return &Blob{
+		secret:     secret,
+		bConfig:    bConfig,
+		restClient: client,
+		transport:  transport,
+	}, nil
 }
 
-func setAzureCredentialsToEnv(secret *core.Secret) error {
-	if val, ok := secret.Data[azureAccountKey]; !ok {
-		return fmt.Errorf("storage secret missing %s key", azureAccountKey)
-	} else {
-		if err := os.Setenv(azureStorageKey, string(val)); err != nil {
-			return err
-		}
+// transportConfig holds the TLS and proxy settings of the connections to a
+// backend and the limiter of its traffic and of its MaxConnections. The
+// limiter is shared by the Blobs of the backend.
+type transportConfig struct {
+	tls     *tls.Config
+	proxy   func(*http.Request) (*url.URL, error)
+	limiter *ratelimit.Limiter
+
+	releaseOnce sync.Once
+}
+
+// backendTransport returns the transportConfig of the backend, nil when
+// neither its TLS, Proxy, MaxConnections and RateLimit settings nor the
+// storage secret configure one.
+func backendTransport(secret *core.Secret, bConfig *api.Backend) (*transportConfig, error) {
+	var data map[string][]byte
+	if secret != nil {
+		data = secret.Data
+	}
+	tlsCfg, err := tlsconfig.FromSecret(bConfig.TLS, data).Config()
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure tls, reason: %v", err)
+	}
+	proxy, err := httpproxy.FromSecret(bConfig.Proxy, data).Func()
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure proxy, reason: %v", err)
 	}
+	limiter := ratelimit.FromSpec(*bConfig).Limiter()
+	if tlsCfg == nil && proxy == nil && limiter == nil {
+		return nil, nil
+	}
+	return &transportConfig{tls: tlsCfg, proxy: proxy, limiter: limiter}, nil
+}
 
-	if val, ok := secret.Data[azureAccountName]; !ok {
-		return fmt.Errorf("storage secret missing %s key", azureAccountName)
-	} else {
-		if err := os.Setenv(azureStorageAccount, string(val)); err != nil {
-			return err
-		}
+// release releases the limiter of the transport, it is called once the Blob
+// that owns the transport is closed or replaced its credentials.
+func (c *transportConfig) release() {
+	if c != nil {
+		c.releaseOnce.Do(c.limiter.Release)
 	}
+}
 
-	return nil
+// apply sets the settings on tr. A nil transportConfig leaves tr as is.
+func (c *transportConfig) apply(tr *http.Transport) {
+	if c == nil {
+		return
+	}
+	if c.tls != nil {
+		tr.TLSClientConfig = c.tls
+	}
+	if c.proxy != nil {
+		tr.Proxy = c.proxy
+	}
+	c.limiter.SizePool(tr)
 }
 
-func writeDataIntoFile(filePath string, val []byte) error {
-	dir, _ := path.Split(filePath)
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		err = os.MkdirAll(dir, 0o777)
-		if err != nil {
-			return err
-		}
+// limits reports whether the requests of the transport are limited.
+func (c *transportConfig) limits() bool {
+	return c != nil && c.limiter != nil
+}
+
+// wrap applies the limits to rt.
+func (c *transportConfig) wrap(rt http.RoundTripper) http.RoundTripper {
+	return c.limiter.Transport(rt)
+}
+
+// roundTripper returns a clone of http.DefaultTransport with the settings and
+// the limits, or nil for the default transport of the provider sdk.
+func (c *transportConfig) roundTripper() http.RoundTripper {
+	if c == nil {
+		return nil
 	}
-	if err := os.WriteFile(filePath, val, 0o755); err != nil {
-		return err
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	c.apply(tr)
+	return c.wrap(tr)
+}
+
+// client returns a client with the settings, or nil for the default client
+// of the provider sdk.
+func (c *transportConfig) client() *http.Client {
+	if c == nil {
+		return nil
 	}
+	return &http.Client{Transport: c.roundTripper()}
+}
 
-	return nil
+func localBlob(bConfig *api.Backend) (*Blob, error) {
+	return &Blob{
+		storageURL: fmt.Sprintf("%s%s?no_tmp_dir=true", localPrefix, bConfig.Local.MountPath),
+		bConfig:    bConfig,
+	}, nil
 }
 
 func (b *Blob) Exists(ctx context.Context, filepath string) (bool, error) {
+	if b.replicas != nil {
+		return b.replicas.exists(ctx, filepath)
+	}
+	var exists bool
+	err := b.retry(ctx, func(ctx context.Context) error {
+		var err error
+		exists, err = b.exists(ctx, filepath)
+		return err
+	})
+	return exists, err
+}
+
+func (b *Blob) exists(ctx context.Context, filepath string) (bool, error) {
 	dir, filename := path.Split(filepath)
 	bucket, err := b.openBucket(ctx, dir)
 	if err != nil {
@@ -214,15 +572,39 @@ func (b *Blob) Exists(ctx context.Context, filepath string) (bool, error) {
 }
 
 func (b *Blob) Get(ctx context.Context, filepath string) ([]byte, error) {
+	var data []byte
+	if b.replicas != nil {
+		err := b.replicas.read(func(r *Blob) error {
+			var err error
+			data, err = r.Get(ctx, filepath)
+			return err
+		})
+		return data, err
+	}
+	err := b.retry(ctx, func(ctx context.Context) error {
+		var err error
+		data, err = b.get(ctx, filepath)
+		return err
+	})
+	return data, err
+}
+
+func (b *Blob) get(ctx context.Context, filepath string) ([]byte, error) {
+	data, _, err := b.read(ctx, filepath)
+	return data, err
+}
+
+// read returns the content and the content type of the object at filepath.
+func (b *Blob) read(ctx context.Context, filepath string) ([]byte, string, error) {
 	dir, fileName := path.Split(filepath)
 	bucket, err := b.openBucket(ctx, dir)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer closeBucket(ctx, bucket)
 	r, err := bucket.NewReader(ctx, fileName, nil)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer func(r *blob.Reader) {
 		closeErr := r.Close()
@@ -231,10 +613,40 @@ func (b *Blob) Get(ctx context.Context, filepath string) ([]byte, error) {
 			logger.Error(closeErr, "failed to close reader")
 		}
 	}(r)
-	return io.ReadAll(r)
+	data, err := io.ReadAll(r)
+	return data, r.ContentType(), err
+}
+
+// UploadOptions sets the attributes of an uploaded object. The s3 specific
+// fields override the defaults of the backend's S3Spec and are ignored by
+// other providers.
+type UploadOptions struct {
+	ContentType string
+	// StorageClass of the object, eg. STANDARD_IA or GLACIER_IR
+	StorageClass string
+	// ACL is the canned ACL of the object, eg. bucket-owner-full-control
+	ACL string
+	// Tags are merged into the tags of the backend, a tag set here wins
+	// over a backend tag with the same key.
+	Tags map[string]string
 }
 
 func (b *Blob) Upload(ctx context.Context, filepath string, data []byte, contentType string) error {
+	return b.UploadWithOptions(ctx, filepath, data, UploadOptions{ContentType: contentType})
+}
+
+func (b *Blob) UploadWithOptions(ctx context.Context, filepath string, data []byte, opts UploadOptions) error {
+	if b.replicas != nil {
+		return b.replicas.write(ctx, func(r *Blob) error {
+			return r.UploadWithOptions(ctx, filepath, data, opts)
+		})
+	}
+	return b.retry(ctx, func(ctx context.Context) error {
+		return b.upload(ctx, filepath, data, opts)
+	})
+}
+
+func (b *Blob) upload(ctx context.Context, filepath string, data []byte, opts UploadOptions) error {
 	dir, fileName := path.Split(filepath)
 	bucket, err := b.openBucket(ctx, dir)
 	if err != nil {
@@ -242,10 +654,7 @@ func (b *Blob) Upload(ctx context.Context, filepath string, data []byte, content
 	}
 	defer closeBucket(ctx, bucket)
 
-	w, err := bucket.NewWriter(ctx, fileName, &blob.WriterOptions{
-		ContentType:                 contentType,
-		DisableContentTypeDetection: true,
-	})
+	w, err := bucket.NewWriter(ctx, fileName, b.writerOptions(opts))
 	if err != nil {
 		return err
 	}
@@ -261,6 +670,17 @@ func (b *Blob) Upload(ctx context.Context, filepath string, data []byte, content
 }
 
 func (b *Blob) Debug(ctx context.Context, filepath string, data []byte, contentType string) error {
+	if b.replicas != nil {
+		return b.replicas.write(ctx, func(r *Blob) error {
+			return r.Debug(ctx, filepath, data, contentType)
+		})
+	}
+	return b.retry(ctx, func(ctx context.Context) error {
+		return b.debug(ctx, filepath, data, contentType)
+	})
+}
+
+func (b *Blob) debug(ctx context.Context, filepath string, data []byte, contentType string) error {
 	dir, fileName := path.Split(filepath)
 	bucket, err := b.openBucketWithDebug(ctx, dir, true)
 	if err != nil {
@@ -270,10 +690,7 @@ func (b *Blob) Debug(ctx context.Context, filepath string, data []byte, contentT
 	defer closeBucket(ctx, bucket)
 
 	klog.Infof("Uploading data to backend...")
-	w, err := bucket.NewWriter(ctx, fileName, &blob.WriterOptions{
-		ContentType:                 contentType,
-		DisableContentTypeDetection: true,
-	})
+	w, err := bucket.NewWriter(ctx, fileName, b.writerOptions(UploadOptions{ContentType: contentType}))
 	if err != nil {
 		return err
 	}
@@ -290,7 +707,27 @@ func (b *Blob) Debug(ctx context.Context, filepath string, data []byte, contentT
 	return bucket.Delete(ctx, fileName)
 }
 
+// List returns the content of the objects in dir. A failed listing or read
+// retries the whole listing.
 func (b *Blob) List(ctx context.Context, dir string) ([][]byte, error) {
+	var objects [][]byte
+	if b.replicas != nil {
+		err := b.replicas.read(func(r *Blob) error {
+			var err error
+			objects, err = r.List(ctx, dir)
+			return err
+		})
+		return objects, err
+	}
+	err := b.retry(ctx, func(ctx context.Context) error {
+		var err error
+		objects, err = b.list(ctx, dir)
+		return err
+	})
+	return objects, err
+}
+
+func (b *Blob) list(ctx context.Context, dir string) ([][]byte, error) {
 	bucket, err := b.openBucket(ctx, dir)
 	if err != nil {
 		return nil, err
@@ -308,7 +745,7 @@ func (b *Blob) List(ctx context.Context, dir string) ([][]byte, error) {
 		}
 		if checkIfObjectFile(obj) {
 			fName := path.Join(dir, obj.Key)
-			file, err := b.Get(ctx, fName)
+			file, err := b.get(ctx, fName)
 			if err != nil {
 				return nil, err
 			}
@@ -320,6 +757,24 @@ func (b *Blob) List(ctx context.Context, dir string) ([][]byte, error) {
 
 // ListDirN depth = 0 → immediate children only.
 func (b *Blob) ListDirN(ctx context.Context, dir string, depth ...int) ([][]byte, error) {
+	var dirs [][]byte
+	if b.replicas != nil {
+		err := b.replicas.read(func(r *Blob) error {
+			var err error
+			dirs, err = r.ListDirN(ctx, dir, depth...)
+			return err
+		})
+		return dirs, err
+	}
+	err := b.retry(ctx, func(ctx context.Context) error {
+		var err error
+		dirs, err = b.listDirN(ctx, dir, depth...)
+		return err
+	})
+	return dirs, err
+}
+
+func (b *Blob) listDirN(ctx context.Context, dir string, depth ...int) ([][]byte, error) {
 	bucket, err := b.openBucket(ctx, dir)
 	if err != nil {
 		return nil, err
@@ -370,7 +825,18 @@ func (b *Blob) ListDirN(ctx context.Context, dir string, depth ...int) ([][]byte
 	return dirs, nil
 }
 
+// Delete deletes the object at filepath, or every object in it when isDir. A
+// failed deletion of a dir lists the objects that are left again.
 func (b *Blob) Delete(ctx context.Context, filepath string, isDir bool) error {
+	if b.replicas != nil {
+		return b.replicas.delete(ctx, filepath, isDir)
+	}
+	return b.retry(ctx, func(ctx context.Context) error {
+		return b.delete(ctx, filepath, isDir)
+	})
+}
+
+func (b *Blob) delete(ctx context.Context, filepath string, isDir bool) error {
 	if isDir {
 		return b.deleteDir(ctx, filepath)
 	}
@@ -400,7 +866,7 @@ func (b *Blob) deleteDir(ctx context.Context, dir string) error {
 			return err
 		}
 		filePath := fmt.Sprintf("%s/%s", dir, obj.Key)
-		err = b.Delete(ctx, filePath, false)
+		err = b.delete(ctx, filePath, false)
 		if err != nil {
 			deleteErrs = append(deleteErrs, err)
 		}
@@ -408,6 +874,18 @@ func (b *Blob) deleteDir(ctx context.Context, dir string) error {
 	return errors.NewAggregate(deleteErrs)
 }
 
+// retry runs op with the retry policy of the backend, within its overall
+// timeout. Without a policy, op runs once.
+func (b *Blob) retry(ctx context.Context, op func(ctx context.Context) error) error {
+	var p *retry.Policy
+	if b.bConfig != nil {
+		p = retry.FromSpec(b.bConfig.RetryPolicy)
+	}
+	ctx, cancel := p.WithTimeout(ctx)
+	defer cancel()
+	return p.Do(ctx, op)
+}
+
 func checkIfObjectFile(obj *blob.ListObject) bool {
 	if !obj.IsDir && len(obj.Key) > 0 && obj.Key[len(obj.Key)-1] != '/' {
 		return true
@@ -420,6 +898,9 @@ func (b *Blob) openBucket(ctx context.Context, dir string) (*blob.Bucket, error)
 }
 
 func (b *Blob) openBucketWithDebug(ctx context.Context, dir string, debug bool) (*blob.Bucket, error) {
+	b.credsMu.RLock()
+	defer b.credsMu.RUnlock()
+
 	var bucket *blob.Bucket
 	var err error
 	provider, err := b.bConfig.Provider()
@@ -428,13 +909,58 @@ func (b *Blob) openBucketWithDebug(ctx context.Context, dir string, debug bool)
 	}
 
 	if provider == api.ProviderS3 {
-		cfg, err := b.getS3Config(ctx, debug)
+		client, err := b.newS3Client(ctx, debug)
+		if err != nil {
+			return nil, err
+		}
+		bucket, err = s3blob.OpenBucketV2(ctx, client, b.bConfig.S3.Bucket, nil)
+		if err != nil {
+			return nil, err
+		}
+	} else if provider == api.ProviderGCS {
+		client, err := b.getGCSClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		bucket, err = gcsblob.OpenBucket(ctx, client, b.bConfig.GCS.Bucket, nil)
+		if err != nil {
+			return nil, err
+		}
+		var sc *storage.Client
+		if b.bConfig.RetryPolicy != nil && bucket.As(&sc) {
+			// the operations are retried by the Blob
+			sc.SetRetry(storage.WithPolicy(storage.RetryNever))
+		}
+	} else if provider == api.ProviderAzure {
+		u, err := url.Parse(b.storageURL)
+		if err != nil {
+			return nil, err
+		}
+		opener := &azureblob.URLOpener{MakeClient: b.getAzureClient}
+		bucket, err = opener.OpenBucketURL(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+	} else if provider == api.ProviderB2 {
+		client, err := b.getB2Client(ctx)
+		if err != nil {
+			return nil, err
+		}
+		bucket, err = b2blob.OpenBucket(ctx, client, b.bConfig.B2.Bucket)
 		if err != nil {
 			return nil, err
 		}
-		bucket, err = s3blob.OpenBucketV2(ctx, s3.NewFromConfig(cfg, func(options *s3.Options) {
-			options.UsePathStyle = true
-		}), b.bConfig.S3.Bucket, nil)
+	} else if provider == api.ProviderSwift {
+		conn, err := b.getSwiftConnection()
+		if err != nil {
+			return nil, err
+		}
+		bucket, err = swiftblob.OpenBucket(ctx, conn, b.bConfig.Swift.Container)
+		if err != nil {
+			return nil, err
+		}
+	} else if provider == api.ProviderRest {
+		bucket, err = restblob.OpenBucket(ctx, b.restClient)
 		if err != nil {
 			return nil, err
 		}
@@ -460,15 +986,45 @@ func closeBucket(ctx context.Context, bucket *blob.Bucket) {
 	}
 }
 
+func (b *Blob) newS3Client(ctx context.Context, debug bool) (*s3.Client, error) {
+	cfg, err := b.getS3Config(ctx, debug)
+	if err != nil {
+		return nil, err
+	}
+	spec := b.bConfig.S3
+	return s3.NewFromConfig(cfg, func(options *s3.Options) {
+		options.UsePathStyle = spec.UsePathStyle()
+		options.UseAccelerate = spec.Accelerate
+		if spec.SignatureVersion == api.S3SignatureV2 {
+			// appliances that need v2 signatures predate the flexible checksums
+			options.RequestChecksumCalculation = aws2.RequestChecksumCalculationWhenRequired
+			options.ResponseChecksumValidation = aws2.ResponseChecksumValidationWhenRequired
+			options.APIOptions = append(options.APIOptions, useSigV2(options.Credentials))
+		}
+		if b.sse != nil {
+			options.APIOptions = append(options.APIOptions, addSSEMiddleware(b.sse))
+		}
+		options.APIOptions = append(options.APIOptions, addExpiredCredentialsMiddleware)
+	}), nil
+}
+
 func (b *Blob) getS3Config(ctx context.Context, debug bool) (aws2.Config, error) {
+	spec := b.bConfig.S3
 	var loadOptions []func(*config.LoadOptions) error
-	if b.secret != nil {
-		if b.bConfig.S3.Endpoint != "" {
-			loadOptions = append(loadOptions, config.WithBaseEndpoint(b.bConfig.S3.Endpoint))
-		}
+	// the sdk resolves the dual-stack, FIPS and accelerate endpoints of AWS
+	// itself and refuses to combine them with a custom endpoint
+	awsVariant := spec.IsAWS() && (spec.DualStack || spec.FIPS || spec.Accelerate)
+	if spec.Endpoint != "" && !awsVariant {
+		loadOptions = append(loadOptions, config.WithBaseEndpoint(spec.Endpoint))
+	}
+	if spec.Region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(spec.Region))
 	}
-	if b.bConfig.S3.Region != "" {
-		loadOptions = append(loadOptions, config.WithRegion(b.bConfig.S3.Region))
+	if spec.DualStack {
+		loadOptions = append(loadOptions, config.WithUseDualStackEndpoint(aws2.DualStackEndpointStateEnabled))
+	}
+	if spec.FIPS {
+		loadOptions = append(loadOptions, config.WithUseFIPSEndpoint(aws2.FIPSEndpointStateEnabled))
 	}
 
 	if debug {
@@ -476,54 +1032,202 @@ func (b *Blob) getS3Config(ctx context.Context, debug bool) (aws2.Config, error)
 			aws2.LogRetries|aws2.LogRequestWithBody|aws2.LogResponseWithBody))
 	}
 
-	if b.secret != nil {
-		id, ok := b.secret.Data[awsAccessKeyId]
-		if !ok {
-			return aws2.Config{}, fmt.Errorf("storage secret %s/%s missing %s key", b.secret.Namespace, b.secret.Name, awsAccessKeyId)
+	creds, err := s3Credentials(b.secret, spec)
+	if err != nil {
+		return aws2.Config{}, err
+	}
+	// without access keys or profile files, the default credentials of the
+	// environment are used, eg. the iam role of the node or of the service account
+	switch {
+	case creds.HasAccessKeys():
+		loadOptions = append(loadOptions, config.WithCredentialsProvider(&expiringProvider{
+			Credentials: aws2.Credentials{
+				AccessKeyID:     creds.AccessKeyID,
+				SecretAccessKey: creds.SecretAccessKey,
+				SessionToken:    creds.SessionToken,
+				Source:          credentials.StaticCredentialsName,
+				CanExpire:       !creds.Expires.IsZero(),
+				Expires:         creds.Expires,
+			},
+		}))
+	case creds.HasSharedFiles():
+		credentialsFile, configFile, dir, err := creds.WriteSharedFiles()
+		if err != nil {
+			return aws2.Config{}, err
 		}
-		key, ok := b.secret.Data[awsSecretAccessKey]
-		if !ok {
-			return aws2.Config{}, fmt.Errorf("storage Secret %s/%s missing %s key", b.secret.Namespace, b.secret.Name, awsSecretAccessKey)
+		// the files are read while the config is loaded
+		defer os.RemoveAll(dir) // nolint:errcheck
+		loadOptions = append(loadOptions,
+			config.WithSharedCredentialsFiles([]string{credentialsFile}),
+			config.WithSharedConfigFiles([]string{configFile}),
+		)
+		if creds.Profile != "" {
+			loadOptions = append(loadOptions, config.WithSharedConfigProfile(creds.Profile))
 		}
+	}
 
-		loadOptions = append(loadOptions, config.WithCredentialsProvider(
-			credentials.NewStaticCredentialsProvider(string(id), string(key), ""),
-		))
+	if spec.InsecureTLS || b.transport != nil {
+		loadOptions = append(loadOptions, config.WithHTTPClient(configureTransport(b.transport, spec.InsecureTLS)))
+	}
+	if b.bConfig.RetryPolicy != nil {
+		// the operations are retried by the Blob
+		loadOptions = append(loadOptions, config.WithRetryer(func() aws2.Retryer {
+			return aws2.NopRetryer{}
+		}))
+	}
 
-		needsTLS := b.bConfig.S3.InsecureTLS || len(b.secret.Data[caCertData]) > 0
-		if needsTLS {
-			httpClient, err := configureTLS(b.secret.Data[caCertData],
-				b.bConfig.S3.InsecureTLS)
-			if err != nil {
-				return aws2.Config{}, err
-			}
-			loadOptions = append(loadOptions, config.WithHTTPClient(httpClient))
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return aws2.Config{}, err
+	}
+	b.s3ClientMu.Lock()
+	if b.s3Client == nil {
+		b.s3Client = cfg.HTTPClient
+		if client, ok := cfg.HTTPClient.(*awshttp.BuildableClient); ok && b.transport.limits() {
+			// the client is wrapped once loaded, the config adds the CA
+			// bundle of the environment to a BuildableClient only
+			b.s3Client = &http.Client{Transport: b.transport.wrap(client.GetTransport())}
 		}
 	}
-
-	return config.LoadDefaultConfig(ctx, loadOptions...)
+	cfg.HTTPClient = b.s3Client
+	b.s3ClientMu.Unlock()
+	if spec.RoleARN != "" {
+		b.s3RoleMu.Lock()
+		if b.s3RoleCreds == nil {
+			b.s3RoleCreds = assumeRoleProvider(cfg, spec)
+		}
+		cfg.Credentials = b.s3RoleCreds
+		b.s3RoleMu.Unlock()
+	}
+	return cfg, nil
 }
 
-func configureTLS(caCert []byte, insecureTLS bool) (*awshttp.BuildableClient, error) {
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: insecureTLS,
+func s3Credentials(secret *core.Secret, spec *api.S3Spec) (*s3auth.Credentials, error) {
+	var data map[string][]byte
+	if secret != nil {
+		data = secret.Data
 	}
-	if len(caCert) > 0 {
-		caCertPool := x509.NewCertPool()
-		if ok := caCertPool.AppendCertsFromPEM(caCert); !ok {
-			return nil, fmt.Errorf("failed to parse CA certificate")
+	creds, err := s3auth.FromSecret(spec, data)
+	if err != nil {
+		if s3auth.IsExpiredCredentials(err) || secret == nil {
+			return nil, err
 		}
-		tlsConfig.RootCAs = caCertPool
+		return nil, fmt.Errorf("storage secret %s/%s %v", secret.Namespace, secret.Name, err)
+	}
+	return creds, nil
+}
+
+func (b *Blob) getGCSClient(ctx context.Context) (*gcp.HTTPClient, error) {
+	b.gcsMu.Lock()
+	defer b.gcsMu.Unlock()
+	if b.gcsClient != nil {
+		return b.gcsClient, nil
+	}
+	var data []byte
+	if b.secret != nil {
+		data = b.secret.Data[googleServiceAccountJsonKey]
+	}
+	if hc := b.transport.client(); hc != nil {
+		// tokens are fetched through the proxy of the backend as well
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, hc)
+	}
+	ts, err := gcsauth.TokenSource(ctx, b.bConfig.GCS, data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get gcs credentials, reason: %v", err)
 	}
-	buildableClient := awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
-		tr.TLSClientConfig = tlsConfig
+	transport := gcp.DefaultTransport()
+	if rt := b.transport.roundTripper(); rt != nil {
+		transport = rt
+	}
+	client, err := gcp.NewHTTPClient(transport, ts)
+	if err != nil {
+		return nil, err
+	}
+	b.gcsClient = client
+	return client, nil
+}
+
+// getAzureClient is the MakeClient of the azblob:// url opener. A Blob
+// always opens the same container, so the client is made once.
+func (b *Blob) getAzureClient(svcURL azureblob.ServiceURL, containerName azureblob.ContainerName) (*container.Client, error) {
+	b.azureMu.Lock()
+	defer b.azureMu.Unlock()
+	if b.azureClient != nil {
+		return b.azureClient, nil
+	}
+	client, err := b.azure.NewContainerClient(string(svcURL), string(containerName))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create azure client, reason: %v", err)
+	}
+	b.azureClient = client
+	return client, nil
+}
+
+func (b *Blob) getB2Client(ctx context.Context) (*b2.Client, error) {
+	b.b2Mu.Lock()
+	defer b.b2Mu.Unlock()
+	if b.b2Client != nil {
+		return b.b2Client, nil
+	}
+	client, err := b2.NewClient(ctx, b2.Options{
+		AccountID:      string(b.secret.Data[api.B2_ACCOUNT_ID]),
+		ApplicationKey: string(b.secret.Data[api.B2_ACCOUNT_KEY]),
+		AuthURL:        string(b.secret.Data[api.B2_AUTH_URL]),
+		MaxConnections: int(b.bConfig.B2.MaxConnections),
+		HTTPClient:     b.transport.client(),
 	})
-	// https://docs.aws.amazon.com/sdk-for-go/v2/developer-guide/configure-http.html#transport
+	if err != nil {
+		return nil, err
+	}
+	b.b2Client = client
+	return client, nil
+}
 
-	return buildableClient, nil
+func (b *Blob) getSwiftConnection() (*swift.Connection, error) {
+	b.swiftMu.Lock()
+	defer b.swiftMu.Unlock()
+	if b.swiftConn != nil {
+		return b.swiftConn, nil
+	}
+	cfg := openstack.SwiftConfigFromSecret(b.secret.Data)
+	conn := cfg.Connection(b.transport.roundTripper())
+	// a manually issued token is used as is
+	if !cfg.ManualAuth() {
+		if err := conn.Authenticate(); err != nil {
+			return nil, fmt.Errorf("unable to authenticate to swift, reason: %v", err)
+		}
+	}
+	b.swiftConn = conn
+	return conn, nil
+}
+
+func configureTransport(c *transportConfig, insecureTLS bool) *awshttp.BuildableClient {
+	// https://docs.aws.amazon.com/sdk-for-go/v2/developer-guide/configure-http.html#transport
+	return awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+		c.apply(tr)
+		if insecureTLS {
+			if tr.TLSClientConfig == nil {
+				tr.TLSClientConfig = &tls.Config{}
+			} else {
+				tr.TLSClientConfig = tr.TLSClientConfig.Clone()
+			}
+			tr.TLSClientConfig.InsecureSkipVerify = true
+		}
+	})
 }
 
 func (b *Blob) SetPathAsDir(ctx context.Context, path string) error {
+	if b.replicas != nil {
+		return b.replicas.write(ctx, func(r *Blob) error {
+			return r.SetPathAsDir(ctx, path)
+		})
+	}
+	return b.retry(ctx, func(ctx context.Context) error {
+		return b.setPathAsDir(ctx, path)
+	})
+}
+
+func (b *Blob) setPathAsDir(ctx context.Context, path string) error {
 	bucket, err := b.openBucket(ctx, path)
 	if err != nil {
 		return err
@@ -531,7 +1235,9 @@ func (b *Blob) SetPathAsDir(ctx context.Context, path string) error {
 	if !strings.HasSuffix(path, "/") {
 		path = fmt.Sprintf("%s/", path)
 	}
-	w, err := bucket.NewWriter(ctx, path, nil)
+	opts := b.writerOptions(UploadOptions{})
+	opts.DisableContentTypeDetection = false
+	w, err := bucket.NewWriter(ctx, path, opts)
 	if err != nil {
 		return err
 	}
@@ -545,3 +1251,60 @@ func (b *Blob) SetPathAsDir(ctx context.Context, path string) error {
 	}
 	return closeErr
 }
+
+// writerOptions applies opts, falling back to the defaults of the S3Spec for
+// the s3 specific settings.
+func (b *Blob) writerOptions(opts UploadOptions) *blob.WriterOptions {
+	wo := &blob.WriterOptions{
+		ContentType:                 opts.ContentType,
+		DisableContentTypeDetection: true,
+	}
+	if b.bConfig == nil {
+		return wo
+	}
+	if n := b.bConfig.MaxConnections(); n > 0 {
+		// no more parts are buffered than can be sent
+		wo.MaxConcurrency = int(n)
+	}
+	if b.bConfig.S3 == nil {
+		return wo
+	}
+
+	spec := b.bConfig.S3
+	storageClass := opts.StorageClass
+	if storageClass == "" {
+		storageClass = spec.StorageClass
+	}
+	acl := opts.ACL
+	if acl == "" {
+		acl = spec.ACL
+	}
+	tags := url.Values{}
+	for k, v := range spec.Tags {
+		tags.Set(k, v)
+	}
+	for k, v := range opts.Tags {
+		tags.Set(k, v)
+	}
+	if storageClass == "" && acl == "" && len(tags) == 0 {
+		return wo
+	}
+
+	wo.BeforeWrite = func(asFunc func(any) bool) error {
+		var in *s3.PutObjectInput
+		if !asFunc(&in) {
+			return nil
+		}
+		if storageClass != "" {
+			in.StorageClass = s3types.StorageClass(storageClass)
+		}
+		if acl != "" {
+			in.ACL = s3types.ObjectCannedACL(acl)
+		}
+		if len(tags) > 0 {
+			in.Tagging = aws2.String(tags.Encode())
+		}
+		return nil
+	}
+	return wo
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"gocloud.dev/gcerrors"
+	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	storageapi "kubestash.dev/apimachinery/apis/storage/v1alpha1"
 	rtc "sigs.k8s.io/controller-runtime/pkg/client"
@@ -161,6 +162,9 @@ func getFakeClient(initObjs ...rtc.Object) (rtc.WithWatch, error) {
 	if err := storageapi.AddToScheme(scheme); err != nil {
 		return nil, err
 	}
+	if err := core.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
 	return fakeClient, nil
 }
@@ -0,0 +1,186 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/blob"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// concurrencyServer forwards the requests to a target server after a delay
+// and records how many of them were in flight at the same time, and over how
+// many connections they came.
+type concurrencyServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	conns       int
+}
+
+func newConcurrencyServer(t *testing.T, target string) *concurrencyServer {
+	u, err := url.Parse(target)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	proxy := httputil.NewSingleHostReverseProxy(u)
+	s := &concurrencyServer{}
+	s.Server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.inFlight++
+		s.maxInFlight = max(s.maxInFlight, s.inFlight)
+		s.mu.Unlock()
+		defer func() {
+			s.mu.Lock()
+			s.inFlight--
+			s.mu.Unlock()
+		}()
+		time.Sleep(20 * time.Millisecond)
+		proxy.ServeHTTP(w, r)
+	}))
+	s.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			s.mu.Lock()
+			s.conns++
+			s.mu.Unlock()
+		}
+	}
+	s.Start()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// stats returns the most requests in flight and the connections so far.
+func (s *concurrencyServer) stats() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.maxInFlight, s.conns
+}
+
+func TestS3MaxConnections(t *testing.T) {
+	srv := newS3Server(t)
+	front := newConcurrencyServer(t, srv.URL)
+	ctx := context.Background()
+	const maxConnections = 2
+	storage := newBackendStorage(t, &api.Backend{
+		S3: &api.S3Spec{
+			Endpoint:       front.URL,
+			Bucket:         s3Bucket,
+			Region:         "us-east-1",
+			MaxConnections: maxConnections,
+		},
+	}, map[string][]byte{
+		api.AWS_ACCESS_KEY_ID:     []byte("id"),
+		api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+	})
+
+	// every kind of request shares the limit
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("data/%d", i)
+			assert.Nil(t, storage.Upload(ctx, key, []byte(sampleData), ""))
+			got, err := storage.Get(ctx, key)
+			assert.Nil(t, err)
+			assert.Equal(t, sampleData, string(got))
+			_, err = storage.List(ctx, "data/")
+			assert.Nil(t, err)
+			assert.Nil(t, storage.Delete(ctx, key, false))
+		}(i)
+	}
+	wg.Wait()
+	inFlight, conns := front.stats()
+	assert.Equal(t, maxConnections, inFlight)
+	// the pool keeps the connections of the slots
+	assert.LessOrEqual(t, conns, maxConnections)
+
+	// and so do the parts of a multipart upload
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1<<20)
+	assert.Nil(t, storage.Upload(ctx, "large", data, ""))
+	obj, ok := srv.Object(s3Bucket, "large")
+	if assert.True(t, ok) {
+		assert.Equal(t, data, obj.Data)
+	}
+	parts := 0
+	for _, r := range srv.Requests() {
+		if r.Query.Has("partNumber") {
+			parts++
+		}
+	}
+	assert.Greater(t, parts, maxConnections)
+	inFlight, _ = front.stats()
+	assert.Equal(t, maxConnections, inFlight)
+	assert.Zero(t, srv.Uploads())
+}
+
+func TestS3MaxConnectionsSharedByBlobs(t *testing.T) {
+	srv := newS3Server(t)
+	front := newConcurrencyServer(t, srv.URL)
+	ctx := context.Background()
+	const maxConnections = 2
+	backend := &api.Backend{
+		S3: &api.S3Spec{
+			Endpoint:       front.URL,
+			Bucket:         s3Bucket,
+			Region:         "us-east-1",
+			MaxConnections: maxConnections,
+		},
+	}
+	creds := map[string][]byte{
+		api.AWS_ACCESS_KEY_ID:     []byte("id"),
+		api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+	}
+	// the limit holds for every Blob of the backend together
+	storages := []*blob.Blob{
+		newBackendStorage(t, backend.DeepCopy(), creds),
+		newBackendStorage(t, backend.DeepCopy(), creds),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			storage := storages[i%len(storages)]
+			key := fmt.Sprintf("data/%d", i)
+			assert.Nil(t, storage.Upload(ctx, key, []byte(sampleData), ""))
+			_, err := storage.Get(ctx, key)
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+	inFlight, _ := front.stats()
+	assert.Equal(t, maxConnections, inFlight)
+	assert.Len(t, srv.Keys(s3Bucket), 8)
+}
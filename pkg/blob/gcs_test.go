@@ -0,0 +1,78 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/blob"
+	"kmodules.xyz/objectstore-api/pkg/credsource"
+
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGCSInvalidCredentials(t *testing.T) {
+	fakeClient, err := getFakeClient(&core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "gcs-secret", Namespace: "db"},
+		Data: map[string][]byte{
+			api.GOOGLE_SERVICE_ACCOUNT_JSON_KEY: []byte("not json"),
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = blob.NewBlob(context.Background(), fakeClient, "db", &api.Backend{
+		StorageSecretName: "gcs-secret",
+		GCS:               &api.GCSSpec{Bucket: "stash"},
+	})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), api.GOOGLE_SERVICE_ACCOUNT_JSON_KEY)
+	}
+}
+
+// TestCredentialsNotInEnvironment makes sure that the credentials of a Blob
+// stay with it, so Blobs of different tenants do not overwrite each other's.
+func TestCredentialsNotInEnvironment(t *testing.T) {
+	envs := []string{"GOOGLE_APPLICATION_CREDENTIALS", "AZURE_STORAGE_ACCOUNT", "AZURE_STORAGE_KEY"}
+	before := map[string]string{}
+	for _, env := range envs {
+		before[env] = os.Getenv(env)
+	}
+
+	_, err := blob.NewBlob(context.Background(), nil, "db", &api.Backend{
+		GCS: &api.GCSSpec{Bucket: "stash"},
+	}, credsource.WithSource(credsource.NewStaticSource(map[string][]byte{
+		api.GOOGLE_SERVICE_ACCOUNT_JSON_KEY: []byte(`{"type":"authorized_user","client_id":"id","client_secret":"secret","refresh_token":"token"}`),
+	})))
+	assert.Nil(t, err)
+	_, err = blob.NewBlob(context.Background(), nil, "db", &api.Backend{
+		Azure: &api.AzureSpec{Container: "stash"},
+	}, credsource.WithSource(credsource.NewStaticSource(map[string][]byte{
+		api.AZURE_ACCOUNT_NAME: []byte("tenant"),
+		api.AZURE_ACCOUNT_KEY:  []byte("a2V5"),
+	})))
+	assert.Nil(t, err)
+
+	for _, env := range envs {
+		assert.Equal(t, before[env], os.Getenv(env), env)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/blob"
+	"kmodules.xyz/objectstore-api/pkg/httpproxy/proxytest"
+
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	proxyUsername = "egress"
+	proxyPassword = "not-so-secret"
+)
+
+func newProxy(t *testing.T) *proxytest.Server {
+	proxy := proxytest.NewServer()
+	t.Cleanup(proxy.Close)
+	proxy.Username = proxyUsername
+	proxy.Password = proxyPassword
+	return proxy
+}
+
+func newBackendStorage(t *testing.T, backend *api.Backend, data map[string][]byte) *blob.Blob {
+	t.Helper()
+	backend.StorageSecretName = "backend-secret"
+	fakeClient, err := getFakeClient(&core.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backend.StorageSecretName,
+			Namespace: "db",
+		},
+		Data: data,
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	storage, err := blob.NewBlob(context.Background(), fakeClient, "db", backend)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return storage
+}
+
+func hostOf(t *testing.T, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return u.Host
+}
+
+func TestS3Proxy(t *testing.T) {
+	srv := newS3Server(t)
+	proxy := newProxy(t)
+	ctx := context.Background()
+	data := map[string][]byte{
+		api.AWS_ACCESS_KEY_ID:     []byte("id"),
+		api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+		api.PROXY_USERNAME:        []byte(proxyUsername),
+		api.PROXY_PASSWORD:        []byte(proxyPassword),
+	}
+	backend := func(noProxy ...string) *api.Backend {
+		return &api.Backend{
+			S3: &api.S3Spec{
+				Endpoint: srv.URL,
+				Bucket:   s3Bucket,
+				Region:   "us-east-1",
+			},
+			Proxy: &api.ProxyConfig{URL: proxy.URL, NoProxy: noProxy},
+		}
+	}
+
+	storage := newBackendStorage(t, backend(), data)
+	assert.Nil(t, storage.Upload(ctx, "proxied", []byte(sampleData), ""))
+	got, err := storage.Get(ctx, "proxied")
+	assert.Nil(t, err)
+	assert.Equal(t, sampleData, string(got))
+	assert.NotEmpty(t, proxy.Hosts())
+	for _, host := range proxy.Hosts() {
+		assert.Equal(t, hostOf(t, srv.URL), host)
+	}
+
+	// hosts of the no proxy list are connected to directly
+	before := len(proxy.Hosts())
+	storage = newBackendStorage(t, backend("127.0.0.1"), data)
+	_, err = storage.Get(ctx, "proxied")
+	assert.Nil(t, err)
+	assert.Len(t, proxy.Hosts(), before)
+
+	// the proxy refuses requests without its credentials
+	delete(data, api.PROXY_PASSWORD)
+	storage = newBackendStorage(t, backend(), data)
+	_, err = storage.Get(ctx, "proxied")
+	assert.Error(t, err)
+	assert.Len(t, proxy.Hosts(), before)
+}
+
+func TestRestProxy(t *testing.T) {
+	srv := newRestServer(t)
+	proxy := newProxy(t)
+	ctx := context.Background()
+	data := restSecretData(srv)
+	data[api.PROXY_USERNAME] = []byte(proxyUsername)
+	data[api.PROXY_PASSWORD] = []byte(proxyPassword)
+
+	// https endpoints are tunneled with CONNECT
+	storage := newBackendStorage(t, &api.Backend{
+		Rest:  &api.RestServerSpec{URL: srv.URL + restRepo},
+		Proxy: &api.ProxyConfig{URL: proxy.URL},
+	}, data)
+	key := "snapshots/" + restID(sampleData)
+	assert.Nil(t, storage.Upload(ctx, key, []byte(sampleData), ""))
+	_, ok := srv.Object(restRepo, key)
+	assert.True(t, ok)
+	assert.Contains(t, proxy.Hosts(), hostOf(t, srv.URL))
+}
@@ -0,0 +1,89 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/blob"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rateOf returns the rate of n units done in d, in units per second.
+func rateOf(n int, d time.Duration) float64 {
+	return float64(n) / d.Seconds()
+}
+
+func TestS3RateLimit(t *testing.T) {
+	srv := newS3Server(t)
+	ctx := context.Background()
+	const (
+		size = 64 << 10
+		bps  = 128 << 10
+	)
+	data := bytes.Repeat([]byte("x"), size)
+	newStorage := func(limit *api.RateLimit) *blob.Blob {
+		return newBackendStorage(t, &api.Backend{
+			S3: &api.S3Spec{
+				Endpoint: srv.URL,
+				Bucket:   s3Bucket,
+				Region:   "us-east-1",
+				Prefix:   t.Name(),
+			},
+			RateLimit: limit,
+		}, map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+		})
+	}
+
+	storage := newStorage(&api.RateLimit{UploadBytesPerSecond: bps, DownloadBytesPerSecond: bps})
+	start := time.Now()
+	assert.Nil(t, storage.Upload(ctx, "limited", data, ""))
+	// the burst of a tenth of a second is free
+	assert.Less(t, rateOf(size, time.Since(start)), 1.25*bps)
+
+	start = time.Now()
+	got, err := storage.Get(ctx, "limited")
+	assert.Nil(t, err)
+	assert.Equal(t, data, got)
+	assert.Less(t, rateOf(size, time.Since(start)), 1.25*bps)
+
+	// the storages of a backend share the limit
+	const rps = 20
+	limit := &api.RateLimit{RequestsPerSecond: rps}
+	storages := []*blob.Blob{newStorage(limit), newStorage(limit)}
+	const requests = 20
+	start = time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(storage *blob.Blob) {
+			defer wg.Done()
+			_, err := storage.Exists(ctx, "limited")
+			assert.Nil(t, err)
+		}(storages[i%len(storages)])
+	}
+	wg.Wait()
+	assert.Less(t, rateOf(requests, time.Since(start)), 1.1*rps)
+}
@@ -0,0 +1,256 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/credsource"
+
+	"gocloud.dev/gcerrors"
+	"k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// replicas are the backends of a Blob of a ReplicatedBackend, the primary
+// first.
+type replicas struct {
+	backends    []*Blob
+	writeQuorum int
+}
+
+// NewReplicatedBlob returns a Blob that writes to the primary and the mirrors
+// of rb, and reads from the first of them that has the object. A write
+// succeeds when WriteQuorum backends stored it, Repair copies the objects to
+// the mirrors that missed them. The credentials of every backend are resolved
+// like by NewBlob.
+func NewReplicatedBlob(ctx context.Context, c client.Client, namespace string, rb *api.ReplicatedBackend, opts ...credsource.Option) (*Blob, error) {
+	rb = rb.DeepCopy()
+	rb.SetDefaults()
+	n := len(rb.Mirrors) + 1
+	if rb.WriteQuorum < 1 || int(rb.WriteQuorum) > n {
+		return nil, fmt.Errorf("write quorum %d must be between 1 and %d", rb.WriteQuorum, n)
+	}
+
+	r := &replicas{writeQuorum: int(rb.WriteQuorum)}
+	for i, backend := range append([]api.Backend{rb.Primary}, rb.Mirrors...) {
+		b, err := NewBlob(ctx, c, namespace, &backend, opts...)
+		if err != nil {
+			_ = (&Blob{replicas: r}).Close()
+			return nil, fmt.Errorf("unable to create blob for %s, reason: %v", replicaName(i), err)
+		}
+		r.backends = append(r.backends, b)
+	}
+	return &Blob{replicas: r}, nil
+}
+
+// replicaName names the i-th backend like the fields of a ReplicatedBackend.
+func replicaName(i int) string {
+	if i == 0 {
+		return "primary"
+	}
+	return fmt.Sprintf("mirrors[%d]", i-1)
+}
+
+// write runs op on every backend concurrently, each with its own retry
+// policy. It fails when fewer than writeQuorum backends succeed, the failures
+// of the others are only logged.
+func (r *replicas) write(ctx context.Context, op func(b *Blob) error) error {
+	errs := make([]error, len(r.backends))
+	var wg sync.WaitGroup
+	for i, b := range r.backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = op(b)
+		}()
+	}
+	wg.Wait()
+
+	var failed []error
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", replicaName(i), err))
+		}
+	}
+	if succeeded := len(r.backends) - len(failed); succeeded < r.writeQuorum {
+		return fmt.Errorf("write quorum of %d not reached, %d of %d backends succeeded: %w", r.writeQuorum, succeeded, len(r.backends), errors.NewAggregate(failed))
+	}
+	if len(failed) > 0 {
+		log.FromContext(ctx).Error(errors.NewAggregate(failed), "write did not reach every backend, it needs to be repaired")
+	}
+	return nil
+}
+
+// read runs op on the primary, and on the mirrors in order until it
+// succeeds. The errors of all backends are returned when every backend fails.
+func (r *replicas) read(op func(b *Blob) error) error {
+	var failed []error
+	for i, b := range r.backends {
+		err := op(b)
+		if err == nil {
+			return nil
+		}
+		failed = append(failed, fmt.Errorf("%s: %w", replicaName(i), err))
+	}
+	return readError{errors.NewAggregate(failed)}
+}
+
+// readError is the error of a read that failed on every backend. It unwraps
+// to the errors of the backends, so gcerrors.Code returns the code of the
+// error of the primary.
+type readError struct {
+	errors.Aggregate
+}
+
+func (e readError) Unwrap() []error {
+	return e.Errors()
+}
+
+// exists reports whether any backend has the object. Backends that fail are
+// skipped, their first error is returned when no other backend has it.
+func (r *replicas) exists(ctx context.Context, filepath string) (bool, error) {
+	var firstErr error
+	for _, b := range r.backends {
+		ok, err := b.Exists(ctx, filepath)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, firstErr
+}
+
+// delete deletes the object from every backend. An object that a lagging
+// mirror never received counts as deleted there.
+func (r *replicas) delete(ctx context.Context, filepath string, isDir bool) error {
+	return r.write(ctx, func(b *Blob) error {
+		err := b.Delete(ctx, filepath, isDir)
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil
+		}
+		return err
+	})
+}
+
+func (r *replicas) watchCredentials(ctx context.Context, onRotate credsource.RotationHook) error {
+	var errs []error
+	for i, b := range r.backends {
+		if err := b.WatchCredentials(ctx, onRotate); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", replicaName(i), err))
+		}
+	}
+	return errors.NewAggregate(errs)
+}
+
+// Repair copies the objects in dir that are missing on the mirrors from the
+// primary, and returns the number of copies. Objects that are only missing on
+// the primary are left as they are, reads find them on the mirrors. It keeps
+// going when a copy fails, and returns the errors together. A Blob that is
+// not replicated has nothing to repair.
+func (b *Blob) Repair(ctx context.Context, dir string) (int, error) {
+	if b.replicas == nil {
+		return 0, nil
+	}
+	return b.replicas.repair(ctx, dir)
+}
+
+func (r *replicas) repair(ctx context.Context, dir string) (int, error) {
+	primary := r.backends[0]
+	keys, err := primary.keys(ctx, dir)
+	if err != nil {
+		return 0, fmt.Errorf("unable to list primary, reason: %v", err)
+	}
+
+	var copied int
+	var errs []error
+	for i, mirror := range r.backends[1:] {
+		name := replicaName(i + 1)
+		mirrorKeys, err := mirror.keys(ctx, dir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to list %s, reason: %v", name, err))
+			continue
+		}
+		have := sets.New(mirrorKeys...)
+		for _, key := range keys {
+			if have.Has(key) {
+				continue
+			}
+			if err := copyObject(ctx, primary, mirror, path.Join(dir, key)); err != nil {
+				errs = append(errs, fmt.Errorf("unable to copy %s to %s, reason: %v", key, name, err))
+				continue
+			}
+			copied++
+		}
+	}
+	return copied, errors.NewAggregate(errs)
+}
+
+// keys returns the keys of the objects in dir, relative to dir.
+func (b *Blob) keys(ctx context.Context, dir string) ([]string, error) {
+	var keys []string
+	err := b.retry(ctx, func(ctx context.Context) error {
+		bucket, err := b.openBucket(ctx, dir)
+		if err != nil {
+			return err
+		}
+		defer closeBucket(ctx, bucket)
+		keys = nil
+		iter := bucket.List(nil)
+		for {
+			obj, err := iter.Next(ctx)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if checkIfObjectFile(obj) {
+				keys = append(keys, obj.Key)
+			}
+		}
+	})
+	return keys, err
+}
+
+// copyObject copies the object at filepath from src to dst, with its content
+// type.
+func copyObject(ctx context.Context, src, dst *Blob, filepath string) error {
+	var data []byte
+	var contentType string
+	err := src.retry(ctx, func(ctx context.Context) error {
+		var err error
+		data, contentType, err = src.read(ctx, filepath)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return dst.Upload(ctx, filepath, data, contentType)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/blob"
+	"kmodules.xyz/objectstore-api/pkg/s3test"
+
+	"github.com/stretchr/testify/assert"
+	"gocloud.dev/gcerrors"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func s3Backend(endpoint string) api.Backend {
+	return api.Backend{
+		StorageSecretName: "backend-secret",
+		S3: &api.S3Spec{
+			Endpoint: endpoint,
+			Bucket:   s3Bucket,
+			Region:   "us-east-1",
+		},
+	}
+}
+
+func newReplicatedStorage(t *testing.T, rb *api.ReplicatedBackend) (*blob.Blob, error) {
+	t.Helper()
+	fakeClient, err := getFakeClient(&core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend-secret", Namespace: "db"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+		},
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return blob.NewReplicatedBlob(context.Background(), fakeClient, "db", rb)
+}
+
+func TestReplicatedWrites(t *testing.T) {
+	primary := newS3Server(t)
+	mirror := newS3Server(t)
+	// the bucket of the lagging mirror is created later
+	lagging := s3test.NewServer()
+	t.Cleanup(lagging.Close)
+	ctx := context.Background()
+
+	rb := &api.ReplicatedBackend{
+		Primary:     s3Backend(primary.URL),
+		Mirrors:     []api.Backend{s3Backend(mirror.URL), s3Backend(lagging.URL)},
+		WriteQuorum: 2,
+	}
+	storage, err := newReplicatedStorage(t, rb)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, storage.Upload(ctx, "data/a.txt", []byte(sampleData), "text/plain"))
+	for _, srv := range []*s3test.Server{primary, mirror} {
+		obj, ok := srv.Object(s3Bucket, "data/a.txt")
+		if assert.True(t, ok) {
+			assert.Equal(t, sampleData, string(obj.Data))
+		}
+	}
+
+	// every backend must store the object
+	rb.WriteQuorum = 0
+	strict, err := newReplicatedStorage(t, rb)
+	if !assert.Nil(t, err) {
+		return
+	}
+	err = strict.Upload(ctx, "data/b.txt", []byte(sampleData), "")
+	assert.ErrorContains(t, err, "write quorum of 3 not reached, 2 of 3 backends succeeded")
+	assert.ErrorContains(t, err, "mirrors[1]")
+
+	lagging.CreateBucket(s3Bucket)
+	copied, err := storage.Repair(ctx, "data")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, copied)
+	assert.Equal(t, []string{"data/a.txt", "data/b.txt"}, lagging.Keys(s3Bucket))
+	if obj, ok := lagging.Object(s3Bucket, "data/a.txt"); assert.True(t, ok) {
+		assert.Equal(t, sampleData, string(obj.Data))
+		assert.Equal(t, "text/plain", obj.Header.Get("Content-Type"))
+	}
+
+	copied, err = storage.Repair(ctx, "data")
+	assert.Nil(t, err)
+	assert.Zero(t, copied)
+
+	rb.WriteQuorum = 4
+	_, err = newReplicatedStorage(t, rb)
+	assert.ErrorContains(t, err, "write quorum 4 must be between 1 and 3")
+}
+
+func TestReplicatedReads(t *testing.T) {
+	primary := newS3Server(t)
+	mirror := newS3Server(t)
+	ctx := context.Background()
+
+	storage, err := newReplicatedStorage(t, &api.ReplicatedBackend{
+		Primary: s3Backend(primary.URL),
+		Mirrors: []api.Backend{s3Backend(mirror.URL)},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, storage.Upload(ctx, "data/a.txt", []byte(sampleData), ""))
+
+	// the object is missing on the primary
+	primaryOnly := newBackendStorage(t, &api.Backend{S3: s3Backend(primary.URL).S3}, map[string][]byte{
+		api.AWS_ACCESS_KEY_ID:     []byte("id"),
+		api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+	})
+	assert.Nil(t, primaryOnly.Delete(ctx, "data/a.txt", false))
+	got, err := storage.Get(ctx, "data/a.txt")
+	assert.Nil(t, err)
+	assert.Equal(t, sampleData, string(got))
+	exists, err := storage.Exists(ctx, "data/a.txt")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	// the errors of every backend are returned when no backend has the object
+	_, err = storage.Get(ctx, "data/missing.txt")
+	assert.Equal(t, gcerrors.NotFound, gcerrors.Code(err))
+	assert.ErrorContains(t, err, "primary: ")
+	assert.ErrorContains(t, err, "mirrors[0]: ")
+	exists, err = storage.Exists(ctx, "data/missing.txt")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	// an object that is missing somewhere is still deleted everywhere else
+	assert.Nil(t, storage.Delete(ctx, "data/a.txt", false))
+	assert.Empty(t, mirror.Keys(s3Bucket))
+}
+
+func TestReplicatedLocalMirror(t *testing.T) {
+	primary := newS3Server(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	storage, err := newReplicatedStorage(t, &api.ReplicatedBackend{
+		Primary: s3Backend(primary.URL),
+		Mirrors: []api.Backend{{Local: &api.LocalSpec{MountPath: dir}}},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, storage.Upload(ctx, "data/a.txt", []byte(sampleData), ""))
+	data, err := os.ReadFile(filepath.Join(dir, "data", "a.txt"))
+	assert.Nil(t, err)
+	assert.Equal(t, sampleData, string(data))
+
+	// the local mirror serves the reads the primary fails
+	primaryOnly := newBackendStorage(t, &api.Backend{S3: s3Backend(primary.URL).S3}, map[string][]byte{
+		api.AWS_ACCESS_KEY_ID:     []byte("id"),
+		api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+	})
+	assert.Nil(t, primaryOnly.Delete(ctx, "data/a.txt", false))
+	got, err := storage.Get(ctx, "data/a.txt")
+	assert.Nil(t, err)
+	assert.Equal(t, sampleData, string(got))
+
+	// the primary is the source of the repair
+	copied, err := storage.Repair(ctx, "data")
+	assert.Nil(t, err)
+	assert.Zero(t, copied)
+	assert.Empty(t, primary.Keys(s3Bucket))
+}
+
+func TestReplicatedPrimaryOutage(t *testing.T) {
+	down := httptest.NewServer(nil)
+	down.Close()
+	mirror := newS3Server(t)
+	ctx := context.Background()
+
+	primary := s3Backend(down.URL)
+	primary.RetryPolicy = fastRetryPolicy()
+	storage, err := newReplicatedStorage(t, &api.ReplicatedBackend{
+		Primary:     primary,
+		Mirrors:     []api.Backend{s3Backend(mirror.URL)},
+		WriteQuorum: 1,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Nil(t, storage.Upload(ctx, "data/a.txt", []byte(sampleData), ""))
+	got, err := storage.Get(ctx, "data/a.txt")
+	assert.Nil(t, err)
+	assert.Equal(t, sampleData, string(got))
+	objects, err := storage.List(ctx, "data")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte(sampleData)}, objects)
+
+	// the primary is the source of the repair
+	_, err = storage.Repair(ctx, "data")
+	assert.ErrorContains(t, err, "unable to list primary")
+}
@@ -0,0 +1,242 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/blob"
+	"kmodules.xyz/objectstore-api/pkg/rest/resttest"
+
+	"github.com/stretchr/testify/assert"
+	"gocloud.dev/gcerrors"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	restUsername   = "stash"
+	restPassword   = "not-so-secret"
+	restRepo       = "/demo/repo"
+	restSecretName = "rest-secret"
+)
+
+func newRestServer(t *testing.T) *resttest.Server {
+	srv := resttest.NewTLSServer()
+	t.Cleanup(srv.Close)
+	srv.Username = restUsername
+	srv.Password = restPassword
+	srv.CreateRepository(restRepo)
+	return srv
+}
+
+func newRestStorage(t *testing.T, srv *resttest.Server, data map[string][]byte) *blob.Blob {
+	t.Helper()
+	secret := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      restSecretName,
+			Namespace: "db",
+		},
+		Data: data,
+	}
+	fakeClient, err := getFakeClient(secret)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	storage, err := blob.NewBlob(context.Background(), fakeClient, "db", &api.Backend{
+		StorageSecretName: restSecretName,
+		Rest: &api.RestServerSpec{
+			URL: srv.URL + restRepo,
+		},
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return storage
+}
+
+func restSecretData(srv *resttest.Server) map[string][]byte {
+	return map[string][]byte{
+		api.REST_SERVER_USERNAME: []byte(restUsername),
+		api.REST_SERVER_PASSWORD: []byte(restPassword),
+		api.CA_CERT_DATA:         srv.CACert(),
+	}
+}
+
+// restID returns the name a rest-server accepts for data.
+func restID(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestRestUploadGetDelete(t *testing.T) {
+	srv := newRestServer(t)
+	storage := newRestStorage(t, srv, restSecretData(srv))
+	ctx := context.Background()
+	key := "snapshots/" + restID(sampleData)
+
+	exists, err := storage.Exists(ctx, key)
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	assert.Nil(t, storage.Upload(ctx, key, []byte(sampleData), ""))
+	data, ok := srv.Object(restRepo, key)
+	assert.True(t, ok)
+	assert.Equal(t, sampleData, string(data))
+
+	exists, err = storage.Exists(ctx, key)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	d, err := storage.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, sampleData, string(d))
+
+	// files are immutable
+	err = storage.Upload(ctx, key, []byte(sampleData), "")
+	assert.Equal(t, gcerrors.PermissionDenied, gcerrors.Code(err))
+
+	assert.Nil(t, storage.Delete(ctx, key, false))
+	_, err = storage.Get(ctx, key)
+	assert.True(t, isNotFound(err))
+	err = storage.Delete(ctx, key, false)
+	assert.True(t, isNotFound(err))
+}
+
+func TestRestConfigAndInvalidKeys(t *testing.T) {
+	srv := newRestServer(t)
+	storage := newRestStorage(t, srv, restSecretData(srv))
+	ctx := context.Background()
+
+	assert.Nil(t, storage.Upload(ctx, "config", []byte("repository config"), ""))
+	d, err := storage.Get(ctx, "config")
+	assert.Nil(t, err)
+	assert.Equal(t, "repository config", string(d))
+
+	for _, key := range []string{"sample.txt", "data/dir/file", "unknown/" + restID(sampleData)} {
+		err := storage.Upload(ctx, key, []byte(sampleData), "")
+		assert.Equal(t, gcerrors.InvalidArgument, gcerrors.Code(err), key)
+	}
+	assert.Equal(t, []string{"config"}, srv.Keys(restRepo))
+}
+
+func TestRestListAndDeleteDir(t *testing.T) {
+	srv := newRestServer(t)
+	storage := newRestStorage(t, srv, restSecretData(srv))
+	ctx := context.Background()
+
+	var ids []string
+	for i := 1; i <= 3; i++ {
+		data := fmt.Sprintf("sample data %d", i)
+		ids = append(ids, restID(data))
+		assert.Nil(t, storage.Upload(ctx, "index/"+restID(data), []byte(data), ""))
+	}
+	assert.Nil(t, storage.Upload(ctx, "keys/"+restID("key"), []byte("key"), ""))
+	assert.Nil(t, storage.Upload(ctx, "config", []byte("config"), ""))
+
+	objects, err := storage.List(ctx, "index")
+	assert.Nil(t, err)
+	assert.Len(t, objects, 3)
+
+	dirs, err := storage.ListDirN(ctx, "", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("index/"), []byte("keys/")}, dirs)
+
+	assert.Nil(t, storage.Delete(ctx, "index", true))
+	assert.Equal(t, []string{"config", "keys/" + restID("key")}, srv.Keys(restRepo))
+	for _, id := range ids {
+		_, ok := srv.Object(restRepo, "index/"+id)
+		assert.False(t, ok)
+	}
+}
+
+func TestRestCredentials(t *testing.T) {
+	srv := newRestServer(t)
+	ctx := context.Background()
+
+	data := restSecretData(srv)
+	data[api.REST_SERVER_PASSWORD] = []byte("wrong")
+	_, err := newRestStorage(t, srv, data).Exists(ctx, "config")
+	assert.Equal(t, gcerrors.PermissionDenied, gcerrors.Code(err))
+
+	data = restSecretData(srv)
+	delete(data, api.CA_CERT_DATA)
+	_, err = newRestStorage(t, srv, data).Exists(ctx, "config")
+	assert.ErrorContains(t, err, "certificate")
+}
+
+func TestRestMutualTLS(t *testing.T) {
+	srv := resttest.NewMutualTLSServer()
+	t.Cleanup(srv.Close)
+	srv.Username = restUsername
+	srv.Password = restPassword
+	srv.CreateRepository(restRepo)
+	cert, key := srv.ClientCert()
+	ctx := context.Background()
+
+	newStorage := func(tls *api.TLSConfig, data map[string][]byte) (*blob.Blob, error) {
+		fakeClient, err := getFakeClient(&core.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      restSecretName,
+				Namespace: "db",
+			},
+			Data: data,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return blob.NewBlob(ctx, fakeClient, "db", &api.Backend{
+			StorageSecretName: restSecretName,
+			Rest:              &api.RestServerSpec{URL: srv.URL + restRepo},
+			TLS:               tls,
+		})
+	}
+
+	data := restSecretData(srv)
+	data[api.TLS_CLIENT_CERT_DATA] = cert
+	data[api.TLS_CLIENT_KEY_DATA] = key
+	// the certificate of httptest servers is valid for example.com
+	storage, err := newStorage(&api.TLSConfig{ServerName: "example.com", MinVersion: api.TLSVersion12}, data)
+	if !assert.Nil(t, err) {
+		return
+	}
+	exists, err := storage.Exists(ctx, "config")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	storage, err = newStorage(&api.TLSConfig{ServerName: "minio.local"}, data)
+	if assert.Nil(t, err) {
+		_, err = storage.Exists(ctx, "config")
+		assert.ErrorContains(t, err, "minio.local")
+	}
+
+	// without the client certificate the server refuses the connection
+	storage, err = newStorage(nil, restSecretData(srv))
+	if assert.Nil(t, err) {
+		_, err = storage.Exists(ctx, "config")
+		assert.Error(t, err)
+	}
+
+	delete(data, api.TLS_CLIENT_KEY_DATA)
+	_, err = newStorage(nil, data)
+	assert.ErrorContains(t, err, "tls client certificate without TLS_CLIENT_KEY_DATA")
+}
@@ -0,0 +1,280 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package restblob provides a gocloud.dev/blob driver for a restic
+// repository hosted by a rest-server. Keys must follow the repository
+// layout, see kmodules.xyz/objectstore-api/pkg/rest.
+package restblob // import "kmodules.xyz/objectstore-api/pkg/blob/restblob"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"kmodules.xyz/objectstore-api/pkg/rest"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/driver"
+	"gocloud.dev/gcerrors"
+)
+
+const defaultPageSize = 1000
+
+// OpenBucket returns a *blob.Bucket for the repository of client.
+func OpenBucket(_ context.Context, client *rest.Client) (*blob.Bucket, error) {
+	if client == nil {
+		return nil, errors.New("restblob.OpenBucket: client is required")
+	}
+	return blob.NewBucket(&bucket{c: client}), nil
+}
+
+type bucket struct {
+	c *rest.Client
+}
+
+func (b *bucket) ErrorCode(err error) gcerrors.ErrorCode {
+	if errors.Is(err, errNotImplemented) {
+		return gcerrors.Unimplemented
+	}
+	if errors.Is(err, rest.ErrInvalidHandle) {
+		return gcerrors.InvalidArgument
+	}
+	var e *rest.Error
+	if errors.As(err, &e) {
+		switch e.Status {
+		case http.StatusNotFound:
+			return gcerrors.NotFound
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return gcerrors.PermissionDenied
+		case http.StatusBadRequest, http.StatusRequestedRangeNotSatisfiable:
+			return gcerrors.InvalidArgument
+		case http.StatusTooManyRequests:
+			return gcerrors.ResourceExhausted
+		}
+	}
+	return gcerrors.Unknown
+}
+
+func (b *bucket) As(i any) bool {
+	p, ok := i.(**rest.Client)
+	if !ok {
+		return false
+	}
+	*p = b.c
+	return true
+}
+
+func (b *bucket) ErrorAs(err error, i any) bool {
+	return errors.As(err, i)
+}
+
+func (b *bucket) Attributes(ctx context.Context, key string) (*driver.Attributes, error) {
+	h, err := rest.ParseHandle(key)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := b.c.Stat(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	return &driver.Attributes{
+		ContentType: "application/octet-stream",
+		Metadata:    map[string]string{},
+		Size:        fi.Size,
+	}, nil
+}
+
+func (b *bucket) ListPaged(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	pageSize := opts.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if opts.BeforeList != nil {
+		if err := opts.BeforeList(func(any) bool { return false }); err != nil {
+			return nil, err
+		}
+	}
+	// the server lists whole directories, so pages are cut on this side
+	files, err := b.c.ListKeys(ctx, opts.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	marker := string(opts.PageToken)
+	i := sort.Search(len(files), func(i int) bool {
+		return files[i].Name > marker
+	})
+
+	page := &driver.ListPage{}
+	var lastDir string
+	for ; i < len(files); i++ {
+		if len(page.Objects) == pageSize {
+			page.NextPageToken = []byte(page.Objects[len(page.Objects)-1].Key)
+			break
+		}
+		f := files[i]
+		if opts.Delimiter != "" {
+			rel := strings.TrimPrefix(f.Name, opts.Prefix)
+			if j := strings.Index(rel, opts.Delimiter); j >= 0 {
+				dir := opts.Prefix + rel[:j+len(opts.Delimiter)]
+				// a directory returned by a previous page sorts before its files
+				if dir != lastDir && dir > marker {
+					lastDir = dir
+					page.Objects = append(page.Objects, &driver.ListObject{
+						Key:   dir,
+						IsDir: true,
+					})
+				}
+				continue
+			}
+		}
+		page.Objects = append(page.Objects, &driver.ListObject{
+			Key:  f.Name,
+			Size: f.Size,
+		})
+	}
+	return page, nil
+}
+
+func (b *bucket) NewRangeReader(ctx context.Context, key string, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
+	h, err := rest.ParseHandle(key)
+	if err != nil {
+		return nil, err
+	}
+	if opts.BeforeRead != nil {
+		if err := opts.BeforeRead(func(any) bool { return false }); err != nil {
+			return nil, err
+		}
+	}
+	var body io.ReadCloser
+	var size int64
+	if length == 0 {
+		fi, err := b.c.Stat(ctx, h)
+		if err != nil {
+			return nil, err
+		}
+		body, size = http.NoBody, fi.Size
+	} else {
+		body, size, err = b.c.Load(ctx, h, offset, length)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &reader{
+		body: body,
+		attrs: driver.ReaderAttributes{
+			ContentType: "application/octet-stream",
+			Size:        size,
+		},
+	}, nil
+}
+
+type reader struct {
+	body  io.ReadCloser
+	attrs driver.ReaderAttributes
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	return r.body.Read(p)
+}
+
+func (r *reader) Close() error {
+	return r.body.Close()
+}
+
+func (r *reader) Attributes() *driver.ReaderAttributes {
+	return &r.attrs
+}
+
+func (r *reader) As(any) bool {
+	return false
+}
+
+// NewTypedWriter streams the content to the server. The content type and
+// metadata are dropped, as a rest-server only stores the content.
+func (b *bucket) NewTypedWriter(ctx context.Context, key, _ string, opts *driver.WriterOptions) (driver.Writer, error) {
+	h, err := rest.ParseHandle(key)
+	if err != nil {
+		return nil, err
+	}
+	if opts.BeforeWrite != nil {
+		if err := opts.BeforeWrite(func(any) bool { return false }); err != nil {
+			return nil, err
+		}
+	}
+
+	pr, pw := io.Pipe()
+	w := &writer{
+		ctx:  ctx,
+		pw:   pw,
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		err := b.c.Save(ctx, h, pr, -1)
+		_ = pr.CloseWithError(err)
+		w.err = err
+	}()
+	return w, nil
+}
+
+// writer streams the content into a single request. Failing the pipe aborts
+// the request, so an incomplete file is never stored.
+type writer struct {
+	ctx  context.Context
+	pw   *io.PipeWriter
+	done chan struct{}
+	err  error
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *writer) Close() error {
+	if err := w.ctx.Err(); err != nil {
+		_ = w.pw.CloseWithError(err)
+		<-w.done
+		return err
+	}
+	_ = w.pw.Close()
+	<-w.done
+	return w.err
+}
+
+func (b *bucket) Copy(context.Context, string, string, *driver.CopyOptions) error {
+	return errNotImplemented
+}
+
+func (b *bucket) Delete(ctx context.Context, key string) error {
+	h, err := rest.ParseHandle(key)
+	if err != nil {
+		return err
+	}
+	return b.c.Remove(ctx, h)
+}
+
+func (b *bucket) SignedURL(context.Context, string, *driver.SignedURLOptions) (string, error) {
+	return "", errNotImplemented
+}
+
+func (b *bucket) Close() error {
+	return nil
+}
+
+var errNotImplemented = errors.New("restblob: not implemented")
@@ -0,0 +1,174 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/blob"
+	"kmodules.xyz/objectstore-api/pkg/rest/resttest"
+	"kmodules.xyz/objectstore-api/pkg/retry/retrytest"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newFaultServer(t *testing.T, target string) *retrytest.Server {
+	u, err := url.Parse(target)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	srv := retrytest.NewServer(u)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func fastRetryPolicy() *api.RetryPolicy {
+	return &api.RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: &metav1.Duration{Duration: time.Millisecond},
+		MaxBackoff:  &metav1.Duration{Duration: 5 * time.Millisecond},
+	}
+}
+
+func TestS3Retry(t *testing.T) {
+	srv := newS3Server(t)
+	faults := newFaultServer(t, srv.URL)
+	ctx := context.Background()
+	policy := fastRetryPolicy()
+	storage := newBackendStorage(t, &api.Backend{
+		S3: &api.S3Spec{
+			Endpoint: faults.URL,
+			Bucket:   s3Bucket,
+			Region:   "us-east-1",
+		},
+		RetryPolicy: policy,
+	}, map[string][]byte{
+		api.AWS_ACCESS_KEY_ID:     []byte("id"),
+		api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+	})
+
+	faults.Inject(
+		retrytest.Fault{Status: http.StatusServiceUnavailable},
+		retrytest.Fault{Drop: true},
+	)
+	assert.Nil(t, storage.Upload(ctx, "retried", []byte(sampleData), ""))
+	assert.Zero(t, faults.Pending())
+	got, err := storage.Get(ctx, "retried")
+	assert.Nil(t, err)
+	assert.Equal(t, sampleData, string(got))
+
+	// the attempts are used up
+	before := faults.Requests()
+	faults.Inject(
+		retrytest.Fault{Status: http.StatusInternalServerError},
+		retrytest.Fault{Status: http.StatusInternalServerError},
+		retrytest.Fault{Status: http.StatusInternalServerError},
+	)
+	_, err = storage.Get(ctx, "retried")
+	assert.Error(t, err)
+	assert.Equal(t, 3, faults.Requests()-before, "the sdk does not retry on its own")
+
+	// a denied request is not retried
+	before = faults.Requests()
+	faults.Inject(retrytest.Fault{Status: http.StatusForbidden})
+	_, err = storage.Get(ctx, "retried")
+	assert.Error(t, err)
+	assert.Equal(t, 1, faults.Requests()-before)
+}
+
+func TestS3RetryTimeouts(t *testing.T) {
+	srv := newS3Server(t)
+	faults := newFaultServer(t, srv.URL)
+	ctx := context.Background()
+	newStorage := func(policy *api.RetryPolicy) *blob.Blob {
+		return newBackendStorage(t, &api.Backend{
+			S3: &api.S3Spec{
+				Endpoint: faults.URL,
+				Bucket:   s3Bucket,
+				Region:   "us-east-1",
+			},
+			RetryPolicy: policy,
+		}, map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+		})
+	}
+	policy := fastRetryPolicy()
+	policy.AttemptTimeout = &metav1.Duration{Duration: 200 * time.Millisecond}
+	storage := newStorage(policy)
+	assert.Nil(t, storage.Upload(ctx, "slow", []byte(sampleData), ""))
+
+	// a hanging attempt is abandoned and retried
+	faults.Inject(retrytest.Fault{Delay: 10 * time.Second})
+	got, err := storage.Get(ctx, "slow")
+	assert.Nil(t, err)
+	assert.Equal(t, sampleData, string(got))
+
+	// the overall timeout ends the retries
+	policy = fastRetryPolicy()
+	policy.MaxAttempts = 10
+	policy.AttemptTimeout = &metav1.Duration{Duration: 800 * time.Millisecond}
+	policy.Timeout = &metav1.Duration{Duration: time.Second}
+	storage = newStorage(policy)
+	faults.Inject(
+		retrytest.Fault{Delay: 10 * time.Second},
+		retrytest.Fault{Delay: 10 * time.Second},
+	)
+	start := time.Now()
+	_, err = storage.Get(ctx, "slow")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestRestRetry(t *testing.T) {
+	srv := resttest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Username = restUsername
+	srv.Password = restPassword
+	srv.CreateRepository(restRepo)
+	faults := newFaultServer(t, srv.URL)
+	ctx := context.Background()
+	storage := newBackendStorage(t, &api.Backend{
+		Rest:        &api.RestServerSpec{URL: faults.URL + restRepo},
+		RetryPolicy: fastRetryPolicy(),
+	}, map[string][]byte{
+		api.REST_SERVER_USERNAME: []byte(restUsername),
+		api.REST_SERVER_PASSWORD: []byte(restPassword),
+	})
+	key := "snapshots/" + restID(sampleData)
+
+	faults.Inject(
+		retrytest.Fault{Status: http.StatusTooManyRequests},
+		retrytest.Fault{Drop: true},
+	)
+	assert.Nil(t, storage.Upload(ctx, key, []byte(sampleData), ""))
+	data, ok := srv.Object(restRepo, key)
+	assert.True(t, ok)
+	assert.Equal(t, sampleData, string(data))
+
+	faults.Inject(retrytest.Fault{Status: http.StatusBadGateway})
+	exists, err := storage.Exists(ctx, key)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	assert.Zero(t, faults.Pending())
+}
@@ -0,0 +1,134 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob
+
+import (
+	"context"
+	"testing"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	aws2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+)
+
+func TestS3ClientConfig(t *testing.T) {
+	cases := []struct {
+		name string
+		spec api.S3Spec
+		// url of the bucket as resolved from the client options
+		url        string
+		pathStyle  bool
+		sigV2      bool
+		accelerate bool
+	}{
+		{
+			name: "aws",
+			spec: api.S3Spec{Region: "us-east-2"},
+			url:  "https://stash.s3.us-east-2.amazonaws.com",
+		},
+		{
+			name: "aws endpoint",
+			spec: api.S3Spec{Endpoint: "https://s3.us-east-2.amazonaws.com", Region: "us-east-2"},
+			url:  "https://stash.s3.us-east-2.amazonaws.com",
+		},
+		{
+			name:      "aws path-style",
+			spec:      api.S3Spec{Region: "us-east-2", AddressingStyle: api.S3AddressingStylePath},
+			url:       "https://s3.us-east-2.amazonaws.com/stash",
+			pathStyle: true,
+		},
+		{
+			name: "aws dual-stack",
+			spec: api.S3Spec{Endpoint: "https://s3.us-east-2.amazonaws.com", Region: "us-east-2", DualStack: true},
+			url:  "https://stash.s3.dualstack.us-east-2.amazonaws.com",
+		},
+		{
+			name: "aws fips",
+			spec: api.S3Spec{Region: "us-east-2", FIPS: true},
+			url:  "https://stash.s3-fips.us-east-2.amazonaws.com",
+		},
+		{
+			name: "aws fips dual-stack",
+			spec: api.S3Spec{Region: "us-east-2", FIPS: true, DualStack: true},
+			url:  "https://stash.s3-fips.dualstack.us-east-2.amazonaws.com",
+		},
+		{
+			name:       "aws accelerate",
+			spec:       api.S3Spec{Region: "us-east-2", Accelerate: true},
+			url:        "https://stash.s3-accelerate.amazonaws.com",
+			accelerate: true,
+		},
+		{
+			name:      "s3 compatible",
+			spec:      api.S3Spec{Endpoint: "https://minio.example.com:9000", Region: "us-east-1"},
+			url:       "https://minio.example.com:9000/stash",
+			pathStyle: true,
+		},
+		{
+			name: "s3 compatible virtual-hosted",
+			spec: api.S3Spec{Endpoint: "https://minio.example.com:9000", Region: "us-east-1", AddressingStyle: api.S3AddressingStyleVirtual},
+			url:  "https://stash.minio.example.com:9000",
+		},
+		{
+			name:      "v2 signatures",
+			spec:      api.S3Spec{Endpoint: "http://ceph.example.com", Region: "us-east-1", SignatureVersion: api.S3SignatureV2},
+			url:       "http://ceph.example.com/stash",
+			pathStyle: true,
+			sigV2:     true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := tc.spec
+			spec.Bucket = "stash"
+			b, err := s3Blob(&core.Secret{Data: map[string][]byte{
+				awsAccessKeyId:     []byte("id"),
+				awsSecretAccessKey: []byte("key"),
+			}}, &api.Backend{S3: &spec})
+			if !assert.Nil(t, err) {
+				return
+			}
+			client, err := b.newS3Client(context.Background(), false)
+			if !assert.Nil(t, err) {
+				return
+			}
+			opts := client.Options()
+			assert.Equal(t, tc.pathStyle, opts.UsePathStyle)
+			assert.Equal(t, tc.accelerate, opts.UseAccelerate)
+			if tc.sigV2 {
+				assert.Equal(t, aws2.RequestChecksumCalculationWhenRequired, opts.RequestChecksumCalculation)
+			}
+
+			endpoint, err := opts.EndpointResolverV2.ResolveEndpoint(context.Background(), s3.EndpointParameters{
+				Bucket:         aws2.String("stash"),
+				Region:         aws2.String(opts.Region),
+				Endpoint:       opts.BaseEndpoint,
+				UseFIPS:        aws2.Bool(opts.EndpointOptions.UseFIPSEndpoint == aws2.FIPSEndpointStateEnabled),
+				UseDualStack:   aws2.Bool(opts.EndpointOptions.UseDualStackEndpoint == aws2.DualStackEndpointStateEnabled),
+				ForcePathStyle: aws2.Bool(opts.UsePathStyle),
+				Accelerate:     aws2.Bool(opts.UseAccelerate),
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, tc.url, endpoint.URI.String())
+		})
+	}
+}
@@ -0,0 +1,737 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/blob"
+	"kmodules.xyz/objectstore-api/pkg/credsource"
+	"kmodules.xyz/objectstore-api/pkg/s3auth"
+	"kmodules.xyz/objectstore-api/pkg/s3test"
+
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	s3Bucket     = "stash"
+	s3SecretName = "s3-secret"
+	// base64 of the raw customer key and of its md5 sum
+	s3CustomerKey       = "0123456789abcdef0123456789abcdef"
+	s3CustomerKeyBase64 = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	s3CustomerKeyMD5    = "hRasmdxgYDKV3nvbahU1MA=="
+)
+
+func newS3Server(t *testing.T) *s3test.Server {
+	srv := s3test.NewServer()
+	t.Cleanup(srv.Close)
+	srv.CreateBucket(s3Bucket)
+	return srv
+}
+
+func newS3Storage(t *testing.T, srv *s3test.Server, spec *api.S3Spec, data map[string][]byte) (*blob.Blob, error) {
+	t.Helper()
+	secret := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s3SecretName,
+			Namespace: "db",
+		},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+		},
+	}
+	for k, v := range data {
+		secret.Data[k] = v
+	}
+	fakeClient, err := getFakeClient(secret)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	spec.Endpoint = srv.URL
+	spec.Bucket = s3Bucket
+	spec.Region = "us-east-1"
+	return blob.NewBlob(context.Background(), fakeClient, "db", &api.Backend{
+		StorageSecretName: s3SecretName,
+		S3:                spec,
+	})
+}
+
+func TestS3Encryption(t *testing.T) {
+	cases := []struct {
+		name       string
+		encryption *api.S3Encryption
+		data       map[string][]byte
+		// headers expected on every write
+		writeHeaders map[string]string
+		// headers expected on every read
+		readHeaders map[string]string
+	}{
+		{
+			name: "none",
+			writeHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption": "",
+			},
+		},
+		{
+			name:       "sse-s3",
+			encryption: &api.S3Encryption{Type: api.S3EncryptionSSES3},
+			writeHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption":                "AES256",
+				"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id": "",
+			},
+			readHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption": "",
+			},
+		},
+		{
+			name: "sse-kms",
+			encryption: &api.S3Encryption{
+				Type:                 api.S3EncryptionSSEKMS,
+				KMSKeyID:             "alias/backup",
+				KMSEncryptionContext: map[string]string{"team": "db"},
+			},
+			writeHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption":                "aws:kms",
+				"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id": "alias/backup",
+				"X-Amz-Server-Side-Encryption-Context":        "eyJ0ZWFtIjoiZGIifQ==",
+			},
+			readHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id": "",
+			},
+		},
+		{
+			name:       "sse-c",
+			encryption: &api.S3Encryption{Type: api.S3EncryptionSSEC},
+			data: map[string][]byte{
+				api.AWS_SSE_CUSTOMER_KEY: []byte(s3CustomerKeyBase64),
+			},
+			writeHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption":                    "",
+				"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256",
+				"X-Amz-Server-Side-Encryption-Customer-Key":       s3CustomerKeyBase64,
+				"X-Amz-Server-Side-Encryption-Customer-Key-Md5":   s3CustomerKeyMD5,
+			},
+			readHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256",
+				"X-Amz-Server-Side-Encryption-Customer-Key":       s3CustomerKeyBase64,
+				"X-Amz-Server-Side-Encryption-Customer-Key-Md5":   s3CustomerKeyMD5,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newS3Server(t)
+			storage, err := newS3Storage(t, srv, &api.S3Spec{Encryption: tc.encryption}, tc.data)
+			if !assert.Nil(t, err) {
+				return
+			}
+			ctx := context.Background()
+			key := filepath.Join(testPath, sampleFile)
+
+			assert.Nil(t, storage.Upload(ctx, key, []byte(sampleData), "text/plain"))
+			assert.Nil(t, storage.SetPathAsDir(ctx, "snapshots"))
+			exists, err := storage.Exists(ctx, key)
+			assert.Nil(t, err)
+			assert.True(t, exists)
+			data, err := storage.Get(ctx, key)
+			assert.Nil(t, err)
+			assert.Equal(t, sampleData, string(data))
+			assert.Len(t, srv.Keys(s3Bucket), 2)
+
+			var writes, reads int
+			for _, req := range srv.Requests() {
+				var expected map[string]string
+				switch req.Method {
+				case http.MethodPut:
+					writes++
+					expected = tc.writeHeaders
+				case http.MethodGet, http.MethodHead:
+					reads++
+					expected = tc.readHeaders
+				}
+				for h, v := range expected {
+					assert.Equal(t, v, req.Header.Get(h), "%s %s: %s", req.Method, req.Key, h)
+				}
+			}
+			assert.Equal(t, 2, writes)
+			assert.Equal(t, 2, reads)
+		})
+	}
+}
+
+func TestS3CustomerKeyRequiredForReads(t *testing.T) {
+	srv := newS3Server(t)
+	ctx := context.Background()
+	key := filepath.Join(testPath, sampleFile)
+
+	// a raw 32 byte key is accepted too
+	storage, err := newS3Storage(t, srv, &api.S3Spec{
+		Encryption: &api.S3Encryption{Type: api.S3EncryptionSSEC},
+	}, map[string][]byte{
+		api.AWS_SSE_CUSTOMER_KEY: []byte(s3CustomerKey),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, storage.Upload(ctx, key, []byte(sampleData), ""))
+	obj, ok := srv.Object(s3Bucket, key)
+	if assert.True(t, ok) {
+		assert.Equal(t, s3CustomerKeyMD5, obj.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key-Md5"))
+	}
+
+	plain, err := newS3Storage(t, srv, &api.S3Spec{}, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = plain.Get(ctx, key)
+	assert.NotNil(t, err)
+}
+
+func TestS3CustomerKeyMissing(t *testing.T) {
+	srv := newS3Server(t)
+	for _, val := range [][]byte{nil, []byte("too-short")} {
+		data := map[string][]byte{}
+		if val != nil {
+			data[api.AWS_SSE_CUSTOMER_KEY] = val
+		}
+		_, err := newS3Storage(t, srv, &api.S3Spec{
+			Encryption: &api.S3Encryption{Type: api.S3EncryptionSSEC},
+		}, data)
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), api.AWS_SSE_CUSTOMER_KEY)
+		}
+	}
+}
+
+func TestS3WriteOptions(t *testing.T) {
+	srv := newS3Server(t)
+	storage, err := newS3Storage(t, srv, &api.S3Spec{
+		StorageClass: "STANDARD_IA",
+		ACL:          "bucket-owner-full-control",
+		Tags:         map[string]string{"team": "db", "env": "prod"},
+	}, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	ctx := context.Background()
+
+	assert.Nil(t, storage.Upload(ctx, "defaults.txt", []byte(sampleData), ""))
+	assert.Nil(t, storage.UploadWithOptions(ctx, "override.txt", []byte(sampleData), blob.UploadOptions{
+		ContentType:  "text/plain",
+		StorageClass: "GLACIER_IR",
+		ACL:          "private",
+		Tags:         map[string]string{"team": "billing", "snapshot": "a b"},
+	}))
+	assert.Nil(t, storage.SetPathAsDir(ctx, "snapshots"))
+
+	expected := map[string]map[string]string{
+		"defaults.txt": {
+			"X-Amz-Storage-Class": "STANDARD_IA",
+			"X-Amz-Acl":           "bucket-owner-full-control",
+			"X-Amz-Tagging":       "env=prod&team=db",
+		},
+		"override.txt": {
+			"Content-Type":        "text/plain",
+			"X-Amz-Storage-Class": "GLACIER_IR",
+			"X-Amz-Acl":           "private",
+			"X-Amz-Tagging":       "env=prod&snapshot=a+b&team=billing",
+		},
+		"snapshots": {
+			"X-Amz-Storage-Class": "STANDARD_IA",
+			"X-Amz-Acl":           "bucket-owner-full-control",
+			"X-Amz-Tagging":       "env=prod&team=db",
+		},
+	}
+	var puts int
+	for _, req := range srv.Requests() {
+		if req.Method != http.MethodPut {
+			continue
+		}
+		puts++
+		headers, ok := expected[path.Base(req.Key)]
+		if !assert.True(t, ok, "unexpected upload of %s", req.Key) {
+			continue
+		}
+		for h, v := range headers {
+			assert.Equal(t, v, req.Header.Get(h), "%s: %s", req.Key, h)
+		}
+	}
+	assert.Equal(t, len(expected), puts)
+}
+
+func TestS3WriteOptionsUnset(t *testing.T) {
+	srv := newS3Server(t)
+	storage, err := newS3Storage(t, srv, &api.S3Spec{}, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, storage.Upload(context.Background(), "plain.txt", []byte(sampleData), ""))
+	for _, req := range srv.Requests() {
+		for _, h := range []string{"X-Amz-Storage-Class", "X-Amz-Acl", "X-Amz-Tagging"} {
+			assert.Empty(t, req.Header.Get(h), h)
+		}
+	}
+}
+
+func TestS3SignatureV2(t *testing.T) {
+	srv := newS3Server(t)
+	storage, err := newS3Storage(t, srv, &api.S3Spec{
+		SignatureVersion: api.S3SignatureV2,
+	}, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	ctx := context.Background()
+	key := filepath.Join(testPath, sampleFile)
+
+	assert.Nil(t, storage.Upload(ctx, key, []byte(sampleData), ""))
+	data, err := storage.Get(ctx, key)
+	if assert.Nil(t, err) {
+		assert.Equal(t, sampleData, string(data))
+	}
+
+	reqs := srv.Requests()
+	assert.NotEmpty(t, reqs)
+	for _, req := range reqs {
+		assert.Regexp(t, `^AWS id:\S+=$`, req.Header.Get("Authorization"), "%s %s", req.Method, req.Key)
+		assert.NotEmpty(t, req.Header.Get("Date"))
+		assert.Empty(t, req.Header.Get("X-Amz-Date"))
+	}
+}
+
+func TestS3SecretKeyMapping(t *testing.T) {
+	srv := newS3Server(t)
+	// a secret of a bucket provisioner, with its own key names
+	fakeClient, err := getFakeClient(&core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bucket-creds", Namespace: "db"},
+		Data: map[string][]byte{
+			"accessKey": []byte("provisioned-id"),
+			"secretKey": []byte("provisioned-key"),
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	backend := &api.Backend{
+		S3: &api.S3Spec{Endpoint: srv.URL, Bucket: s3Bucket, Region: "us-east-1"},
+		StorageSecret: &api.StorageSecretReference{
+			Name: "bucket-creds",
+			Keys: map[string]string{
+				api.AWS_ACCESS_KEY_ID:     "accessKey",
+				api.AWS_SECRET_ACCESS_KEY: "secretKey",
+			},
+		},
+	}
+	storage, err := blob.NewBlob(context.Background(), fakeClient, "db", backend)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, storage.Upload(context.Background(), sampleFile, []byte(sampleData), ""))
+	for _, req := range srv.Requests() {
+		assert.Contains(t, req.Header.Get("Authorization"), "Credential=provisioned-id/")
+	}
+
+	backend.StorageSecret.Keys[api.AWS_SECRET_ACCESS_KEY] = "secret-key"
+	_, err = blob.NewBlob(context.Background(), fakeClient, "db", backend)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `storage secret db/bucket-creds is missing key "secret-key" for AWS_SECRET_ACCESS_KEY`, err.Error())
+	}
+}
+
+func TestS3CrossNamespaceCredentials(t *testing.T) {
+	srv := newS3Server(t)
+	keyFile := filepath.Join(t.TempDir(), "secret-key")
+	if !assert.Nil(t, os.WriteFile(keyFile, []byte("platform-key"), 0o600)) {
+		return
+	}
+	fakeClient, err := getFakeClient(&core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bucket-creds", Namespace: "platform"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID: []byte("platform-id"),
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	backend := &api.Backend{
+		S3: &api.S3Spec{Endpoint: srv.URL, Bucket: s3Bucket, Region: "us-east-1"},
+		StorageSecret: &api.StorageSecretReference{
+			Name:      "bucket-creds",
+			Namespace: "platform",
+			Sources: []api.CredentialKeySource{
+				{Key: api.AWS_SECRET_ACCESS_KEY, File: keyFile},
+			},
+		},
+	}
+
+	_, err = blob.NewBlob(context.Background(), fakeClient, "db", backend)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Secret platform/bucket-creds can not be read from namespace db", err.Error())
+	}
+
+	storage, err := blob.NewBlob(context.Background(), fakeClient, "db", backend,
+		credsource.WithNamespacePolicy(credsource.AllowNamespaces("platform")))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, storage.Upload(context.Background(), sampleFile, []byte(sampleData), ""))
+	for _, req := range srv.Requests() {
+		assert.Contains(t, req.Header.Get("Authorization"), "Credential=platform-id/")
+	}
+}
+
+func TestS3AssumeRole(t *testing.T) {
+	srv := newS3Server(t)
+	sts := s3test.NewSTSServer()
+	defer sts.Close()
+	sts.ExternalID = "tenant-a"
+
+	storage, err := newS3Storage(t, srv, &api.S3Spec{
+		RoleARN:         "arn:aws:iam::123456789012:role/backup",
+		ExternalID:      "tenant-a",
+		RoleSessionName: "stash",
+		STSEndpoint:     sts.URL,
+	}, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, storage.Upload(context.Background(), sampleFile, []byte(sampleData), ""))
+
+	calls := sts.Requests()
+	if assert.Len(t, calls, 1) {
+		assert.Equal(t, "AssumeRole", calls[0].Action)
+		assert.Equal(t, "arn:aws:iam::123456789012:role/backup", calls[0].Form.Get("RoleArn"))
+		assert.Equal(t, "stash", calls[0].Form.Get("RoleSessionName"))
+		// signed with the access keys of the storage secret
+		assert.Contains(t, calls[0].Header.Get("Authorization"), "Credential=id/")
+	}
+	issued := sts.Issued()
+	for _, req := range srv.Requests() {
+		assert.Contains(t, req.Header.Get("Authorization"), "Credential="+issued[0].AccessKeyID+"/")
+		assert.Equal(t, issued[0].SessionToken, req.Header.Get("X-Amz-Security-Token"))
+	}
+
+	storage, err = newS3Storage(t, srv, &api.S3Spec{
+		RoleARN:     "arn:aws:iam::123456789012:role/backup",
+		ExternalID:  "tenant-b",
+		STSEndpoint: sts.URL,
+	}, nil)
+	if assert.Nil(t, err) {
+		err = storage.Upload(context.Background(), sampleFile, []byte(sampleData), "")
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), "AccessDenied")
+		}
+	}
+}
+
+func TestS3WebIdentity(t *testing.T) {
+	srv := newS3Server(t)
+	sts := s3test.NewSTSServer()
+	defer sts.Close()
+	sts.WebIdentityToken = "projected-token"
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if !assert.Nil(t, os.WriteFile(tokenFile, []byte("projected-token"), 0o600)) {
+		return
+	}
+
+	// no storage secret, the endpoint of the compatible service must still be used
+	fakeClient, err := getFakeClient()
+	if !assert.Nil(t, err) {
+		return
+	}
+	storage, err := blob.NewBlob(context.Background(), fakeClient, "db", &api.Backend{
+		S3: &api.S3Spec{
+			Endpoint:             srv.URL,
+			Bucket:               s3Bucket,
+			Region:               "us-east-1",
+			RoleARN:              "arn:minio:iam:::role/backup",
+			WebIdentityTokenFile: tokenFile,
+			STSEndpoint:          sts.URL,
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	ctx := context.Background()
+	assert.Nil(t, storage.Upload(ctx, sampleFile, []byte(sampleData), ""))
+	data, err := storage.Get(ctx, sampleFile)
+	if assert.Nil(t, err) {
+		assert.Equal(t, sampleData, string(data))
+	}
+
+	calls := sts.Requests()
+	if assert.Len(t, calls, 1) {
+		assert.Equal(t, "AssumeRoleWithWebIdentity", calls[0].Action)
+		assert.Empty(t, calls[0].Header.Get("Authorization"))
+	}
+	issued := sts.Issued()
+	reqs := srv.Requests()
+	assert.Len(t, reqs, 2)
+	for _, req := range reqs {
+		assert.Contains(t, req.Header.Get("Authorization"), "Credential="+issued[0].AccessKeyID+"/")
+	}
+}
+
+func TestS3SessionToken(t *testing.T) {
+	srv := newS3Server(t)
+	storage, err := newS3Storage(t, srv, &api.S3Spec{}, map[string][]byte{
+		api.AWS_SESSION_TOKEN:         []byte("session-token"),
+		api.AWS_CREDENTIAL_EXPIRATION: []byte(time.Now().Add(time.Hour).Format(time.RFC3339)),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, storage.Upload(context.Background(), sampleFile, []byte(sampleData), ""))
+	for _, req := range srv.Requests() {
+		assert.Equal(t, "session-token", req.Header.Get("X-Amz-Security-Token"))
+	}
+
+	// S3 rejects the token once it expired
+	srv.ExpireSessionToken("session-token")
+	_, err = storage.Get(context.Background(), sampleFile)
+	if assert.NotNil(t, err) {
+		assert.True(t, s3auth.IsExpiredCredentials(err), err.Error())
+	}
+}
+
+func TestS3ExpiredCredentials(t *testing.T) {
+	srv := newS3Server(t)
+	expires := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	storage, err := newS3Storage(t, srv, &api.S3Spec{}, map[string][]byte{
+		api.AWS_SESSION_TOKEN:         []byte("session-token"),
+		api.AWS_CREDENTIAL_EXPIRATION: []byte(expires.Format(time.RFC3339)),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	err = storage.Upload(context.Background(), sampleFile, []byte(sampleData), "")
+	var expired *s3auth.ExpiredCredentialsError
+	if assert.True(t, errors.As(err, &expired)) {
+		assert.True(t, expires.Equal(expired.Expires))
+	}
+	// the expired credentials are not sent
+	assert.Empty(t, srv.Requests())
+}
+
+func TestS3Profile(t *testing.T) {
+	srv := newS3Server(t)
+	secret := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s3SecretName,
+			Namespace: "db",
+		},
+		Data: map[string][]byte{
+			api.AWS_SHARED_CREDENTIALS: []byte("[default]\naws_access_key_id = default-id\naws_secret_access_key = default-key\n\n" +
+				"[backup]\naws_access_key_id = backup-id\naws_secret_access_key = backup-key\naws_session_token = backup-token\n"),
+			api.AWS_CONFIG: []byte("[profile backup]\nregion = us-east-1\n"),
+		},
+	}
+	fakeClient, err := getFakeClient(secret)
+	if !assert.Nil(t, err) {
+		return
+	}
+	storage, err := blob.NewBlob(context.Background(), fakeClient, "db", &api.Backend{
+		StorageSecretName: s3SecretName,
+		S3: &api.S3Spec{
+			Endpoint: srv.URL,
+			Bucket:   s3Bucket,
+			Profile:  "backup",
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, storage.Upload(context.Background(), sampleFile, []byte(sampleData), ""))
+	reqs := srv.Requests()
+	if assert.NotEmpty(t, reqs) {
+		for _, req := range reqs {
+			assert.Contains(t, req.Header.Get("Authorization"), "Credential=backup-id/")
+			assert.Equal(t, "backup-token", req.Header.Get("X-Amz-Security-Token"))
+		}
+	}
+}
+
+func TestS3ProfileWithoutFiles(t *testing.T) {
+	srv := newS3Server(t)
+	storage, err := newS3Storage(t, srv, &api.S3Spec{
+		Profile: "backup",
+	}, nil)
+	if assert.Nil(t, err) {
+		err = storage.Upload(context.Background(), sampleFile, []byte(sampleData), "")
+		assert.EqualError(t, err, "storage secret db/s3-secret profile backup requires AWS_SHARED_CREDENTIALS or AWS_CONFIG")
+	}
+}
+
+func TestS3ProfileWithoutSecret(t *testing.T) {
+	srv := newS3Server(t)
+	storage, err := blob.NewBlob(context.Background(), nil, "db", &api.Backend{
+		S3: &api.S3Spec{
+			Endpoint: srv.URL,
+			Bucket:   s3Bucket,
+			Region:   "us-east-1",
+			Profile:  "backup",
+		},
+	})
+	if assert.Nil(t, err) {
+		err = storage.Upload(context.Background(), sampleFile, []byte(sampleData), "")
+		assert.EqualError(t, err, "profile backup requires AWS_SHARED_CREDENTIALS or AWS_CONFIG")
+	}
+}
+
+func TestS3StaticCredentialSource(t *testing.T) {
+	srv := newS3Server(t)
+	// no kubernetes client is needed
+	storage, err := blob.NewBlob(context.Background(), nil, "db", &api.Backend{
+		S3: &api.S3Spec{
+			Endpoint: srv.URL,
+			Bucket:   s3Bucket,
+			Region:   "us-east-1",
+		},
+	}, credsource.WithSource(credsource.NewStaticSource(map[string][]byte{
+		api.AWS_ACCESS_KEY_ID:     []byte("static-id"),
+		api.AWS_SECRET_ACCESS_KEY: []byte("static-key"),
+	})))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, storage.Upload(context.Background(), sampleFile, []byte(sampleData), ""))
+	for _, req := range srv.Requests() {
+		assert.Contains(t, req.Header.Get("Authorization"), "Credential=static-id/")
+	}
+}
+
+func TestS3PerInstanceCredentials(t *testing.T) {
+	srv := newS3Server(t)
+	tenants := []string{"tenant-a", "tenant-b"}
+	storages := map[string]*blob.Blob{}
+	for _, tenant := range tenants {
+		storage, err := blob.NewBlob(context.Background(), nil, tenant, &api.Backend{
+			S3: &api.S3Spec{
+				Endpoint: srv.URL,
+				Bucket:   s3Bucket,
+				Region:   "us-east-1",
+			},
+		}, credsource.WithSource(credsource.NewStaticSource(map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte(tenant + "-id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte(tenant + "-key"),
+		})))
+		if !assert.Nil(t, err) {
+			return
+		}
+		storages[tenant] = storage
+	}
+
+	var wg sync.WaitGroup
+	for tenant, storage := range storages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				assert.Nil(t, storage.Upload(context.Background(), path.Join(tenant, strconv.Itoa(i)), []byte(sampleData), ""))
+			}
+		}()
+	}
+	wg.Wait()
+
+	reqs := srv.Requests()
+	assert.Len(t, reqs, 20)
+	for _, req := range reqs {
+		tenant, _, _ := strings.Cut(req.Key, "/")
+		assert.Contains(t, req.Header.Get("Authorization"), "Credential="+tenant+"-id/")
+	}
+}
+
+func TestS3CredentialRotation(t *testing.T) {
+	srv := newS3Server(t)
+	secret := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: s3SecretName, Namespace: "db"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("old-id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("old-key"),
+		},
+	}
+	fakeClient, err := getFakeClient(secret)
+	if !assert.Nil(t, err) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	storage, err := blob.NewBlob(ctx, fakeClient, "db", &api.Backend{
+		StorageSecretName: s3SecretName,
+		S3: &api.S3Spec{
+			Endpoint: srv.URL,
+			Bucket:   s3Bucket,
+			Region:   "us-east-1",
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	rotated := make(chan error, 1)
+	if !assert.Nil(t, storage.WatchCredentials(ctx, func(err error) { rotated <- err })) {
+		return
+	}
+	assert.Nil(t, storage.Upload(ctx, sampleFile, []byte(sampleData), ""))
+
+	secret.Data[api.AWS_ACCESS_KEY_ID] = []byte("new-id")
+	secret.Data[api.AWS_SECRET_ACCESS_KEY] = []byte("new-key")
+	if !assert.Nil(t, fakeClient.Update(ctx, secret)) {
+		return
+	}
+	select {
+	case err := <-rotated:
+		assert.Nil(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("credentials were not rotated")
+	}
+	assert.Nil(t, storage.Upload(ctx, sampleFile, []byte(sampleData), ""))
+
+	reqs := srv.Requests()
+	if assert.Len(t, reqs, 2) {
+		assert.Contains(t, reqs[0].Header.Get("Authorization"), "Credential=old-id/")
+		assert.Contains(t, reqs[1].Header.Get("Authorization"), "Credential=new-id/")
+	}
+
+	// changes that break the credentials are reported and the last ones are kept
+	delete(secret.Data, api.AWS_SECRET_ACCESS_KEY)
+	if !assert.Nil(t, fakeClient.Update(ctx, secret)) {
+		return
+	}
+	select {
+	case err := <-rotated:
+		assert.EqualError(t, err, "storage secret db/s3-secret missing AWS_SECRET_ACCESS_KEY key")
+	case <-time.After(10 * time.Second):
+		t.Fatal("invalid credentials were not reported")
+	}
+	assert.Nil(t, storage.Upload(ctx, sampleFile, []byte(sampleData), ""))
+	reqs = srv.Requests()
+	assert.Contains(t, reqs[len(reqs)-1].Header.Get("Authorization"), "Credential=new-id/")
+}
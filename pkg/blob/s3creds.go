@@ -0,0 +1,61 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"kmodules.xyz/objectstore-api/pkg/s3auth"
+
+	aws2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// expiringProvider provides the access keys of the storage secret. Unlike
+// the static provider of the sdk, it knows when temporary access keys expire
+// and refuses to sign requests with them afterwards.
+type expiringProvider struct {
+	aws2.Credentials
+}
+
+func (p *expiringProvider) Retrieve(context.Context) (aws2.Credentials, error) {
+	if p.Expired() {
+		return aws2.Credentials{}, &s3auth.ExpiredCredentialsError{
+			Expires: p.Expires,
+			Err:     errors.New("access keys of the storage secret expired at " + p.Expires.Format(time.RFC3339)),
+		}
+	}
+	return p.Credentials, nil
+}
+
+// addExpiredCredentialsMiddleware turns the errors S3 returns for expired
+// session tokens into an s3auth.ExpiredCredentialsError, so callers don't
+// have to tell them apart from other access denied errors.
+func addExpiredCredentialsMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("ExpiredCredentials",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			out, md, err := next.HandleInitialize(ctx, in)
+			var apiErr smithy.APIError
+			if err != nil && errors.As(err, &apiErr) && s3auth.IsExpiredCode(apiErr.ErrorCode()) {
+				err = &s3auth.ExpiredCredentialsError{Err: err}
+			}
+			return out, md, err
+		}), middleware.After)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"kmodules.xyz/objectstore-api/pkg/s3auth"
+
+	aws2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// useSigV2 replaces the v4 signer of the client with a v2 signer, in the
+// same way the s3 location of osm does. The v2 signature
+// covers the path of the url, so it is only used with path-style addressing.
+func useSigV2(creds aws2.CredentialsProvider) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		_, err := stack.Finalize.Swap("Signing", middleware.FinalizeMiddlewareFunc("Signing",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				req, ok := in.Request.(*smithyhttp.Request)
+				if !ok {
+					return middleware.FinalizeOutput{}, middleware.Metadata{}, fmt.Errorf("unexpected transport type %T", in.Request)
+				}
+				if creds != nil {
+					c, err := creds.Retrieve(ctx)
+					if err != nil {
+						return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+					}
+					req.Header.Del("X-Amz-Date")
+					req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+					s3auth.SignV2(req.Request, c.AccessKeyID, c.SecretAccessKey, c.SessionToken)
+				}
+				return next.HandleFinalize(ctx, in)
+			}))
+		return err
+	}
+}
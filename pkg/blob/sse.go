@@ -0,0 +1,81 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob
+
+import (
+	"context"
+
+	"kmodules.xyz/objectstore-api/pkg/s3sse"
+
+	aws2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// addSSEMiddleware sets the server side encryption of every object written
+// by the client, and sends the SSE-C key with every read of an object. It
+// hooks into the client instead of the gocloud writer and reader options, as
+// gocloud has no hook for the HeadObject calls behind Exists.
+func addSSEMiddleware(sse *s3sse.Config) func(*middleware.Stack) error {
+	algorithm, key, keyMD5 := sse.CustomerKeyHeaders()
+	setCustomerKey := func(a, k, m **string) {
+		if key != "" && *k == nil {
+			*a, *k, *m = aws2.String(algorithm), aws2.String(key), aws2.String(keyMD5)
+		}
+	}
+
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("ServerSideEncryption",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				switch p := in.Parameters.(type) {
+				case *s3.PutObjectInput:
+					setEncryption(sse, &p.ServerSideEncryption, &p.SSEKMSKeyId, &p.SSEKMSEncryptionContext)
+					setCustomerKey(&p.SSECustomerAlgorithm, &p.SSECustomerKey, &p.SSECustomerKeyMD5)
+				case *s3.CreateMultipartUploadInput:
+					setEncryption(sse, &p.ServerSideEncryption, &p.SSEKMSKeyId, &p.SSEKMSEncryptionContext)
+					setCustomerKey(&p.SSECustomerAlgorithm, &p.SSECustomerKey, &p.SSECustomerKeyMD5)
+				case *s3.CopyObjectInput:
+					setEncryption(sse, &p.ServerSideEncryption, &p.SSEKMSKeyId, &p.SSEKMSEncryptionContext)
+					setCustomerKey(&p.SSECustomerAlgorithm, &p.SSECustomerKey, &p.SSECustomerKeyMD5)
+					setCustomerKey(&p.CopySourceSSECustomerAlgorithm, &p.CopySourceSSECustomerKey, &p.CopySourceSSECustomerKeyMD5)
+				case *s3.UploadPartInput:
+					setCustomerKey(&p.SSECustomerAlgorithm, &p.SSECustomerKey, &p.SSECustomerKeyMD5)
+				case *s3.GetObjectInput:
+					setCustomerKey(&p.SSECustomerAlgorithm, &p.SSECustomerKey, &p.SSECustomerKeyMD5)
+				case *s3.HeadObjectInput:
+					setCustomerKey(&p.SSECustomerAlgorithm, &p.SSECustomerKey, &p.SSECustomerKeyMD5)
+				}
+				return next.HandleInitialize(ctx, in)
+			}), middleware.Before)
+	}
+}
+
+// setEncryption applies SSE-S3 or SSE-KMS unless the request already asks
+// for an encryption.
+func setEncryption(sse *s3sse.Config, typ *s3types.ServerSideEncryption, keyID, kmsContext **string) {
+	if sse.ServerSideEncryption == "" || *typ != "" {
+		return
+	}
+	*typ = s3types.ServerSideEncryption(sse.ServerSideEncryption)
+	if sse.KMSKeyID != "" {
+		*keyID = aws2.String(sse.KMSKeyID)
+	}
+	if sse.KMSEncryptionContext != "" {
+		*kmsContext = aws2.String(sse.KMSEncryptionContext)
+	}
+}
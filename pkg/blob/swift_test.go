@@ -0,0 +1,212 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	osconst "kmodules.xyz/constants/openstack"
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/blob"
+	"kmodules.xyz/objectstore-api/pkg/blob/swiftblob/swifttest"
+
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	swiftUsername   = "swift-user"
+	swiftPassword   = "swift-password"
+	swiftTenant     = "backups"
+	swiftContainer  = "stash-backup"
+	swiftSecretName = "swift-secret"
+)
+
+func newSwiftServer(t *testing.T) *swifttest.Server {
+	srv := swifttest.NewServer(swiftUsername, swiftPassword)
+	t.Cleanup(srv.Close)
+	srv.Tenant = swiftTenant
+	srv.CreateContainer(swiftContainer)
+	return srv
+}
+
+func newSwiftStorage(t *testing.T, data map[string][]byte) (*blob.Blob, error) {
+	t.Helper()
+	secret := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      swiftSecretName,
+			Namespace: "db",
+		},
+		Data: data,
+	}
+	fakeClient, err := getFakeClient(secret)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return blob.NewBlob(context.Background(), fakeClient, "db", &api.Backend{
+		StorageSecretName: swiftSecretName,
+		Swift: &api.SwiftSpec{
+			Container: swiftContainer,
+			Prefix:    prefix,
+		},
+	})
+}
+
+func swiftSecretData(srv *swifttest.Server) map[string]map[string][]byte {
+	return map[string]map[string][]byte{
+		"v1": {
+			osconst.ST_AUTH: []byte(srv.AuthURL(1)),
+			osconst.ST_USER: []byte(swiftUsername),
+			osconst.ST_KEY:  []byte(swiftPassword),
+		},
+		"v2": {
+			osconst.OS_AUTH_URL:    []byte(srv.AuthURL(2)),
+			osconst.OS_USERNAME:    []byte(swiftUsername),
+			osconst.OS_PASSWORD:    []byte(swiftPassword),
+			osconst.OS_TENANT_NAME: []byte(swiftTenant),
+			osconst.OS_REGION_NAME: []byte(srv.Region),
+		},
+		"v3": {
+			osconst.OS_AUTH_URL:            []byte(srv.AuthURL(3)),
+			osconst.OS_USERNAME:            []byte(swiftUsername),
+			osconst.OS_PASSWORD:            []byte(swiftPassword),
+			osconst.OS_USER_DOMAIN_NAME:    []byte(srv.Domain),
+			osconst.OS_PROJECT_NAME:        []byte(swiftTenant),
+			osconst.OS_PROJECT_DOMAIN_NAME: []byte(srv.Domain),
+			osconst.OS_REGION_NAME:         []byte(srv.Region),
+		},
+		"manual": {
+			osconst.OS_STORAGE_URL: []byte(srv.StorageURL()),
+			osconst.OS_AUTH_TOKEN:  []byte(srv.IssueToken()),
+		},
+	}
+}
+
+func TestSwiftAuthModes(t *testing.T) {
+	srv := newSwiftServer(t)
+	ctx := context.Background()
+	key := filepath.Join(testPath, sampleFile)
+
+	for name, data := range swiftSecretData(srv) {
+		t.Run(name, func(t *testing.T) {
+			storage, err := newSwiftStorage(t, data)
+			if !assert.Nil(t, err) {
+				return
+			}
+			authRequests := srv.AuthRequests()
+
+			assert.Nil(t, storage.Upload(ctx, key, []byte(sampleData), "text/plain"))
+			stored, ok := srv.Object(swiftContainer, prefix+"/"+key)
+			assert.True(t, ok, "object must be stored under the prefix")
+			assert.Equal(t, sampleData, string(stored))
+
+			exists, err := storage.Exists(ctx, key)
+			assert.Nil(t, err)
+			assert.True(t, exists)
+
+			d, err := storage.Get(ctx, key)
+			assert.Nil(t, err)
+			assert.Equal(t, sampleData, string(d))
+
+			assert.Nil(t, storage.Delete(ctx, key, false))
+			_, err = storage.Get(ctx, key)
+			assert.True(t, isNotFound(err))
+
+			if name == "manual" {
+				assert.Equal(t, authRequests, srv.AuthRequests(), "manual token must not authenticate")
+			} else {
+				assert.Equal(t, authRequests+1, srv.AuthRequests(), "connection must be shared")
+			}
+		})
+	}
+}
+
+func TestSwiftListAndDeleteDir(t *testing.T) {
+	srv := newSwiftServer(t)
+	storage, err := newSwiftStorage(t, swiftSecretData(srv)["v3"])
+	if !assert.Nil(t, err) {
+		return
+	}
+	ctx := context.Background()
+
+	paths := []string{"sample1.txt", "sample2.txt", "sample3.txt"}
+	for i, p := range paths {
+		assert.Nil(t, storage.Upload(ctx, filepath.Join(testPath, p), []byte(fmt.Sprintf("sample data %d", i+1)), ""))
+	}
+	assert.Nil(t, storage.Upload(ctx, filepath.Join(testPath, "snapshots", "s1", "meta.json"), []byte("{}"), ""))
+
+	objects, err := storage.List(ctx, testPath)
+	assert.Nil(t, err)
+	if assert.Len(t, objects, 4) {
+		for i := range paths {
+			assert.Equal(t, fmt.Sprintf("sample data %d", i+1), string(objects[i]))
+		}
+	}
+
+	dirs, err := storage.ListDirN(ctx, "", -1)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("data/"), []byte("data/snapshots/"), []byte("data/snapshots/s1/")}, dirs)
+
+	assert.Nil(t, storage.Delete(ctx, testPath, true))
+	assert.Empty(t, srv.Keys(swiftContainer))
+}
+
+func TestSwiftExpiredToken(t *testing.T) {
+	srv := newSwiftServer(t)
+	storage, err := newSwiftStorage(t, swiftSecretData(srv)["v2"])
+	if !assert.Nil(t, err) {
+		return
+	}
+	ctx := context.Background()
+	key := filepath.Join(testPath, sampleFile)
+
+	assert.Nil(t, storage.Upload(ctx, key, []byte(sampleData), ""))
+	srv.ExpireTokens()
+	d, err := storage.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, sampleData, string(d))
+	srv.ExpireTokens()
+	assert.Nil(t, storage.Upload(ctx, key, []byte("updated"), ""))
+	stored, _ := srv.Object(swiftContainer, prefix+"/"+key)
+	assert.Equal(t, "updated", string(stored))
+	assert.Equal(t, 3, srv.AuthRequests())
+}
+
+func TestSwiftWrongRegion(t *testing.T) {
+	srv := newSwiftServer(t)
+	data := swiftSecretData(srv)["v3"]
+	data[osconst.OS_REGION_NAME] = []byte("RegionTwo")
+	storage, err := newSwiftStorage(t, data)
+	if !assert.Nil(t, err) {
+		return
+	}
+	err = storage.Upload(context.Background(), filepath.Join(testPath, sampleFile), []byte(sampleData), "")
+	assert.NotNil(t, err)
+	assert.Empty(t, srv.Keys(swiftContainer))
+}
+
+func TestSwiftMissingCredentials(t *testing.T) {
+	_, err := newSwiftStorage(t, map[string][]byte{
+		osconst.OS_USERNAME: []byte(swiftUsername),
+		osconst.OS_PASSWORD: []byte(swiftPassword),
+	})
+	assert.EqualError(t, err, "storage secret db/swift-secret missing OS_AUTH_URL or OS_STORAGE_URL/OS_AUTH_TOKEN keys")
+}
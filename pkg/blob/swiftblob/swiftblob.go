@@ -0,0 +1,394 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package swiftblob provides a gocloud.dev/blob driver for OpenStack Swift.
+package swiftblob // import "kmodules.xyz/objectstore-api/pkg/blob/swiftblob"
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ncw/swift"
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/driver"
+	"gocloud.dev/gcerrors"
+)
+
+const defaultPageSize = 1000
+
+// OpenBucket returns a *blob.Bucket for the named Swift container. The
+// connection is authenticated on first use unless it already carries a
+// storage url and token.
+func OpenBucket(_ context.Context, conn *swift.Connection, container string) (*blob.Bucket, error) {
+	if conn == nil {
+		return nil, errors.New("swiftblob.OpenBucket: conn is required")
+	}
+	if container == "" {
+		return nil, errors.New("swiftblob.OpenBucket: container is required")
+	}
+	return blob.NewBucket(&bucket{conn: conn, container: container}), nil
+}
+
+type bucket struct {
+	conn      *swift.Connection
+	container string
+}
+
+func (b *bucket) ErrorCode(err error) gcerrors.ErrorCode {
+	if errors.Is(err, errNotImplemented) {
+		return gcerrors.Unimplemented
+	}
+	var e *swift.Error
+	if errors.As(err, &e) {
+		switch e.StatusCode {
+		case http.StatusNotFound:
+			return gcerrors.NotFound
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return gcerrors.PermissionDenied
+		case http.StatusBadRequest:
+			return gcerrors.InvalidArgument
+		case http.StatusConflict, http.StatusPreconditionFailed:
+			return gcerrors.FailedPrecondition
+		case http.StatusTooManyRequests, swift.RateLimit.StatusCode:
+			return gcerrors.ResourceExhausted
+		}
+	}
+	return gcerrors.Unknown
+}
+
+func (b *bucket) As(i any) bool {
+	p, ok := i.(**swift.Connection)
+	if !ok {
+		return false
+	}
+	*p = b.conn
+	return true
+}
+
+func (b *bucket) ErrorAs(err error, i any) bool {
+	return errors.As(err, i)
+}
+
+func (b *bucket) Attributes(_ context.Context, key string) (*driver.Attributes, error) {
+	info, headers, err := b.conn.Object(b.container, key)
+	if err != nil {
+		return nil, err
+	}
+	return &driver.Attributes{
+		CacheControl:       headers["Cache-Control"],
+		ContentDisposition: headers["Content-Disposition"],
+		ContentEncoding:    headers["Content-Encoding"],
+		ContentLanguage:    headers["Content-Language"],
+		ContentType:        info.ContentType,
+		Metadata:           headers.ObjectMetadata(),
+		ModTime:            info.LastModified,
+		Size:               info.Bytes,
+		MD5:                md5(info.Hash),
+		ETag:               strconv.Quote(strings.Trim(info.Hash, `"`)),
+		AsFunc: func(i any) bool {
+			p, ok := i.(*swift.Headers)
+			if !ok {
+				return false
+			}
+			*p = headers
+			return true
+		},
+	}, nil
+}
+
+// md5 decodes an etag that is the md5 of the content. Large object etags
+// are quoted md5s of their manifest, which are not returned.
+func md5(etag string) []byte {
+	sum, err := hex.DecodeString(etag)
+	if err != nil || len(sum) != 16 {
+		return nil
+	}
+	return sum
+}
+
+func (b *bucket) ListPaged(_ context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	pageSize := opts.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	in := swift.ObjectsOpts{
+		Prefix: opts.Prefix,
+		Marker: string(opts.PageToken),
+		Limit:  pageSize,
+	}
+	if opts.Delimiter != "" {
+		d := []rune(opts.Delimiter)
+		if len(d) != 1 {
+			return nil, swift.BadRequest
+		}
+		in.Delimiter = d[0]
+	}
+	if opts.BeforeList != nil {
+		asFunc := func(i any) bool {
+			p, ok := i.(**swift.ObjectsOpts)
+			if !ok {
+				return false
+			}
+			*p = &in
+			return true
+		}
+		if err := opts.BeforeList(asFunc); err != nil {
+			return nil, err
+		}
+	}
+	objects, err := b.conn.Objects(b.container, &in)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &driver.ListPage{}
+	if len(objects) > 0 && len(objects) == in.Limit {
+		page.NextPageToken = []byte(objects[len(objects)-1].Name)
+	}
+	for i := range objects {
+		obj := objects[i]
+		if obj.PseudoDirectory {
+			page.Objects = append(page.Objects, &driver.ListObject{
+				Key:   obj.Name,
+				IsDir: true,
+			})
+			continue
+		}
+		page.Objects = append(page.Objects, &driver.ListObject{
+			Key:     obj.Name,
+			ModTime: obj.LastModified,
+			Size:    obj.Bytes,
+			MD5:     md5(obj.Hash),
+			AsFunc: func(i any) bool {
+				p, ok := i.(*swift.Object)
+				if !ok {
+					return false
+				}
+				*p = obj
+				return true
+			},
+		})
+	}
+	return page, nil
+}
+
+func (b *bucket) NewRangeReader(_ context.Context, key string, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
+	if length == 0 {
+		// only the attributes are wanted
+		info, headers, err := b.conn.Object(b.container, key)
+		if err != nil {
+			return nil, err
+		}
+		return newReader(io.NopCloser(strings.NewReader("")), headers, info.Bytes), nil
+	}
+
+	h := swift.Headers{}
+	if length > 0 {
+		h["Range"] = fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	} else if offset > 0 {
+		h["Range"] = fmt.Sprintf("bytes=%d-", offset)
+	}
+	if opts.BeforeRead != nil {
+		asFunc := func(i any) bool {
+			p, ok := i.(*swift.Headers)
+			if !ok {
+				return false
+			}
+			*p = h
+			return true
+		}
+		if err := opts.BeforeRead(asFunc); err != nil {
+			return nil, err
+		}
+	}
+	f, headers, err := b.conn.ObjectOpen(b.container, key, false, h)
+	if err != nil {
+		return nil, err
+	}
+	size, err := f.Length()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if total := totalSize(headers["Content-Range"]); total >= 0 {
+		size = total
+	}
+	return newReader(f, headers, size), nil
+}
+
+// totalSize returns the complete length from a "bytes a-b/total" header, or
+// -1 when it is unknown.
+func totalSize(contentRange string) int64 {
+	i := strings.LastIndex(contentRange, "/")
+	if i < 0 {
+		return -1
+	}
+	n, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+type reader struct {
+	body    io.ReadCloser
+	headers swift.Headers
+	attrs   driver.ReaderAttributes
+}
+
+func newReader(body io.ReadCloser, headers swift.Headers, size int64) *reader {
+	modTime, _ := time.Parse(http.TimeFormat, headers["Last-Modified"])
+	return &reader{
+		body:    body,
+		headers: headers,
+		attrs: driver.ReaderAttributes{
+			ContentType: headers["Content-Type"],
+			ModTime:     modTime,
+			Size:        size,
+		},
+	}
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	return r.body.Read(p)
+}
+
+func (r *reader) Close() error {
+	return r.body.Close()
+}
+
+func (r *reader) Attributes() *driver.ReaderAttributes {
+	return &r.attrs
+}
+
+func (r *reader) As(i any) bool {
+	p, ok := i.(*swift.Headers)
+	if !ok {
+		return false
+	}
+	*p = r.headers
+	return true
+}
+
+func (b *bucket) NewTypedWriter(ctx context.Context, key, contentType string, opts *driver.WriterOptions) (driver.Writer, error) {
+	h := swift.Metadata(opts.Metadata).ObjectHeaders()
+	for k, v := range map[string]string{
+		"Cache-Control":       opts.CacheControl,
+		"Content-Disposition": opts.ContentDisposition,
+		"Content-Encoding":    opts.ContentEncoding,
+		"Content-Language":    opts.ContentLanguage,
+	} {
+		if v != "" {
+			h[k] = v
+		}
+	}
+	var hash string
+	if len(opts.ContentMD5) > 0 {
+		hash = hex.EncodeToString(opts.ContentMD5)
+	}
+	if opts.BeforeWrite != nil {
+		asFunc := func(i any) bool {
+			p, ok := i.(*swift.Headers)
+			if !ok {
+				return false
+			}
+			*p = h
+			return true
+		}
+		if err := opts.BeforeWrite(asFunc); err != nil {
+			return nil, err
+		}
+	}
+
+	pr, pw := io.Pipe()
+	w := &writer{
+		ctx:  ctx,
+		pw:   pw,
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		// the md5 is checked by the server when known and by the client otherwise
+		_, err := b.conn.ObjectPut(b.container, key, pr, hash == "", hash, contentType, h)
+		_ = pr.CloseWithError(err)
+		w.err = err
+	}()
+	return w, nil
+}
+
+// writer streams the content into a single PUT. Failing the pipe aborts the
+// request, so an incomplete object is never stored.
+type writer struct {
+	ctx  context.Context
+	pw   *io.PipeWriter
+	done chan struct{}
+	err  error
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *writer) Close() error {
+	if err := w.ctx.Err(); err != nil {
+		_ = w.pw.CloseWithError(err)
+		<-w.done
+		return err
+	}
+	_ = w.pw.Close()
+	<-w.done
+	return w.err
+}
+
+func (b *bucket) Copy(_ context.Context, dstKey, srcKey string, opts *driver.CopyOptions) error {
+	h := swift.Headers{}
+	if opts.BeforeCopy != nil {
+		asFunc := func(i any) bool {
+			p, ok := i.(*swift.Headers)
+			if !ok {
+				return false
+			}
+			*p = h
+			return true
+		}
+		if err := opts.BeforeCopy(asFunc); err != nil {
+			return err
+		}
+	}
+	_, err := b.conn.ObjectCopy(b.container, srcKey, b.container, dstKey, h)
+	return err
+}
+
+func (b *bucket) Delete(_ context.Context, key string) error {
+	return b.conn.ObjectDelete(b.container, key)
+}
+
+func (b *bucket) SignedURL(_ context.Context, _ string, _ *driver.SignedURLOptions) (string, error) {
+	return "", errNotImplemented
+}
+
+func (b *bucket) Close() error {
+	return nil
+}
+
+var errNotImplemented = errors.New("swiftblob: not implemented")
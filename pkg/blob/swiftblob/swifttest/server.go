@@ -0,0 +1,690 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package swifttest provides an in-memory stand-in for Keystone and the
+// Swift object api so that Swift backends can be tested without network
+// access.
+package swifttest // import "kmodules.xyz/objectstore-api/pkg/blob/swiftblob/swifttest"
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	accountPath = "/v1/AUTH_test"
+	// decoyRegion is listed first in the service catalog, so a client only
+	// reaches the working endpoint when it selects the configured region.
+	decoyRegion = "RegionTwo"
+)
+
+// Server is a Swift stand-in backed by memory. Besides the object api it
+// serves the v1 (/auth/v1.0), v2 (/v2.0/tokens) and v3 (/v3/auth/tokens)
+// auth apis.
+type Server struct {
+	*httptest.Server
+
+	Username string
+	Password string
+	// Tenant and TenantID are required in v2 requests when set, Tenant is
+	// also the project name of v3 requests.
+	Tenant   string
+	TenantID string
+	// Domain is the user and project domain of v3 requests. Keystone
+	// defaults it to "Default".
+	Domain string
+	// Region of the object-store endpoint in the service catalog.
+	Region string
+
+	mu           sync.Mutex
+	seq          int
+	authRequests int
+	tokens       map[string]bool
+	containers   map[string]map[string]*object
+}
+
+type object struct {
+	data     []byte
+	hash     string
+	modTime  time.Time
+	metadata http.Header
+}
+
+// NewServer starts a Server that accepts the given credentials.
+func NewServer(username, password string) *Server {
+	s := &Server{
+		Username:   username,
+		Password:   password,
+		Domain:     "Default",
+		Region:     "RegionOne",
+		tokens:     map[string]bool{},
+		containers: map[string]map[string]*object{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/v1.0", s.authV1)
+	mux.HandleFunc("/v2.0/tokens", s.authV2)
+	mux.HandleFunc("/v3/auth/tokens", s.authV3)
+	mux.HandleFunc("/v1/", s.storage)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// AuthURL returns the auth url for the given auth version.
+func (s *Server) AuthURL(version int) string {
+	switch version {
+	case 1:
+		return s.URL + "/auth/v1.0"
+	case 2:
+		return s.URL + "/v2.0"
+	default:
+		return s.URL + "/v3"
+	}
+}
+
+// StorageURL returns the account url served by the object api.
+func (s *Server) StorageURL() string {
+	return s.URL + accountPath
+}
+
+// IssueToken returns a valid token without an auth request, as an operator
+// would hand out for manual authentication.
+func (s *Server) IssueToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.newToken()
+}
+
+// ExpireTokens invalidates every issued token.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]bool{}
+}
+
+// AuthRequests returns the number of successful auth requests.
+func (s *Server) AuthRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.authRequests
+}
+
+// CreateContainer creates an empty container.
+func (s *Server) CreateContainer(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.containers[name]; !ok {
+		s.containers[name] = map[string]*object{}
+	}
+}
+
+// Object returns the content of a stored object.
+func (s *Server) Object(container, name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.containers[container][name]
+	if !ok {
+		return nil, false
+	}
+	return obj.data, true
+}
+
+// Keys returns the sorted object names of a container.
+func (s *Server) Keys(container string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.containers[container]))
+	for k := range s.containers[container] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// newToken must be called with mu held.
+func (s *Server) newToken() string {
+	s.seq++
+	token := fmt.Sprintf("tk_%d", s.seq)
+	s.tokens[token] = true
+	return token
+}
+
+func (s *Server) login() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.authRequests++
+	return s.newToken()
+}
+
+func (s *Server) authV1(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Header.Get("X-Auth-User") != s.Username || r.Header.Get("X-Auth-Key") != s.Password {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("X-Storage-Url", s.StorageURL())
+	w.Header().Set("X-Auth-Token", s.login())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) authV2(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Auth struct {
+			PasswordCredentials *struct {
+				Username string `json:"username"`
+				Password string `json:"password"`
+			} `json:"passwordCredentials"`
+			TenantName string `json:"tenantName"`
+			TenantID   string `json:"tenantId"`
+		} `json:"auth"`
+	}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&in) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	// the RAX-KSKEY api key variant is not supported, which makes the
+	// client retry with password credentials
+	creds := in.Auth.PasswordCredentials
+	if creds == nil ||
+		creds.Username != s.Username || creds.Password != s.Password ||
+		(s.Tenant != "" && in.Auth.TenantName != s.Tenant) ||
+		(s.TenantID != "" && in.Auth.TenantID != s.TenantID) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	type endpoint struct {
+		PublicURL   string `json:"publicURL"`
+		InternalURL string `json:"internalURL"`
+		Region      string `json:"region"`
+	}
+	var out struct {
+		Access struct {
+			Token struct {
+				ID      string `json:"id"`
+				Expires string `json:"expires"`
+			} `json:"token"`
+			ServiceCatalog []struct {
+				Type      string     `json:"type"`
+				Name      string     `json:"name"`
+				Endpoints []endpoint `json:"endpoints"`
+			} `json:"serviceCatalog"`
+		} `json:"access"`
+	}
+	out.Access.Token.ID = s.login()
+	out.Access.Token.Expires = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	out.Access.ServiceCatalog = append(out.Access.ServiceCatalog, struct {
+		Type      string     `json:"type"`
+		Name      string     `json:"name"`
+		Endpoints []endpoint `json:"endpoints"`
+	}{
+		Type: "object-store",
+		Name: "swift",
+		Endpoints: []endpoint{
+			{PublicURL: s.decoyURL(), InternalURL: s.decoyURL(), Region: decoyRegion},
+			{PublicURL: s.StorageURL(), InternalURL: s.StorageURL(), Region: s.Region},
+		},
+	})
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) authV3(w http.ResponseWriter, r *http.Request) {
+	type domain struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	var in struct {
+		Auth struct {
+			Identity struct {
+				Methods  []string `json:"methods"`
+				Password *struct {
+					User struct {
+						Name     string  `json:"name"`
+						Password string  `json:"password"`
+						Domain   *domain `json:"domain"`
+					} `json:"user"`
+				} `json:"password"`
+			} `json:"identity"`
+			Scope *struct {
+				Project *struct {
+					ID     string  `json:"id"`
+					Name   string  `json:"name"`
+					Domain *domain `json:"domain"`
+				} `json:"project"`
+			} `json:"scope"`
+		} `json:"auth"`
+	}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&in) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	domainName := func(d *domain) string {
+		if d == nil || d.Name == "" {
+			return "Default"
+		}
+		return d.Name
+	}
+	pw := in.Auth.Identity.Password
+	if pw == nil ||
+		pw.User.Name != s.Username || pw.User.Password != s.Password ||
+		domainName(pw.User.Domain) != s.Domain {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if s.Tenant != "" || s.TenantID != "" {
+		if in.Auth.Scope == nil || in.Auth.Scope.Project == nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		p := in.Auth.Scope.Project
+		if p.ID != "" {
+			if p.ID != s.TenantID {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		} else if p.Name != s.Tenant || domainName(p.Domain) != s.Domain {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	type endpoint struct {
+		URL       string `json:"url"`
+		Interface string `json:"interface"`
+		Region    string `json:"region"`
+	}
+	type service struct {
+		Type      string     `json:"type"`
+		Endpoints []endpoint `json:"endpoints"`
+	}
+	var out struct {
+		Token struct {
+			ExpiresAt string    `json:"expires_at"`
+			Catalog   []service `json:"catalog"`
+		} `json:"token"`
+	}
+	out.Token.ExpiresAt = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	out.Token.Catalog = []service{{
+		Type: "object-store",
+		Endpoints: []endpoint{
+			{URL: s.decoyURL(), Interface: "public", Region: decoyRegion},
+			{URL: s.StorageURL(), Interface: "public", Region: s.Region},
+			{URL: s.StorageURL(), Interface: "internal", Region: s.Region},
+		},
+	}}
+	w.Header().Set("X-Subject-Token", s.login())
+	writeJSON(w, http.StatusCreated, out)
+}
+
+func (s *Server) decoyURL() string {
+	return s.URL + "/v1/AUTH_decoy"
+}
+
+func (s *Server) storage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	valid := s.tokens[r.Header.Get("X-Auth-Token")]
+	s.mu.Unlock()
+	if !valid {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.URL.Path == accountPath || r.URL.Path == accountPath+"/" {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		s.listContainers(w, r)
+		return
+	}
+	rest, ok := strings.CutPrefix(r.URL.Path, accountPath+"/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	container, name, _ := strings.Cut(rest, "/")
+	if name == "" {
+		switch r.Method {
+		case http.MethodGet:
+			s.listObjects(w, r, container)
+		case http.MethodHead:
+			s.headContainer(w, container)
+		case http.MethodPut:
+			s.putContainer(w, container)
+		case http.MethodDelete:
+			s.deleteContainer(w, container)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		s.getObject(w, r, container, name)
+	case http.MethodPut:
+		s.putObject(w, r, container, name)
+	case http.MethodDelete:
+		s.deleteObject(w, container, name)
+	case "COPY":
+		s.copyObject(w, r, container, name)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+type listEntry struct {
+	Name         string `json:"name,omitempty"`
+	Bytes        int64  `json:"bytes,omitempty"`
+	Hash         string `json:"hash,omitempty"`
+	ContentType  string `json:"content_type,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Subdir       string `json:"subdir,omitempty"`
+}
+
+type containerEntry struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+	Bytes int64  `json:"bytes"`
+}
+
+func (s *Server) listContainers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	prefix := q.Get("prefix")
+	marker := q.Get("marker")
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 10000
+	}
+
+	s.mu.Lock()
+	names := make([]string, 0, len(s.containers))
+	for k := range s.containers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	entries := []containerEntry{}
+	for _, name := range names {
+		if len(entries) == limit {
+			break
+		}
+		if !strings.HasPrefix(name, prefix) || name <= marker {
+			continue
+		}
+		entry := containerEntry{Name: name, Count: int64(len(s.containers[name]))}
+		for _, obj := range s.containers[name] {
+			entry.Bytes += int64(len(obj.data))
+		}
+		entries = append(entries, entry)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func (s *Server) putContainer(w http.ResponseWriter, container string) {
+	s.mu.Lock()
+	_, ok := s.containers[container]
+	if !ok {
+		s.containers[container] = map[string]*object{}
+	}
+	s.mu.Unlock()
+	if ok {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// deleteContainer refuses to delete a container with objects, as swift does.
+func (s *Server) deleteContainer(w http.ResponseWriter, container string) {
+	s.mu.Lock()
+	objects, ok := s.containers[container]
+	empty := len(objects) == 0
+	if ok && empty {
+		delete(s.containers, container)
+	}
+	s.mu.Unlock()
+	switch {
+	case !ok:
+		w.WriteHeader(http.StatusNotFound)
+	case !empty:
+		w.WriteHeader(http.StatusConflict)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) headContainer(w http.ResponseWriter, container string) {
+	s.mu.Lock()
+	objects, ok := s.containers[container]
+	var used int
+	for _, obj := range objects {
+		used += len(obj.data)
+	}
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("X-Container-Object-Count", strconv.Itoa(len(objects)))
+	w.Header().Set("X-Container-Bytes-Used", strconv.Itoa(used))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listObjects(w http.ResponseWriter, r *http.Request, container string) {
+	q := r.URL.Query()
+	prefix := q.Get("prefix")
+	delimiter := q.Get("delimiter")
+	marker := q.Get("marker")
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 10000
+	}
+
+	s.mu.Lock()
+	objects, ok := s.containers[container]
+	if !ok {
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	names := make([]string, 0, len(objects))
+	for k := range objects {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	entries := []listEntry{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		if len(entries) == limit {
+			break
+		}
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if i := strings.Index(name[len(prefix):], delimiter); i >= 0 {
+				dir := name[:len(prefix)+i+len(delimiter)]
+				if dir > marker && !seen[dir] {
+					seen[dir] = true
+					entries = append(entries, listEntry{Subdir: dir})
+				}
+				continue
+			}
+		}
+		if name <= marker {
+			continue
+		}
+		obj := objects[name]
+		entries = append(entries, listEntry{
+			Name:         name,
+			Bytes:        int64(len(obj.data)),
+			Hash:         obj.hash,
+			ContentType:  obj.metadata.Get("Content-Type"),
+			LastModified: obj.modTime.UTC().Format("2006-01-02T15:04:05.000000"),
+		})
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func (s *Server) lookup(container, name string) (*object, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	objects, ok := s.containers[container]
+	if !ok {
+		return nil, http.StatusNotFound
+	}
+	obj, ok := objects[name]
+	if !ok {
+		return nil, http.StatusNotFound
+	}
+	return obj, http.StatusOK
+}
+
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request, container, name string) {
+	obj, status := s.lookup(container, name)
+	if obj == nil {
+		w.WriteHeader(status)
+		return
+	}
+	for k, v := range obj.metadata {
+		w.Header()[k] = v
+	}
+	w.Header().Set("Etag", obj.hash)
+	http.ServeContent(w, r, name, obj.modTime, bytes.NewReader(obj.data))
+}
+
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request, container, name string) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		// an aborted upload is never stored
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	sum := md5.Sum(data)
+	hash := hex.EncodeToString(sum[:])
+	if etag := r.Header.Get("Etag"); etag != "" && !strings.EqualFold(etag, hash) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+	obj := &object{
+		data:     data,
+		hash:     hash,
+		modTime:  time.Now().Truncate(time.Second),
+		metadata: storedHeaders(r.Header),
+	}
+	if obj.metadata.Get("Content-Type") == "" {
+		obj.metadata.Set("Content-Type", "application/octet-stream")
+	}
+
+	s.mu.Lock()
+	objects, ok := s.containers[container]
+	if ok {
+		objects[name] = obj
+	}
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Etag", hash)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) deleteObject(w http.ResponseWriter, container, name string) {
+	s.mu.Lock()
+	_, ok := s.containers[container][name]
+	delete(s.containers[container], name)
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) copyObject(w http.ResponseWriter, r *http.Request, container, name string) {
+	src, status := s.lookup(container, name)
+	if src == nil {
+		w.WriteHeader(status)
+		return
+	}
+	dst, err := url.PathUnescape(r.Header.Get("Destination"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	dstContainer, dstName, ok := strings.Cut(strings.TrimPrefix(dst, "/"), "/")
+	if !ok || dstName == "" {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+	obj := *src
+	obj.modTime = time.Now().Truncate(time.Second)
+	obj.metadata = src.metadata.Clone()
+	for k, v := range storedHeaders(r.Header) {
+		obj.metadata[k] = v
+	}
+
+	s.mu.Lock()
+	objects, ok := s.containers[dstContainer]
+	if ok {
+		objects[dstName] = &obj
+	}
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// storedHeaders returns the request headers that are kept with an object.
+func storedHeaders(h http.Header) http.Header {
+	out := http.Header{}
+	for k, v := range h {
+		switch {
+		case strings.HasPrefix(k, "X-Object-Meta-"),
+			k == "Content-Type",
+			k == "Cache-Control",
+			k == "Content-Disposition",
+			k == "Content-Encoding",
+			k == "Content-Language":
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package objectstore provides the controller of the ObjectStore kind. It
+// checks the buckets of the ObjectStores periodically with the checks of
+// osm.CheckBucketAccess and publishes the results in their status.
+package objectstore // import "kmodules.xyz/objectstore-api/pkg/controller/objectstore"
+
+import (
+	"context"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/credsource"
+	"kmodules.xyz/objectstore-api/pkg/osm"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciler checks the bucket of an ObjectStore every CheckInterval. An
+// ObjectStore whose spec does not pass validation is not checked until its
+// spec changes.
+type Reconciler struct {
+	client.Client
+
+	// Namespace is the namespace that the storage secrets are read from,
+	// usually the namespace of the controller.
+	Namespace string
+	// Options of the credential lookup, eg. credsource.WithNamespacePolicy
+	// to allow the storage secrets of other namespaces.
+	Options []credsource.Option
+	// Clock defaults to the real clock.
+	Clock clock.PassiveClock
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+// SetupWithManager adds the reconciler to mgr. Only the changes of the specs
+// trigger a check, the status updates do not.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("objectstore").
+		For(&api.ObjectStore{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var obj api.ObjectStore
+	if err := r.Get(ctx, req.NamespacedName, &obj); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if obj.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	spec := obj.Spec.DeepCopy()
+	api.SetDefaults_ObjectStoreSpec(spec)
+	interval := spec.CheckInterval.Duration
+	now := r.clock().Now()
+
+	// a requeued request or a resync does not check the bucket before it is due
+	if obj.Status.ObservedGeneration == obj.Generation && obj.Status.LastCheckTime != nil {
+		if next := obj.Status.LastCheckTime.Add(interval); now.Before(next) {
+			return reconcile.Result{RequeueAfter: next.Sub(now)}, nil
+		}
+	}
+
+	orig := obj.DeepCopy()
+	obj.Status.ObservedGeneration = obj.Generation
+	ready := metav1.Condition{
+		Type:               api.ObjectStoreConditionReady,
+		ObservedGeneration: obj.Generation,
+		LastTransitionTime: metav1.NewTime(now),
+	}
+
+	if errs := spec.ValidateWithPath(field.NewPath("spec")); len(errs) > 0 {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = api.ObjectStoreReasonInvalidSpec
+		ready.Message = errs.ToAggregate().Error()
+		meta.SetStatusCondition(&obj.Status.Conditions, ready)
+		return reconcile.Result{}, r.patchStatus(ctx, orig, &obj)
+	}
+
+	info, err := osm.InspectBucket(nil, spec.Backend, r.Namespace, credsource.WithSource(credsource.NewClientSource(r.Client, r.Options...)))
+	obj.Status.LastCheckTime = &metav1.Time{Time: now}
+	obj.Status.Region = ""
+	obj.Status.Capabilities = nil
+	if info != nil {
+		obj.Status.Region = info.Region
+		obj.Status.Capabilities = info.Capabilities
+	}
+	if err != nil {
+		logger.Info("bucket check failed", "objectstore", obj.Name, "error", err.Error())
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = api.ObjectStoreReasonCheckFailed
+		ready.Message = err.Error()
+	} else {
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = api.ObjectStoreReasonAvailable
+		ready.Message = "The bucket is reachable and writable."
+	}
+	meta.SetStatusCondition(&obj.Status.Conditions, ready)
+	if err := r.patchStatus(ctx, orig, &obj); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: interval}, nil
+}
+
+func (r *Reconciler) patchStatus(ctx context.Context, orig, obj *api.ObjectStore) error {
+	return r.Status().Patch(ctx, obj, client.MergeFrom(orig))
+}
+
+func (r *Reconciler) clock() clock.PassiveClock {
+	if r.Clock == nil {
+		return clock.RealClock{}
+	}
+	return r.Clock
+}
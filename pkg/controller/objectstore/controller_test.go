@@ -0,0 +1,204 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectstore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/controller/objectstore"
+	"kmodules.xyz/objectstore-api/pkg/s3test"
+
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	bucket    = "stash-backup"
+	namespace = "objectstore-system"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Since(t time.Time) time.Duration {
+	return c.now.Sub(t)
+}
+
+func newReconciler(t *testing.T, objs ...client.Object) (*objectstore.Reconciler, *fakeClock) {
+	scheme := runtime.NewScheme()
+	if !assert.Nil(t, core.AddToScheme(scheme)) || !assert.Nil(t, api.AddToScheme(scheme)) {
+		t.FailNow()
+	}
+	objs = append(objs, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-secret", Namespace: namespace},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+		},
+	})
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&api.ObjectStore{}).
+		Build()
+	clk := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return &objectstore.Reconciler{Client: c, Namespace: namespace, Clock: clk}, clk
+}
+
+func newObjectStore(srv *s3test.Server, bucket string) *api.ObjectStore {
+	return &api.ObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "backups", Generation: 1},
+		Spec: api.ObjectStoreSpec{
+			Backend: api.Backend{
+				StorageSecretName: "s3-secret",
+				S3:                &api.S3Spec{Endpoint: srv.URL, Bucket: bucket},
+			},
+			CheckInterval: &metav1.Duration{Duration: time.Minute},
+		},
+	}
+}
+
+func reconcileObjectStore(t *testing.T, r *objectstore.Reconciler, name string) (reconcile.Result, *api.ObjectStore) {
+	t.Helper()
+	key := types.NamespacedName{Name: name}
+	res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	var obj api.ObjectStore
+	if !assert.Nil(t, r.Get(context.Background(), key, &obj)) {
+		t.FailNow()
+	}
+	return res, &obj
+}
+
+func TestReconcile(t *testing.T) {
+	srv := s3test.NewServer()
+	t.Cleanup(srv.Close)
+	srv.CreateBucket(bucket)
+	r, clk := newReconciler(t, newObjectStore(srv, bucket))
+	start := clk.now
+
+	res, obj := reconcileObjectStore(t, r, "backups")
+	assert.Equal(t, time.Minute, res.RequeueAfter)
+	assert.Equal(t, int64(1), obj.Status.ObservedGeneration)
+	if assert.NotNil(t, obj.Status.LastCheckTime) {
+		assert.True(t, start.Equal(obj.Status.LastCheckTime.Time))
+	}
+	assert.Equal(t, api.DefaultS3Region, obj.Status.Region)
+	assert.Equal(t, []api.ObjectStoreCapability{api.CapabilityRead, api.CapabilityList, api.CapabilityWrite}, obj.Status.Capabilities)
+	ready := meta.FindStatusCondition(obj.Status.Conditions, api.ObjectStoreConditionReady)
+	if assert.NotNil(t, ready) {
+		assert.Equal(t, metav1.ConditionTrue, ready.Status)
+		assert.Equal(t, api.ObjectStoreReasonAvailable, ready.Reason)
+	}
+	assert.Empty(t, srv.Keys(bucket))
+
+	// the bucket is not checked before it is due
+	requests := len(srv.Requests())
+	clk.now = start.Add(20 * time.Second)
+	res, obj = reconcileObjectStore(t, r, "backups")
+	assert.Equal(t, 40*time.Second, res.RequeueAfter)
+	assert.Equal(t, requests, len(srv.Requests()))
+	assert.True(t, start.Equal(obj.Status.LastCheckTime.Time))
+
+	clk.now = start.Add(time.Minute)
+	res, obj = reconcileObjectStore(t, r, "backups")
+	assert.Equal(t, time.Minute, res.RequeueAfter)
+	assert.Greater(t, len(srv.Requests()), requests)
+	assert.True(t, clk.now.Equal(obj.Status.LastCheckTime.Time))
+
+	// a new spec is checked right away
+	obj.Spec.Backend.S3.Bucket = "missing"
+	obj.Generation = 2
+	if !assert.Nil(t, r.Update(context.Background(), obj)) {
+		return
+	}
+	clk.now = clk.now.Add(time.Second)
+	_, obj = reconcileObjectStore(t, r, "backups")
+	assert.Equal(t, obj.Generation, obj.Status.ObservedGeneration)
+	assert.True(t, clk.now.Equal(obj.Status.LastCheckTime.Time))
+	assert.Empty(t, obj.Status.Region)
+	assert.Empty(t, obj.Status.Capabilities)
+	ready = meta.FindStatusCondition(obj.Status.Conditions, api.ObjectStoreConditionReady)
+	if assert.NotNil(t, ready) {
+		assert.Equal(t, metav1.ConditionFalse, ready.Status)
+		assert.Equal(t, api.ObjectStoreReasonCheckFailed, ready.Reason)
+		assert.True(t, clk.now.Equal(ready.LastTransitionTime.Time))
+	}
+}
+
+func TestReconcileMissingSecret(t *testing.T) {
+	srv := s3test.NewServer()
+	t.Cleanup(srv.Close)
+	srv.CreateBucket(bucket)
+	obj := newObjectStore(srv, bucket)
+	obj.Spec.Backend.StorageSecretName = "missing"
+	r, _ := newReconciler(t, obj)
+
+	res, obj := reconcileObjectStore(t, r, "backups")
+	assert.Equal(t, time.Minute, res.RequeueAfter)
+	assert.Empty(t, srv.Requests())
+	ready := meta.FindStatusCondition(obj.Status.Conditions, api.ObjectStoreConditionReady)
+	if assert.NotNil(t, ready) {
+		assert.Equal(t, metav1.ConditionFalse, ready.Status)
+		assert.Equal(t, api.ObjectStoreReasonCheckFailed, ready.Reason)
+		assert.Contains(t, ready.Message, "not found")
+	}
+}
+
+func TestReconcileInvalidSpec(t *testing.T) {
+	srv := s3test.NewServer()
+	t.Cleanup(srv.Close)
+	obj := newObjectStore(srv, bucket)
+	obj.Spec.Backend.S3 = nil
+	obj.Spec.Backend.Local = &api.LocalSpec{MountPath: "/safe/data"}
+	r, _ := newReconciler(t, obj)
+
+	res, obj := reconcileObjectStore(t, r, "backups")
+	assert.Zero(t, res.RequeueAfter)
+	assert.Nil(t, obj.Status.LastCheckTime)
+	assert.Equal(t, int64(1), obj.Status.ObservedGeneration)
+	ready := meta.FindStatusCondition(obj.Status.Conditions, api.ObjectStoreConditionReady)
+	if assert.NotNil(t, ready) {
+		assert.Equal(t, metav1.ConditionFalse, ready.Status)
+		assert.Equal(t, api.ObjectStoreReasonInvalidSpec, ready.Reason)
+		assert.Contains(t, ready.Message, "spec.backend.local")
+	}
+}
+
+func TestReconcileDeleted(t *testing.T) {
+	r, _ := newReconciler(t)
+	res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "backups"}})
+	assert.Nil(t, err)
+	assert.Zero(t, res)
+}
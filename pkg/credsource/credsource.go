@@ -0,0 +1,280 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credsource resolves the credentials of a backend from its storage
+// secret and credential sources, so that osm and blob read them the same way.
+// Outside of kubernetes, the credentials are read from a CredentialSource,
+// eg. the environment or a mounted directory.
+package credsource // import "kmodules.xyz/objectstore-api/pkg/credsource"
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"gomodules.xyz/pointer"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Kinds of the objects passed to a NamespacePolicy
+const (
+	KindSecret    = "Secret"
+	KindConfigMap = "ConfigMap"
+)
+
+// Getter reads the secrets and config maps that hold credentials.
+type Getter interface {
+	GetSecret(ctx context.Context, namespace, name string) (*core.Secret, error)
+	GetConfigMap(ctx context.Context, namespace, name string) (*core.ConfigMap, error)
+}
+
+// NewKubernetesGetter returns a Getter that uses a client-go clientset.
+func NewKubernetesGetter(kc kubernetes.Interface) Getter {
+	return kubeGetter{kc: kc}
+}
+
+type kubeGetter struct {
+	kc kubernetes.Interface
+}
+
+func (g kubeGetter) GetSecret(ctx context.Context, namespace, name string) (*core.Secret, error) {
+	return g.kc.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (g kubeGetter) GetConfigMap(ctx context.Context, namespace, name string) (*core.ConfigMap, error) {
+	return g.kc.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// NewClientGetter returns a Getter that uses a controller-runtime client.
+func NewClientGetter(c client.Client) Getter {
+	return clientGetter{c: c}
+}
+
+type clientGetter struct {
+	c client.Client
+}
+
+func (g clientGetter) GetSecret(ctx context.Context, namespace, name string) (*core.Secret, error) {
+	var secret core.Secret
+	if err := g.c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &secret); err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+func (g clientGetter) GetConfigMap(ctx context.Context, namespace, name string) (*core.ConfigMap, error) {
+	var cm core.ConfigMap
+	if err := g.c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &cm); err != nil {
+		return nil, err
+	}
+	return &cm, nil
+}
+
+// ObjectReference names a secret or config map that holds credentials.
+type ObjectReference struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (r ObjectReference) String() string {
+	return r.Kind + " " + r.Namespace + "/" + r.Name
+}
+
+// NamespacePolicy is called before a backend of namespace reads an object of
+// another namespace. The read is denied when it returns an error.
+type NamespacePolicy func(ctx context.Context, namespace string, obj ObjectReference) error
+
+// AllowNamespaces returns a NamespacePolicy that allows reading objects of the
+// given namespaces from any namespace.
+func AllowNamespaces(namespaces ...string) NamespacePolicy {
+	allowed := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		allowed[ns] = true
+	}
+	return func(_ context.Context, namespace string, obj ObjectReference) error {
+		if !allowed[obj.Namespace] {
+			return fmt.Errorf("namespace %s is not allowed", obj.Namespace)
+		}
+		return nil
+	}
+}
+
+// Options of Resolve
+type Options struct {
+	// NamespacePolicy allows references to other namespaces. They are denied
+	// when it is nil.
+	NamespacePolicy NamespacePolicy
+	// Source provides the credentials instead of the Getter passed to Resolve
+	Source CredentialSource
+}
+
+// Option sets an option of Resolve
+type Option func(*Options)
+
+// WithNamespacePolicy sets the NamespacePolicy of Resolve
+func WithNamespacePolicy(p NamespacePolicy) Option {
+	return func(o *Options) {
+		o.NamespacePolicy = p
+	}
+}
+
+// WithSource makes Resolve read the credentials from src instead of the
+// storage secret, so that no kubernetes client is needed.
+func WithSource(src CredentialSource) Option {
+	return func(o *Options) {
+		o.Source = src
+	}
+}
+
+// Resolve returns the credentials of a backend of namespace, keyed by the
+// credential keys of the providers, eg. AWS_ACCESS_KEY_ID. It returns nil
+// when the backend has neither a storage secret nor credential sources.
+func Resolve(ctx context.Context, g Getter, namespace string, backend api.Backend, opts ...Option) (map[string][]byte, error) {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Source != nil {
+		return o.Source.Credentials(ctx, namespace, backend)
+	}
+	return getterSource{g: g, opts: o}.Credentials(ctx, namespace, backend)
+}
+
+func (s getterSource) Credentials(ctx context.Context, namespace string, backend api.Backend) (map[string][]byte, error) {
+	r := resolver{g: s.g, namespace: namespace, refNamespace: namespace, opts: s.opts}
+	if backend.StorageSecret != nil && backend.StorageSecret.Namespace != "" {
+		r.refNamespace = backend.StorageSecret.Namespace
+	}
+
+	var data map[string][]byte
+	if name := backend.SecretName(); name != "" {
+		secret, err := r.secret(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		data, err = backend.SecretData(secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r.withSources(ctx, backend, data)
+}
+
+// withSources returns data with the values of the credential sources of the
+// backend added.
+func (r resolver) withSources(ctx context.Context, backend api.Backend, data map[string][]byte) (map[string][]byte, error) {
+	if backend.StorageSecret == nil || len(backend.StorageSecret.Sources) == 0 {
+		return data, nil
+	}
+
+	out := make(map[string][]byte, len(data)+len(backend.StorageSecret.Sources))
+	for k, v := range data {
+		out[k] = v
+	}
+	for _, src := range backend.StorageSecret.Sources {
+		v, ok, err := r.source(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out[src.Key] = v
+		}
+	}
+	return out, nil
+}
+
+type resolver struct {
+	g            Getter
+	namespace    string
+	refNamespace string
+	opts         Options
+}
+
+func (r resolver) allow(ctx context.Context, kind, name string) error {
+	obj := ObjectReference{Kind: kind, Namespace: r.refNamespace, Name: name}
+	if r.g == nil {
+		return fmt.Errorf("%s can not be read without a kubernetes credential source", obj)
+	}
+	if r.refNamespace == r.namespace {
+		return nil
+	}
+	if r.opts.NamespacePolicy == nil {
+		return fmt.Errorf("%s can not be read from namespace %s", obj, r.namespace)
+	}
+	if err := r.opts.NamespacePolicy(ctx, r.namespace, obj); err != nil {
+		return fmt.Errorf("%s can not be read from namespace %s: %w", obj, r.namespace, err)
+	}
+	return nil
+}
+
+func (r resolver) secret(ctx context.Context, name string) (*core.Secret, error) {
+	if err := r.allow(ctx, KindSecret, name); err != nil {
+		return nil, err
+	}
+	return r.g.GetSecret(ctx, r.refNamespace, name)
+}
+
+// source returns the value of src. It returns false for a missing optional key.
+func (r resolver) source(ctx context.Context, src api.CredentialKeySource) ([]byte, bool, error) {
+	switch {
+	case src.SecretKeyRef != nil:
+		ref := src.SecretKeyRef
+		secret, err := r.secret(ctx, ref.Name)
+		if err != nil {
+			return nil, false, err
+		}
+		if v, ok := secret.Data[ref.Key]; ok {
+			return v, true, nil
+		}
+		if pointer.Bool(ref.Optional) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("secret %s/%s is missing key %q for %s", secret.Namespace, secret.Name, ref.Key, src.Key)
+	case src.ConfigMapKeyRef != nil:
+		ref := src.ConfigMapKeyRef
+		if err := r.allow(ctx, KindConfigMap, ref.Name); err != nil {
+			return nil, false, err
+		}
+		cm, err := r.g.GetConfigMap(ctx, r.refNamespace, ref.Name)
+		if err != nil {
+			return nil, false, err
+		}
+		if v, ok := cm.Data[ref.Key]; ok {
+			return []byte(v), true, nil
+		}
+		if v, ok := cm.BinaryData[ref.Key]; ok {
+			return v, true, nil
+		}
+		if pointer.Bool(ref.Optional) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("config map %s/%s is missing key %q for %s", cm.Namespace, cm.Name, ref.Key, src.Key)
+	case src.File != "":
+		v, err := os.ReadFile(src.File)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read %s: %w", src.Key, err)
+		}
+		return v, true, nil
+	}
+	return nil, false, fmt.Errorf("credential source of %s has neither secretKeyRef, configMapKeyRef nor file", src.Key)
+}
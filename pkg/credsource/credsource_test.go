@@ -0,0 +1,197 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credsource
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"github.com/stretchr/testify/assert"
+	"gomodules.xyz/pointer"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testObjects() []runtime.Object {
+	return []runtime.Object{
+		&core.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "s3-secret", Namespace: "demo"},
+			Data: map[string][]byte{
+				api.AWS_ACCESS_KEY_ID:     []byte("tenant-id"),
+				api.AWS_SECRET_ACCESS_KEY: []byte("tenant-key"),
+			},
+		},
+		&core.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "bucket-creds", Namespace: "platform"},
+			Data: map[string][]byte{
+				"accessKey": []byte("platform-id"),
+				"secretKey": []byte("platform-key"),
+			},
+		},
+		&core.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "platform"},
+			Data:       map[string]string{"ca.crt": "platform-ca"},
+			BinaryData: map[string][]byte{"ca.der": []byte("der")},
+		},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if !assert.Nil(t, os.WriteFile(keyFile, []byte("file-key"), 0o600)) {
+		return
+	}
+	platform := func(sources ...api.CredentialKeySource) *api.StorageSecretReference {
+		return &api.StorageSecretReference{
+			Name:      "bucket-creds",
+			Namespace: "platform",
+			Keys: map[string]string{
+				api.AWS_ACCESS_KEY_ID:     "accessKey",
+				api.AWS_SECRET_ACCESS_KEY: "secretKey",
+			},
+			Sources: sources,
+		}
+	}
+
+	cases := []struct {
+		name     string
+		backend  api.Backend
+		opts     []Option
+		expected map[string][]byte
+		err      string
+	}{
+		{
+			name:    "no credentials",
+			backend: api.Backend{S3: &api.S3Spec{}},
+		},
+		{
+			name:    "secret of the namespace",
+			backend: api.Backend{StorageSecretName: "s3-secret", S3: &api.S3Spec{}},
+			expected: map[string][]byte{
+				api.AWS_ACCESS_KEY_ID:     []byte("tenant-id"),
+				api.AWS_SECRET_ACCESS_KEY: []byte("tenant-key"),
+			},
+		},
+		{
+			name:    "other namespace without policy",
+			backend: api.Backend{StorageSecret: platform(), S3: &api.S3Spec{}},
+			err:     "Secret platform/bucket-creds can not be read from namespace demo",
+		},
+		{
+			name:    "other namespace denied by policy",
+			backend: api.Backend{StorageSecret: platform(), S3: &api.S3Spec{}},
+			opts:    []Option{WithNamespacePolicy(AllowNamespaces("shared"))},
+			err:     "Secret platform/bucket-creds can not be read from namespace demo: namespace platform is not allowed",
+		},
+		{
+			name: "other namespace with sources",
+			backend: api.Backend{
+				StorageSecret: platform(
+					api.CredentialKeySource{
+						Key:             api.CA_CERT_DATA,
+						ConfigMapKeyRef: &core.ConfigMapKeySelector{LocalObjectReference: core.LocalObjectReference{Name: "ca"}, Key: "ca.crt"},
+					},
+					api.CredentialKeySource{
+						Key:  api.AWS_SECRET_ACCESS_KEY,
+						File: keyFile,
+					},
+					api.CredentialKeySource{
+						Key:          "OPTIONAL",
+						SecretKeyRef: &core.SecretKeySelector{LocalObjectReference: core.LocalObjectReference{Name: "bucket-creds"}, Key: "missing", Optional: pointer.TrueP()},
+					},
+				),
+				S3: &api.S3Spec{},
+			},
+			opts: []Option{WithNamespacePolicy(AllowNamespaces("platform"))},
+			expected: map[string][]byte{
+				"accessKey":               []byte("platform-id"),
+				"secretKey":               []byte("platform-key"),
+				api.AWS_ACCESS_KEY_ID:     []byte("platform-id"),
+				api.AWS_SECRET_ACCESS_KEY: []byte("file-key"),
+				api.CA_CERT_DATA:          []byte("platform-ca"),
+			},
+		},
+		{
+			name: "sources only",
+			backend: api.Backend{
+				StorageSecret: &api.StorageSecretReference{
+					Sources: []api.CredentialKeySource{{
+						Key:             api.CA_CERT_DATA,
+						ConfigMapKeyRef: &core.ConfigMapKeySelector{LocalObjectReference: core.LocalObjectReference{Name: "ca"}, Key: "ca.der"},
+					}},
+					Namespace: "platform",
+				},
+				S3: &api.S3Spec{},
+			},
+			opts: []Option{WithNamespacePolicy(func(_ context.Context, namespace string, obj ObjectReference) error {
+				if namespace == "demo" && obj.Kind == KindConfigMap {
+					return nil
+				}
+				return errors.New("denied")
+			})},
+			expected: map[string][]byte{
+				api.CA_CERT_DATA: []byte("der"),
+			},
+		},
+		{
+			name: "missing source key",
+			backend: api.Backend{
+				StorageSecretName: "s3-secret",
+				StorageSecret: &api.StorageSecretReference{
+					Sources: []api.CredentialKeySource{{
+						Key:          api.CA_CERT_DATA,
+						SecretKeyRef: &core.SecretKeySelector{LocalObjectReference: core.LocalObjectReference{Name: "s3-secret"}, Key: "ca.crt"},
+					}},
+				},
+				S3: &api.S3Spec{},
+			},
+			err: `secret demo/s3-secret is missing key "ca.crt" for CA_CERT_DATA`,
+		},
+		{
+			name: "missing file",
+			backend: api.Backend{
+				StorageSecret: &api.StorageSecretReference{
+					Sources: []api.CredentialKeySource{{Key: api.CA_CERT_DATA, File: filepath.Join(filepath.Dir(keyFile), "ca.crt")}},
+				},
+				S3: &api.S3Spec{},
+			},
+			err: "failed to read CA_CERT_DATA",
+		},
+	}
+	g := NewClientGetter(fake.NewClientBuilder().WithRuntimeObjects(testObjects()...).Build())
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := Resolve(context.Background(), g, "demo", tc.backend, tc.opts...)
+			if tc.err != "" {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), tc.err)
+				}
+				return
+			}
+			if assert.Nil(t, err) {
+				assert.Equal(t, tc.expected, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credsource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CredentialSource provides the credentials of backends. It is passed to
+// Resolve with WithSource.
+type CredentialSource interface {
+	// Credentials returns the credentials of a backend of namespace, keyed by
+	// the credential keys of the providers, eg. AWS_ACCESS_KEY_ID.
+	Credentials(ctx context.Context, namespace string, backend api.Backend) (map[string][]byte, error)
+}
+
+// NewKubernetesSource returns a CredentialSource that reads the storage
+// secret and the credential sources of a backend with a client-go clientset.
+func NewKubernetesSource(kc kubernetes.Interface, opts ...Option) CredentialSource {
+	return newGetterSource(NewKubernetesGetter(kc), opts)
+}
+
+// NewClientSource returns a CredentialSource that reads the storage secret
+// and the credential sources of a backend with a controller-runtime client.
+func NewClientSource(c client.Client, opts ...Option) CredentialSource {
+	return newGetterSource(NewClientGetter(c), opts)
+}
+
+type getterSource struct {
+	g    Getter
+	opts Options
+}
+
+func newGetterSource(g Getter, opts []Option) getterSource {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return getterSource{g: g, opts: o}
+}
+
+// NewEnvSource returns a CredentialSource that reads the credentials from
+// the environment variables that start with prefix, eg. OBJECTSTORE_ for
+// OBJECTSTORE_AWS_ACCESS_KEY_ID. The prefix is removed from the keys. With
+// an empty prefix, the whole environment is used.
+func NewEnvSource(prefix string) CredentialSource {
+	return dataSource{
+		name: "environment",
+		read: func() (map[string][]byte, error) {
+			data := map[string][]byte{}
+			for _, kv := range os.Environ() {
+				k, v, _ := strings.Cut(kv, "=")
+				if k, ok := strings.CutPrefix(k, prefix); ok && k != "" {
+					data[k] = []byte(v)
+				}
+			}
+			return data, nil
+		},
+	}
+}
+
+// NewDirSource returns a CredentialSource that reads the credentials from
+// the files of dir, one key per file, like the secrets mounted by the
+// Secrets Store CSI driver or a secret volume. Hidden files are skipped. The
+// files are read on every call, so rotated values are picked up.
+func NewDirSource(dir string) CredentialSource {
+	return dataSource{
+		name: dir,
+		read: func() (map[string][]byte, error) {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				return nil, err
+			}
+			data := map[string][]byte{}
+			for _, e := range entries {
+				// secret volumes keep the files in hidden, timestamped directories
+				if strings.HasPrefix(e.Name(), ".") {
+					continue
+				}
+				name := filepath.Join(dir, e.Name())
+				info, err := os.Stat(name)
+				if err != nil {
+					return nil, err
+				}
+				if info.IsDir() {
+					continue
+				}
+				v, err := os.ReadFile(name)
+				if err != nil {
+					return nil, err
+				}
+				data[e.Name()] = v
+			}
+			return data, nil
+		},
+	}
+}
+
+// NewStaticSource returns a CredentialSource with fixed credentials.
+func NewStaticSource(data map[string][]byte) CredentialSource {
+	return dataSource{
+		name: "static",
+		read: func() (map[string][]byte, error) {
+			return data, nil
+		},
+	}
+}
+
+// dataSource provides credentials that are not read from kubernetes. Its
+// data is used like the data of the storage secret, so the keys of the
+// backend are mapped the same way. Credential sources of the backend can
+// only reference files.
+type dataSource struct {
+	name string
+	read func() (map[string][]byte, error)
+}
+
+func (s dataSource) Credentials(ctx context.Context, namespace string, backend api.Backend) (map[string][]byte, error) {
+	data, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	data, err = backend.SecretData(&core.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      s.name,
+		},
+		Data: data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := resolver{namespace: namespace, refNamespace: namespace}
+	return r.withSources(ctx, backend, data)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credsource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSources(t *testing.T) {
+	t.Setenv("TEST_CREDSOURCE_AWS_ACCESS_KEY_ID", "env-id")
+	t.Setenv("TEST_CREDSOURCE_AWS_SECRET_ACCESS_KEY", "env-key")
+
+	// laid out like a secret volume, the keys link into a hidden directory
+	dir := t.TempDir()
+	data := filepath.Join(dir, "..data")
+	if !assert.Nil(t, os.Mkdir(data, 0o700)) {
+		return
+	}
+	for k, v := range map[string]string{"accessKey": "dir-id", "secretKey": "dir-key"} {
+		if !assert.Nil(t, os.WriteFile(filepath.Join(data, k), []byte(v), 0o600)) ||
+			!assert.Nil(t, os.Symlink(filepath.Join("..data", k), filepath.Join(dir, k))) {
+			return
+		}
+	}
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	if !assert.Nil(t, os.WriteFile(caFile, []byte("file-ca"), 0o600)) {
+		return
+	}
+
+	mapped := &api.StorageSecretReference{
+		Keys: map[string]string{
+			api.AWS_ACCESS_KEY_ID:     "accessKey",
+			api.AWS_SECRET_ACCESS_KEY: "secretKey",
+		},
+	}
+	cases := []struct {
+		name     string
+		src      CredentialSource
+		backend  api.Backend
+		expected map[string][]byte
+		err      string
+	}{
+		{
+			name:    "kubernetes secret",
+			src:     NewClientSource(fake.NewClientBuilder().WithRuntimeObjects(testObjects()...).Build()),
+			backend: api.Backend{StorageSecretName: "s3-secret", S3: &api.S3Spec{}},
+			expected: map[string][]byte{
+				api.AWS_ACCESS_KEY_ID:     []byte("tenant-id"),
+				api.AWS_SECRET_ACCESS_KEY: []byte("tenant-key"),
+			},
+		},
+		{
+			name: "kubernetes secret of other namespace",
+			src: NewClientSource(fake.NewClientBuilder().WithRuntimeObjects(testObjects()...).Build(),
+				WithNamespacePolicy(AllowNamespaces("platform"))),
+			backend: api.Backend{
+				StorageSecret: &api.StorageSecretReference{Name: "bucket-creds", Namespace: "platform", Keys: mapped.Keys},
+				S3:            &api.S3Spec{},
+			},
+			expected: map[string][]byte{
+				"accessKey":               []byte("platform-id"),
+				"secretKey":               []byte("platform-key"),
+				api.AWS_ACCESS_KEY_ID:     []byte("platform-id"),
+				api.AWS_SECRET_ACCESS_KEY: []byte("platform-key"),
+			},
+		},
+		{
+			name:    "environment",
+			src:     NewEnvSource("TEST_CREDSOURCE_"),
+			backend: api.Backend{S3: &api.S3Spec{}},
+			expected: map[string][]byte{
+				api.AWS_ACCESS_KEY_ID:     []byte("env-id"),
+				api.AWS_SECRET_ACCESS_KEY: []byte("env-key"),
+			},
+		},
+		{
+			name:    "directory with mapped keys",
+			src:     NewDirSource(dir),
+			backend: api.Backend{StorageSecret: mapped, S3: &api.S3Spec{}},
+			expected: map[string][]byte{
+				"accessKey":               []byte("dir-id"),
+				"secretKey":               []byte("dir-key"),
+				api.AWS_ACCESS_KEY_ID:     []byte("dir-id"),
+				api.AWS_SECRET_ACCESS_KEY: []byte("dir-key"),
+			},
+		},
+		{
+			name:    "missing directory",
+			src:     NewDirSource(filepath.Join(dir, "missing")),
+			backend: api.Backend{S3: &api.S3Spec{}},
+			err:     "no such file or directory",
+		},
+		{
+			name: "static with file source",
+			src: NewStaticSource(map[string][]byte{
+				api.AWS_ACCESS_KEY_ID:     []byte("static-id"),
+				api.AWS_SECRET_ACCESS_KEY: []byte("static-key"),
+			}),
+			backend: api.Backend{
+				StorageSecret: &api.StorageSecretReference{
+					Sources: []api.CredentialKeySource{{Key: api.CA_CERT_DATA, File: caFile}},
+				},
+				S3: &api.S3Spec{},
+			},
+			expected: map[string][]byte{
+				api.AWS_ACCESS_KEY_ID:     []byte("static-id"),
+				api.AWS_SECRET_ACCESS_KEY: []byte("static-key"),
+				api.CA_CERT_DATA:          []byte("file-ca"),
+			},
+		},
+		{
+			name:    "static with missing mapped key",
+			src:     NewStaticSource(map[string][]byte{"accessKey": []byte("static-id")}),
+			backend: api.Backend{StorageSecret: mapped, S3: &api.S3Spec{}},
+			err:     `storage secret demo/static is missing key "secretKey" for AWS_SECRET_ACCESS_KEY`,
+		},
+		{
+			name: "static with secret source",
+			src:  NewStaticSource(nil),
+			backend: api.Backend{
+				StorageSecret: &api.StorageSecretReference{
+					Sources: []api.CredentialKeySource{{
+						Key:          api.CA_CERT_DATA,
+						SecretKeyRef: &core.SecretKeySelector{LocalObjectReference: core.LocalObjectReference{Name: "ca"}, Key: "ca.crt"},
+					}},
+				},
+				S3: &api.S3Spec{},
+			},
+			err: "Secret demo/ca can not be read without a kubernetes credential source",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// the getter is never used with a source
+			data, err := Resolve(context.Background(), nil, "demo", tc.backend, WithSource(tc.src))
+			if tc.err != "" {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), tc.err)
+				}
+				return
+			}
+			if assert.Nil(t, err) {
+				assert.Equal(t, tc.expected, data)
+			}
+		})
+	}
+}
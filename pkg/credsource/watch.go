@@ -0,0 +1,241 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rewatchInterval is the wait before a watch that was closed by the api
+// server is started again.
+const rewatchInterval = time.Second
+
+// RotationHook is called after the credentials of a Blob or an osm Location
+// changed. err is set when the new credentials could not be used, the
+// previous ones are kept then.
+type RotationHook func(err error)
+
+// ObjectWatcher is implemented by the Getters that can watch the secrets and
+// config maps that hold credentials.
+type ObjectWatcher interface {
+	// WatchObject watches the object of ref. The watch starts after
+	// resourceVersion, or with the current object when it is empty.
+	WatchObject(ctx context.Context, ref ObjectReference, resourceVersion string) (watch.Interface, error)
+}
+
+// nameSelector selects the object of ref by its name.
+func nameSelector(ref ObjectReference) fields.Selector {
+	return fields.OneTermEqualSelector("metadata.name", ref.Name)
+}
+
+func (g kubeGetter) WatchObject(ctx context.Context, ref ObjectReference, resourceVersion string) (watch.Interface, error) {
+	opts := metav1.ListOptions{
+		FieldSelector:   nameSelector(ref).String(),
+		ResourceVersion: resourceVersion,
+	}
+	if ref.Kind == KindConfigMap {
+		return g.kc.CoreV1().ConfigMaps(ref.Namespace).Watch(ctx, opts)
+	}
+	return g.kc.CoreV1().Secrets(ref.Namespace).Watch(ctx, opts)
+}
+
+func (g clientGetter) WatchObject(ctx context.Context, ref ObjectReference, resourceVersion string) (watch.Interface, error) {
+	w, ok := g.c.(client.WithWatch)
+	if !ok {
+		return nil, errors.New("client does not support watching objects, use client.NewWithWatch")
+	}
+	var list client.ObjectList = &core.SecretList{}
+	if ref.Kind == KindConfigMap {
+		list = &core.ConfigMapList{}
+	}
+	return w.Watch(ctx, list,
+		&client.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: resourceVersion}},
+		client.InNamespace(ref.Namespace),
+		client.MatchingFieldsSelector{Selector: nameSelector(ref)},
+	)
+}
+
+// Watch calls fn whenever the credentials of a backend of namespace change,
+// with the new credentials or the error of resolving them. It watches the
+// storage secret and the secrets and config maps referenced by the
+// credential sources until ctx is done, g must be an ObjectWatcher. fn is not
+// called for changes that leave the credentials as they are.
+func Watch(ctx context.Context, g Getter, namespace string, backend api.Backend, fn func(map[string][]byte, error), opts ...Option) error {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Source != nil {
+		return errors.New("credentials of a CredentialSource can not be watched")
+	}
+	ow, ok := g.(ObjectWatcher)
+	if !ok {
+		return fmt.Errorf("%T can not watch objects", g)
+	}
+	refs := watchedObjects(namespace, backend)
+	if len(refs) == 0 {
+		return errors.New("backend has no storage secret to watch")
+	}
+
+	last, err := Resolve(ctx, g, namespace, backend, opts...)
+	if err != nil {
+		return err
+	}
+	watches := make([]watch.Interface, 0, len(refs))
+	for _, ref := range refs {
+		w, err := ow.WatchObject(ctx, ref, "")
+		if err != nil {
+			for _, w := range watches {
+				w.Stop()
+			}
+			return err
+		}
+		watches = append(watches, w)
+	}
+	changed := make(chan struct{}, 1)
+	for i, ref := range refs {
+		go watchObject(ctx, ow, ref, watches[i], changed)
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+			}
+			data, err := Resolve(ctx, g, namespace, backend, opts...)
+			if err == nil && reflect.DeepEqual(data, last) {
+				continue
+			}
+			if err == nil {
+				last = data
+			}
+			fn(data, err)
+		}
+	}()
+	return nil
+}
+
+// watchObject signals changed whenever the object of ref is added or updated,
+// until ctx is done. A watch that was closed by the api server is started
+// again after the last resource version it delivered, so no change is missed.
+func watchObject(ctx context.Context, ow ObjectWatcher, ref ObjectReference, w watch.Interface, changed chan<- struct{}) {
+	defer func() {
+		if w != nil {
+			w.Stop()
+		}
+	}()
+	var resourceVersion string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				w = rewatch(ctx, ow, ref, resourceVersion)
+				if w == nil {
+					return
+				}
+				continue
+			}
+			if e.Type == watch.Error {
+				// eg. the resource version is too old, the next watch starts
+				// with the current object instead
+				resourceVersion = ""
+				continue
+			}
+			obj, err := meta.Accessor(e.Object)
+			if err != nil || obj.GetName() != ref.Name {
+				continue
+			}
+			resourceVersion = obj.GetResourceVersion()
+			if e.Type == watch.Deleted {
+				// the last credentials are kept while the object is deleted
+				continue
+			}
+			select {
+			case changed <- struct{}{}:
+			default:
+				// a change is pending already
+			}
+		}
+	}
+}
+
+// rewatch starts the watch of ref again after resourceVersion. It returns nil
+// when ctx is done first.
+func rewatch(ctx context.Context, ow ObjectWatcher, ref ObjectReference, resourceVersion string) watch.Interface {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(rewatchInterval):
+		}
+		w, err := ow.WatchObject(ctx, ref, resourceVersion)
+		if err == nil {
+			return w
+		}
+		if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+			resourceVersion = ""
+		}
+	}
+}
+
+// watchedObjects returns the secrets and config maps the credentials of a
+// backend of namespace are read from.
+func watchedObjects(namespace string, backend api.Backend) []ObjectReference {
+	if backend.StorageSecret != nil && backend.StorageSecret.Namespace != "" {
+		namespace = backend.StorageSecret.Namespace
+	}
+	var refs []ObjectReference
+	seen := map[ObjectReference]bool{}
+	add := func(kind, name string) {
+		ref := ObjectReference{Kind: kind, Namespace: namespace, Name: name}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	if name := backend.SecretName(); name != "" {
+		add(KindSecret, name)
+	}
+	if backend.StorageSecret != nil {
+		for _, src := range backend.StorageSecret.Sources {
+			if src.SecretKeyRef != nil {
+				add(KindSecret, src.SecretKeyRef.Name)
+			}
+			if src.ConfigMapKeyRef != nil {
+				add(KindConfigMap, src.ConfigMapKeyRef.Name)
+			}
+		}
+	}
+	return refs
+}
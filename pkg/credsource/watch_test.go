@@ -0,0 +1,198 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credsource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWatch(t *testing.T) {
+	secret := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("old-id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("old-key"),
+		},
+	}
+	other := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "demo"},
+	}
+	c := fake.NewClientBuilder().WithObjects(secret, other).Build()
+	backend := api.Backend{StorageSecretName: "s3-secret", S3: &api.S3Spec{}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan map[string][]byte, 10)
+	err := Watch(ctx, NewClientGetter(c), "demo", backend, func(data map[string][]byte, err error) {
+		assert.Nil(t, err)
+		changes <- data
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// neither other secrets nor updates that keep the credentials are reported
+	other.Labels = map[string]string{"changed": "true"}
+	assert.Nil(t, c.Update(ctx, other))
+	secret.Labels = map[string]string{"changed": "true"}
+	assert.Nil(t, c.Update(ctx, secret))
+	secret.Data[api.AWS_SECRET_ACCESS_KEY] = []byte("new-key")
+	assert.Nil(t, c.Update(ctx, secret))
+	select {
+	case data := <-changes:
+		assert.Equal(t, "new-key", string(data[api.AWS_SECRET_ACCESS_KEY]))
+	case <-time.After(10 * time.Second):
+		t.Fatal("change was not reported")
+	}
+	select {
+	case data := <-changes:
+		t.Errorf("unexpected change %v", data)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	err = Watch(ctx, NewClientGetter(c), "demo", backend, nil, WithSource(NewStaticSource(nil)))
+	assert.EqualError(t, err, "credentials of a CredentialSource can not be watched")
+}
+
+func TestWatchConfigMap(t *testing.T) {
+	secret := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+		},
+	}
+	cm := &core.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "demo"},
+		Data:       map[string]string{"ca.crt": "old-ca"},
+	}
+	c := fake.NewClientBuilder().WithObjects(secret, cm).Build()
+	backend := api.Backend{
+		StorageSecret: &api.StorageSecretReference{
+			Name: "s3-secret",
+			Sources: []api.CredentialKeySource{{
+				Key:             api.CA_CERT_DATA,
+				ConfigMapKeyRef: &core.ConfigMapKeySelector{LocalObjectReference: core.LocalObjectReference{Name: "ca"}, Key: "ca.crt"},
+			}},
+		},
+		S3: &api.S3Spec{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan map[string][]byte, 10)
+	err := Watch(ctx, NewClientGetter(c), "demo", backend, func(data map[string][]byte, err error) {
+		assert.Nil(t, err)
+		changes <- data
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	cm.Data["ca.crt"] = "new-ca"
+	assert.Nil(t, c.Update(ctx, cm))
+	select {
+	case data := <-changes:
+		assert.Equal(t, "new-ca", string(data[api.CA_CERT_DATA]))
+	case <-time.After(10 * time.Second):
+		t.Fatal("change was not reported")
+	}
+}
+
+// recordingClient records the options of its watches, the test sends their
+// events.
+type recordingClient struct {
+	client.WithWatch
+	watches chan recordedWatch
+}
+
+type recordedWatch struct {
+	opts client.ListOptions
+	w    *watch.FakeWatcher
+}
+
+func (c *recordingClient) Watch(_ context.Context, _ client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+	var o client.ListOptions
+	o.ApplyOptions(opts)
+	w := watch.NewFake()
+	c.watches <- recordedWatch{opts: o, w: w}
+	return w, nil
+}
+
+func TestWatchResumes(t *testing.T) {
+	secret := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("old-key"),
+		},
+	}
+	c := &recordingClient{
+		WithWatch: fake.NewClientBuilder().WithObjects(secret).Build(),
+		watches:   make(chan recordedWatch, 2),
+	}
+	backend := api.Backend{StorageSecretName: "s3-secret", S3: &api.S3Spec{}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan map[string][]byte, 10)
+	err := Watch(ctx, NewClientGetter(c), "demo", backend, func(data map[string][]byte, err error) {
+		assert.Nil(t, err)
+		changes <- data
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// only the secret of the backend is watched
+	first := <-c.watches
+	assert.Equal(t, "demo", first.opts.Namespace)
+	assert.Equal(t, "metadata.name=s3-secret", first.opts.FieldSelector.String())
+	assert.Empty(t, first.opts.Raw.ResourceVersion)
+
+	secret.Data[api.AWS_SECRET_ACCESS_KEY] = []byte("new-key")
+	assert.Nil(t, c.Update(ctx, secret))
+	first.w.Modify(secret)
+	select {
+	case data := <-changes:
+		assert.Equal(t, "new-key", string(data[api.AWS_SECRET_ACCESS_KEY]))
+	case <-time.After(10 * time.Second):
+		t.Fatal("change was not reported")
+	}
+
+	// the watch is started again after the last change it delivered
+	first.w.Stop()
+	select {
+	case second := <-c.watches:
+		assert.Equal(t, "metadata.name=s3-secret", second.opts.FieldSelector.String())
+		assert.Equal(t, secret.ResourceVersion, second.opts.Raw.ResourceVersion)
+		assert.NotEmpty(t, second.opts.Raw.ResourceVersion)
+	case <-time.After(10 * time.Second):
+		t.Fatal("watch was not started again")
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gcsauth resolves the credentials of a GCS backend, so that the blob
+// driver and the osm config authenticate the same way. Besides service
+// account keys, it supports application default credentials (eg. GKE
+// workload identity), external_account credential files of workload
+// identity federation and impersonated service accounts.
+package gcsauth // import "kmodules.xyz/objectstore-api/pkg/gcsauth"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/iamcredentials/v1"
+	"google.golang.org/api/option"
+)
+
+const (
+	// DefaultScope is used when a GCSSpec has no scopes. It matches the
+	// default of gomodules.xyz/stow/google.
+	DefaultScope = "https://www.googleapis.com/auth/devstorage.read_write"
+	// CloudPlatformScope is required by the source credentials of an
+	// impersonated service account.
+	CloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// types of Google credential files
+const (
+	TypeServiceAccount  = "service_account"
+	TypeExternalAccount = "external_account"
+)
+
+// iamEndpoint overrides the IAM credentials endpoint in tests
+var iamEndpoint string
+
+// CredentialsType returns the type of a credential file.
+func CredentialsType(data []byte) (string, error) {
+	var f struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return "", fmt.Errorf("failed to parse google credentials: %w", err)
+	}
+	return f.Type, nil
+}
+
+// Scopes returns the scopes requested for spec.
+func Scopes(spec *api.GCSSpec) []string {
+	if len(spec.Scopes) == 0 {
+		return []string{DefaultScope}
+	}
+	return spec.Scopes
+}
+
+// TokenSource returns the access tokens for spec. data is the credential
+// file of the storage secret, the application default credentials are used
+// when it is empty. The token source outlives ctx.
+func TokenSource(ctx context.Context, spec *api.GCSSpec, data []byte) (oauth2.TokenSource, error) {
+	ctx = context.WithoutCancel(ctx)
+
+	scopes := Scopes(spec)
+	if spec.ImpersonateServiceAccount != "" {
+		scopes = []string{CloudPlatformScope}
+	}
+	var creds *google.Credentials
+	var err error
+	if len(data) > 0 {
+		creds, err = google.CredentialsFromJSON(ctx, data, scopes...)
+	} else {
+		creds, err = google.FindDefaultCredentials(ctx, scopes...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if spec.ImpersonateServiceAccount == "" {
+		return creds.TokenSource, nil
+	}
+
+	opts := []option.ClientOption{option.WithTokenSource(creds.TokenSource)}
+	if iamEndpoint != "" {
+		opts = append(opts, option.WithEndpoint(iamEndpoint))
+	}
+	svc, err := iamcredentials.NewService(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return oauth2.ReuseTokenSource(nil, &impersonatedTokenSource{
+		ctx:            ctx,
+		svc:            svc,
+		serviceAccount: spec.ImpersonateServiceAccount,
+		scopes:         Scopes(spec),
+	}), nil
+}
+
+// impersonatedTokenSource issues short-lived tokens of a service account
+// with the IAM credentials API.
+//
+// ref: https://cloud.google.com/iam/docs/create-short-lived-credentials-direct
+type impersonatedTokenSource struct {
+	ctx            context.Context
+	svc            *iamcredentials.Service
+	serviceAccount string
+	scopes         []string
+}
+
+func (s *impersonatedTokenSource) Token() (*oauth2.Token, error) {
+	name := "projects/-/serviceAccounts/" + s.serviceAccount
+	resp, err := s.svc.Projects.ServiceAccounts.GenerateAccessToken(name, &iamcredentials.GenerateAccessTokenRequest{
+		Scope: s.scopes,
+	}).Context(s.ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate %s: %w", s.serviceAccount, err)
+	}
+	expiry, err := time.Parse(time.RFC3339, resp.ExpireTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate %s: %w", s.serviceAccount, err)
+	}
+	return &oauth2.Token{
+		AccessToken: resp.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      expiry,
+	}, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcsauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newGoogleServer returns a server that exchanges federated tokens like STS
+// and issues service account tokens like the IAM credentials API.
+func newGoogleServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/token":
+			if r.FormValue("subject_token") != "oidc-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token":      "federated-token",
+				"issued_token_type": "urn:ietf:params:oauth:token-type:access_token",
+				"token_type":        "Bearer",
+				"expires_in":        3600,
+			})
+		case strings.HasSuffix(r.URL.Path, ":generateAccessToken"):
+			if r.Header.Get("Authorization") != "Bearer federated-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			var in struct {
+				Scope []string `json:"scope"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&in)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"accessToken": strings.TrimPrefix(r.URL.Path, "/v1/projects/-/serviceAccounts/") + " " + strings.Join(in.Scope, ","),
+				"expireTime":  time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func externalAccount(t *testing.T, srv *httptest.Server) []byte {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if !assert.Nil(t, os.WriteFile(tokenFile, []byte("oidc-token"), 0o600)) {
+		t.FailNow()
+	}
+	data, _ := json.Marshal(map[string]any{
+		"type":               TypeExternalAccount,
+		"audience":           "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/k8s",
+		"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+		"token_url":          srv.URL + "/token",
+		"credential_source":  map[string]any{"file": tokenFile},
+	})
+	return data
+}
+
+func TestCredentialsType(t *testing.T) {
+	srv := newGoogleServer(t)
+	typ, err := CredentialsType(externalAccount(t, srv))
+	assert.Nil(t, err)
+	assert.Equal(t, TypeExternalAccount, typ)
+
+	_, err = CredentialsType([]byte("not json"))
+	assert.NotNil(t, err)
+}
+
+func TestTokenSource(t *testing.T) {
+	srv := newGoogleServer(t)
+	iamEndpoint = srv.URL + "/"
+	t.Cleanup(func() { iamEndpoint = "" })
+
+	cases := []struct {
+		name     string
+		spec     api.GCSSpec
+		expected string
+	}{
+		{
+			name:     "external account",
+			spec:     api.GCSSpec{Bucket: "stash"},
+			expected: "federated-token",
+		},
+		{
+			name: "impersonation",
+			spec: api.GCSSpec{
+				Bucket:                    "stash",
+				ImpersonateServiceAccount: "backup@demo.iam.gserviceaccount.com",
+			},
+			expected: "backup@demo.iam.gserviceaccount.com:generateAccessToken " + DefaultScope,
+		},
+		{
+			name: "impersonation with scopes",
+			spec: api.GCSSpec{
+				Bucket:                    "stash",
+				ImpersonateServiceAccount: "backup@demo.iam.gserviceaccount.com",
+				Scopes:                    []string{"https://www.googleapis.com/auth/devstorage.read_only"},
+			},
+			expected: "backup@demo.iam.gserviceaccount.com:generateAccessToken https://www.googleapis.com/auth/devstorage.read_only",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, err := TokenSource(context.Background(), &tc.spec, externalAccount(t, srv))
+			if !assert.Nil(t, err) {
+				return
+			}
+			tok, err := ts.Token()
+			if assert.Nil(t, err) {
+				assert.Equal(t, tc.expected, tok.AccessToken)
+			}
+		})
+	}
+}
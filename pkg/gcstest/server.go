@@ -0,0 +1,465 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gcstest provides an in-memory stand-in for the JSON api of Google
+// Cloud Storage, addressed like an emulator with STORAGE_EMULATOR_HOST, so
+// that GCS backends can be tested without network access. It also issues
+// the access tokens of service account keys and federated credentials.
+package gcstest // import "kmodules.xyz/objectstore-api/pkg/gcstest"
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenPath is the path of the token endpoint, the token_uri of the keys of
+// ServiceAccountKey.
+const TokenPath = "/token"
+
+// Request is a request of the storage api received by the Server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+}
+
+// Object is an object stored by the Server.
+type Object struct {
+	Data        []byte
+	ContentType string
+	Metadata    map[string]string
+	ModTime     time.Time
+}
+
+// Server is a GCS stand-in backed by memory. Requests of the storage api are
+// authorized by a bearer token issued by its token endpoint.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	buckets  map[string]map[string]*Object
+	tokens   map[string]bool
+	requests []Request
+}
+
+// NewServer starts a plain http Server.
+func NewServer() *Server {
+	s := &Server{buckets: map[string]map[string]*Object{}, tokens: map[string]bool{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// ServiceAccountKey returns the json key of a new service account whose
+// tokens are issued by the Server.
+func (s *Server) ServiceAccountKey() ([]byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "demo",
+		"private_key_id": "1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"client_email":   "backup@demo.iam.gserviceaccount.com",
+		"client_id":      "1",
+		"token_uri":      s.URL + TokenPath,
+	})
+}
+
+// CreateBucket creates an empty bucket.
+func (s *Server) CreateBucket(bucket string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.buckets[bucket]; !ok {
+		s.buckets[bucket] = map[string]*Object{}
+	}
+}
+
+// Object returns an object of a bucket.
+func (s *Server) Object(bucket, name string) (*Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.buckets[bucket][name]
+	return obj, ok
+}
+
+// Names returns the sorted object names of a bucket.
+func (s *Server) Names(bucket string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0)
+	for k := range s.buckets[bucket] {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Requests returns the storage api requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == TokenPath {
+		s.serveToken(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Header: r.Header.Clone()})
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	authorized := s.tokens[token]
+	s.mu.Unlock()
+	if !authorized {
+		writeError(w, http.StatusUnauthorized, "Invalid Credentials")
+		return
+	}
+
+	// /storage/v1/b/<bucket>/o/<object> and /upload/storage/v1/b/<bucket>/o,
+	// object names are escaped
+	path := r.URL.EscapedPath()
+	upload := strings.HasPrefix(path, "/upload/")
+	parts := strings.Split(strings.TrimPrefix(strings.TrimPrefix(path, "/upload"), "/storage/v1/"), "/")
+	if parts[0] != "b" {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	switch {
+	case len(parts) == 1:
+		s.serveBuckets(w, r)
+	case len(parts) == 2:
+		s.serveBucket(w, r, parts[1])
+	case len(parts) == 3 && parts[2] == "o" && upload:
+		s.insert(w, r, parts[1])
+	case len(parts) == 3 && parts[2] == "o":
+		s.list(w, r, parts[1])
+	case len(parts) == 4 && parts[2] == "iam" && parts[3] == "testPermissions":
+		// every permission is granted
+		writeJSON(w, map[string]any{"permissions": r.URL.Query()["permissions"]})
+	case len(parts) == 4 && parts[2] == "o":
+		name, err := url.PathUnescape(parts[3])
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid object name")
+			return
+		}
+		s.serveObject(w, r, parts[1], name)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// serveToken issues a token for any assertion or subject token, like the
+// token endpoints of Google and STS.
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("assertion") == "" && r.FormValue("subject_token") == "" {
+		writeError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+	s.mu.Lock()
+	token := fmt.Sprintf("token-%d", len(s.tokens))
+	s.tokens[token] = true
+	s.mu.Unlock()
+	writeJSON(w, map[string]any{
+		"access_token":      token,
+		"issued_token_type": "urn:ietf:params:oauth:token-type:access_token",
+		"token_type":        "Bearer",
+		"expires_in":        3600,
+	})
+}
+
+// serveBuckets handles buckets.list and buckets.insert. Page tokens are the
+// name of the first bucket of the next page.
+func (s *Server) serveBuckets(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case http.MethodPost:
+		var b struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil || b.Name == "" {
+			writeError(w, http.StatusBadRequest, "Invalid bucket")
+			return
+		}
+		if _, ok := s.buckets[b.Name]; ok {
+			writeError(w, http.StatusConflict, "The requested bucket name is not available.")
+			return
+		}
+		s.buckets[b.Name] = map[string]*Object{}
+		writeJSON(w, bucketResource(b.Name))
+	case http.MethodGet:
+		q := r.URL.Query()
+		names := make([]string, 0)
+		for name := range s.buckets {
+			if strings.HasPrefix(name, q.Get("prefix")) && name >= q.Get("pageToken") {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		limit := maxResults(q)
+		out := map[string]any{}
+		var items []any
+		for i, name := range names {
+			if i == limit {
+				out["nextPageToken"] = name
+				break
+			}
+			items = append(items, bucketResource(name))
+		}
+		out["items"] = items
+		writeJSON(w, out)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// serveBucket handles buckets.get and buckets.delete.
+func (s *Server) serveBucket(w http.ResponseWriter, r *http.Request, bucket string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.buckets[bucket]; !ok {
+		writeError(w, http.StatusNotFound, "The specified bucket does not exist.")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, bucketResource(bucket))
+	case http.MethodDelete:
+		delete(s.buckets, bucket)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// insert handles multipart and media uploads of objects.insert.
+func (s *Server) insert(w http.ResponseWriter, r *http.Request, bucket string) {
+	obj := &Object{Metadata: map[string]string{}, ModTime: time.Now().UTC().Truncate(time.Second)}
+	name := r.URL.Query().Get("name")
+	switch r.URL.Query().Get("uploadType") {
+	case "multipart":
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid multipart upload")
+			return
+		}
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		part, err := mr.NextPart()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid multipart upload")
+			return
+		}
+		var meta struct {
+			Name     string            `json:"name"`
+			Metadata map[string]string `json:"metadata"`
+		}
+		if err := json.NewDecoder(part).Decode(&meta); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid object metadata")
+			return
+		}
+		name = meta.Name
+		for k, v := range meta.Metadata {
+			obj.Metadata[k] = v
+		}
+		if part, err = mr.NextPart(); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid multipart upload")
+			return
+		}
+		obj.ContentType = part.Header.Get("Content-Type")
+		if obj.Data, err = io.ReadAll(part); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid multipart upload")
+			return
+		}
+	case "media":
+		var err error
+		obj.ContentType = r.Header.Get("Content-Type")
+		if obj.Data, err = io.ReadAll(r.Body); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid upload")
+			return
+		}
+	default:
+		writeError(w, http.StatusNotImplemented, "Unsupported upload type")
+		return
+	}
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Required object name")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		writeError(w, http.StatusNotFound, "The specified bucket does not exist.")
+		return
+	}
+	objects[name] = obj
+	writeJSON(w, s.objectResource(bucket, name, obj))
+}
+
+// list handles objects.list. Page tokens are the name of the first object
+// of the next page.
+func (s *Server) list(w http.ResponseWriter, r *http.Request, bucket string) {
+	q := r.URL.Query()
+	prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		writeError(w, http.StatusNotFound, "The specified bucket does not exist.")
+		return
+	}
+	names := make([]string, 0)
+	for name := range objects {
+		if strings.HasPrefix(name, prefix) && name >= q.Get("pageToken") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	limit := maxResults(q)
+	out := map[string]any{}
+	var items []any
+	var prefixes []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		if delimiter != "" {
+			if j := strings.Index(name[len(prefix):], delimiter); j >= 0 {
+				p := name[:len(prefix)+j+len(delimiter)]
+				if !seen[p] {
+					seen[p] = true
+					prefixes = append(prefixes, p)
+				}
+				continue
+			}
+		}
+		if len(items) == limit {
+			out["nextPageToken"] = name
+			break
+		}
+		items = append(items, s.objectResource(bucket, name, objects[name]))
+	}
+	out["items"] = items
+	out["prefixes"] = prefixes
+	writeJSON(w, out)
+}
+
+// serveObject handles objects.get, its media downloads and objects.delete.
+func (s *Server) serveObject(w http.ResponseWriter, r *http.Request, bucket, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.buckets[bucket][name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "No such object: "+bucket+"/"+name)
+		return
+	}
+	switch {
+	case r.Method == http.MethodDelete:
+		delete(s.buckets[bucket], name)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && r.URL.Query().Get("alt") == "media":
+		data := obj.Data
+		status := http.StatusOK
+		if rng := r.Header.Get("Range"); rng != "" {
+			var start, end int
+			n, _ := fmt.Sscanf(rng, "bytes=%d-%d", &start, &end)
+			if n < 2 || end >= len(data) {
+				end = len(data) - 1
+			}
+			if start > end {
+				writeError(w, http.StatusRequestedRangeNotSatisfiable, "Requested range not satisfiable")
+				return
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+			data = data[start : end+1]
+			status = http.StatusPartialContent
+		}
+		w.Header().Set("Content-Type", obj.ContentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		_, _ = w.Write(data)
+	case r.Method == http.MethodGet:
+		writeJSON(w, s.objectResource(bucket, name, obj))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func bucketResource(name string) map[string]any {
+	return map[string]any{"kind": "storage#bucket", "id": name, "name": name}
+}
+
+func (s *Server) objectResource(bucket, name string, obj *Object) map[string]any {
+	sum := md5.Sum(obj.Data)
+	generation := strconv.FormatInt(obj.ModTime.UnixNano(), 10)
+	return map[string]any{
+		"kind":        "storage#object",
+		"bucket":      bucket,
+		"name":        name,
+		"size":        strconv.Itoa(len(obj.Data)),
+		"contentType": obj.ContentType,
+		"md5Hash":     base64.StdEncoding.EncodeToString(sum[:]),
+		"etag":        generation,
+		"generation":  generation,
+		"updated":     obj.ModTime.Format(time.RFC3339),
+		"metadata":    obj.Metadata,
+		"mediaLink":   s.URL + "/download/storage/v1/b/" + bucket + "/o/" + url.PathEscape(name) + "?generation=" + generation + "&alt=media",
+	}
+}
+
+func maxResults(q url.Values) int {
+	n, _ := strconv.Atoi(q.Get("maxResults"))
+	if n <= 0 {
+		n = 1000
+	}
+	return n
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{"code": status, "message": message},
+	})
+}
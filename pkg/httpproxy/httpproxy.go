@@ -0,0 +1,199 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httpproxy routes the connections of a backend through the proxy of
+// its Proxy section, instead of the proxy of the environment of the process,
+// so that the in-cluster connections of the process are not proxied.
+package httpproxy // import "kmodules.xyz/objectstore-api/pkg/httpproxy"
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"gomodules.xyz/stow"
+)
+
+// Stow config keys of the proxy settings. ConfigNoProxy is a comma separated
+// list of hosts.
+const (
+	ConfigURL      = "proxy_url"
+	ConfigUsername = "proxy_username"
+	ConfigPassword = "proxy_password"
+	ConfigNoProxy  = "no_proxy"
+)
+
+// Options are the proxy settings of a backend.
+type Options struct {
+	URL      string
+	Username string
+	Password string
+	// NoProxy lists the hosts that are connected to directly, see
+	// api.ProxyConfig.
+	NoProxy []string
+}
+
+// FromSecret reads the Options of a backend from its Proxy section and the
+// storage secret data. spec may be nil.
+func FromSecret(spec *api.ProxyConfig, data map[string][]byte) Options {
+	if spec == nil {
+		return Options{}
+	}
+	return Options{
+		URL:      spec.URL,
+		Username: string(data[api.PROXY_USERNAME]),
+		Password: string(data[api.PROXY_PASSWORD]),
+		NoProxy:  spec.NoProxy,
+	}
+}
+
+// FromStowConfig reads the Options from a stow config.
+func FromStowConfig(config stow.Config) Options {
+	var o Options
+	o.URL, _ = config.Config(ConfigURL)
+	o.Username, _ = config.Config(ConfigUsername)
+	o.Password, _ = config.Config(ConfigPassword)
+	if v, ok := config.Config(ConfigNoProxy); ok && v != "" {
+		o.NoProxy = strings.Split(v, ",")
+	}
+	return o
+}
+
+// SetStowConfig sets the stow config keys of o.
+func (o Options) SetStowConfig(cfg stow.ConfigMap) {
+	if o.URL == "" {
+		return
+	}
+	cfg[ConfigURL] = o.URL
+	if o.Username != "" || o.Password != "" {
+		cfg[ConfigUsername] = o.Username
+		cfg[ConfigPassword] = o.Password
+	}
+	if len(o.NoProxy) > 0 {
+		cfg[ConfigNoProxy] = strings.Join(o.NoProxy, ",")
+	}
+}
+
+// IsZero reports whether no proxy is configured.
+func (o Options) IsZero() bool {
+	return o.URL == ""
+}
+
+// Func returns the Proxy func of an http.Transport, or nil when no proxy is
+// configured, so that the proxy of the environment stays in place. Unlike
+// http.ProxyFromEnvironment, requests to localhost are proxied unless they
+// match NoProxy.
+func (o Options) Func() (func(*http.Request) (*url.URL, error), error) {
+	if o.IsZero() {
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(o.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	if proxyURL.Scheme != "http" && proxyURL.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported proxy url scheme %q", proxyURL.Scheme)
+	}
+	if o.Username != "" || o.Password != "" {
+		// the transport sends them in the Proxy-Authorization header
+		proxyURL.User = url.UserPassword(o.Username, o.Password)
+	}
+	noProxy, err := parseNoProxy(o.NoProxy)
+	if err != nil {
+		return nil, err
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if noProxy.match(req.URL) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+type noProxyList struct {
+	all   bool
+	nets  []*net.IPNet
+	hosts []hostMatcher
+}
+
+// hostMatcher matches a host name, which includes its sub domains, or an ip
+// address. port is empty for any port.
+type hostMatcher struct {
+	host string
+	port string
+}
+
+func parseNoProxy(hosts []string) (*noProxyList, error) {
+	l := &noProxyList{}
+	for _, h := range hosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		switch {
+		case h == "":
+			continue
+		case h == "*":
+			l.all = true
+			continue
+		case strings.Contains(h, "/"):
+			_, n, err := net.ParseCIDR(h)
+			if err != nil {
+				return nil, fmt.Errorf("invalid no proxy host %q: %w", h, err)
+			}
+			l.nets = append(l.nets, n)
+			continue
+		}
+		m := hostMatcher{host: h}
+		if host, port, err := net.SplitHostPort(h); err == nil {
+			m = hostMatcher{host: host, port: port}
+		}
+		m.host = strings.TrimPrefix(m.host, ".")
+		l.hosts = append(l.hosts, m)
+	}
+	return l, nil
+}
+
+func (l *noProxyList) match(u *url.URL) bool {
+	if l.all {
+		return true
+	}
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if port == "" {
+		if u.Scheme == "https" {
+			port = "443"
+		} else {
+			port = "80"
+		}
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		for _, n := range l.nets {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+	}
+	for _, m := range l.hosts {
+		if m.port != "" && m.port != port {
+			continue
+		}
+		if host == m.host || strings.HasSuffix(host, "."+m.host) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpproxy
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"github.com/stretchr/testify/assert"
+	"gomodules.xyz/stow"
+)
+
+func TestFunc(t *testing.T) {
+	opts := Options{
+		URL:      "http://proxy.example.com:3128",
+		Username: "egress",
+		Password: "not-so-secret",
+		NoProxy:  []string{".svc.cluster.local", "minio.example.com:9000", "10.0.0.0/8", "192.168.1.10"},
+	}
+	proxy, err := opts.Func()
+	if !assert.Nil(t, err) {
+		return
+	}
+	cases := []struct {
+		url     string
+		proxied bool
+	}{
+		{"https://s3.amazonaws.com/stash", true},
+		{"http://127.0.0.1:9000/stash", true},
+		{"http://minio.default.svc.cluster.local:9000", false},
+		{"http://svc.cluster.local", false},
+		{"http://minio.example.com:9000/stash", false},
+		{"https://minio.example.com/stash", true},
+		{"https://console.minio.example.com:9000", false},
+		{"https://10.1.2.3/stash", false},
+		{"https://192.168.1.10:8443", false},
+		{"https://192.168.1.11:8443", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.url, func(t *testing.T) {
+			u, err := url.Parse(tc.url)
+			if !assert.Nil(t, err) {
+				return
+			}
+			got, err := proxy(&http.Request{URL: u})
+			assert.Nil(t, err)
+			if !tc.proxied {
+				assert.Nil(t, got)
+				return
+			}
+			if assert.NotNil(t, got) {
+				assert.Equal(t, "proxy.example.com:3128", got.Host)
+				password, _ := got.User.Password()
+				assert.Equal(t, "egress", got.User.Username())
+				assert.Equal(t, "not-so-secret", password)
+			}
+		})
+	}
+
+	proxy, err = Options{URL: "http://proxy:3128", NoProxy: []string{"*"}}.Func()
+	if assert.Nil(t, err) {
+		got, err := proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "s3.amazonaws.com"}})
+		assert.Nil(t, err)
+		assert.Nil(t, got)
+	}
+
+	proxy, err = Options{}.Func()
+	assert.Nil(t, err)
+	assert.Nil(t, proxy)
+
+	_, err = Options{URL: "socks5://proxy:1080"}.Func()
+	assert.ErrorContains(t, err, `unsupported proxy url scheme "socks5"`)
+	_, err = Options{URL: "http://proxy:3128", NoProxy: []string{"10.0.0.0/33"}}.Func()
+	assert.ErrorContains(t, err, "invalid no proxy host")
+}
+
+func TestStowConfig(t *testing.T) {
+	opts := FromSecret(&api.ProxyConfig{
+		URL:     "http://proxy.example.com:3128",
+		NoProxy: []string{".svc", "10.0.0.0/8"},
+	}, map[string][]byte{
+		api.PROXY_USERNAME: []byte("egress"),
+		api.PROXY_PASSWORD: []byte("not-so-secret"),
+	})
+	cfg := stow.ConfigMap{}
+	opts.SetStowConfig(cfg)
+	assert.Equal(t, stow.ConfigMap{
+		ConfigURL:      "http://proxy.example.com:3128",
+		ConfigUsername: "egress",
+		ConfigPassword: "not-so-secret",
+		ConfigNoProxy:  ".svc,10.0.0.0/8",
+	}, cfg)
+	assert.Equal(t, opts, FromStowConfig(cfg))
+
+	assert.True(t, FromSecret(nil, map[string][]byte{api.PROXY_USERNAME: []byte("egress")}).IsZero())
+	cfg = stow.ConfigMap{}
+	Options{}.SetStowConfig(cfg)
+	assert.Empty(t, cfg)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package proxytest provides a forward proxy stand-in so that the proxy
+// settings of backends can be tested without network access.
+package proxytest // import "kmodules.xyz/objectstore-api/pkg/httpproxy/proxytest"
+
+import (
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// Server is a forward proxy stand-in. It tunnels CONNECT requests, forwards
+// plain http requests and records the hosts it connected to.
+type Server struct {
+	*httptest.Server
+
+	// Username and Password require proxy basic auth when set.
+	Username string
+	Password string
+
+	mu    sync.Mutex
+	hosts []string
+}
+
+// NewServer starts a plain http Server.
+func NewServer() *Server {
+	s := &Server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Hosts returns the host:port of every connection made by the proxy, in
+// order.
+func (s *Server) Hosts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.hosts...)
+}
+
+func (s *Server) record(host string) {
+	s.mu.Lock()
+	s.hosts = append(s.hosts, host)
+	s.mu.Unlock()
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.Username == "" && s.Password == "" {
+		return true
+	}
+	want := "Basic " + base64.StdEncoding.EncodeToString([]byte(s.Username+":"+s.Password))
+	return r.Header.Get("Proxy-Authorization") == want
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("Proxy-Authenticate", `Basic realm="proxytest"`)
+		http.Error(w, http.StatusText(http.StatusProxyAuthRequired), http.StatusProxyAuthRequired)
+		return
+	}
+	if r.Method == http.MethodConnect {
+		s.tunnel(w, r)
+		return
+	}
+	s.forward(w, r)
+}
+
+func (s *Server) tunnel(w http.ResponseWriter, r *http.Request) {
+	upstream, err := net.DialTimeout("tcp", r.Host, 10*time.Second)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	s.record(r.Host)
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		_ = upstream.Close()
+		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		_ = upstream.Close()
+		return
+	}
+	// the client may have sent the start of the handshake already
+	if n := rw.Reader.Buffered(); n > 0 {
+		buffered, _ := rw.Reader.Peek(n)
+		if _, err := upstream.Write(buffered); err != nil {
+			_ = conn.Close()
+			_ = upstream.Close()
+			return
+		}
+	}
+	go func() {
+		_, _ = io.Copy(upstream, conn)
+		_ = upstream.Close()
+	}()
+	go func() {
+		_, _ = io.Copy(conn, upstream)
+		_ = conn.Close()
+	}()
+}
+
+// transport forwards plain requests without the proxy of the environment.
+var transport = &http.Transport{Proxy: nil}
+
+func (s *Server) forward(w http.ResponseWriter, r *http.Request) {
+	if !r.URL.IsAbs() {
+		http.Error(w, "not a proxy request", http.StatusBadRequest)
+		return
+	}
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	out.Header.Del("Proxy-Authorization")
+	out.Header.Del("Proxy-Connection")
+	s.record(r.URL.Host)
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
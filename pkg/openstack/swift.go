@@ -0,0 +1,106 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package openstack maps the OS_* and ST_* keys of a storage secret onto
+// the settings of a Swift connection. The same mapping is used by the osm
+// config and the blob driver so that both talk to a cluster the same way.
+package openstack // import "kmodules.xyz/objectstore-api/pkg/openstack"
+
+import (
+	"net/http"
+
+	osconst "kmodules.xyz/constants/openstack"
+
+	"github.com/ncw/swift"
+)
+
+// SwiftConfig holds the Swift authentication settings found in a storage
+// secret.
+//
+// ref: https://github.com/restic/restic/blob/master/src/restic/backend/swift/config.go
+type SwiftConfig struct {
+	Username     string
+	Key          string
+	Region       string
+	AuthURL      string
+	Domain       string
+	TenantName   string
+	TenantDomain string
+	TenantID     string
+	StorageURL   string
+	AuthToken    string
+}
+
+// SwiftConfigFromSecret reads a SwiftConfig from secret data. When a setting
+// can come from more than one key, the first non-empty key wins, so v2/v3
+// keys take precedence over their v1 counterparts.
+func SwiftConfigFromSecret(data map[string][]byte) SwiftConfig {
+	var cfg SwiftConfig
+	for _, val := range []struct {
+		field      *string
+		secretKeys []string
+	}{
+		// v2/v3 specific, with v1 fallbacks
+		{&cfg.Username, []string{osconst.OS_USERNAME, osconst.ST_USER}},
+		{&cfg.Key, []string{osconst.OS_PASSWORD, osconst.ST_KEY}},
+		{&cfg.Region, []string{osconst.OS_REGION_NAME}},
+		{&cfg.AuthURL, []string{osconst.OS_AUTH_URL, osconst.ST_AUTH}},
+
+		// v3 specific, with v2 fallback for the tenant name
+		{&cfg.Domain, []string{osconst.OS_USER_DOMAIN_NAME}},
+		{&cfg.TenantName, []string{osconst.OS_PROJECT_NAME, osconst.OS_TENANT_NAME}},
+		{&cfg.TenantDomain, []string{osconst.OS_PROJECT_DOMAIN_NAME}},
+
+		// v2 specific
+		{&cfg.TenantID, []string{osconst.OS_TENANT_ID}},
+
+		// Manual authentication
+		{&cfg.StorageURL, []string{osconst.OS_STORAGE_URL}},
+		{&cfg.AuthToken, []string{osconst.OS_AUTH_TOKEN}},
+	} {
+		for _, key := range val.secretKeys {
+			if v := string(data[key]); v != "" {
+				*val.field = v
+				break
+			}
+		}
+	}
+	return cfg
+}
+
+// ManualAuth reports whether the config carries a pre-issued token and
+// storage url, in which case no auth request is needed.
+func (c SwiftConfig) ManualAuth() bool {
+	return c.StorageURL != "" && c.AuthToken != ""
+}
+
+// Connection returns an unauthenticated connection for the config. A nil
+// transport leaves the swift default in place.
+func (c SwiftConfig) Connection(transport http.RoundTripper) *swift.Connection {
+	return &swift.Connection{
+		UserName:     c.Username,
+		ApiKey:       c.Key,
+		AuthUrl:      c.AuthURL,
+		Domain:       c.Domain,
+		Region:       c.Region,
+		Tenant:       c.TenantName,
+		TenantId:     c.TenantID,
+		TenantDomain: c.TenantDomain,
+		StorageUrl:   c.StorageURL,
+		AuthToken:    c.AuthToken,
+		Transport:    transport,
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osm
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/credsource"
+	"kmodules.xyz/objectstore-api/pkg/stow/rest"
+	"kmodules.xyz/objectstore-api/pkg/stow/s3"
+	"kmodules.xyz/objectstore-api/pkg/stow/swift"
+
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+	"k8s.io/client-go/kubernetes"
+)
+
+// BucketInfo is what InspectBucket found out about a bucket.
+type BucketInfo struct {
+	// Region of the bucket, empty for the providers without regions.
+	Region string
+	// Capabilities are the operations on the bucket that were allowed.
+	Capabilities []api.ObjectStoreCapability
+}
+
+// InspectBucket runs the checks of CheckBucketAccess, and also reports the
+// region of the bucket and which operations the credentials are allowed. When
+// the bucket is found but can not be written to, the error is returned
+// together with the capabilities that were found.
+func InspectBucket(client kubernetes.Interface, spec api.Backend, namespace string, opts ...credsource.Option) (*BucketInfo, error) {
+	cfg, err := NewOSMContext(client, spec, namespace, opts...)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer loc.Close() // nolint:errcheck
+
+	bucket, err := spec.Container()
+	if err != nil {
+		return nil, err
+	}
+	c, err := loc.Container(bucket)
+	if err != nil {
+		return nil, expiredCredentialsError(err)
+	}
+
+	info := &BucketInfo{Region: bucketRegionOf(cfg)}
+	key, err := probeKey(cfg.Provider)
+	if err != nil {
+		return nil, err
+	}
+	// a missing object is only reported as not found with read access
+	if _, err := c.Item(key); errors.Is(err, stow.ErrNotFound) {
+		info.Capabilities = append(info.Capabilities, api.CapabilityRead)
+	}
+	if _, _, err := c.Items("", stow.CursorStart, 1); err == nil {
+		info.Capabilities = append(info.Capabilities, api.CapabilityList)
+	}
+	if err := c.HasWriteAccess(); err != nil {
+		return info, expiredCredentialsError(err)
+	}
+	info.Capabilities = append(info.Capabilities, api.CapabilityWrite)
+	return info, nil
+}
+
+// bucketRegionOf returns the region of the bucket of osmCtx, as it was
+// configured or looked up by NewOSMContext.
+func bucketRegionOf(osmCtx *Context) string {
+	var key string
+	switch osmCtx.Provider {
+	case s3.Kind:
+		key = s3.ConfigRegion
+	case swift.Kind:
+		key = swift.ConfigRegion
+	default:
+		return ""
+	}
+	region, _ := osmCtx.Config.Config(key)
+	return region
+}
+
+// probeKey returns the key of an object that does not exist, in the layout
+// that the provider accepts.
+func probeKey(provider string) (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	if provider == rest.Kind {
+		// the rest server only serves the files of a restic repository
+		return "locks/" + hex.EncodeToString(data), nil
+	}
+	return ".trash/" + hex.EncodeToString(data), nil
+}
@@ -92,7 +92,7 @@ func (config *OSMConfig) Dial(cliCtx string) (stow.Location, error) {
 	}
 	for _, osmCtx := range config.Contexts {
 		if osmCtx.Name == ctx {
-			return stow.Dial(osmCtx.Provider, osmCtx.Config)
+			return dial(osmCtx)
 		}
 	}
 	return nil, errors.New("failed to determine context")
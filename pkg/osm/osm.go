@@ -23,27 +23,40 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	awsconst "kmodules.xyz/constants/aws"
-	azconst "kmodules.xyz/constants/azure"
 	googconst "kmodules.xyz/constants/google"
-	osconst "kmodules.xyz/constants/openstack"
 	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/azureauth"
+	"kmodules.xyz/objectstore-api/pkg/credsource"
+	"kmodules.xyz/objectstore-api/pkg/gcsauth"
+	"kmodules.xyz/objectstore-api/pkg/httpproxy"
+	"kmodules.xyz/objectstore-api/pkg/openstack"
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+	"kmodules.xyz/objectstore-api/pkg/retry"
+	"kmodules.xyz/objectstore-api/pkg/s3auth"
+	"kmodules.xyz/objectstore-api/pkg/s3sse"
+	"kmodules.xyz/objectstore-api/pkg/stow/azure"
+	"kmodules.xyz/objectstore-api/pkg/stow/b2"
+	"kmodules.xyz/objectstore-api/pkg/stow/gcs"
+	"kmodules.xyz/objectstore-api/pkg/stow/rest"
+	"kmodules.xyz/objectstore-api/pkg/stow/s3"
+	"kmodules.xyz/objectstore-api/pkg/stow/swift"
+	"kmodules.xyz/objectstore-api/pkg/tlsconfig"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
 	_s3 "github.com/aws/aws-sdk-go/service/s3"
 	"github.com/pkg/errors"
 	"gomodules.xyz/pointer"
 	"gomodules.xyz/stow"
-	"gomodules.xyz/stow/azure"
-	gcs "gomodules.xyz/stow/google"
 	"gomodules.xyz/stow/local"
-	"gomodules.xyz/stow/s3"
-	"gomodules.xyz/stow/swift"
 	stringz "gomodules.xyz/x/strings"
 	core "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -52,8 +65,97 @@ import (
 )
 
 const (
-	SecretMountPath = "/etc/osm"
-	CaCertFileName  = "ca.crt"
+	SecretMountPath           = "/etc/osm"
+	CaCertFileName            = "ca.crt"
+	GoogleCredentialsFileName = "google-credentials.json"
+	AWSCredentialsFileName    = "aws-credentials"
+	AWSConfigFileName         = "aws-config"
+	TLSCertFileName           = "tls.crt"
+	TLSKeyFileName            = "tls.key"
+)
+
+// Credentials of gcs backends, read by the gcs location of package
+// kmodules.xyz/objectstore-api/pkg/stow/gcs. Service account keys are kept in
+// its json config, other credential files are written to
+// GoogleCredentialsFileName.
+const (
+	ConfigGCSCredentialsData           = gcs.ConfigCredentialsData
+	ConfigGCSCredentialsFile           = gcs.ConfigCredentialsFile
+	ConfigGCSImpersonateServiceAccount = gcs.ConfigImpersonateServiceAccount
+)
+
+// projectedFiles are the config values that are written into files next to
+// the config. The data key is replaced by the path of the file.
+var projectedFiles = []struct {
+	dataKey  string
+	fileKey  string
+	fileName string
+}{
+	{s3.ConfigCACertData, s3.ConfigCACertFile, CaCertFileName},
+	{ConfigGCSCredentialsData, ConfigGCSCredentialsFile, GoogleCredentialsFileName},
+	{ConfigS3SharedCredentialsData, ConfigS3SharedCredentialsFile, AWSCredentialsFileName},
+	{ConfigS3SharedConfigData, ConfigS3SharedConfigFile, AWSConfigFileName},
+	{tlsconfig.ConfigClientCertData, tlsconfig.ConfigClientCertFile, TLSCertFileName},
+	{tlsconfig.ConfigClientKeyData, tlsconfig.ConfigClientKeyFile, TLSKeyFileName},
+}
+
+// Server side encryption of s3 backends, see the keys of package
+// kmodules.xyz/objectstore-api/pkg/stow/s3.
+const (
+	ConfigS3ServerSideEncryption    = s3.ConfigServerSideEncryption
+	ConfigS3SSEKMSKeyID             = s3.ConfigSSEKMSKeyID
+	ConfigS3SSEKMSEncryptionContext = s3.ConfigSSEKMSEncryptionContext
+	ConfigS3SSECustomerAlgorithm    = s3.ConfigSSECustomerAlgorithm
+	ConfigS3SSECustomerKey          = s3.ConfigSSECustomerKey
+	ConfigS3SSECustomerKeyMD5       = s3.ConfigSSECustomerKeyMD5
+)
+
+// Addressing and endpoint modes of s3 backends, see the keys of package
+// kmodules.xyz/objectstore-api/pkg/stow/s3.
+const (
+	ConfigS3ForcePathStyle = s3.ConfigForcePathStyle
+	ConfigS3UseDualStack   = s3.ConfigUseDualStack
+	ConfigS3UseFIPS        = s3.ConfigUseFIPS
+	ConfigS3UseAccelerate  = s3.ConfigUseAccelerate
+	ConfigS3InsecureTLS    = s3.ConfigInsecureTLS
+)
+
+// Roles of s3 backends that are assumed through STS, see the keys of package
+// kmodules.xyz/objectstore-api/pkg/stow/s3.
+const (
+	ConfigS3RoleARN              = s3.ConfigRoleARN
+	ConfigS3ExternalID           = s3.ConfigExternalID
+	ConfigS3RoleSessionName      = s3.ConfigRoleSessionName
+	ConfigS3WebIdentityTokenFile = s3.ConfigWebIdentityTokenFile
+	ConfigS3STSEndpoint          = s3.ConfigSTSEndpoint
+)
+
+// Temporary credentials and profiles of s3 backends, see the keys of package
+// kmodules.xyz/objectstore-api/pkg/stow/s3. The shared credentials and config
+// files are written to AWSCredentialsFileName and AWSConfigFileName of the
+// osm secret.
+const (
+	ConfigS3Token                 = s3.ConfigToken
+	ConfigS3CredentialExpiration  = s3.ConfigCredentialExpiration
+	ConfigS3SharedCredentialsData = s3.ConfigSharedCredentialsData
+	ConfigS3SharedCredentialsFile = s3.ConfigSharedCredentialsFile
+	ConfigS3SharedConfigData      = s3.ConfigSharedConfigData
+	ConfigS3SharedConfigFile      = s3.ConfigSharedConfigFile
+	ConfigS3Profile               = s3.ConfigProfile
+)
+
+// The TLS and proxy settings of every backend are set with the keys of
+// packages tlsconfig and httpproxy. They are read by the azure, b2, gcs, rest,
+// s3 and swift locations of this module.
+
+// Authentication and endpoint of azure backends, read by the azure location
+// of package kmodules.xyz/objectstore-api/pkg/stow/azure. ConfigAzureClientID
+// selects the workload or managed identity that is used when there is neither
+// a key nor a SAS token.
+const (
+	ConfigAzureEndpoint = azure.ConfigEndpoint
+	ConfigAzureSASToken = azure.ConfigSASToken
+	ConfigAzureClientID = azure.ConfigClientID
 )
 
 // NewOSMSecret creates a secret that contains the config file of OSM.
@@ -69,17 +171,34 @@ const (
 // /etc/osm
 // ├── ca.crt
 // └── config
+//
+// Likewise, gcs credentials other than service account keys are added as
+// `google-credentials.json`, the aws profile files of s3 backends as
+// `aws-credentials` and `aws-config`, and the tls client certificate of any
+// backend as `tls.crt` and `tls.key`.
 
-func NewOSMSecret(kc kubernetes.Interface, name, namespace string, spec api.Backend) (*core.Secret, error) {
-	osmCtx, err := NewOSMContext(kc, spec, namespace)
+func NewOSMSecret(kc kubernetes.Interface, name, namespace string, spec api.Backend, opts ...credsource.Option) (*core.Secret, error) {
+	osmCtx, err := NewOSMContext(kc, spec, namespace, opts...)
 	if err != nil {
 		return nil, err
 	}
-	cacertData, certDataFound := osmCtx.Config[s3.ConfigCACertData]
-	if certDataFound {
-		// assume that CA cert file is mounted at SecretMountPath directory
-		osmCtx.Config[s3.ConfigCACertFile] = filepath.Join(SecretMountPath, CaCertFileName)
-		delete(osmCtx.Config, s3.ConfigCACertData)
+	out := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{},
+	}
+	for _, f := range projectedFiles {
+		data, found := osmCtx.Config[f.dataKey]
+		if !found {
+			continue
+		}
+		// assume that the file is mounted at SecretMountPath directory
+		osmCtx.Config[f.fileKey] = filepath.Join(SecretMountPath, f.fileName)
+		delete(osmCtx.Config, f.dataKey)
+		// inject the data as a file into the osm secret so that the file exists.
+		out.Data[f.fileName] = []byte(data)
 	}
 
 	osmCfg := &OSMConfig{
@@ -90,24 +209,12 @@ func NewOSMSecret(kc kubernetes.Interface, name, namespace string, spec api.Back
 	if err != nil {
 		return nil, err
 	}
-	out := &core.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: namespace,
-		},
-		Data: map[string][]byte{
-			"config": osmBytes,
-		},
-	}
-	if certDataFound {
-		// inject ca cert data as a file into the osm secret so that CaCertFileName exists.
-		out.Data[CaCertFileName] = []byte(cacertData)
-	}
+	out.Data["config"] = osmBytes
 	return out, nil
 }
 
-func WriteOSMConfig(kc kubernetes.Interface, namespace string, spec api.Backend, filename string) error {
-	osmCtx, err := NewOSMContext(kc, spec, namespace)
+func WriteOSMConfig(kc kubernetes.Interface, namespace string, spec api.Backend, filename string, opts ...credsource.Option) error {
+	osmCtx, err := NewOSMContext(kc, spec, namespace, opts...)
 	if err != nil {
 		return err
 	}
@@ -119,16 +226,18 @@ func WriteOSMConfig(kc kubernetes.Interface, namespace string, spec api.Backend,
 		return err
 	}
 
-	cacertData, certDataFound := osmCtx.Config[s3.ConfigCACertData]
-	if certDataFound {
-		// assume that CA cert file is mounted at SecretMountPath directory
-		f2 := filepath.Join(dir, CaCertFileName)
-		err = os.WriteFile(f2, []byte(cacertData), 0o644)
+	for _, f := range projectedFiles {
+		data, found := osmCtx.Config[f.dataKey]
+		if !found {
+			continue
+		}
+		f2 := filepath.Join(dir, f.fileName)
+		err = os.WriteFile(f2, []byte(data), 0o644)
 		if err != nil {
 			return err
 		}
-		osmCtx.Config[s3.ConfigCACertFile] = f2
-		delete(osmCtx.Config, s3.ConfigCACertData)
+		osmCtx.Config[f.fileKey] = f2
+		delete(osmCtx.Config, f.dataKey)
 	}
 
 	osmCfg := &OSMConfig{
@@ -142,36 +251,37 @@ func WriteOSMConfig(kc kubernetes.Interface, namespace string, spec api.Backend,
 	return os.WriteFile(filename, osmBytes, 0o644)
 }
 
-func CheckBucketAccess(client kubernetes.Interface, spec api.Backend, namespace string) error {
-	cfg, err := NewOSMContext(client, spec, namespace)
-	if err != nil {
-		return err
-	}
-	loc, err := stow.Dial(cfg.Provider, cfg.Config)
-	if err != nil {
-		return err
-	}
-	bucket, err := spec.Container()
-	if err != nil {
-		return err
+// CheckBucketAccess checks that the bucket of the backend exists and can be
+// written to, see InspectBucket.
+func CheckBucketAccess(client kubernetes.Interface, spec api.Backend, namespace string, opts ...credsource.Option) error {
+	_, err := InspectBucket(client, spec, namespace, opts...)
+	return err
+}
+
+// NewOSMContext returns the osm context of the backend. The credentials are
+// resolved the same way as by blob.NewBlob, opts allow reading them from
+// other namespaces. With credsource.WithSource, the credentials are read from
+// the given source and client may be nil.
+func NewOSMContext(client kubernetes.Interface, spec api.Backend, namespace string, opts ...credsource.Option) (*Context, error) {
+	var g credsource.Getter
+	if client != nil {
+		g = credsource.NewKubernetesGetter(client)
 	}
-	c, err := loc.Container(bucket)
+	config, err := credsource.Resolve(context.TODO(), g, namespace, spec, opts...)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return c.HasWriteAccess()
+	return newOSMContext(spec, config)
 }
 
-func NewOSMContext(client kubernetes.Interface, spec api.Backend, namespace string) (*Context, error) {
-	config := make(map[string][]byte)
-
-	if spec.StorageSecretName != "" {
-		secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), spec.StorageSecretName, metav1.GetOptions{})
-		if err != nil {
-			return nil, err
-		}
-		config = secret.Data
+// newOSMContext returns the osm context of the backend with the resolved
+// credentials. The defaults of the backend are applied to a copy of spec.
+func newOSMContext(spec api.Backend, config map[string][]byte) (*Context, error) {
+	if config == nil {
+		config = make(map[string][]byte)
 	}
+	spec = *spec.DeepCopy()
+	spec.SetDefaults()
 
 	nc := &Context{
 		Name:   "objectstore",
@@ -181,58 +291,33 @@ func NewOSMContext(client kubernetes.Interface, spec api.Backend, namespace stri
 	if spec.S3 != nil {
 		nc.Provider = s3.Kind
 
-		keyID, foundKeyID := config[awsconst.AWS_ACCESS_KEY_ID]
-		key, foundKey := config[awsconst.AWS_SECRET_ACCESS_KEY]
-		if foundKey && foundKeyID {
-			nc.Config[s3.ConfigAccessKeyID] = string(keyID)
-			nc.Config[s3.ConfigSecretKey] = string(key)
+		creds, err := s3auth.FromSecret(spec.S3, config)
+		if err != nil {
+			return nil, err
+		}
+		if creds.HasAccessKeys() {
+			nc.Config[s3.ConfigAccessKeyID] = creds.AccessKeyID
+			nc.Config[s3.ConfigSecretKey] = creds.SecretAccessKey
 			nc.Config[s3.ConfigAuthType] = "accesskey"
 		} else {
 			nc.Config[s3.ConfigAuthType] = "iam"
 		}
-		if spec.S3.Endpoint == "" || strings.HasSuffix(spec.S3.Endpoint, ".amazonaws.com") {
+		setTemporaryCredentialsConfig(nc.Config, creds)
+		if spec.S3.IsAWS() {
 			// Using s3 and not s3-compatible service like minio or rook, etc. Now, find region
-			var sess *session.Session
-			var err error
-			if nc.Config[s3.ConfigAuthType] == "iam" {
-				// The aws sdk does not currently support automatically setting the region based on an instances placement.
-				// This automatically sets region based on ec2 instance metadata when running on EC2.
-				// ref: https://docs.aws.amazon.com/sdk-for-javascript/v2/developer-guide/setting-region.html#setting-region-order-of-precedence
-				var c aws.Config
-				if s, e := session.NewSession(); e == nil {
-					if region, e := ec2metadata.New(s).Region(); e == nil {
-						c.WithRegion(region)
-					}
+			region := spec.S3.Region
+			if region == "" {
+				var err error
+				region, err = bucketRegion(spec.S3, creds, retry.FromSpec(spec.RetryPolicy))
+				if err != nil {
+					return nil, err
 				}
-				sess, err = session.NewSessionWithOptions(session.Options{
-					Config: c,
-					// Support MFA when authing using assumed roles.
-					SharedConfigState:       session.SharedConfigEnable,
-					AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
-				})
-			} else {
-				sess, err = session.NewSessionWithOptions(session.Options{
-					Config: aws.Config{
-						Credentials: credentials.NewStaticCredentials(string(keyID), string(key), ""),
-						Region:      aws.String("us-east-1"),
-					},
-					// Support MFA when authing using assumed roles.
-					SharedConfigState:       session.SharedConfigEnable,
-					AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
-				})
 			}
-			if err != nil {
-				return nil, err
-			}
-			svc := _s3.New(sess)
-			out, err := svc.GetBucketLocation(&_s3.GetBucketLocationInput{
-				Bucket: pointer.StringP(spec.S3.Bucket),
-			})
-			if err != nil {
-				return nil, err
-			}
-			nc.Config[s3.ConfigRegion] = stringz.Val(pointer.String(out.LocationConstraint), "us-east-1")
+			nc.Config[s3.ConfigRegion] = region
 		} else {
+			if spec.S3.Region != "" {
+				nc.Config[s3.ConfigRegion] = spec.S3.Region
+			}
 			nc.Config[s3.ConfigEndpoint] = spec.S3.Endpoint
 			u, err := url.Parse(spec.S3.Endpoint)
 			if err != nil {
@@ -245,16 +330,67 @@ func NewOSMContext(client kubernetes.Interface, spec api.Backend, namespace stri
 				nc.Config[s3.ConfigCACertData] = string(cacertData)
 			}
 		}
+		setAddressingConfig(nc.Config, spec.S3)
+		setRoleConfig(nc.Config, spec.S3)
+		if err := setTransportConfig(nc.Config, spec, config, false); err != nil {
+			return nil, err
+		}
+
+		sse, err := s3sse.ConfigFromSpec(spec.S3.Encryption, config)
+		if err != nil {
+			return nil, err
+		}
+		if sse != nil {
+			setSSEConfig(nc.Config, sse)
+		}
 		return nc, nil
 	} else if spec.GCS != nil {
 		nc.Provider = gcs.Kind
 		nc.Config[gcs.ConfigProjectId] = string(config[googconst.GOOGLE_PROJECT_ID])
-		nc.Config[gcs.ConfigJSON] = string(config[googconst.GOOGLE_SERVICE_ACCOUNT_JSON_KEY])
+		// the application default credentials are used without a credential file
+		if data, ok := config[googconst.GOOGLE_SERVICE_ACCOUNT_JSON_KEY]; ok {
+			typ, err := gcsauth.CredentialsType(data)
+			if err != nil {
+				return nil, err
+			}
+			if typ == gcsauth.TypeServiceAccount {
+				nc.Config[gcs.ConfigJSON] = string(data)
+			} else {
+				nc.Config[ConfigGCSCredentialsData] = string(data)
+			}
+		}
+		if len(spec.GCS.Scopes) > 0 {
+			nc.Config[gcs.ConfigScopes] = strings.Join(spec.GCS.Scopes, ",")
+		}
+		if spec.GCS.ImpersonateServiceAccount != "" {
+			nc.Config[ConfigGCSImpersonateServiceAccount] = spec.GCS.ImpersonateServiceAccount
+		}
+		if err := setTransportConfig(nc.Config, spec, config, true); err != nil {
+			return nil, err
+		}
 		return nc, nil
 	} else if spec.Azure != nil {
 		nc.Provider = azure.Kind
-		nc.Config[azure.ConfigAccount] = string(config[azconst.AZURE_ACCOUNT_NAME])
-		nc.Config[azure.ConfigKey] = string(config[azconst.AZURE_ACCOUNT_KEY])
+		cfg, err := azureauth.ConfigFromSecret(spec.Azure, config)
+		if err != nil {
+			return nil, err
+		}
+		nc.Config[azure.ConfigAccount] = cfg.AccountName
+		if cfg.AccountKey != "" {
+			nc.Config[azure.ConfigKey] = cfg.AccountKey
+		}
+		if spec.Azure.Endpoint != "" {
+			nc.Config[ConfigAzureEndpoint] = cfg.Endpoint
+		}
+		if cfg.AccountKey == "" && cfg.SASToken != "" {
+			nc.Config[ConfigAzureSASToken] = cfg.SASToken
+		}
+		if cfg.ClientID != "" {
+			nc.Config[ConfigAzureClientID] = cfg.ClientID
+		}
+		if err := setTransportConfig(nc.Config, spec, config, true); err != nil {
+			return nil, err
+		}
 		return nc, nil
 	} else if spec.Local != nil {
 		nc.Provider = local.Kind
@@ -262,40 +398,275 @@ func NewOSMContext(client kubernetes.Interface, spec api.Backend, namespace stri
 		return nc, nil
 	} else if spec.Swift != nil {
 		nc.Provider = swift.Kind
-		// https://github.com/restic/restic/blob/master/src/restic/backend/swift/config.go
-		for _, val := range []struct {
-			stowKey   string
-			secretKey string
-		}{
-			// v2/v3 specific
-			{swift.ConfigUsername, osconst.OS_USERNAME},
-			{swift.ConfigKey, osconst.OS_PASSWORD},
-			{swift.ConfigRegion, osconst.OS_REGION_NAME},
-			{swift.ConfigTenantAuthURL, osconst.OS_AUTH_URL},
-
-			// v3 specific
-			{swift.ConfigDomain, osconst.OS_USER_DOMAIN_NAME},
-			{swift.ConfigTenantName, osconst.OS_PROJECT_NAME},
-			{swift.ConfigTenantDomain, osconst.OS_PROJECT_DOMAIN_NAME},
-
-			// v2 specific
-			{swift.ConfigTenantId, osconst.OS_TENANT_ID},
-			{swift.ConfigTenantName, osconst.OS_TENANT_NAME},
-
-			// v1 specific
-			{swift.ConfigTenantAuthURL, osconst.ST_AUTH},
-			{swift.ConfigUsername, osconst.ST_USER},
-			{swift.ConfigKey, osconst.ST_KEY},
-
-			// Manual authentication
-			{swift.ConfigStorageURL, osconst.OS_STORAGE_URL},
-			{swift.ConfigAuthToken, osconst.OS_AUTH_TOKEN},
-		} {
-			if _, exists := nc.Config.Config(val.stowKey); !exists {
-				nc.Config[val.stowKey] = string(config[val.secretKey])
-			}
+		// every key is written, even when it is empty, as gomodules.xyz/stow/swift
+		// required
+		cfg := openstack.SwiftConfigFromSecret(config)
+		nc.Config[swift.ConfigUsername] = cfg.Username
+		nc.Config[swift.ConfigKey] = cfg.Key
+		nc.Config[swift.ConfigRegion] = cfg.Region
+		nc.Config[swift.ConfigTenantAuthURL] = cfg.AuthURL
+		nc.Config[swift.ConfigDomain] = cfg.Domain
+		nc.Config[swift.ConfigTenantName] = cfg.TenantName
+		nc.Config[swift.ConfigTenantDomain] = cfg.TenantDomain
+		nc.Config[swift.ConfigTenantId] = cfg.TenantID
+		nc.Config[swift.ConfigStorageURL] = cfg.StorageURL
+		nc.Config[swift.ConfigAuthToken] = cfg.AuthToken
+		if err := setTransportConfig(nc.Config, spec, config, true); err != nil {
+			return nil, err
+		}
+		return nc, nil
+	} else if spec.B2 != nil {
+		nc.Provider = b2.Kind
+		nc.Config[b2.ConfigAccountID] = string(config[api.B2_ACCOUNT_ID])
+		nc.Config[b2.ConfigApplicationKey] = string(config[api.B2_ACCOUNT_KEY])
+		if authURL, ok := config[api.B2_AUTH_URL]; ok {
+			nc.Config[b2.ConfigAuthURL] = string(authURL)
+		}
+		if spec.B2.MaxConnections > 0 {
+			nc.Config[b2.ConfigMaxConnections] = strconv.FormatInt(spec.B2.MaxConnections, 10)
+		}
+		if err := setTransportConfig(nc.Config, spec, config, true); err != nil {
+			return nil, err
+		}
+		return nc, nil
+	} else if spec.Rest != nil {
+		nc.Provider = rest.Kind
+		nc.Config[rest.ConfigURL] = spec.Rest.URL
+		if username, ok := config[api.REST_SERVER_USERNAME]; ok {
+			nc.Config[rest.ConfigUsername] = string(username)
+			nc.Config[rest.ConfigPassword] = string(config[api.REST_SERVER_PASSWORD])
+		}
+		u, err := url.Parse(spec.Rest.URL)
+		if err != nil {
+			return nil, err
+		}
+		cacertData, ok := config[awsconst.CA_CERT_DATA]
+		if ok && u.Scheme == "https" {
+			nc.Config[rest.ConfigCACertData] = string(cacertData)
+		}
+		if err := setTransportConfig(nc.Config, spec, config, false); err != nil {
+			return nil, err
 		}
 		return nc, nil
 	}
 	return nil, errors.New("no storage provider is configured")
 }
+
+// setTransportConfig sets the TLS, proxy, retry and rate limit settings of the
+// backend.
+// withCA sets the CA bundle, s3 and rest backends only trust it for https
+// endpoints.
+func setTransportConfig(cfg stow.ConfigMap, spec api.Backend, config map[string][]byte, withCA bool) error {
+	opts := tlsconfig.FromSecret(spec.TLS, config)
+	// fail early on the client certificate instead of when the location is
+	// dialed. The CA bundle is left to the location, as it always was.
+	check := opts
+	check.CACertData = nil
+	if _, err := check.Config(); err != nil {
+		return err
+	}
+	if withCA && len(opts.CACertData) > 0 {
+		cfg[tlsconfig.ConfigCACertData] = string(opts.CACertData)
+	}
+	opts.SetStowConfig(cfg)
+
+	proxy := httpproxy.FromSecret(spec.Proxy, config)
+	if _, err := proxy.Func(); err != nil {
+		return err
+	}
+	proxy.SetStowConfig(cfg)
+
+	retry.FromSpec(spec.RetryPolicy).SetStowConfig(cfg)
+	ratelimit.FromSpec(spec).SetStowConfig(cfg)
+	return nil
+}
+
+// bucketRegionTimeout bounds the lookup of the region of a bucket whose
+// backend has no retry policy timeout.
+const bucketRegionTimeout = time.Minute
+
+// bucketRegion looks up the region of an AWS bucket. p may be nil.
+func bucketRegion(spec *api.S3Spec, creds *s3auth.Credentials, p *retry.Policy) (string, error) {
+	var sess *session.Session
+	var err error
+	switch {
+	case creds.HasAccessKeys():
+		sess, err = session.NewSessionWithOptions(session.Options{
+			Config: *withEndpointModes(&aws.Config{
+				Credentials: credentials.NewStaticCredentials(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken),
+				Region:      aws.String("us-east-1"),
+			}, spec),
+			// Support MFA when authing using assumed roles.
+			SharedConfigState:       session.SharedConfigEnable,
+			AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+		})
+	case creds.HasSharedFiles():
+		credentialsFile, configFile, dir, e := creds.WriteSharedFiles()
+		if e != nil {
+			return "", e
+		}
+		// the files are read while the session is created
+		defer os.RemoveAll(dir) // nolint:errcheck
+		sess, err = session.NewSessionWithOptions(session.Options{
+			Config: *withEndpointModes(&aws.Config{
+				Region: aws.String("us-east-1"),
+			}, spec),
+			Profile:                 creds.Profile,
+			SharedConfigFiles:       []string{credentialsFile, configFile},
+			SharedConfigState:       session.SharedConfigEnable,
+			AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+		})
+	default:
+		// The aws sdk does not currently support automatically setting the region based on an instances placement.
+		// This automatically sets region based on ec2 instance metadata when running on EC2.
+		// ref: https://docs.aws.amazon.com/sdk-for-javascript/v2/developer-guide/setting-region.html#setting-region-order-of-precedence
+		var c aws.Config
+		if s, e := session.NewSession(); e == nil {
+			if region, e := ec2metadata.New(s).Region(); e == nil {
+				c.WithRegion(region)
+			}
+		}
+		sess, err = session.NewSessionWithOptions(session.Options{
+			Config: *withEndpointModes(&c, spec),
+			// Support MFA when authing using assumed roles.
+			SharedConfigState:       session.SharedConfigEnable,
+			AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+		})
+	}
+	if err != nil {
+		return "", err
+	}
+	if spec.RoleARN != "" {
+		sess.Config.Credentials = s3.AssumeRoleCredentials(sess, s3.Role{
+			ARN:                  spec.RoleARN,
+			ExternalID:           spec.ExternalID,
+			SessionName:          spec.RoleSessionName,
+			WebIdentityTokenFile: spec.WebIdentityTokenFile,
+			STSEndpoint:          spec.STSEndpoint,
+		})
+	}
+	if p != nil {
+		// the lookup is retried by p
+		sess.Config.MaxRetries = aws.Int(0)
+	}
+	svc := _s3.New(sess)
+
+	ctx, cancel := p.WithTimeout(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		var cancelLookup context.CancelFunc
+		ctx, cancelLookup = context.WithTimeout(ctx, bucketRegionTimeout)
+		defer cancelLookup()
+	}
+	var out *_s3.GetBucketLocationOutput
+	err = p.Do(ctx, func(ctx context.Context) error {
+		var err error
+		out, err = svc.GetBucketLocationWithContext(ctx, &_s3.GetBucketLocationInput{
+			Bucket: pointer.StringP(spec.Bucket),
+		})
+		return err
+	})
+	if err != nil {
+		return "", expiredCredentialsError(err)
+	}
+	return stringz.Val(pointer.String(out.LocationConstraint), "us-east-1"), nil
+}
+
+// withEndpointModes makes the bucket location lookup use the same kind of
+// endpoint as the backend. Transfer acceleration does not support
+// GetBucketLocation and is left out.
+func withEndpointModes(c *aws.Config, spec *api.S3Spec) *aws.Config {
+	if spec.DualStack {
+		c.UseDualStackEndpoint = endpoints.DualStackEndpointStateEnabled
+	}
+	if spec.FIPS {
+		c.UseFIPSEndpoint = endpoints.FIPSEndpointStateEnabled
+	}
+	if spec.AddressingStyle != "" {
+		c.WithS3ForcePathStyle(spec.UsePathStyle())
+	}
+	return c
+}
+
+func setTemporaryCredentialsConfig(cfg stow.ConfigMap, creds *s3auth.Credentials) {
+	if creds.SessionToken != "" {
+		cfg[ConfigS3Token] = creds.SessionToken
+	}
+	if !creds.Expires.IsZero() {
+		cfg[ConfigS3CredentialExpiration] = creds.Expires.Format(time.RFC3339)
+	}
+	if len(creds.SharedCredentials) > 0 {
+		cfg[ConfigS3SharedCredentialsData] = string(creds.SharedCredentials)
+	}
+	if len(creds.SharedConfig) > 0 {
+		cfg[ConfigS3SharedConfigData] = string(creds.SharedConfig)
+	}
+	if creds.Profile != "" {
+		cfg[ConfigS3Profile] = creds.Profile
+	}
+}
+
+// expiredCredentialsError returns an s3auth.ExpiredCredentialsError if S3
+// rejected the request because the session token expired.
+func expiredCredentialsError(err error) error {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && s3auth.IsExpiredCode(awsErr.Code()) {
+		return &s3auth.ExpiredCredentialsError{Err: err}
+	}
+	return err
+}
+
+func setRoleConfig(cfg stow.ConfigMap, spec *api.S3Spec) {
+	if spec.RoleARN == "" {
+		return
+	}
+	cfg[ConfigS3RoleARN] = spec.RoleARN
+	for k, v := range map[string]string{
+		ConfigS3ExternalID:           spec.ExternalID,
+		ConfigS3RoleSessionName:      spec.RoleSessionName,
+		ConfigS3WebIdentityTokenFile: spec.WebIdentityTokenFile,
+		ConfigS3STSEndpoint:          spec.STSEndpoint,
+	} {
+		if v != "" {
+			cfg[k] = v
+		}
+	}
+}
+
+func setAddressingConfig(cfg stow.ConfigMap, spec *api.S3Spec) {
+	if spec.AddressingStyle != "" || spec.SignatureVersion == api.S3SignatureV2 {
+		cfg[ConfigS3ForcePathStyle] = strconv.FormatBool(spec.UsePathStyle())
+	}
+	if spec.SignatureVersion == api.S3SignatureV2 {
+		cfg[s3.ConfigV2Signing] = "true"
+	}
+	if spec.DualStack {
+		cfg[ConfigS3UseDualStack] = "true"
+	}
+	if spec.FIPS {
+		cfg[ConfigS3UseFIPS] = "true"
+	}
+	if spec.Accelerate {
+		cfg[ConfigS3UseAccelerate] = "true"
+	}
+	if spec.InsecureTLS {
+		cfg[ConfigS3InsecureTLS] = "true"
+	}
+}
+
+func setSSEConfig(cfg stow.ConfigMap, sse *s3sse.Config) {
+	if sse.ServerSideEncryption != "" {
+		cfg[ConfigS3ServerSideEncryption] = sse.ServerSideEncryption
+	}
+	if sse.KMSKeyID != "" {
+		cfg[ConfigS3SSEKMSKeyID] = sse.KMSKeyID
+	}
+	if sse.KMSEncryptionContext != "" {
+		cfg[ConfigS3SSEKMSEncryptionContext] = sse.KMSEncryptionContext
+	}
+	if algorithm, key, keyMD5 := sse.CustomerKeyHeaders(); key != "" {
+		cfg[ConfigS3SSECustomerAlgorithm] = algorithm
+		cfg[ConfigS3SSECustomerKey] = key
+		cfg[ConfigS3SSECustomerKeyMD5] = keyMD5
+	}
+}
@@ -0,0 +1,2012 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	osconst "kmodules.xyz/constants/openstack"
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/azuretest"
+	"kmodules.xyz/objectstore-api/pkg/b2/b2test"
+	"kmodules.xyz/objectstore-api/pkg/blob/swiftblob/swifttest"
+	"kmodules.xyz/objectstore-api/pkg/credsource"
+	"kmodules.xyz/objectstore-api/pkg/gcstest"
+	"kmodules.xyz/objectstore-api/pkg/httpproxy"
+	"kmodules.xyz/objectstore-api/pkg/httpproxy/proxytest"
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+	"kmodules.xyz/objectstore-api/pkg/rest/resttest"
+	"kmodules.xyz/objectstore-api/pkg/retry"
+	"kmodules.xyz/objectstore-api/pkg/retry/retrytest"
+	"kmodules.xyz/objectstore-api/pkg/s3auth"
+	"kmodules.xyz/objectstore-api/pkg/s3test"
+	"kmodules.xyz/objectstore-api/pkg/stow/azure"
+	"kmodules.xyz/objectstore-api/pkg/stow/b2"
+	"kmodules.xyz/objectstore-api/pkg/stow/gcs"
+	"kmodules.xyz/objectstore-api/pkg/stow/rest"
+	"kmodules.xyz/objectstore-api/pkg/stow/s3"
+	"kmodules.xyz/objectstore-api/pkg/stow/swift"
+	"kmodules.xyz/objectstore-api/pkg/tlsconfig"
+
+	"github.com/stretchr/testify/assert"
+	"gomodules.xyz/stow"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+)
+
+// newKubeClient returns a clientset backed by a minimal api server that
+// serves the given secrets and config maps.
+func newKubeClient(t *testing.T, objs ...metav1.Object) kubernetes.Interface {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// /api/v1/namespaces/<ns>/<resource>/<name>
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if r.Method == http.MethodGet && len(parts) == 6 {
+			for _, obj := range objs {
+				if obj.GetNamespace() != parts[3] || obj.GetName() != parts[5] {
+					continue
+				}
+				switch o := obj.(type) {
+				case *core.Secret:
+					if parts[4] == "secrets" {
+						out := o.DeepCopy()
+						out.APIVersion, out.Kind = "v1", "Secret"
+						_ = json.NewEncoder(w).Encode(out)
+						return
+					}
+				case *core.ConfigMap:
+					if parts[4] == "configmaps" {
+						out := o.DeepCopy()
+						out.APIVersion, out.Kind = "v1", "ConfigMap"
+						_ = json.NewEncoder(w).Encode(out)
+						return
+					}
+				}
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(&metav1.Status{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Status"},
+			Status:   metav1.StatusFailure,
+			Reason:   metav1.StatusReasonNotFound,
+			Code:     http.StatusNotFound,
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	kc, err := kubernetes.NewForConfig(&restclient.Config{Host: srv.URL})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return kc
+}
+
+func TestB2Context(t *testing.T) {
+	srv := b2test.NewServer("b2-account", "b2-key")
+	defer srv.Close()
+	srv.CreateBucket("cold-backups")
+
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "b2-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.B2_ACCOUNT_ID:  []byte("b2-account"),
+			api.B2_ACCOUNT_KEY: []byte("b2-key"),
+			api.B2_AUTH_URL:    []byte(srv.URL),
+		},
+	})
+	spec := api.Backend{
+		StorageSecretName: "b2-secret",
+		B2: &api.B2Spec{
+			Bucket:         "cold-backups",
+			Prefix:         "demo",
+			MaxConnections: 4,
+		},
+	}
+
+	osmCtx, err := NewOSMContext(kc, spec, "demo")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, b2.Kind, osmCtx.Provider)
+	assert.Equal(t, stow.ConfigMap{
+		b2.ConfigAccountID:             "b2-account",
+		b2.ConfigApplicationKey:        "b2-key",
+		b2.ConfigAuthURL:               srv.URL,
+		b2.ConfigMaxConnections:        "4",
+		ratelimit.ConfigKey:            "b2://cold-backups/demo?maxConnections=4",
+		ratelimit.ConfigMaxConnections: "4",
+	}, osmCtx.Config)
+
+	assert.Nil(t, CheckBucketAccess(kc, spec, "demo"))
+	assert.Empty(t, srv.Keys("cold-backups"))
+
+	loc, err := stow.Dial(osmCtx.Provider, osmCtx.Config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = loc.Container("missing")
+	assert.Equal(t, stow.ErrNotFound, err)
+
+	c, err := loc.Container("cold-backups")
+	assert.Nil(t, err)
+	item, err := c.Put("demo/file.txt", strings.NewReader("data"), 4, map[string]any{"owner": "demo"})
+	assert.Nil(t, err)
+	assert.Equal(t, "b2://cold-backups/demo/file.txt", item.URL().String())
+
+	byURL, err := loc.ItemByURL(item.URL())
+	if assert.Nil(t, err) {
+		md, err := byURL.Metadata()
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]any{"owner": "demo"}, md)
+	}
+
+	page, err := c.Browse("", "/", stow.CursorStart, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"demo/"}, page.Prefixes)
+	assert.Nil(t, c.RemoveItem("demo/file.txt"))
+}
+
+func TestB2ContextWrongCredentials(t *testing.T) {
+	srv := b2test.NewServer("b2-account", "b2-key")
+	defer srv.Close()
+
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "b2-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.B2_ACCOUNT_ID:  []byte("b2-account"),
+			api.B2_ACCOUNT_KEY: []byte("wrong"),
+			api.B2_AUTH_URL:    []byte(srv.URL),
+		},
+	})
+	err := CheckBucketAccess(kc, api.Backend{
+		StorageSecretName: "b2-secret",
+		B2:                &api.B2Spec{Bucket: "cold-backups"},
+	}, "demo")
+	assert.ErrorContains(t, err, "401 unauthorized")
+}
+
+func TestSwiftContext(t *testing.T) {
+	srv := swifttest.NewServer("swift-user", "swift-password")
+	defer srv.Close()
+	srv.Tenant = "backups"
+	srv.CreateContainer("stash-backup")
+
+	cases := []struct {
+		name     string
+		data     map[string][]byte
+		expected stow.ConfigMap
+	}{
+		{
+			name: "v1",
+			data: map[string][]byte{
+				osconst.ST_AUTH: []byte(srv.AuthURL(1)),
+				osconst.ST_USER: []byte("swift-user"),
+				osconst.ST_KEY:  []byte("swift-password"),
+			},
+			expected: stow.ConfigMap{
+				swift.ConfigUsername:      "swift-user",
+				swift.ConfigKey:           "swift-password",
+				swift.ConfigTenantAuthURL: srv.AuthURL(1),
+			},
+		},
+		{
+			name: "v2",
+			data: map[string][]byte{
+				osconst.OS_AUTH_URL:    []byte(srv.AuthURL(2)),
+				osconst.OS_USERNAME:    []byte("swift-user"),
+				osconst.OS_PASSWORD:    []byte("swift-password"),
+				osconst.OS_TENANT_NAME: []byte("backups"),
+				osconst.OS_REGION_NAME: []byte(srv.Region),
+			},
+			expected: stow.ConfigMap{
+				swift.ConfigUsername:      "swift-user",
+				swift.ConfigKey:           "swift-password",
+				swift.ConfigTenantAuthURL: srv.AuthURL(2),
+				swift.ConfigTenantName:    "backups",
+				swift.ConfigRegion:        srv.Region,
+			},
+		},
+		{
+			name: "v3",
+			data: map[string][]byte{
+				osconst.OS_AUTH_URL:            []byte(srv.AuthURL(3)),
+				osconst.OS_USERNAME:            []byte("swift-user"),
+				osconst.OS_PASSWORD:            []byte("swift-password"),
+				osconst.OS_USER_DOMAIN_NAME:    []byte(srv.Domain),
+				osconst.OS_PROJECT_NAME:        []byte("backups"),
+				osconst.OS_PROJECT_DOMAIN_NAME: []byte(srv.Domain),
+				osconst.OS_REGION_NAME:         []byte(srv.Region),
+				// the v2 name is ignored when the v3 one is present
+				osconst.OS_TENANT_NAME: []byte("ignored"),
+			},
+			expected: stow.ConfigMap{
+				swift.ConfigUsername:      "swift-user",
+				swift.ConfigKey:           "swift-password",
+				swift.ConfigTenantAuthURL: srv.AuthURL(3),
+				swift.ConfigDomain:        srv.Domain,
+				swift.ConfigTenantName:    "backups",
+				swift.ConfigTenantDomain:  srv.Domain,
+				swift.ConfigRegion:        srv.Region,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			kc := newKubeClient(t, &core.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "swift-secret", Namespace: "demo"},
+				Data:       tc.data,
+			})
+			spec := api.Backend{
+				StorageSecretName: "swift-secret",
+				Swift:             &api.SwiftSpec{Container: "stash-backup"},
+			}
+
+			osmCtx, err := NewOSMContext(kc, spec, "demo")
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, swift.Kind, osmCtx.Provider)
+			// every key is set, even when it is empty
+			expected := stow.ConfigMap{
+				swift.ConfigUsername:      "",
+				swift.ConfigKey:           "",
+				swift.ConfigRegion:        "",
+				swift.ConfigTenantAuthURL: "",
+				swift.ConfigDomain:        "",
+				swift.ConfigTenantName:    "",
+				swift.ConfigTenantDomain:  "",
+				swift.ConfigTenantId:      "",
+				swift.ConfigStorageURL:    "",
+				swift.ConfigAuthToken:     "",
+			}
+			for k, v := range tc.expected {
+				expected[k] = v
+			}
+			assert.Equal(t, expected, osmCtx.Config)
+
+			info, err := InspectBucket(kc, spec, "demo")
+			if assert.Nil(t, err) {
+				assert.Equal(t, tc.expected[swift.ConfigRegion], info.Region)
+				assert.Equal(t, []api.ObjectStoreCapability{api.CapabilityRead, api.CapabilityList, api.CapabilityWrite}, info.Capabilities)
+			}
+			assert.Empty(t, srv.Keys("stash-backup"))
+		})
+	}
+}
+
+func TestRestContext(t *testing.T) {
+	srv := resttest.NewTLSServer()
+	defer srv.Close()
+	srv.Username, srv.Password = "stash", "not-so-secret"
+	srv.CreateRepository("/demo")
+
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rest-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.REST_SERVER_USERNAME: []byte("stash"),
+			api.REST_SERVER_PASSWORD: []byte("not-so-secret"),
+			api.CA_CERT_DATA:         srv.CACert(),
+		},
+	})
+	spec := api.Backend{
+		StorageSecretName: "rest-secret",
+		Rest:              &api.RestServerSpec{URL: srv.URL + "/demo"},
+	}
+
+	osmCtx, err := NewOSMContext(kc, spec, "demo")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, rest.Kind, osmCtx.Provider)
+	assert.Equal(t, stow.ConfigMap{
+		rest.ConfigURL:        srv.URL + "/demo",
+		rest.ConfigUsername:   "stash",
+		rest.ConfigPassword:   "not-so-secret",
+		rest.ConfigCACertData: string(srv.CACert()),
+	}, osmCtx.Config)
+
+	assert.Nil(t, CheckBucketAccess(kc, spec, "demo"))
+	assert.Empty(t, srv.Keys("/demo"))
+
+	osmSecret, err := NewOSMSecret(kc, "osm", "demo", spec)
+	if assert.Nil(t, err) {
+		assert.Equal(t, srv.CACert(), osmSecret.Data[CaCertFileName])
+		assert.Contains(t, string(osmSecret.Data["config"]), rest.ConfigCACertFile)
+	}
+
+	// plain names from here on
+	srv.NoVerifyUpload = true
+	loc, err := stow.Dial(osmCtx.Provider, osmCtx.Config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	container, err := spec.Container()
+	assert.Nil(t, err)
+	c, err := loc.Container(container)
+	if !assert.Nil(t, err) {
+		return
+	}
+	item, err := c.Put("snapshots/s1", strings.NewReader("data"), 4, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "rest://"+container+"/snapshots/s1", item.URL().String())
+
+	byURL, err := loc.ItemByURL(item.URL())
+	if assert.Nil(t, err) {
+		r, err := byURL.Open()
+		if assert.Nil(t, err) {
+			data, _ := io.ReadAll(r)
+			_ = r.Close()
+			assert.Equal(t, "data", string(data))
+		}
+	}
+
+	page, err := c.Browse("", "/", stow.CursorStart, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"snapshots/"}, page.Prefixes)
+	assert.Nil(t, c.RemoveItem("snapshots/s1"))
+	assert.Equal(t, stow.ErrNotFound, c.RemoveItem("snapshots/s1"))
+}
+
+func TestInspectReadOnlyBucket(t *testing.T) {
+	srv := resttest.NewServer()
+	defer srv.Close()
+	srv.CreateRepository("/demo")
+	srv.ReadOnly = true
+
+	kc := newKubeClient(t)
+	spec := api.Backend{Rest: &api.RestServerSpec{URL: srv.URL + "/demo"}}
+	info, err := InspectBucket(kc, spec, "demo")
+	assert.ErrorContains(t, err, "403")
+	if assert.NotNil(t, info) {
+		assert.Empty(t, info.Region)
+		assert.Equal(t, []api.ObjectStoreCapability{api.CapabilityRead, api.CapabilityList}, info.Capabilities)
+	}
+	assert.ErrorContains(t, CheckBucketAccess(kc, spec, "demo"), "403")
+
+	srv.ReadOnly = false
+	info, err = InspectBucket(kc, spec, "demo")
+	assert.Nil(t, err)
+	assert.Equal(t, []api.ObjectStoreCapability{api.CapabilityRead, api.CapabilityList, api.CapabilityWrite}, info.Capabilities)
+}
+
+func TestMutualTLSContext(t *testing.T) {
+	srv := resttest.NewMutualTLSServer()
+	defer srv.Close()
+	srv.CreateRepository("/demo")
+	cert, key := srv.ClientCert()
+
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tls-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.CA_CERT_DATA:         srv.CACert(),
+			api.TLS_CLIENT_CERT_DATA: cert,
+			api.TLS_CLIENT_KEY_DATA:  key,
+		},
+	})
+	// the certificate of httptest servers is valid for example.com
+	tls := &api.TLSConfig{ServerName: "example.com", MinVersion: api.TLSVersion12}
+	spec := api.Backend{
+		StorageSecretName: "tls-secret",
+		Rest:              &api.RestServerSpec{URL: srv.URL + "/demo"},
+		TLS:               tls,
+	}
+
+	osmCtx, err := NewOSMContext(kc, spec, "demo")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, stow.ConfigMap{
+		rest.ConfigURL:                 srv.URL + "/demo",
+		rest.ConfigCACertData:          string(srv.CACert()),
+		tlsconfig.ConfigClientCertData: string(cert),
+		tlsconfig.ConfigClientKeyData:  string(key),
+		tlsconfig.ConfigServerName:     "example.com",
+		tlsconfig.ConfigMinVersion:     "1.2",
+	}, osmCtx.Config)
+	assert.Nil(t, CheckBucketAccess(kc, spec, "demo"))
+
+	osmSecret, err := NewOSMSecret(kc, "osm", "demo", spec)
+	if assert.Nil(t, err) {
+		assert.Equal(t, srv.CACert(), osmSecret.Data[CaCertFileName])
+		assert.Equal(t, cert, osmSecret.Data[TLSCertFileName])
+		assert.Equal(t, key, osmSecret.Data[TLSKeyFileName])
+		assert.Contains(t, string(osmSecret.Data["config"]), tlsconfig.ConfigClientCertFile)
+		assert.Contains(t, string(osmSecret.Data["config"]), tlsconfig.ConfigClientKeyFile)
+		assert.NotContains(t, string(osmSecret.Data["config"]), tlsconfig.ConfigClientKeyData)
+	}
+
+	// the projected files are read when the location is dialed
+	configFile := filepath.Join(t.TempDir(), "config")
+	if !assert.Nil(t, WriteOSMConfig(kc, "demo", spec, configFile)) {
+		return
+	}
+	osmConfig, err := LoadConfig(configFile)
+	if !assert.Nil(t, err) {
+		return
+	}
+	container, err := spec.Container()
+	assert.Nil(t, err)
+	loc, err := osmConfig.Dial("")
+	if assert.Nil(t, err) {
+		c, err := loc.Container(container)
+		if assert.Nil(t, err) {
+			assert.Nil(t, c.HasWriteAccess())
+		}
+	}
+
+	// the tls settings are set for every provider
+	swiftSpec := api.Backend{
+		StorageSecretName: "tls-secret",
+		Swift:             &api.SwiftSpec{Container: "stash"},
+		TLS:               tls,
+	}
+	osmCtx, err = NewOSMContext(kc, swiftSpec, "demo")
+	if assert.Nil(t, err) {
+		assert.Equal(t, string(srv.CACert()), osmCtx.Config[tlsconfig.ConfigCACertData])
+		assert.Equal(t, string(cert), osmCtx.Config[tlsconfig.ConfigClientCertData])
+		assert.Equal(t, "example.com", osmCtx.Config[tlsconfig.ConfigServerName])
+	}
+
+	kc = newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tls-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.TLS_CLIENT_CERT_DATA: cert,
+		},
+	})
+	_, err = NewOSMContext(kc, spec, "demo")
+	assert.ErrorContains(t, err, "tls client certificate without TLS_CLIENT_KEY_DATA")
+}
+
+func TestProxyContext(t *testing.T) {
+	srv := resttest.NewTLSServer()
+	defer srv.Close()
+	srv.CreateRepository("/demo")
+	proxy := proxytest.NewServer()
+	defer proxy.Close()
+	proxy.Username, proxy.Password = "egress", "not-so-secret"
+
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxied-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.CA_CERT_DATA:       srv.CACert(),
+			api.PROXY_USERNAME:     []byte("egress"),
+			api.PROXY_PASSWORD:     []byte("not-so-secret"),
+			api.B2_ACCOUNT_ID:      []byte("id"),
+			api.B2_ACCOUNT_KEY:     []byte("key"),
+			osconst.OS_AUTH_URL:    []byte("https://keystone.example.com/v3"),
+			osconst.OS_USERNAME:    []byte("stash"),
+			osconst.OS_PASSWORD:    []byte("secret"),
+			osconst.OS_REGION_NAME: []byte("RegionOne"),
+		},
+	})
+	proxyConfig := &api.ProxyConfig{URL: proxy.URL, NoProxy: []string{".svc"}}
+	spec := api.Backend{
+		StorageSecretName: "proxied-secret",
+		Rest:              &api.RestServerSpec{URL: srv.URL + "/demo"},
+		Proxy:             proxyConfig,
+	}
+
+	osmCtx, err := NewOSMContext(kc, spec, "demo")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, stow.ConfigMap{
+		rest.ConfigURL:           srv.URL + "/demo",
+		rest.ConfigCACertData:    string(srv.CACert()),
+		httpproxy.ConfigURL:      proxy.URL,
+		httpproxy.ConfigUsername: "egress",
+		httpproxy.ConfigPassword: "not-so-secret",
+		httpproxy.ConfigNoProxy:  ".svc",
+	}, osmCtx.Config)
+
+	// the location connects through the proxy
+	assert.Nil(t, CheckBucketAccess(kc, spec, "demo"))
+	assert.Contains(t, proxy.Hosts(), strings.TrimPrefix(srv.URL, "https://"))
+
+	// the proxy settings are set for every provider
+	for _, backend := range []api.Backend{
+		{B2: &api.B2Spec{Bucket: "stash-backup"}},
+		{Swift: &api.SwiftSpec{Container: "stash"}},
+	} {
+		backend.StorageSecretName = "proxied-secret"
+		backend.Proxy = proxyConfig
+		osmCtx, err = NewOSMContext(kc, backend, "demo")
+		if assert.Nil(t, err) {
+			assert.Equal(t, proxy.URL, osmCtx.Config[httpproxy.ConfigURL])
+			assert.Equal(t, "not-so-secret", osmCtx.Config[httpproxy.ConfigPassword])
+		}
+	}
+}
+
+func TestProxyDial(t *testing.T) {
+	proxy := proxytest.NewServer()
+	defer proxy.Close()
+	proxy.Username, proxy.Password = "egress", "not-so-secret"
+	azureSrv := azuretest.NewServer()
+	defer azureSrv.Close()
+	gcsSrv := gcstest.NewServer()
+	defer gcsSrv.Close()
+	t.Setenv("STORAGE_EMULATOR_HOST", gcsSrv.URL)
+	gcsSrv.CreateBucket("stash")
+	swiftSrv := swifttest.NewServer("swift-user", "swift-password")
+	defer swiftSrv.Close()
+	swiftSrv.CreateContainer("stash")
+
+	serviceAccount, err := gcsSrv.ServiceAccountKey()
+	if !assert.Nil(t, err) {
+		return
+	}
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxied-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.PROXY_USERNAME:                  []byte("egress"),
+			api.PROXY_PASSWORD:                  []byte("not-so-secret"),
+			api.AZURE_ACCOUNT_NAME:              []byte(azuretest.AccountName),
+			api.AZURE_ACCOUNT_KEY:               []byte(azuretest.AccountKey),
+			api.GOOGLE_PROJECT_ID:               []byte("demo"),
+			api.GOOGLE_SERVICE_ACCOUNT_JSON_KEY: serviceAccount,
+			osconst.ST_AUTH:                     []byte(swiftSrv.AuthURL(1)),
+			osconst.ST_USER:                     []byte("swift-user"),
+			osconst.ST_KEY:                      []byte("swift-password"),
+		},
+	})
+
+	cases := []struct {
+		name    string
+		backend api.Backend
+		host    string
+	}{
+		{
+			name: "azure",
+			backend: api.Backend{Azure: &api.AzureSpec{
+				StorageAccount: azuretest.AccountName,
+				Container:      "stash",
+				Endpoint:       azureSrv.Endpoint(),
+			}},
+			host: azureSrv.Listener.Addr().String(),
+		},
+		{
+			name:    "gcs",
+			backend: api.Backend{GCS: &api.GCSSpec{Bucket: "stash"}},
+			host:    gcsSrv.Listener.Addr().String(),
+		},
+		{
+			name:    "swift",
+			backend: api.Backend{Swift: &api.SwiftSpec{Container: "stash"}},
+			host:    swiftSrv.Listener.Addr().String(),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.backend.StorageSecretName = "proxied-secret"
+			tc.backend.Proxy = &api.ProxyConfig{URL: proxy.URL}
+			osmCtx, err := NewOSMContext(kc, tc.backend, "demo")
+			if !assert.Nil(t, err) {
+				return
+			}
+			loc, err := dial(osmCtx)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer loc.Close()
+			if tc.name == "azure" {
+				_, err = loc.CreateContainer("stash")
+				assert.Nil(t, err)
+			}
+			_, err = loc.Container("stash")
+			assert.Nil(t, err)
+			// the location connects through the proxy
+			assert.Contains(t, proxy.Hosts(), tc.host)
+		})
+	}
+}
+
+func TestS3EncryptionContext(t *testing.T) {
+	customerKey := []byte("0123456789abcdef0123456789abcdef")
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+			api.AWS_SSE_CUSTOMER_KEY:  customerKey,
+		},
+	}, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-key", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+		},
+	})
+
+	cases := []struct {
+		name       string
+		secret     string
+		encryption *api.S3Encryption
+		expected   stow.ConfigMap
+		err        string
+	}{
+		{
+			name:       "sse-s3",
+			secret:     "s3-secret",
+			encryption: &api.S3Encryption{Type: api.S3EncryptionSSES3},
+			expected:   stow.ConfigMap{ConfigS3ServerSideEncryption: "AES256"},
+		},
+		{
+			name:   "sse-kms",
+			secret: "s3-secret",
+			encryption: &api.S3Encryption{
+				Type:                 api.S3EncryptionSSEKMS,
+				KMSKeyID:             "alias/backup",
+				KMSEncryptionContext: map[string]string{"team": "db"},
+			},
+			expected: stow.ConfigMap{
+				ConfigS3ServerSideEncryption:    "aws:kms",
+				ConfigS3SSEKMSKeyID:             "alias/backup",
+				ConfigS3SSEKMSEncryptionContext: "eyJ0ZWFtIjoiZGIifQ==",
+			},
+		},
+		{
+			name:       "sse-c",
+			secret:     "s3-secret",
+			encryption: &api.S3Encryption{Type: api.S3EncryptionSSEC},
+			expected: stow.ConfigMap{
+				ConfigS3SSECustomerAlgorithm: "AES256",
+				ConfigS3SSECustomerKey:       "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+				ConfigS3SSECustomerKeyMD5:    "hRasmdxgYDKV3nvbahU1MA==",
+			},
+		},
+		{
+			name:       "sse-c without key",
+			secret:     "no-key",
+			encryption: &api.S3Encryption{Type: api.S3EncryptionSSEC},
+			err:        api.AWS_SSE_CUSTOMER_KEY,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			osmCtx, err := NewOSMContext(kc, api.Backend{
+				StorageSecretName: tc.secret,
+				S3: &api.S3Spec{
+					Endpoint:   "http://minio.example.com:9000",
+					Bucket:     "stash",
+					Encryption: tc.encryption,
+				},
+			}, "demo")
+			if tc.err != "" {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), tc.err)
+				}
+				return
+			}
+			if !assert.Nil(t, err) {
+				return
+			}
+			expected := stow.ConfigMap{
+				s3.ConfigAccessKeyID: "id",
+				s3.ConfigSecretKey:   "key",
+				s3.ConfigAuthType:    "accesskey",
+				s3.ConfigEndpoint:    "http://minio.example.com:9000",
+				s3.ConfigRegion:      api.DefaultS3Region,
+				s3.ConfigDisableSSL:  "true",
+			}
+			for k, v := range tc.expected {
+				expected[k] = v
+			}
+			assert.Equal(t, expected, osmCtx.Config)
+		})
+	}
+}
+
+func TestS3EncryptionDial(t *testing.T) {
+	srv := s3test.NewTLSServer()
+	defer srv.Close()
+	srv.CreateBucket("stash")
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+			api.CA_CERT_DATA:          srv.CACert(),
+			api.AWS_SSE_CUSTOMER_KEY:  []byte("0123456789abcdef0123456789abcdef"),
+		},
+	})
+
+	cases := []struct {
+		name       string
+		encryption *api.S3Encryption
+		// headers stored with the object
+		stored map[string]string
+	}{
+		{
+			name:       "sse-kms",
+			encryption: &api.S3Encryption{Type: api.S3EncryptionSSEKMS, KMSKeyID: "alias/backup"},
+			stored: map[string]string{
+				"X-Amz-Server-Side-Encryption":                "aws:kms",
+				"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id": "alias/backup",
+			},
+		},
+		{
+			name:       "sse-c",
+			encryption: &api.S3Encryption{Type: api.S3EncryptionSSEC},
+			stored: map[string]string{
+				"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256",
+				"X-Amz-Server-Side-Encryption-Customer-Key-Md5":   "hRasmdxgYDKV3nvbahU1MA==",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			osmCtx, err := NewOSMContext(kc, api.Backend{
+				StorageSecretName: "s3-secret",
+				S3: &api.S3Spec{
+					Endpoint:   srv.URL,
+					Bucket:     "stash",
+					Encryption: tc.encryption,
+				},
+			}, "demo")
+			if !assert.Nil(t, err) {
+				return
+			}
+			loc, err := dial(osmCtx)
+			if !assert.Nil(t, err) {
+				return
+			}
+			c, err := loc.Container("stash")
+			if !assert.Nil(t, err) {
+				return
+			}
+			name := tc.name + "/file.txt"
+			_, err = c.Put(name, strings.NewReader("data"), 4, nil)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if obj, ok := srv.Object("stash", name); assert.True(t, ok) {
+				for k, v := range tc.stored {
+					assert.Equal(t, v, obj.Header.Get(k), k)
+				}
+			}
+
+			// the SSE-C key is sent with the reads
+			item, err := c.Item(name)
+			if !assert.Nil(t, err) {
+				return
+			}
+			rc, err := item.Open()
+			if assert.Nil(t, err) {
+				got, err := io.ReadAll(rc)
+				assert.Nil(t, err)
+				assert.Equal(t, "data", string(got))
+				assert.Nil(t, rc.Close())
+			}
+		})
+	}
+}
+
+func TestS3AddressingContext(t *testing.T) {
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+		},
+	})
+
+	cases := []struct {
+		name     string
+		spec     api.S3Spec
+		expected stow.ConfigMap
+	}{
+		{
+			name: "aws with region",
+			spec: api.S3Spec{Region: "eu-west-1"},
+			expected: stow.ConfigMap{
+				s3.ConfigRegion: "eu-west-1",
+			},
+		},
+		{
+			name: "aws endpoint with region",
+			spec: api.S3Spec{Endpoint: "https://s3.eu-west-1.amazonaws.com", Region: "eu-west-1", DualStack: true, FIPS: true},
+			expected: stow.ConfigMap{
+				s3.ConfigRegion:      "eu-west-1",
+				ConfigS3UseDualStack: "true",
+				ConfigS3UseFIPS:      "true",
+			},
+		},
+		{
+			name: "aws accelerate virtual-hosted",
+			spec: api.S3Spec{Region: "eu-west-1", Accelerate: true, AddressingStyle: api.S3AddressingStyleVirtual},
+			expected: stow.ConfigMap{
+				s3.ConfigRegion:        "eu-west-1",
+				ConfigS3UseAccelerate:  "true",
+				ConfigS3ForcePathStyle: "false",
+			},
+		},
+		{
+			name: "s3 compatible",
+			spec: api.S3Spec{Endpoint: "https://minio.example.com:9000", Region: "us-west-1", InsecureTLS: true},
+			expected: stow.ConfigMap{
+				s3.ConfigEndpoint:   "https://minio.example.com:9000",
+				s3.ConfigDisableSSL: "false",
+				s3.ConfigRegion:     "us-west-1",
+				ConfigS3InsecureTLS: "true",
+			},
+		},
+		{
+			name: "s3 compatible virtual-hosted",
+			spec: api.S3Spec{Endpoint: "http://minio.example.com:9000", AddressingStyle: api.S3AddressingStyleVirtual},
+			expected: stow.ConfigMap{
+				s3.ConfigEndpoint:      "http://minio.example.com:9000",
+				s3.ConfigRegion:        api.DefaultS3Region,
+				s3.ConfigDisableSSL:    "true",
+				ConfigS3ForcePathStyle: "false",
+			},
+		},
+		{
+			name: "v2 signatures",
+			spec: api.S3Spec{Endpoint: "http://ceph.example.com", SignatureVersion: api.S3SignatureV2},
+			expected: stow.ConfigMap{
+				s3.ConfigEndpoint:      "http://ceph.example.com",
+				s3.ConfigRegion:        api.DefaultS3Region,
+				s3.ConfigDisableSSL:    "true",
+				s3.ConfigV2Signing:     "true",
+				ConfigS3ForcePathStyle: "true",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := tc.spec
+			spec.Bucket = "stash"
+			osmCtx, err := NewOSMContext(kc, api.Backend{
+				StorageSecretName: "s3-secret",
+				S3:                &spec,
+			}, "demo")
+			if !assert.Nil(t, err) {
+				return
+			}
+			expected := stow.ConfigMap{
+				s3.ConfigAccessKeyID: "id",
+				s3.ConfigSecretKey:   "key",
+				s3.ConfigAuthType:    "accesskey",
+			}
+			for k, v := range tc.expected {
+				expected[k] = v
+			}
+			assert.Equal(t, expected, osmCtx.Config)
+		})
+	}
+}
+
+func TestS3AddressingDial(t *testing.T) {
+	proxy := proxytest.NewServer()
+	defer proxy.Close()
+	srv := s3test.NewServer()
+	defer srv.Close()
+	srv.CreateBucket("stash")
+	srvHost := strings.TrimPrefix(srv.URL, "http://")
+
+	cases := []struct {
+		name   string
+		config stow.ConfigMap
+		// host the bucket is requested from
+		host string
+	}{
+		{
+			name:   "s3 compatible",
+			config: stow.ConfigMap{s3.ConfigEndpoint: srv.URL},
+			host:   srvHost,
+		},
+		{
+			name: "s3 compatible virtual-hosted",
+			config: stow.ConfigMap{
+				s3.ConfigEndpoint:      srv.URL,
+				ConfigS3ForcePathStyle: "false",
+			},
+			host: "stash." + srvHost,
+		},
+		// the region of aws buckets is looked up with path-style urls
+		{
+			name:   "aws dualstack",
+			config: stow.ConfigMap{ConfigS3UseDualStack: "true"},
+			host:   "s3.dualstack.eu-west-1.amazonaws.com",
+		},
+		{
+			name:   "aws fips",
+			config: stow.ConfigMap{ConfigS3UseFIPS: "true"},
+			host:   "s3-fips.eu-west-1.amazonaws.com",
+		},
+		{
+			name:   "aws accelerate",
+			config: stow.ConfigMap{ConfigS3UseAccelerate: "true"},
+			host:   "stash.s3-accelerate.amazonaws.com",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := stow.ConfigMap{
+				s3.ConfigAccessKeyID: "id",
+				s3.ConfigSecretKey:   "key",
+				s3.ConfigRegion:      "eu-west-1",
+				// the proxy only records the hosts of plain http requests
+				s3.ConfigDisableSSL:     "true",
+				httpproxy.ConfigURL:     proxy.URL,
+				retry.ConfigMaxAttempts: "1",
+			}
+			for k, v := range tc.config {
+				config[k] = v
+			}
+			loc, err := dial(&Context{Provider: s3.Kind, Config: config})
+			if !assert.Nil(t, err) {
+				return
+			}
+			container, err := loc.Container("stash")
+			if err == nil {
+				_, err = container.Put("a.txt", strings.NewReader("data"), 4, nil)
+			}
+			if tc.host == srvHost {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+			}
+			assert.Contains(t, proxy.Hosts(), tc.host)
+		})
+	}
+
+	t.Run("insecure tls", func(t *testing.T) {
+		srv := s3test.NewTLSServer()
+		defer srv.Close()
+		srv.CreateBucket("stash")
+		config := stow.ConfigMap{
+			s3.ConfigAccessKeyID: "id",
+			s3.ConfigSecretKey:   "key",
+			s3.ConfigEndpoint:    srv.URL,
+		}
+		loc, err := dial(&Context{Provider: s3.Kind, Config: config})
+		if assert.Nil(t, err) {
+			_, err = loc.Container("stash")
+			assert.ErrorContains(t, err, "certificate")
+		}
+
+		config[ConfigS3InsecureTLS] = "true"
+		loc, err = dial(&Context{Provider: s3.Kind, Config: config})
+		if assert.Nil(t, err) {
+			_, err = loc.Container("stash")
+			assert.Nil(t, err)
+		}
+	})
+}
+
+func TestGCSContext(t *testing.T) {
+	serviceAccount := `{"type":"service_account","project_id":"demo"}`
+	externalAccount := `{"type":"external_account","audience":"//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/k8s"}`
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "service-account", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.GOOGLE_PROJECT_ID:               []byte("demo"),
+			api.GOOGLE_SERVICE_ACCOUNT_JSON_KEY: []byte(serviceAccount),
+		},
+	}, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "external-account", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.GOOGLE_SERVICE_ACCOUNT_JSON_KEY: []byte(externalAccount),
+		},
+	})
+
+	cases := []struct {
+		name     string
+		secret   string
+		spec     api.GCSSpec
+		expected stow.ConfigMap
+		// files expected in the osm secret, besides the config
+		files map[string]string
+	}{
+		{
+			name: "application default credentials",
+			expected: stow.ConfigMap{
+				gcs.ConfigProjectId: "",
+			},
+		},
+		{
+			name:   "service account key",
+			secret: "service-account",
+			spec:   api.GCSSpec{Scopes: []string{"https://www.googleapis.com/auth/devstorage.read_only"}},
+			expected: stow.ConfigMap{
+				gcs.ConfigProjectId: "demo",
+				gcs.ConfigJSON:      serviceAccount,
+				gcs.ConfigScopes:    "https://www.googleapis.com/auth/devstorage.read_only",
+			},
+		},
+		{
+			name:   "external account with impersonation",
+			secret: "external-account",
+			spec:   api.GCSSpec{ImpersonateServiceAccount: "backup@demo.iam.gserviceaccount.com"},
+			expected: stow.ConfigMap{
+				gcs.ConfigProjectId:                "",
+				ConfigGCSCredentialsFile:           "/etc/osm/" + GoogleCredentialsFileName,
+				ConfigGCSImpersonateServiceAccount: "backup@demo.iam.gserviceaccount.com",
+			},
+			files: map[string]string{
+				GoogleCredentialsFileName: externalAccount,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := tc.spec
+			spec.Bucket = "stash"
+			out, err := NewOSMSecret(kc, "osm", "demo", api.Backend{
+				StorageSecretName: tc.secret,
+				GCS:               &spec,
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			var cfg OSMConfig
+			if !assert.Nil(t, yaml.Unmarshal(out.Data["config"], &cfg)) {
+				return
+			}
+			assert.Equal(t, gcs.Kind, cfg.Contexts[0].Provider)
+			assert.Equal(t, tc.expected, cfg.Contexts[0].Config)
+
+			delete(out.Data, "config")
+			files := map[string]string{}
+			for k, v := range out.Data {
+				files[k] = string(v)
+			}
+			if tc.files == nil {
+				tc.files = map[string]string{}
+			}
+			assert.Equal(t, tc.files, files)
+		})
+	}
+}
+
+func TestGCSDial(t *testing.T) {
+	srv := gcstest.NewServer()
+	defer srv.Close()
+	t.Setenv("STORAGE_EMULATOR_HOST", srv.URL)
+	srv.CreateBucket("stash")
+
+	serviceAccount, err := srv.ServiceAccountKey()
+	if !assert.Nil(t, err) {
+		return
+	}
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if !assert.Nil(t, os.WriteFile(tokenFile, []byte("oidc-token"), 0o600)) {
+		return
+	}
+	externalAccount, _ := json.Marshal(map[string]any{
+		"type":               "external_account",
+		"audience":           "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/k8s",
+		"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+		"token_url":          srv.URL + gcstest.TokenPath,
+		"credential_source":  map[string]any{"file": tokenFile},
+	})
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "service-account", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.GOOGLE_PROJECT_ID:               []byte("demo"),
+			api.GOOGLE_SERVICE_ACCOUNT_JSON_KEY: serviceAccount,
+		},
+	}, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "external-account", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.GOOGLE_PROJECT_ID:               []byte("demo"),
+			api.GOOGLE_SERVICE_ACCOUNT_JSON_KEY: externalAccount,
+		},
+	})
+
+	for _, secret := range []string{"service-account", "external-account"} {
+		t.Run(secret, func(t *testing.T) {
+			osmCtx, err := NewOSMContext(kc, api.Backend{
+				StorageSecretName: secret,
+				GCS:               &api.GCSSpec{Bucket: "stash"},
+			}, "demo")
+			if !assert.Nil(t, err) {
+				return
+			}
+			loc, err := dial(osmCtx)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer loc.Close()
+
+			c, err := loc.Container("stash")
+			if !assert.Nil(t, err) {
+				return
+			}
+			name := secret + "/file.txt"
+			put, err := c.Put(name, strings.NewReader("data"), 4, map[string]any{"owner": "stash"})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if obj, ok := srv.Object("stash", name); assert.True(t, ok) {
+				assert.Equal(t, "data", string(obj.Data))
+				assert.Equal(t, map[string]string{"owner": "stash"}, obj.Metadata)
+			}
+
+			page, err := c.Browse("", "/", stow.CursorStart, 10)
+			if assert.Nil(t, err) {
+				assert.Contains(t, page.Prefixes, secret+"/")
+				assert.Empty(t, page.Items)
+			}
+			item, err := loc.ItemByURL(put.URL())
+			if !assert.Nil(t, err) {
+				return
+			}
+			md, err := item.Metadata()
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]any{"owner": "stash"}, md)
+			rc, err := item.(stow.ItemRanger).OpenRange(1, 2)
+			if assert.Nil(t, err) {
+				got, err := io.ReadAll(rc)
+				assert.Nil(t, err)
+				assert.Equal(t, "at", string(got))
+				assert.Nil(t, rc.Close())
+			}
+
+			assert.Nil(t, c.HasWriteAccess())
+			assert.Nil(t, c.RemoveItem(name))
+			_, err = c.Item(name)
+			assert.Equal(t, stow.ErrNotFound, err)
+		})
+	}
+	for _, r := range srv.Requests() {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "Bearer token-"), r.Path)
+	}
+}
+
+func TestAzureContext(t *testing.T) {
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "account-key", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AZURE_ACCOUNT_NAME: []byte("backups"),
+			api.AZURE_ACCOUNT_KEY:  []byte("a2V5"),
+		},
+	}, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sas-token", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AZURE_ACCOUNT_SAS: []byte("?sv=2022-11-02&sig=abc"),
+		},
+	})
+
+	cases := []struct {
+		name     string
+		secret   string
+		spec     api.AzureSpec
+		expected stow.ConfigMap
+	}{
+		{
+			name:   "account key",
+			secret: "account-key",
+			expected: stow.ConfigMap{
+				azure.ConfigAccount: "backups",
+				azure.ConfigKey:     "a2V5",
+			},
+		},
+		{
+			name:   "sas token with sovereign cloud endpoint",
+			secret: "sas-token",
+			spec: api.AzureSpec{
+				StorageAccount: "backups",
+				Endpoint:       "https://backups.blob.core.chinacloudapi.cn/",
+			},
+			expected: stow.ConfigMap{
+				azure.ConfigAccount: "backups",
+				ConfigAzureEndpoint: "https://backups.blob.core.chinacloudapi.cn",
+				ConfigAzureSASToken: "sv=2022-11-02&sig=abc",
+			},
+		},
+		{
+			name: "managed identity",
+			spec: api.AzureSpec{
+				StorageAccount: "backups",
+				ClientID:       "00000000-0000-0000-0000-000000000001",
+			},
+			expected: stow.ConfigMap{
+				azure.ConfigAccount: "backups",
+				ConfigAzureClientID: "00000000-0000-0000-0000-000000000001",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := tc.spec
+			spec.Container = "stash"
+			out, err := NewOSMContext(kc, api.Backend{
+				StorageSecretName: tc.secret,
+				Azure:             &spec,
+			}, "demo")
+			if assert.Nil(t, err) {
+				assert.Equal(t, azure.Kind, out.Provider)
+				assert.Equal(t, tc.expected, out.Config)
+			}
+		})
+	}
+
+	_, err := NewOSMContext(kc, api.Backend{Azure: &api.AzureSpec{Container: "stash"}}, "demo")
+	assert.NotNil(t, err)
+}
+
+func TestAzureDial(t *testing.T) {
+	srv := azuretest.NewServer()
+	defer srv.Close()
+	t.Setenv("IDENTITY_ENDPOINT", srv.URL+azuretest.IdentityPath)
+	t.Setenv("IDENTITY_HEADER", azuretest.IdentityHeader)
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+	srv.ClientID = "backup-identity"
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "account-key", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AZURE_ACCOUNT_NAME: []byte(azuretest.AccountName),
+			api.AZURE_ACCOUNT_KEY:  []byte(azuretest.AccountKey),
+		},
+	}, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sas-token", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AZURE_ACCOUNT_NAME: []byte(azuretest.AccountName),
+			api.AZURE_ACCOUNT_SAS:  []byte(srv.SASToken),
+		},
+	})
+
+	cases := []struct {
+		name     string
+		secret   string
+		clientID string
+	}{
+		{name: "account key", secret: "account-key"},
+		{name: "sas token", secret: "sas-token"},
+		{name: "managed identity", clientID: "backup-identity"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			container := strings.ReplaceAll(tc.name, " ", "-")
+			osmCtx, err := NewOSMContext(kc, api.Backend{
+				StorageSecretName: tc.secret,
+				Azure: &api.AzureSpec{
+					StorageAccount: azuretest.AccountName,
+					Container:      container,
+					Endpoint:       srv.Endpoint(),
+					ClientID:       tc.clientID,
+				},
+			}, "demo")
+			if !assert.Nil(t, err) {
+				return
+			}
+			loc, err := dial(osmCtx)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer loc.Close()
+
+			_, err = loc.Container(container)
+			assert.Equal(t, stow.ErrNotFound, err)
+			c, err := loc.CreateContainer(container)
+			if !assert.Nil(t, err) {
+				return
+			}
+			put, err := c.Put("dir/file.txt", strings.NewReader("data"), 4, map[string]any{"owner": "stash"})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if b, ok := srv.Blob(container, "dir/file.txt"); assert.True(t, ok) {
+				assert.Equal(t, "data", string(b.Data))
+				assert.Equal(t, map[string]string{"owner": "stash"}, b.Metadata)
+			}
+
+			page, err := c.Browse("", "/", stow.CursorStart, 10)
+			if assert.Nil(t, err) {
+				assert.Equal(t, []string{"dir/"}, page.Prefixes)
+				assert.Empty(t, page.Items)
+			}
+			items, _, err := c.Items("dir/", stow.CursorStart, 10)
+			if assert.Nil(t, err) && assert.Len(t, items, 1) {
+				md, err := items[0].Metadata()
+				assert.Nil(t, err)
+				assert.Equal(t, map[string]any{"owner": "stash"}, md)
+			}
+
+			item, err := loc.ItemByURL(put.URL())
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, "dir/file.txt", item.ID())
+			size, _ := item.Size()
+			assert.Equal(t, int64(4), size)
+			rc, err := item.(stow.ItemRanger).OpenRange(1, 2)
+			if assert.Nil(t, err) {
+				got, err := io.ReadAll(rc)
+				assert.Nil(t, err)
+				assert.Equal(t, "at", string(got))
+				assert.Nil(t, rc.Close())
+			}
+
+			assert.Nil(t, c.HasWriteAccess())
+			assert.Nil(t, c.RemoveItem("dir/file.txt"))
+			assert.Equal(t, []string{}, srv.Names(container))
+			assert.Nil(t, loc.RemoveContainer(container))
+		})
+	}
+}
+
+func TestSwiftDial(t *testing.T) {
+	srv := swifttest.NewServer("swift-user", "swift-password")
+	defer srv.Close()
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "swift-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			osconst.ST_AUTH: []byte(srv.AuthURL(1)),
+			osconst.ST_USER: []byte("swift-user"),
+			osconst.ST_KEY:  []byte("swift-password"),
+		},
+	})
+	osmCtx, err := NewOSMContext(kc, api.Backend{
+		StorageSecretName: "swift-secret",
+		Swift:             &api.SwiftSpec{Container: "stash"},
+	}, "demo")
+	if !assert.Nil(t, err) {
+		return
+	}
+	loc, err := dial(osmCtx)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer loc.Close()
+
+	_, err = loc.Container("stash")
+	assert.Equal(t, stow.ErrNotFound, err)
+	c, err := loc.CreateContainer("stash")
+	if !assert.Nil(t, err) {
+		return
+	}
+	containers, _, err := loc.Containers("st", stow.CursorStart, 10)
+	if assert.Nil(t, err) && assert.Len(t, containers, 1) {
+		assert.Equal(t, "stash", containers[0].ID())
+	}
+	put, err := c.Put("dir/file.txt", strings.NewReader("data"), 4, map[string]any{"owner": "stash"})
+	if !assert.Nil(t, err) {
+		return
+	}
+	data, ok := srv.Object("stash", "dir/file.txt")
+	if assert.True(t, ok) {
+		assert.Equal(t, "data", string(data))
+	}
+
+	page, err := c.Browse("", "/", stow.CursorStart, 10)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"dir/"}, page.Prefixes)
+		assert.Empty(t, page.Items)
+	}
+	items, _, err := c.Items("dir/", stow.CursorStart, 10)
+	if assert.Nil(t, err) && assert.Len(t, items, 1) {
+		md, err := items[0].Metadata()
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]any{"owner": "stash"}, md)
+	}
+
+	item, err := loc.ItemByURL(put.URL())
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "dir/file.txt", item.ID())
+	rc, err := item.Open()
+	if assert.Nil(t, err) {
+		got, err := io.ReadAll(rc)
+		assert.Nil(t, err)
+		assert.Equal(t, "data", string(got))
+		assert.Nil(t, rc.Close())
+	}
+
+	assert.Nil(t, c.HasWriteAccess())
+	assert.Equal(t, stow.ErrNotFound, c.RemoveItem("missing"))
+	assert.Nil(t, c.RemoveItem("dir/file.txt"))
+	assert.Equal(t, []string{}, srv.Keys("stash"))
+	assert.Nil(t, loc.RemoveContainer("stash"))
+}
+
+func TestSecretKeyMapping(t *testing.T) {
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bucket-creds", Namespace: "demo"},
+		Data: map[string][]byte{
+			"accountName": []byte("backups"),
+			"accountKey":  []byte("a2V5"),
+		},
+	})
+	backend := api.Backend{
+		Azure: &api.AzureSpec{Container: "stash"},
+		StorageSecret: &api.StorageSecretReference{
+			Name: "bucket-creds",
+			Keys: map[string]string{
+				api.AZURE_ACCOUNT_NAME: "accountName",
+				api.AZURE_ACCOUNT_KEY:  "accountKey",
+			},
+		},
+	}
+	out, err := NewOSMContext(kc, backend, "demo")
+	if assert.Nil(t, err) {
+		assert.Equal(t, stow.ConfigMap{
+			azure.ConfigAccount: "backups",
+			azure.ConfigKey:     "a2V5",
+		}, out.Config)
+	}
+
+	backend.StorageSecret.Keys[api.AZURE_ACCOUNT_KEY] = "key"
+	_, err = NewOSMContext(kc, backend, "demo")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `storage secret demo/bucket-creds is missing key "key" for AZURE_ACCOUNT_KEY`, err.Error())
+	}
+}
+
+func TestCrossNamespaceCredentials(t *testing.T) {
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bucket-creds", Namespace: "platform"},
+		Data: map[string][]byte{
+			"accessKey": []byte("platform-id"),
+			"secretKey": []byte("platform-key"),
+		},
+	}, &core.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: "platform"},
+		Data:       map[string]string{"ca.crt": "platform-ca"},
+	})
+	backend := api.Backend{
+		S3: &api.S3Spec{Endpoint: "https://minio.example.com", Bucket: "stash"},
+		StorageSecret: &api.StorageSecretReference{
+			Name:      "bucket-creds",
+			Namespace: "platform",
+			Keys: map[string]string{
+				api.AWS_ACCESS_KEY_ID:     "accessKey",
+				api.AWS_SECRET_ACCESS_KEY: "secretKey",
+			},
+			Sources: []api.CredentialKeySource{{
+				Key: api.CA_CERT_DATA,
+				ConfigMapKeyRef: &core.ConfigMapKeySelector{
+					LocalObjectReference: core.LocalObjectReference{Name: "ca-bundle"},
+					Key:                  "ca.crt",
+				},
+			}},
+		},
+	}
+
+	_, err := NewOSMSecret(kc, "osm", "tenant", backend)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Secret platform/bucket-creds can not be read from namespace tenant", err.Error())
+	}
+
+	out, err := NewOSMSecret(kc, "osm", "tenant", backend, credsource.WithNamespacePolicy(credsource.AllowNamespaces("platform")))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "platform-ca", string(out.Data[CaCertFileName]))
+	var cfg OSMConfig
+	if assert.Nil(t, yaml.Unmarshal(out.Data["config"], &cfg)) {
+		assert.Equal(t, stow.ConfigMap{
+			s3.ConfigAccessKeyID: "platform-id",
+			s3.ConfigSecretKey:   "platform-key",
+			s3.ConfigAuthType:    "accesskey",
+			s3.ConfigEndpoint:    "https://minio.example.com",
+			s3.ConfigRegion:      api.DefaultS3Region,
+			s3.ConfigDisableSSL:  "false",
+			s3.ConfigCACertFile:  "/etc/osm/" + CaCertFileName,
+		}, cfg.Contexts[0].Config)
+	}
+}
+
+func TestS3RoleContext(t *testing.T) {
+	kc := newKubeClient(t)
+	osmCtx, err := NewOSMContext(kc, api.Backend{
+		S3: &api.S3Spec{
+			Endpoint:             "https://minio.example.com",
+			Bucket:               "stash",
+			RoleARN:              "arn:minio:iam:::role/backup",
+			RoleSessionName:      "stash",
+			WebIdentityTokenFile: "/var/run/secrets/tokens/sts",
+			STSEndpoint:          "https://minio.example.com",
+		},
+	}, "demo")
+	if assert.Nil(t, err) {
+		assert.Equal(t, stow.ConfigMap{
+			s3.ConfigAuthType:            "iam",
+			s3.ConfigEndpoint:            "https://minio.example.com",
+			s3.ConfigRegion:              api.DefaultS3Region,
+			s3.ConfigDisableSSL:          "false",
+			ConfigS3RoleARN:              "arn:minio:iam:::role/backup",
+			ConfigS3RoleSessionName:      "stash",
+			ConfigS3WebIdentityTokenFile: "/var/run/secrets/tokens/sts",
+			ConfigS3STSEndpoint:          "https://minio.example.com",
+		}, osmCtx.Config)
+	}
+}
+
+func TestS3RoleDial(t *testing.T) {
+	srv := s3test.NewServer()
+	defer srv.Close()
+	srv.CreateBucket("stash")
+	sts := s3test.NewSTSServer()
+	defer sts.Close()
+	sts.ExternalID = "tenant-a"
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:     []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+		},
+	})
+
+	osmCtx, err := NewOSMContext(kc, api.Backend{
+		StorageSecretName: "s3-secret",
+		S3: &api.S3Spec{
+			Endpoint:        srv.URL,
+			Bucket:          "stash",
+			RoleARN:         "arn:aws:iam::123456789012:role/backup",
+			ExternalID:      "tenant-a",
+			RoleSessionName: "stash",
+			STSEndpoint:     sts.URL,
+		},
+	}, "demo")
+	if !assert.Nil(t, err) {
+		return
+	}
+	loc, err := dial(osmCtx)
+	if !assert.Nil(t, err) {
+		return
+	}
+	c, err := loc.Container("stash")
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = c.Put("file.txt", strings.NewReader("data"), 4, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	calls := sts.Requests()
+	if assert.NotEmpty(t, calls) {
+		assert.Equal(t, "AssumeRole", calls[0].Action)
+		assert.Equal(t, "arn:aws:iam::123456789012:role/backup", calls[0].Form.Get("RoleArn"))
+		assert.Equal(t, "stash", calls[0].Form.Get("RoleSessionName"))
+		// signed with the access keys of the storage secret
+		assert.Contains(t, calls[0].Header.Get("Authorization"), "Credential=id/")
+	}
+	issued := sts.Issued()
+	if assert.NotEmpty(t, issued) {
+		for _, req := range srv.Requests() {
+			assert.Contains(t, req.Header.Get("Authorization"), "Credential="+issued[0].AccessKeyID+"/")
+			assert.Equal(t, issued[0].SessionToken, req.Header.Get("X-Amz-Security-Token"))
+		}
+	}
+}
+
+func TestS3TemporaryCredentialsContext(t *testing.T) {
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second).Format(time.RFC3339)
+	credentialsFile := "[backup]\naws_access_key_id = id\naws_secret_access_key = key\n"
+	configFile := "[profile backup]\nregion = us-east-1\n"
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "session-token", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:         []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY:     []byte("key"),
+			api.AWS_SESSION_TOKEN:         []byte("token"),
+			api.AWS_CREDENTIAL_EXPIRATION: []byte(expires),
+		},
+	}, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_SHARED_CREDENTIALS: []byte(credentialsFile),
+			api.AWS_CONFIG:             []byte(configFile),
+		},
+	}, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "expired", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:         []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY:     []byte("key"),
+			api.AWS_SESSION_TOKEN:         []byte("token"),
+			api.AWS_CREDENTIAL_EXPIRATION: []byte("2020-01-01T00:00:00Z"),
+		},
+	})
+
+	cases := []struct {
+		name     string
+		secret   string
+		profile  string
+		expected stow.ConfigMap
+		// files expected in the osm secret, besides the config
+		files map[string]string
+	}{
+		{
+			name:   "session token",
+			secret: "session-token",
+			expected: stow.ConfigMap{
+				s3.ConfigAuthType:            "accesskey",
+				s3.ConfigAccessKeyID:         "id",
+				s3.ConfigSecretKey:           "key",
+				ConfigS3Token:                "token",
+				ConfigS3CredentialExpiration: expires,
+				s3.ConfigEndpoint:            "http://minio.example.com",
+				s3.ConfigRegion:              api.DefaultS3Region,
+				s3.ConfigDisableSSL:          "true",
+			},
+		},
+		{
+			name:    "profile",
+			secret:  "profile",
+			profile: "backup",
+			expected: stow.ConfigMap{
+				s3.ConfigAuthType:             "iam",
+				ConfigS3SharedCredentialsFile: "/etc/osm/" + AWSCredentialsFileName,
+				ConfigS3SharedConfigFile:      "/etc/osm/" + AWSConfigFileName,
+				ConfigS3Profile:               "backup",
+				s3.ConfigEndpoint:             "http://minio.example.com",
+				s3.ConfigRegion:               api.DefaultS3Region,
+				s3.ConfigDisableSSL:           "true",
+			},
+			files: map[string]string{
+				AWSCredentialsFileName: credentialsFile,
+				AWSConfigFileName:      configFile,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := NewOSMSecret(kc, "osm", "demo", api.Backend{
+				StorageSecretName: tc.secret,
+				S3: &api.S3Spec{
+					Endpoint: "http://minio.example.com",
+					Bucket:   "stash",
+					Profile:  tc.profile,
+				},
+			})
+			if !assert.Nil(t, err) {
+				return
+			}
+			var cfg OSMConfig
+			if !assert.Nil(t, yaml.Unmarshal(out.Data["config"], &cfg)) {
+				return
+			}
+			assert.Equal(t, tc.expected, cfg.Contexts[0].Config)
+
+			delete(out.Data, "config")
+			files := map[string]string{}
+			for k, v := range out.Data {
+				files[k] = string(v)
+			}
+			if tc.files == nil {
+				tc.files = map[string]string{}
+			}
+			assert.Equal(t, tc.files, files)
+		})
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := NewOSMContext(kc, api.Backend{
+			StorageSecretName: "expired",
+			S3: &api.S3Spec{
+				Endpoint: "http://minio.example.com",
+				Bucket:   "stash",
+			},
+		}, "demo")
+		assert.True(t, s3auth.IsExpiredCredentials(err))
+		assert.EqualError(t, err, "s3 credentials expired: AWS_CREDENTIAL_EXPIRATION is 2020-01-01T00:00:00Z")
+	})
+}
+
+func TestS3TemporaryCredentialsDial(t *testing.T) {
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "session-token", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_ACCESS_KEY_ID:         []byte("id"),
+			api.AWS_SECRET_ACCESS_KEY:     []byte("key"),
+			api.AWS_SESSION_TOKEN:         []byte("token"),
+			api.AWS_CREDENTIAL_EXPIRATION: []byte(time.Now().Add(time.Hour).UTC().Format(time.RFC3339)),
+		},
+	}, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.AWS_SHARED_CREDENTIALS: []byte("[backup]\naws_access_key_id = profile-id\naws_secret_access_key = profile-key\naws_session_token = profile-token\n"),
+		},
+	})
+
+	cases := []struct {
+		name    string
+		secret  string
+		profile string
+		// access key id and session token of the signed requests
+		accessKeyID string
+		token       string
+	}{
+		{
+			name:        "session token",
+			secret:      "session-token",
+			accessKeyID: "id",
+			token:       "token",
+		},
+		{
+			name:        "profile",
+			secret:      "profile",
+			profile:     "backup",
+			accessKeyID: "profile-id",
+			token:       "profile-token",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := s3test.NewServer()
+			defer srv.Close()
+			srv.CreateBucket("stash")
+			osmCtx, err := NewOSMContext(kc, api.Backend{
+				StorageSecretName: tc.secret,
+				S3: &api.S3Spec{
+					Endpoint: srv.URL,
+					Bucket:   "stash",
+					Profile:  tc.profile,
+				},
+			}, "demo")
+			if !assert.Nil(t, err) {
+				return
+			}
+			loc, err := dial(osmCtx)
+			if !assert.Nil(t, err) {
+				return
+			}
+			c, err := loc.Container("stash")
+			if !assert.Nil(t, err) {
+				return
+			}
+			_, err = c.Put("file.txt", strings.NewReader("data"), 4, nil)
+			if !assert.Nil(t, err) {
+				return
+			}
+			requests := srv.Requests()
+			assert.NotEmpty(t, requests)
+			for _, req := range requests {
+				assert.Contains(t, req.Header.Get("Authorization"), "Credential="+tc.accessKeyID+"/")
+				assert.Equal(t, tc.token, req.Header.Get("X-Amz-Security-Token"))
+			}
+		})
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := dial(&Context{Provider: s3.Kind, Config: stow.ConfigMap{
+			s3.ConfigAccessKeyID:         "id",
+			s3.ConfigSecretKey:           "key",
+			ConfigS3Token:                "token",
+			ConfigS3CredentialExpiration: "2020-01-01T00:00:00Z",
+		}})
+		assert.True(t, s3auth.IsExpiredCredentials(err))
+	})
+}
+
+func TestEnvCredentialSource(t *testing.T) {
+	t.Setenv("OSM_TEST_AWS_ACCESS_KEY_ID", "env-id")
+	t.Setenv("OSM_TEST_AWS_SECRET_ACCESS_KEY", "env-key")
+
+	// no kubernetes client is needed
+	osmCtx, err := NewOSMContext(nil, api.Backend{
+		S3: &api.S3Spec{
+			Endpoint: "http://minio.example.com",
+			Bucket:   "stash",
+		},
+	}, "demo", credsource.WithSource(credsource.NewEnvSource("OSM_TEST_")))
+	if assert.Nil(t, err) {
+		assert.Equal(t, stow.ConfigMap{
+			s3.ConfigAuthType:    "accesskey",
+			s3.ConfigAccessKeyID: "env-id",
+			s3.ConfigSecretKey:   "env-key",
+			s3.ConfigEndpoint:    "http://minio.example.com",
+			s3.ConfigRegion:      api.DefaultS3Region,
+			s3.ConfigDisableSSL:  "true",
+		}, osmCtx.Config)
+	}
+
+	_, err = NewOSMContext(nil, api.Backend{
+		StorageSecretName: "s3-secret",
+		S3:                &api.S3Spec{Bucket: "stash", Endpoint: "http://minio.example.com"},
+	}, "demo")
+	assert.EqualError(t, err, "Secret demo/s3-secret can not be read without a kubernetes credential source")
+}
+
+func TestRetryContext(t *testing.T) {
+	srv := resttest.NewServer()
+	defer srv.Close()
+	srv.CreateRepository("/demo")
+	target, err := url.Parse(srv.URL)
+	if !assert.Nil(t, err) {
+		return
+	}
+	faults := retrytest.NewServer(target)
+	defer faults.Close()
+
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rest-secret", Namespace: "demo"},
+	})
+	spec := api.Backend{
+		StorageSecretName: "rest-secret",
+		Rest:              &api.RestServerSpec{URL: faults.URL + "/demo"},
+		RetryPolicy: &api.RetryPolicy{
+			BaseBackoff:    &metav1.Duration{Duration: time.Millisecond},
+			MaxBackoff:     &metav1.Duration{Duration: 5 * time.Millisecond},
+			AttemptTimeout: &metav1.Duration{Duration: 200 * time.Millisecond},
+		},
+	}
+	osmCtx, err := NewOSMContext(kc, spec, "demo")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, stow.ConfigMap{
+		rest.ConfigURL:             faults.URL + "/demo",
+		retry.ConfigMaxAttempts:    "3",
+		retry.ConfigBaseBackoff:    "1ms",
+		retry.ConfigMaxBackoff:     "5ms",
+		retry.ConfigAttemptTimeout: "200ms",
+		retry.ConfigRetryOn:        "Throttling,ServerError,Timeout,Network",
+	}, osmCtx.Config)
+
+	faults.Inject(
+		retrytest.Fault{Status: http.StatusServiceUnavailable},
+		retrytest.Fault{Drop: true},
+	)
+	assert.Nil(t, CheckBucketAccess(kc, spec, "demo"))
+	assert.Zero(t, faults.Pending())
+
+	loc, err := dial(osmCtx)
+	if !assert.Nil(t, err) {
+		return
+	}
+	bucket, err := spec.Container()
+	if !assert.Nil(t, err) {
+		return
+	}
+	c, err := loc.Container(bucket)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// a seekable reader is rewound before the upload is retried
+	data := "retried data"
+	sum := sha256.Sum256([]byte(data))
+	name := "snapshots/" + hex.EncodeToString(sum[:])
+	faults.Inject(retrytest.Fault{Status: http.StatusInternalServerError})
+	_, err = c.Put(name, strings.NewReader(data), int64(len(data)), nil)
+	assert.Nil(t, err)
+	stored, ok := srv.Object("/demo", name)
+	assert.True(t, ok)
+	assert.Equal(t, data, string(stored))
+
+	// a hanging request is abandoned after the attempt timeout
+	faults.Inject(retrytest.Fault{Delay: 10 * time.Second})
+	item, err := c.Item(name)
+	if assert.Nil(t, err) {
+		rc, err := item.Open()
+		if assert.Nil(t, err) {
+			got, err := io.ReadAll(rc)
+			assert.Nil(t, err)
+			assert.Equal(t, data, string(got))
+			assert.Nil(t, rc.Close())
+		}
+	}
+
+	// a missing item is not retried
+	before := faults.Requests()
+	_, err = c.Item("snapshots/" + strings.Repeat("0", 64))
+	assert.True(t, errors.Is(err, stow.ErrNotFound))
+	assert.Equal(t, 1, faults.Requests()-before)
+}
+
+func TestRateLimitContext(t *testing.T) {
+	srv := resttest.NewServer()
+	defer srv.Close()
+	srv.CreateRepository("/demo")
+
+	kc := newKubeClient(t, &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rest-secret", Namespace: "demo"},
+	})
+	const (
+		size = 64 << 10
+		bps  = 128 << 10
+		rps  = 20
+	)
+	spec := api.Backend{
+		StorageSecretName: "rest-secret",
+		Rest:              &api.RestServerSpec{URL: srv.URL + "/demo"},
+		RateLimit: &api.RateLimit{
+			UploadBytesPerSecond:   bps,
+			DownloadBytesPerSecond: bps,
+			RequestsPerSecond:      rps,
+		},
+	}
+	osmCtx, err := NewOSMContext(kc, spec, "demo")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, stow.ConfigMap{
+		rest.ConfigURL:                         srv.URL + "/demo",
+		ratelimit.ConfigKey:                    "rest:" + srv.URL + "/demo#rateLimit.downloadBytesPerSecond=131072&rateLimit.requestsPerSecond=20&rateLimit.uploadBytesPerSecond=131072",
+		ratelimit.ConfigUploadBytesPerSecond:   "131072",
+		ratelimit.ConfigDownloadBytesPerSecond: "131072",
+		ratelimit.ConfigRequestsPerSecond:      "20",
+	}, osmCtx.Config)
+
+	loc, err := dial(osmCtx)
+	if !assert.Nil(t, err) {
+		return
+	}
+	bucket, err := spec.Container()
+	if !assert.Nil(t, err) {
+		return
+	}
+	c, err := loc.Container(bucket)
+	if !assert.Nil(t, err) {
+		return
+	}
+	// rateOf returns the rate of n units done since start, in units per second
+	rateOf := func(n int, start time.Time) float64 {
+		return float64(n) / time.Since(start).Seconds()
+	}
+
+	data := strings.Repeat("x", size)
+	sum := sha256.Sum256([]byte(data))
+	name := "snapshots/" + hex.EncodeToString(sum[:])
+	start := time.Now()
+	item, err := c.Put(name, strings.NewReader(data), size, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	// the burst of a tenth of a second is free
+	assert.Less(t, rateOf(size, start), 1.25*bps)
+
+	start = time.Now()
+	rc, err := item.Open()
+	if assert.Nil(t, err) {
+		got, err := io.ReadAll(rc)
+		assert.Nil(t, err)
+		assert.Equal(t, data, string(got))
+		assert.Nil(t, rc.Close())
+	}
+	assert.Less(t, rateOf(size, start), 1.25*bps)
+
+	// the locations of a backend share the limit
+	other, err := dial(osmCtx)
+	if !assert.Nil(t, err) {
+		return
+	}
+	const requests = 20
+	start = time.Now()
+	for i := 0; i < requests; i++ {
+		l := loc
+		if i%2 == 1 {
+			l = other
+		}
+		_, err := l.Container(bucket)
+		assert.Nil(t, err)
+	}
+	assert.Less(t, rateOf(requests, start), 1.1*rps)
+}
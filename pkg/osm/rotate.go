@@ -0,0 +1,114 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osm
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/credsource"
+
+	"gomodules.xyz/stow"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DialRotating dials the location of the backend like CheckBucketAccess,
+// and dials it again whenever the credentials of the backend change until
+// ctx is done. The calls made afterwards use the new location. The previous
+// location is closed, the containers and items that were returned before
+// keep its credentials. onRotate may be nil.
+func DialRotating(ctx context.Context, kc kubernetes.Interface, spec api.Backend, namespace string, onRotate credsource.RotationHook, opts ...credsource.Option) (stow.Location, error) {
+	osmCtx, err := NewOSMContext(kc, spec, namespace, opts...)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := dial(osmCtx)
+	if err != nil {
+		return nil, err
+	}
+	r := &rotatingLocation{loc: loc}
+	err = credsource.Watch(ctx, credsource.NewKubernetesGetter(kc), namespace, spec, func(data map[string][]byte, err error) {
+		if err == nil {
+			err = r.redial(spec, data)
+		}
+		if onRotate != nil {
+			onRotate(err)
+		}
+	}, opts...)
+	if err != nil {
+		_ = loc.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// rotatingLocation is a stow.Location whose credentials are swapped when
+// they are rotated.
+type rotatingLocation struct {
+	mu  sync.RWMutex
+	loc stow.Location
+}
+
+var _ stow.Location = &rotatingLocation{}
+
+func (r *rotatingLocation) redial(spec api.Backend, data map[string][]byte) error {
+	osmCtx, err := newOSMContext(spec, data)
+	if err != nil {
+		return err
+	}
+	loc, err := dial(osmCtx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	prev := r.loc
+	r.loc = loc
+	r.mu.Unlock()
+	return prev.Close()
+}
+
+func (r *rotatingLocation) location() stow.Location {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.loc
+}
+
+func (r *rotatingLocation) Close() error {
+	return r.location().Close()
+}
+
+func (r *rotatingLocation) CreateContainer(name string) (stow.Container, error) {
+	return r.location().CreateContainer(name)
+}
+
+func (r *rotatingLocation) Containers(prefix, cursor string, count int) ([]stow.Container, string, error) {
+	return r.location().Containers(prefix, cursor, count)
+}
+
+func (r *rotatingLocation) Container(id string) (stow.Container, error) {
+	return r.location().Container(id)
+}
+
+func (r *rotatingLocation) RemoveContainer(id string) error {
+	return r.location().RemoveContainer(id)
+}
+
+func (r *rotatingLocation) ItemByURL(u *url.URL) (stow.Item, error) {
+	return r.location().ItemByURL(u)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/rest/resttest"
+
+	"github.com/stretchr/testify/assert"
+	"gomodules.xyz/stow"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// newWatchKubeClient returns a clientset backed by a minimal api server that
+// serves a single secret and streams its updates to watches. update replaces
+// the secret.
+func newWatchKubeClient(t *testing.T, secret *core.Secret) (kc kubernetes.Interface, update func(*core.Secret)) {
+	t.Helper()
+	var mu sync.Mutex
+	current := secret.DeepCopy()
+	current.APIVersion, current.Kind = "v1", "Secret"
+	events := make(chan *core.Secret, 10)
+	namespace, name := secret.Namespace, secret.Name
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// /api/v1/namespaces/<ns>/secrets[/<name>]
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case r.URL.Query().Get("watch") == "true" && len(parts) == 5 && parts[3] == namespace:
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			enc := json.NewEncoder(w)
+			for {
+				select {
+				case <-r.Context().Done():
+					return
+				case s := <-events:
+					raw, _ := json.Marshal(s)
+					_ = enc.Encode(metav1.WatchEvent{Type: string(watch.Modified), Object: runtime.RawExtension{Raw: raw}})
+					w.(http.Flusher).Flush()
+				}
+			}
+		case len(parts) == 6 && parts[3] == namespace && parts[5] == name:
+			mu.Lock()
+			defer mu.Unlock()
+			_ = json.NewEncoder(w).Encode(current)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(&metav1.Status{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Status"},
+				Status:   metav1.StatusFailure,
+				Reason:   metav1.StatusReasonNotFound,
+				Code:     http.StatusNotFound,
+			})
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	kc, err := kubernetes.NewForConfig(&restclient.Config{Host: srv.URL})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return kc, func(s *core.Secret) {
+		s = s.DeepCopy()
+		s.APIVersion, s.Kind = "v1", "Secret"
+		mu.Lock()
+		current = s
+		mu.Unlock()
+		events <- s
+	}
+}
+
+func TestDialRotating(t *testing.T) {
+	srv := resttest.NewServer()
+	defer srv.Close()
+	srv.Username, srv.Password = "stash", "new-password"
+	srv.NoVerifyUpload = true
+	srv.CreateRepository("/demo")
+
+	secret := &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rest-secret", Namespace: "demo"},
+		Data: map[string][]byte{
+			api.REST_SERVER_USERNAME: []byte("stash"),
+			api.REST_SERVER_PASSWORD: []byte("old-password"),
+		},
+	}
+	kc, update := newWatchKubeClient(t, secret)
+	spec := api.Backend{
+		StorageSecretName: "rest-secret",
+		Rest:              &api.RestServerSpec{URL: srv.URL + "/demo"},
+	}
+	container, err := spec.Container()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rotated := make(chan error, 1)
+	loc, err := DialRotating(ctx, kc, spec, "demo", func(err error) { rotated <- err })
+	if !assert.Nil(t, err) {
+		return
+	}
+	c, err := loc.Container(container)
+	if assert.Nil(t, err) {
+		_, err = c.Put("snapshots/s1", strings.NewReader("data"), 4, nil)
+		assert.NotNil(t, err)
+	}
+
+	secret.Data[api.REST_SERVER_PASSWORD] = []byte("new-password")
+	update(secret)
+	select {
+	case err := <-rotated:
+		assert.Nil(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("credentials were not rotated")
+	}
+
+	c, err = loc.Container(container)
+	if assert.Nil(t, err) {
+		_, err = c.Put("snapshots/s1", strings.NewReader("data"), 4, nil)
+		assert.Nil(t, err)
+	}
+}
+
+// closeRecorder is a stow.Location that records whether it was closed.
+type closeRecorder struct {
+	stow.Location
+	closed bool
+}
+
+func (l *closeRecorder) Close() error {
+	l.closed = true
+	return nil
+}
+
+func TestRedialClosesPreviousLocation(t *testing.T) {
+	prev := &closeRecorder{}
+	r := &rotatingLocation{loc: prev}
+	spec := api.Backend{Rest: &api.RestServerSpec{URL: "http://127.0.0.1/demo"}}
+	err := r.redial(spec, map[string][]byte{
+		api.REST_SERVER_USERNAME: []byte("stash"),
+		api.REST_SERVER_PASSWORD: []byte("new-password"),
+	})
+	assert.Nil(t, err)
+	assert.True(t, prev.closed)
+	assert.NotSame(t, prev, r.location())
+}
@@ -0,0 +1,361 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osm
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+	"kmodules.xyz/objectstore-api/pkg/retry"
+
+	"gomodules.xyz/stow"
+)
+
+// dial dials the location of osmCtx, the s3 kind is the location of package
+// kmodules.xyz/objectstore-api/pkg/stow/s3. With the retry keys in its
+// config, every call of the location and of its containers and items is
+// retried. Stow calls take no context, so the
+// AttemptTimeout is only applied by the http clients of this module's
+// drivers, and the Timeout ends the retries but not a running call. With the
+// rate limit keys, every call counts as a request and the bytes of Put and of
+// the opened items are limited.
+func dial(osmCtx *Context) (stow.Location, error) {
+	p, err := retry.FromStowConfig(osmCtx.Config)
+	if err != nil {
+		return nil, err
+	}
+	limits, err := ratelimit.FromStowConfig(osmCtx.Config)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := stow.Dial(osmCtx.Provider, osmCtx.Config)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil && limits.IsZero() {
+		return loc, nil
+	}
+	limiter := limits.Limiter()
+	return &wrappedLocation{
+		loc:     loc,
+		w:       &wrapper{p: p, limiter: limiter},
+		release: sync.OnceFunc(limiter.Release),
+	}, nil
+}
+
+// wrapper applies the retry policy and the limits of a location to its
+// calls. Both may be nil.
+type wrapper struct {
+	p       *retry.Policy
+	limiter *ratelimit.Limiter
+}
+
+// do runs fn with the retry policy, every attempt waits for the limiter.
+func (w *wrapper) do(fn func() error) error {
+	ctx, cancel := w.p.WithTimeout(context.Background())
+	defer cancel()
+	return w.p.Do(ctx, func(context.Context) error {
+		if err := w.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		return fn()
+	})
+}
+
+// download limits the bytes read from an opened item.
+func (w *wrapper) download(rc io.ReadCloser) io.ReadCloser {
+	return w.limiter.Download(context.Background(), rc)
+}
+
+type wrappedLocation struct {
+	loc stow.Location
+	w   *wrapper
+	// release releases the limiter of w once
+	release func()
+}
+
+var _ stow.Location = &wrappedLocation{}
+
+// Close closes the location and releases the limiter of the wrapper.
+func (l *wrappedLocation) Close() error {
+	l.release()
+	return l.loc.Close()
+}
+
+func (l *wrappedLocation) CreateContainer(name string) (stow.Container, error) {
+	var c stow.Container
+	err := l.w.do(func() (err error) {
+		c, err = l.loc.CreateContainer(name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedContainer{c: c, w: l.w}, nil
+}
+
+func (l *wrappedLocation) Containers(prefix, cursor string, count int) ([]stow.Container, string, error) {
+	var cs []stow.Container
+	var next string
+	err := l.w.do(func() (err error) {
+		cs, next, err = l.loc.Containers(prefix, cursor, count)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range cs {
+		cs[i] = &wrappedContainer{c: cs[i], w: l.w}
+	}
+	return cs, next, nil
+}
+
+func (l *wrappedLocation) Container(id string) (stow.Container, error) {
+	var c stow.Container
+	err := l.w.do(func() (err error) {
+		c, err = l.loc.Container(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedContainer{c: c, w: l.w}, nil
+}
+
+func (l *wrappedLocation) RemoveContainer(id string) error {
+	return l.w.do(func() error {
+		return l.loc.RemoveContainer(id)
+	})
+}
+
+func (l *wrappedLocation) ItemByURL(u *url.URL) (stow.Item, error) {
+	var item stow.Item
+	err := l.w.do(func() (err error) {
+		item, err = l.loc.ItemByURL(u)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedItem{item: item, w: l.w}, nil
+}
+
+type wrappedContainer struct {
+	c stow.Container
+	w *wrapper
+}
+
+var _ stow.Container = &wrappedContainer{}
+
+func (c *wrappedContainer) ID() string {
+	return c.c.ID()
+}
+
+func (c *wrappedContainer) Name() string {
+	return c.c.Name()
+}
+
+func (c *wrappedContainer) Item(id string) (stow.Item, error) {
+	var item stow.Item
+	err := c.w.do(func() (err error) {
+		item, err = c.c.Item(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedItem{item: item, w: c.w}, nil
+}
+
+func (c *wrappedContainer) Browse(prefix, delimiter, cursor string, count int) (*stow.ItemPage, error) {
+	var page *stow.ItemPage
+	err := c.w.do(func() (err error) {
+		page, err = c.c.Browse(prefix, delimiter, cursor, count)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.wrap(page.Items)
+	return page, nil
+}
+
+func (c *wrappedContainer) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	var items []stow.Item
+	var next string
+	err := c.w.do(func() (err error) {
+		items, next, err = c.c.Items(prefix, cursor, count)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	c.wrap(items)
+	return items, next, nil
+}
+
+func (c *wrappedContainer) wrap(items []stow.Item) {
+	for i := range items {
+		items[i] = &wrappedItem{item: items[i], w: c.w}
+	}
+}
+
+func (c *wrappedContainer) RemoveItem(id string) error {
+	return c.w.do(func() error {
+		return c.c.RemoveItem(id)
+	})
+}
+
+// Put is retried when r is an io.Seeker, which is rewound before every
+// retry. Any other reader is consumed by the first attempt.
+func (c *wrappedContainer) Put(name string, r io.Reader, size int64, metadata map[string]any) (stow.Item, error) {
+	var item stow.Item
+	put := func() (err error) {
+		item, err = c.c.Put(name, c.w.limiter.Upload(context.Background(), r), size, metadata)
+		return err
+	}
+	w := &wrapper{limiter: c.w.limiter}
+	if seeker, ok := r.(io.Seeker); ok {
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			w = c.w
+			attempt := 0
+			first := put
+			put = func() error {
+				if attempt++; attempt > 1 {
+					if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+						return err
+					}
+				}
+				return first()
+			}
+		}
+	}
+	if err := w.do(put); err != nil {
+		return nil, err
+	}
+	return &wrappedItem{item: item, w: c.w}, nil
+}
+
+func (c *wrappedContainer) HasWriteAccess() error {
+	return c.w.do(c.c.HasWriteAccess)
+}
+
+// wrappedItem wraps the calls of an item. OpenRange and Tags return a
+// stow.NotSupported error when the item does not support them.
+type wrappedItem struct {
+	item stow.Item
+	w    *wrapper
+}
+
+var (
+	_ stow.Item       = &wrappedItem{}
+	_ stow.ItemRanger = &wrappedItem{}
+	_ stow.Taggable   = &wrappedItem{}
+)
+
+func (i *wrappedItem) ID() string {
+	return i.item.ID()
+}
+
+func (i *wrappedItem) Name() string {
+	return i.item.Name()
+}
+
+func (i *wrappedItem) URL() *url.URL {
+	return i.item.URL()
+}
+
+func (i *wrappedItem) Size() (int64, error) {
+	var size int64
+	err := i.w.do(func() (err error) {
+		size, err = i.item.Size()
+		return err
+	})
+	return size, err
+}
+
+func (i *wrappedItem) Open() (io.ReadCloser, error) {
+	var rc io.ReadCloser
+	err := i.w.do(func() (err error) {
+		rc, err = i.item.Open()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return i.w.download(rc), nil
+}
+
+func (i *wrappedItem) OpenRange(start, end uint64) (io.ReadCloser, error) {
+	ranger, ok := i.item.(stow.ItemRanger)
+	if !ok {
+		return nil, stow.NotSupported("OpenRange")
+	}
+	var rc io.ReadCloser
+	err := i.w.do(func() (err error) {
+		rc, err = ranger.OpenRange(start, end)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return i.w.download(rc), nil
+}
+
+func (i *wrappedItem) ETag() (string, error) {
+	var etag string
+	err := i.w.do(func() (err error) {
+		etag, err = i.item.ETag()
+		return err
+	})
+	return etag, err
+}
+
+func (i *wrappedItem) LastMod() (time.Time, error) {
+	var t time.Time
+	err := i.w.do(func() (err error) {
+		t, err = i.item.LastMod()
+		return err
+	})
+	return t, err
+}
+
+func (i *wrappedItem) Metadata() (map[string]any, error) {
+	var md map[string]any
+	err := i.w.do(func() (err error) {
+		md, err = i.item.Metadata()
+		return err
+	})
+	return md, err
+}
+
+func (i *wrappedItem) Tags() (map[string]any, error) {
+	tagged, ok := i.item.(stow.Taggable)
+	if !ok {
+		return nil, stow.NotSupported("Tags")
+	}
+	var tags map[string]any
+	err := i.w.do(func() (err error) {
+		tags, err = tagged.Tags()
+		return err
+	})
+	return tags, err
+}
@@ -0,0 +1,125 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// connLimiter is a semaphore on the requests in flight to a backend. A
+// request holds its slot until the body of its response is read to the end
+// or closed. A nil connLimiter limits nothing.
+type connLimiter chan struct{}
+
+// newConnLimiter returns a connLimiter of n slots, nil when n is not positive.
+func newConnLimiter(n int64) connLimiter {
+	if n <= 0 {
+		return nil
+	}
+	return make(connLimiter, n)
+}
+
+// LimitsConnections reports whether l limits the requests in flight.
+func (l *Limiter) LimitsConnections() bool {
+	return l != nil && l.conns != nil
+}
+
+// SizePool sizes the connection pool of tr to the MaxConnections of l, so
+// that every connection slot keeps its connection.
+func (l *Limiter) SizePool(tr *http.Transport) {
+	if l != nil {
+		l.conns.apply(tr)
+	}
+}
+
+// ConnTransport returns rt with every request holding a connection slot of
+// l, for the callers that apply the rate limits themselves. A nil rt is
+// http.DefaultTransport.
+func (l *Limiter) ConnTransport(rt http.RoundTripper) http.RoundTripper {
+	if l == nil || l.conns == nil {
+		return rt
+	}
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return l.conns.transport(rt)
+}
+
+// apply sizes the connection pool of tr to the slots of l.
+func (l connLimiter) apply(tr *http.Transport) {
+	if l == nil {
+		return
+	}
+	tr.MaxConnsPerHost = cap(l)
+	tr.MaxIdleConnsPerHost = cap(l)
+}
+
+// transport returns rt with every request holding a slot of l.
+func (l connLimiter) transport(rt http.RoundTripper) http.RoundTripper {
+	if l == nil {
+		return rt
+	}
+	return &connTransport{rt: rt, conns: l}
+}
+
+type connTransport struct {
+	rt    http.RoundTripper
+	conns connLimiter
+}
+
+func (t *connTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.conns <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	release := sync.OnceFunc(func() { <-t.conns })
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if resp.Body == nil || resp.Body == http.NoBody {
+		release()
+		return resp, nil
+	}
+	resp.Body = &connBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// connBody releases the slot of its request once it is read to the end or
+// closed, whichever comes first.
+type connBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *connBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil {
+		b.release()
+	}
+	return n, err
+}
+
+func (b *connBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,302 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimit limits the bandwidth and the request rate to a backend
+// according to its RateLimit, and the requests in flight according to its
+// MaxConnections. The limiters are shared by the Blobs and stow locations
+// of a backend in the process while they are in use, so the limits hold for
+// all of their goroutines together. Every owner releases its Limiter once it
+// is done, the Limiter is dropped with the release of its last owner.
+package ratelimit // import "kmodules.xyz/objectstore-api/pkg/ratelimit"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"golang.org/x/time/rate"
+	"gomodules.xyz/stow"
+)
+
+// Stow config keys of the limits. ConfigKey identifies the backend whose
+// limiters are shared.
+const (
+	ConfigKey                    = "ratelimit_key"
+	ConfigUploadBytesPerSecond   = "ratelimit_upload_bytes_per_second"
+	ConfigDownloadBytesPerSecond = "ratelimit_download_bytes_per_second"
+	ConfigRequestsPerSecond      = "ratelimit_requests_per_second"
+	ConfigMaxConnections         = "ratelimit_max_connections"
+)
+
+// Options are the limits of a backend. A limit of 0 is no limit.
+type Options struct {
+	// Key identifies the backend, the backends with the same Key and limits
+	// share their Limiter.
+	Key                    string
+	UploadBytesPerSecond   int64
+	DownloadBytesPerSecond int64
+	RequestsPerSecond      int64
+	MaxConnections         int64
+}
+
+// FromSpec returns the Options of the RateLimit and the MaxConnections of a
+// backend, keyed by the url of the backend, which has every setting of its
+// spec.
+func FromSpec(backend api.Backend) Options {
+	o := Options{MaxConnections: backend.MaxConnections()}
+	if backend.RateLimit != nil {
+		o.UploadBytesPerSecond = backend.RateLimit.UploadBytesPerSecond
+		o.DownloadBytesPerSecond = backend.RateLimit.DownloadBytesPerSecond
+		o.RequestsPerSecond = int64(backend.RateLimit.RequestsPerSecond)
+	}
+	if o.IsZero() {
+		return Options{}
+	}
+	// the url identifies the backend without its secret
+	key, err := backend.URL()
+	if err != nil {
+		key, _ = backend.Location()
+	}
+	o.Key = key
+	return o
+}
+
+// FromStowConfig reads the Options from a stow config.
+func FromStowConfig(config stow.Config) (Options, error) {
+	var o Options
+	o.Key, _ = config.Config(ConfigKey)
+	for key, limit := range map[string]*int64{
+		ConfigUploadBytesPerSecond:   &o.UploadBytesPerSecond,
+		ConfigDownloadBytesPerSecond: &o.DownloadBytesPerSecond,
+		ConfigRequestsPerSecond:      &o.RequestsPerSecond,
+		ConfigMaxConnections:         &o.MaxConnections,
+	} {
+		v, ok := config.Config(key)
+		if !ok || v == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return Options{}, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		*limit = n
+	}
+	return o, nil
+}
+
+// SetStowConfig sets the stow config keys of o. Options without limits set
+// none.
+func (o Options) SetStowConfig(cfg stow.ConfigMap) {
+	if o.IsZero() {
+		return
+	}
+	cfg[ConfigKey] = o.Key
+	for key, limit := range map[string]int64{
+		ConfigUploadBytesPerSecond:   o.UploadBytesPerSecond,
+		ConfigDownloadBytesPerSecond: o.DownloadBytesPerSecond,
+		ConfigRequestsPerSecond:      o.RequestsPerSecond,
+		ConfigMaxConnections:         o.MaxConnections,
+	} {
+		if limit > 0 {
+			cfg[key] = strconv.FormatInt(limit, 10)
+		}
+	}
+}
+
+// IsZero reports whether o has no limits.
+func (o Options) IsZero() bool {
+	return o.UploadBytesPerSecond <= 0 && o.DownloadBytesPerSecond <= 0 && o.RequestsPerSecond <= 0 && o.MaxConnections <= 0
+}
+
+// limiters holds the Limiters in use, limitersMu guards it and the owners of
+// the Limiters.
+var (
+	limitersMu sync.Mutex
+	limiters   = map[Options]*Limiter{}
+)
+
+// Limiter returns the Limiter of o for a new owner, which must Release it
+// once. The owners with the same Options share the Limiter. It is nil when o
+// has no limits.
+func (o Options) Limiter() *Limiter {
+	if o.IsZero() {
+		return nil
+	}
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	l, ok := limiters[o]
+	if !ok {
+		l = newLimiter(o)
+		limiters[o] = l
+	}
+	l.owners++
+	return l
+}
+
+// Release ends the use of l by one of its owners. The Limiter keeps limiting
+// the calls in flight, but once its last owner released it, the next owner
+// of its Options gets a new one.
+func (l *Limiter) Release() {
+	if l == nil {
+		return
+	}
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	if l.owners--; l.owners == 0 && limiters[l.options] == l {
+		delete(limiters, l.options)
+	}
+}
+
+// Limiter limits the requests and the bytes sent to and read from a backend.
+// A nil Limiter limits nothing.
+type Limiter struct {
+	requests *rate.Limiter
+	upload   *rate.Limiter
+	download *rate.Limiter
+	conns    connLimiter
+
+	// options key the Limiter in limiters, owners counts the callers of
+	// Limiter that did not release it yet
+	options Options
+	owners  int
+}
+
+func newLimiter(o Options) *Limiter {
+	l := &Limiter{conns: newConnLimiter(o.MaxConnections), options: o}
+	if o.RequestsPerSecond > 0 {
+		l.requests = rate.NewLimiter(rate.Limit(o.RequestsPerSecond), 1)
+	}
+	if o.UploadBytesPerSecond > 0 {
+		l.upload = bandwidth(o.UploadBytesPerSecond)
+	}
+	if o.DownloadBytesPerSecond > 0 {
+		l.download = bandwidth(o.DownloadBytesPerSecond)
+	}
+	return l
+}
+
+// bandwidth returns a limiter of bytes per second. The burst, and so the size
+// of a read, is a tenth of a second of traffic.
+func bandwidth(bps int64) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(bps), int(max(bps/10, 1)))
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil || l.requests == nil {
+		return nil
+	}
+	return l.requests.Wait(ctx)
+}
+
+// Upload limits the bytes read from r, which are sent to the backend.
+func (l *Limiter) Upload(ctx context.Context, r io.Reader) io.Reader {
+	if l == nil || l.upload == nil {
+		return r
+	}
+	return &reader{ctx: ctx, r: r, limiter: l.upload}
+}
+
+// Download limits the bytes read from rc, which are read from the backend.
+func (l *Limiter) Download(ctx context.Context, rc io.ReadCloser) io.ReadCloser {
+	if l == nil || l.download == nil {
+		return rc
+	}
+	return &readCloser{reader: reader{ctx: ctx, r: rc, limiter: l.download}, c: rc}
+}
+
+type reader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rate.Limiter
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if burst := r.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.limiter.WaitN(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+type readCloser struct {
+	reader
+	c io.Closer
+}
+
+func (r *readCloser) Close() error {
+	return r.c.Close()
+}
+
+// Transport returns rt with the limits applied to its requests, their bodies
+// and the bodies of their responses. The rate limits are waited for before a
+// connection slot is taken. A nil rt is http.DefaultTransport.
+func (l *Limiter) Transport(rt http.RoundTripper) http.RoundTripper {
+	if l == nil {
+		return rt
+	}
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &transport{rt: l.conns.transport(rt), limiter: l}
+}
+
+type transport struct {
+	rt      http.RoundTripper
+	limiter *Limiter
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if err := t.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	if t.limiter.upload != nil && req.Body != nil && req.Body != http.NoBody {
+		// the request of the caller is not modified
+		body, getBody := req.Body, req.GetBody
+		req = req.Clone(ctx)
+		req.Body = t.limiter.uploadBody(ctx, body)
+		if getBody != nil {
+			req.GetBody = func() (io.ReadCloser, error) {
+				body, err := getBody()
+				if err != nil {
+					return nil, err
+				}
+				return t.limiter.uploadBody(ctx, body), nil
+			}
+		}
+	}
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = t.limiter.Download(ctx, resp.Body)
+	return resp, nil
+}
+
+func (l *Limiter) uploadBody(ctx context.Context, rc io.ReadCloser) io.ReadCloser {
+	return &readCloser{reader: reader{ctx: ctx, r: rc, limiter: l.upload}, c: rc}
+}
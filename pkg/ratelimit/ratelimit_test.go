@@ -0,0 +1,190 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"gomodules.xyz/stow"
+)
+
+func TestFromSpec(t *testing.T) {
+	assert.True(t, ratelimit.FromSpec(api.Backend{S3: &api.S3Spec{Bucket: "stash"}}).IsZero())
+	assert.Nil(t, ratelimit.Options{Key: "s3://stash"}.Limiter())
+
+	backend := api.Backend{
+		S3:        &api.S3Spec{Bucket: "stash"},
+		RateLimit: &api.RateLimit{UploadBytesPerSecond: 1 << 20, RequestsPerSecond: 10},
+	}
+	o := ratelimit.FromSpec(backend)
+	assert.Equal(t, ratelimit.Options{
+		Key:                  "s3://stash?rateLimit.requestsPerSecond=10&rateLimit.uploadBytesPerSecond=1048576",
+		UploadBytesPerSecond: 1 << 20,
+		RequestsPerSecond:    10,
+	}, o)
+	// the backend is limited as a whole
+	assert.Same(t, o.Limiter(), ratelimit.FromSpec(*backend.DeepCopy()).Limiter())
+	backend.S3.Bucket = "other"
+	assert.NotSame(t, o.Limiter(), ratelimit.FromSpec(backend).Limiter())
+
+	// MaxConnections is limited without a RateLimit
+	o = ratelimit.FromSpec(api.Backend{GCS: &api.GCSSpec{Bucket: "stash", MaxConnections: 2}})
+	assert.Equal(t, ratelimit.Options{Key: "gs://stash?maxConnections=2", MaxConnections: 2}, o)
+	assert.NotNil(t, o.Limiter())
+}
+
+func TestLimiterRelease(t *testing.T) {
+	o := ratelimit.Options{Key: t.Name(), RequestsPerSecond: 10}
+	a, b := o.Limiter(), o.Limiter()
+	assert.Same(t, a, b)
+	// the limits are part of the key
+	other := ratelimit.Options{Key: t.Name(), RequestsPerSecond: 20}.Limiter()
+	assert.NotSame(t, a, other)
+	other.Release()
+
+	a.Release()
+	c := o.Limiter()
+	assert.Same(t, a, c)
+
+	// the Limiter is dropped with its last owner
+	b.Release()
+	c.Release()
+	d := o.Limiter()
+	assert.NotSame(t, a, d)
+	d.Release()
+
+	// a nil Limiter has no owners
+	(*ratelimit.Limiter)(nil).Release()
+}
+
+func TestStowConfig(t *testing.T) {
+	o, err := ratelimit.FromStowConfig(stow.ConfigMap{})
+	assert.Nil(t, err)
+	assert.True(t, o.IsZero())
+
+	want := ratelimit.Options{
+		Key:                    "gs://stash",
+		UploadBytesPerSecond:   1 << 20,
+		DownloadBytesPerSecond: 4 << 20,
+		RequestsPerSecond:      50,
+		MaxConnections:         8,
+	}
+	cfg := stow.ConfigMap{}
+	want.SetStowConfig(cfg)
+	got, err := ratelimit.FromStowConfig(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = ratelimit.FromStowConfig(stow.ConfigMap{ratelimit.ConfigRequestsPerSecond: "many"})
+	assert.ErrorContains(t, err, "invalid ratelimit_requests_per_second")
+}
+
+// rateOf returns the rate of n units done in d, in units per second.
+func rateOf(n int, d time.Duration) float64 {
+	return float64(n) / d.Seconds()
+}
+
+func TestTransport(t *testing.T) {
+	const size = 64 << 10
+	data := bytes.Repeat([]byte("x"), size)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			_, _ = io.Copy(io.Discard, r.Body)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+
+	newClient := func(o ratelimit.Options) *http.Client {
+		o.Key = t.Name()
+		return &http.Client{Transport: o.Limiter().Transport(nil)}
+	}
+
+	t.Run("download", func(t *testing.T) {
+		client := newClient(ratelimit.Options{DownloadBytesPerSecond: 128 << 10})
+		start := time.Now()
+		resp, err := client.Get(srv.URL)
+		if !assert.Nil(t, err) {
+			return
+		}
+		n, err := io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		assert.Nil(t, err)
+		assert.EqualValues(t, size, n)
+		// the burst of a tenth of a second is free
+		assert.Less(t, rateOf(size, time.Since(start)), 1.25*(128<<10))
+	})
+
+	t.Run("upload", func(t *testing.T) {
+		client := newClient(ratelimit.Options{UploadBytesPerSecond: 128 << 10})
+		start := time.Now()
+		req, err := http.NewRequest(http.MethodPut, srv.URL, bytes.NewReader(data))
+		if !assert.Nil(t, err) {
+			return
+		}
+		resp, err := client.Do(req)
+		if !assert.Nil(t, err) {
+			return
+		}
+		_ = resp.Body.Close()
+		assert.Less(t, rateOf(size, time.Since(start)), 1.25*(128<<10))
+	})
+
+	t.Run("requests", func(t *testing.T) {
+		o := ratelimit.Options{RequestsPerSecond: 20}
+		// the clients of a backend share the limit
+		clients := []*http.Client{newClient(o), newClient(o)}
+		const requests = 20
+		start := time.Now()
+		var wg sync.WaitGroup
+		for i := 0; i < requests; i++ {
+			wg.Add(1)
+			go func(client *http.Client) {
+				defer wg.Done()
+				resp, err := client.Get(srv.URL)
+				if assert.Nil(t, err) {
+					_ = resp.Body.Close()
+				}
+			}(clients[i%len(clients)])
+		}
+		wg.Wait()
+		assert.Less(t, rateOf(requests, time.Since(start)), 1.1*20)
+	})
+}
+
+func TestWaitCanceled(t *testing.T) {
+	l := ratelimit.Options{Key: t.Name(), RequestsPerSecond: 1}.Limiter()
+	assert.Nil(t, l.Wait(context.Background()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, l.Wait(ctx))
+
+	var nilLimiter *ratelimit.Limiter
+	assert.Nil(t, nilLimiter.Wait(ctx))
+}
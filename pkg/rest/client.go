@@ -0,0 +1,416 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// contentTypeV2 asks the server for listings that include the file sizes.
+const contentTypeV2 = "application/vnd.x.restic.rest.v2"
+
+// FileType is a directory of a restic repository.
+type FileType string
+
+const (
+	ConfigFile   FileType = "config"
+	DataFile     FileType = "data"
+	KeyFile      FileType = "keys"
+	LockFile     FileType = "locks"
+	SnapshotFile FileType = "snapshots"
+	IndexFile    FileType = "index"
+)
+
+// FileTypes lists the types that hold named files, in listing order.
+var FileTypes = []FileType{DataFile, IndexFile, KeyFile, LockFile, SnapshotFile}
+
+// ErrInvalidHandle is returned for keys outside of the repository layout.
+var ErrInvalidHandle = errors.New("rest: key is not part of the repository layout")
+
+// Handle identifies a file of the repository.
+type Handle struct {
+	Type FileType
+	Name string
+}
+
+// ParseHandle parses "config" or "<type>/<name>".
+func ParseHandle(key string) (Handle, error) {
+	if key == string(ConfigFile) {
+		return Handle{Type: ConfigFile}, nil
+	}
+	t, name, ok := strings.Cut(key, "/")
+	if !ok || name == "" || strings.Contains(name, "/") || !isFileType(FileType(t)) {
+		return Handle{}, fmt.Errorf("%w: %q", ErrInvalidHandle, key)
+	}
+	return Handle{Type: FileType(t), Name: name}, nil
+}
+
+func isFileType(t FileType) bool {
+	for _, ft := range FileTypes {
+		if ft == t {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the key of the file.
+func (h Handle) String() string {
+	if h.Type == ConfigFile {
+		return string(ConfigFile)
+	}
+	return string(h.Type) + "/" + h.Name
+}
+
+// FileInfo describes a file of the repository. Name is the key of the file.
+type FileInfo struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// Options configures a Client.
+type Options struct {
+	// URL of the repository, eg. https://rest-server:8000/user/repo.
+	// Credentials in the URL are used unless Username is set.
+	URL string
+	// Username and Password are sent with basic auth.
+	Username string
+	Password string
+	// CACertData holds PEM encoded certificates trusted in addition to the
+	// system roots.
+	CACertData []byte
+	// HTTPClient is used to send requests. It takes precedence over
+	// CACertData.
+	HTTPClient *http.Client
+}
+
+// Client talks to a single repository of a rest-server. It is safe for
+// concurrent use.
+type Client struct {
+	base     *url.URL
+	hc       *http.Client
+	username string
+	password string
+}
+
+// NewClient returns a client for the repository at opts.URL. No request is
+// sent.
+func NewClient(opts Options) (*Client, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("rest: unsupported url scheme %q", u.Scheme)
+	}
+	c := &Client{
+		hc:       opts.HTTPClient,
+		username: opts.Username,
+		password: opts.Password,
+	}
+	if c.username == "" && u.User != nil {
+		c.username = u.User.Username()
+		c.password, _ = u.User.Password()
+	}
+	u.User = nil
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	c.base = u
+
+	if c.hc == nil {
+		c.hc = http.DefaultClient
+		if len(opts.CACertData) > 0 {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(opts.CACertData) {
+				return nil, errors.New("rest: failed to parse CA certificate")
+			}
+			tr := http.DefaultTransport.(*http.Transport).Clone()
+			tr.TLSClientConfig = &tls.Config{RootCAs: pool}
+			c.hc = &http.Client{Transport: tr}
+		}
+	}
+	return c, nil
+}
+
+// URL returns the repository url without credentials.
+func (c *Client) URL() string {
+	return c.base.String()
+}
+
+func (c *Client) fileURL(h Handle) string {
+	return c.base.JoinPath(h.String()).String()
+}
+
+func (c *Client) newRequest(ctx context.Context, method, u string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	return req, nil
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, decodeError(req, resp)
+	}
+	return resp, nil
+}
+
+// CreateRepository creates the directories of a new repository.
+func (c *Client) CreateRepository(ctx context.Context) error {
+	u := *c.base
+	u.RawQuery = "create=true"
+	req, err := c.newRequest(ctx, http.MethodPost, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	return discard(resp)
+}
+
+// Stat returns the size of a file.
+func (c *Client) Stat(ctx context.Context, h Handle) (FileInfo, error) {
+	req, err := c.newRequest(ctx, http.MethodHead, c.fileURL(h), nil)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	_ = resp.Body.Close()
+	if resp.ContentLength < 0 {
+		return FileInfo{}, errors.New("rest: missing Content-Length in HEAD response")
+	}
+	return FileInfo{Name: h.String(), Size: resp.ContentLength}, nil
+}
+
+// Load returns length bytes of a file from offset, or the rest of the file
+// when length is negative. The size of the whole file is returned as well.
+func (c *Client) Load(ctx context.Context, h Handle, offset, length int64) (io.ReadCloser, int64, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.fileURL(h), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if length > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	} else if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	size := resp.ContentLength
+	if cr := resp.Header.Get("Content-Range"); cr != "" {
+		if i := strings.LastIndex(cr, "/"); i >= 0 {
+			if n, err := strconv.ParseInt(cr[i+1:], 10, 64); err == nil {
+				size = n
+			}
+		}
+	}
+	return resp.Body, size, nil
+}
+
+// Save stores the content of rd. A negative size streams the content with
+// chunked encoding. Files can not be overwritten, and an upload that fails
+// to read rd is discarded by the server.
+func (c *Client) Save(ctx context.Context, h Handle, rd io.Reader, size int64) error {
+	req, err := c.newRequest(ctx, http.MethodPost, c.fileURL(h), rd)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	return discard(resp)
+}
+
+// Remove deletes a file.
+func (c *Client) Remove(ctx context.Context, h Handle) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, c.fileURL(h), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	return discard(resp)
+}
+
+// List returns the files of a type sorted by key.
+func (c *Client) List(ctx context.Context, t FileType) ([]FileInfo, error) {
+	if t == ConfigFile {
+		fi, err := c.Stat(ctx, Handle{Type: ConfigFile})
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []FileInfo{fi}, nil
+	}
+	if !isFileType(t) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidHandle, t)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, c.base.JoinPath(string(t)).String()+"/", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", contentTypeV2)
+	resp, err := c.do(req)
+	if IsNotFound(err) {
+		// the repository has not been created yet
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []FileInfo
+	if resp.Header.Get("Content-Type") == contentTypeV2 {
+		if err := json.Unmarshal(data, &files); err != nil {
+			return nil, err
+		}
+	} else {
+		// v1 servers only return the names
+		var names []string
+		if err := json.Unmarshal(data, &names); err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			fi, err := c.Stat(ctx, Handle{Type: t, Name: name})
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, FileInfo{Name: name, Size: fi.Size})
+		}
+	}
+	for i := range files {
+		files[i].Name = string(t) + "/" + files[i].Name
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	return files, nil
+}
+
+// ListKeys returns every file whose key starts with prefix, sorted by key.
+// Only the types that can match prefix are listed.
+func (c *Client) ListKeys(ctx context.Context, prefix string) ([]FileInfo, error) {
+	var out []FileInfo
+	for _, t := range append([]FileType{ConfigFile}, FileTypes...) {
+		dir := string(t)
+		if t != ConfigFile {
+			dir += "/"
+		}
+		if !strings.HasPrefix(dir, prefix) && !strings.HasPrefix(prefix, dir) {
+			continue
+		}
+		files, err := c.List(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if strings.HasPrefix(f.Name, prefix) {
+				out = append(out, f)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+func discard(resp *http.Response) error {
+	defer func() { _ = resp.Body.Close() }()
+	_, err := io.Copy(io.Discard, resp.Body)
+	return err
+}
+
+// Error is returned for requests the server rejected.
+type Error struct {
+	Status  int
+	Method  string
+	URL     string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("rest: %s %s: %d %s", e.Method, e.URL, e.Status, http.StatusText(e.Status))
+	}
+	return fmt.Sprintf("rest: %s %s: %d %s", e.Method, e.URL, e.Status, e.Message)
+}
+
+func decodeError(req *http.Request, resp *http.Response) error {
+	e := &Error{
+		Status: resp.StatusCode,
+		Method: req.Method,
+		URL:    req.URL.Redacted(),
+	}
+	if data, err := io.ReadAll(io.LimitReader(resp.Body, 1024)); err == nil {
+		e.Message = strings.TrimSpace(string(data))
+	}
+	return e
+}
+
+// IsNotFound returns true if err reports a missing file or repository.
+func IsNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Status == http.StatusNotFound
+}
@@ -0,0 +1,99 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"kmodules.xyz/objectstore-api/pkg/rest"
+	"kmodules.xyz/objectstore-api/pkg/rest/resttest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHandle(t *testing.T) {
+	for key, expected := range map[string]rest.Handle{
+		"config":        {Type: rest.ConfigFile},
+		"data/0123":     {Type: rest.DataFile, Name: "0123"},
+		"snapshots/abc": {Type: rest.SnapshotFile, Name: "abc"},
+	} {
+		h, err := rest.ParseHandle(key)
+		assert.Nil(t, err, key)
+		assert.Equal(t, expected, h, key)
+		assert.Equal(t, key, h.String())
+	}
+	for _, key := range []string{"", "config/x", "data", "data/", "data/a/b", "trash/abc"} {
+		_, err := rest.ParseHandle(key)
+		assert.ErrorIs(t, err, rest.ErrInvalidHandle, key)
+	}
+}
+
+func TestClient(t *testing.T) {
+	srv := resttest.NewServer()
+	defer srv.Close()
+	srv.NoVerifyUpload = true
+	ctx := context.Background()
+
+	// credentials in the url are used for basic auth
+	srv.Username, srv.Password = "user", "pass"
+	c, err := rest.NewClient(rest.Options{URL: strings.Replace(srv.URL, "http://", "http://user:pass@", 1) + "/repo"})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, srv.URL+"/repo/", c.URL())
+
+	files, err := c.List(ctx, rest.DataFile)
+	assert.Nil(t, err, "a missing repository has no files")
+	assert.Empty(t, files)
+	assert.Nil(t, c.CreateRepository(ctx))
+
+	for _, key := range []string{"data/b", "data/a", "index/c", "config"} {
+		h, _ := rest.ParseHandle(key)
+		assert.Nil(t, c.Save(ctx, h, strings.NewReader("0123456789"), 10))
+	}
+
+	files, err = c.ListKeys(ctx, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []rest.FileInfo{
+		{Name: "config", Size: 10},
+		{Name: "data/a", Size: 10},
+		{Name: "data/b", Size: 10},
+		{Name: "index/c", Size: 10},
+	}, files)
+	files, err = c.ListKeys(ctx, "data/b")
+	assert.Nil(t, err)
+	assert.Equal(t, []rest.FileInfo{{Name: "data/b", Size: 10}}, files)
+
+	r, size, err := c.Load(ctx, rest.Handle{Type: rest.DataFile, Name: "a"}, 2, 3)
+	if assert.Nil(t, err) {
+		data, _ := io.ReadAll(r)
+		_ = r.Close()
+		assert.Equal(t, "234", string(data))
+		assert.Equal(t, int64(10), size)
+	}
+
+	_, err = c.Stat(ctx, rest.Handle{Type: rest.LockFile, Name: "x"})
+	assert.True(t, rest.IsNotFound(err))
+
+	wrong, err := rest.NewClient(rest.Options{URL: srv.URL + "/repo", Username: "user", Password: "wrong"})
+	assert.Nil(t, err)
+	_, err = wrong.Stat(ctx, rest.Handle{Type: rest.ConfigFile})
+	assert.ErrorContains(t, err, "401")
+}
@@ -0,0 +1,23 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rest is a client for the HTTP protocol of the restic REST server.
+// A rest-server hosts restic repositories, so the keys of a repository are
+// limited to its layout: "config" and "<type>/<name>" where type is one of
+// data, keys, locks, snapshots or index.
+//
+// ref: https://restic.readthedocs.io/en/latest/100_references.html#rest-backend
+package rest // import "kmodules.xyz/objectstore-api/pkg/rest"
@@ -0,0 +1,328 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resttest provides an in-memory stand-in for the restic
+// rest-server so that rest backends can be tested without network access.
+package resttest // import "kmodules.xyz/objectstore-api/pkg/rest/resttest"
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const contentTypeV2 = "application/vnd.x.restic.rest.v2"
+
+var fileTypes = map[string]bool{
+	"data":      true,
+	"index":     true,
+	"keys":      true,
+	"locks":     true,
+	"snapshots": true,
+}
+
+// Server is a rest-server stand-in backed by memory. Like rest-server it
+// rejects overwrites and, unless NoVerifyUpload is set, files other than
+// config whose name is not the sha256 of their content.
+type Server struct {
+	*httptest.Server
+
+	// Username and Password enable basic auth when set.
+	Username string
+	Password string
+	// NoVerifyUpload accepts any file name.
+	NoVerifyUpload bool
+	// ReadOnly answers the uploads and removals with 403 Forbidden.
+	ReadOnly bool
+
+	mu    sync.Mutex
+	repos map[string]map[string][]byte // by repository path, then key
+
+	clientCert []byte
+	clientKey  []byte
+}
+
+// NewServer starts a plain http Server.
+func NewServer() *Server {
+	s := &Server{repos: map[string]map[string][]byte{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// NewTLSServer starts a Server with a self signed certificate, see CACert.
+func NewTLSServer() *Server {
+	s := &Server{repos: map[string]map[string][]byte{}}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// NewMutualTLSServer starts a TLS Server that only accepts connections with
+// the client certificate returned by ClientCert.
+func NewMutualTLSServer() *Server {
+	s := &Server{repos: map[string]map[string][]byte{}}
+	cert, key, pool := newClientCert()
+	s.clientCert, s.clientKey = cert, key
+	s.Server = httptest.NewUnstartedServer(http.HandlerFunc(s.serve))
+	s.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  pool,
+	}
+	s.StartTLS()
+	return s
+}
+
+// ClientCert returns the PEM encoded client certificate and key of a mutual
+// TLS server.
+func (s *Server) ClientCert() (cert, key []byte) {
+	return s.clientCert, s.clientKey
+}
+
+// newClientCert returns a self signed client certificate, its key and a pool
+// that trusts it.
+func newClientCert() (cert, key []byte, pool *x509.CertPool) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "resttest client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &priv.PublicKey, priv)
+	if err != nil {
+		panic(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		panic(err)
+	}
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		panic(err)
+	}
+	pool = x509.NewCertPool()
+	pool.AddCert(c)
+	cert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	key = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return cert, key, pool
+}
+
+// CACert returns the PEM encoded certificate of a TLS server.
+func (s *Server) CACert() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+}
+
+// CreateRepository creates an empty repository at repo, eg. "/demo".
+func (s *Server) CreateRepository(repo string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.createRepository(cleanRepo(repo))
+}
+
+func (s *Server) createRepository(repo string) {
+	if _, ok := s.repos[repo]; !ok {
+		s.repos[repo] = map[string][]byte{}
+	}
+}
+
+// Object returns the content of a file of a repository.
+func (s *Server) Object(repo, key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.repos[cleanRepo(repo)][key]
+	return data, ok
+}
+
+// Keys returns the sorted keys of a repository.
+func (s *Server) Keys(repo string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0)
+	for k := range s.repos[cleanRepo(repo)] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func cleanRepo(repo string) string {
+	return strings.TrimSuffix(path.Clean("/"+repo), "/")
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if s.Username != "" || s.Password != "" {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != s.Username || pass != s.Password {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	p := r.URL.Path
+	if r.Method == http.MethodPost && r.URL.Query().Get("create") == "true" {
+		s.mu.Lock()
+		s.createRepository(cleanRepo(p))
+		s.mu.Unlock()
+		return
+	}
+
+	// <repo>/config, <repo>/<type>/ or <repo>/<type>/<name>
+	dir, name := path.Split(strings.TrimSuffix(p, "/"))
+	if strings.HasSuffix(p, "/") {
+		dir, name = strings.TrimSuffix(p, "/")+"/", ""
+	}
+	var repo, key string
+	switch {
+	case name == "config" && !fileTypes[path.Base(dir)]:
+		repo, key = cleanRepo(dir), "config"
+	case name == "" && fileTypes[path.Base(dir)]:
+		s.list(w, r, cleanRepo(path.Dir(strings.TrimSuffix(dir, "/"))), path.Base(dir))
+		return
+	case name != "" && fileTypes[path.Base(dir)]:
+		repo, key = cleanRepo(path.Dir(strings.TrimSuffix(dir, "/"))), path.Base(dir)+"/"+name
+	default:
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	if s.ReadOnly && (r.Method == http.MethodPost || r.Method == http.MethodDelete) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		s.get(w, r, repo, key)
+	case http.MethodPost:
+		s.save(w, r, repo, key)
+	case http.MethodDelete:
+		s.remove(w, repo, key)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, repo, fileType string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	type entry struct {
+		Name string `json:"name"`
+		Size int64  `json:"size"`
+	}
+	s.mu.Lock()
+	files, ok := s.repos[repo]
+	entries := []entry{}
+	for k, data := range files {
+		if name, found := strings.CutPrefix(k, fileType+"/"); found {
+			entries = append(entries, entry{Name: name, Size: int64(len(data))})
+		}
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	if r.Header.Get("Accept") == contentTypeV2 {
+		w.Header().Set("Content-Type", contentTypeV2)
+		_ = json.NewEncoder(w).Encode(entries)
+		return
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	w.Header().Set("Content-Type", "application/vnd.x.restic.rest.v1")
+	_ = json.NewEncoder(w).Encode(names)
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request, repo, key string) {
+	s.mu.Lock()
+	data, ok := s.repos[repo][key]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+}
+
+func (s *Server) save(w http.ResponseWriter, r *http.Request, repo, key string) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		// an aborted upload is never stored
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !s.NoVerifyUpload && key != "config" {
+		sum := sha256.Sum256(data)
+		if path.Base(key) != hex.EncodeToString(sum[:]) {
+			http.Error(w, "file content does not match hash", http.StatusBadRequest)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files, ok := s.repos[repo]
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	if _, exists := files[key]; exists {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	files[key] = data
+}
+
+func (s *Server) remove(w http.ResponseWriter, repo, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.repos[repo][key]; !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	delete(s.repos[repo], key)
+}
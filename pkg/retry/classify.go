@@ -0,0 +1,139 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/b2"
+	"kmodules.xyz/objectstore-api/pkg/rest"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	az "github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/ncw/swift"
+	"google.golang.org/api/googleapi"
+)
+
+// Classify returns the class of err, false when err is not retried whatever
+// the policy, eg. a missing object or a denied request.
+func Classify(err error) (api.RetryErrorClass, bool) {
+	if err == nil {
+		return "", false
+	}
+	// the sdks report a status of 0 when the request was not answered
+	if code, ok := statusCode(err); ok && code != 0 {
+		return ClassifyStatus(code)
+	}
+	// the errors of aws-sdk-go hold their cause without unwrapping to it
+	var awsErr interface{ OrigErr() error }
+	if errors.As(err, &awsErr) && awsErr.OrigErr() != nil {
+		return Classify(awsErr.OrigErr())
+	}
+	if isCertificateError(err) {
+		return "", false
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return api.RetryOnTimeout, true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return "", false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		// the connection was closed before the response was read
+		errors.Is(err, io.EOF) {
+		return api.RetryOnNetwork, true
+	}
+	return "", false
+}
+
+// ClassifyStatus returns the class of an http response status, false when it
+// is not retried whatever the policy.
+func ClassifyStatus(code int) (api.RetryErrorClass, bool) {
+	switch {
+	case code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable:
+		return api.RetryOnThrottling, true
+	case code == http.StatusRequestTimeout:
+		return api.RetryOnTimeout, true
+	case code >= 500 && code != http.StatusNotImplemented && code != http.StatusHTTPVersionNotSupported:
+		return api.RetryOnServerError, true
+	}
+	return "", false
+}
+
+// statusCode returns the http response status of the errors of the provider
+// sdks and the clients of this module.
+func statusCode(err error) (int, bool) {
+	// aws-sdk-go, aws-sdk-go-v2 and smithy
+	var sc interface{ StatusCode() int }
+	if errors.As(err, &sc) {
+		return sc.StatusCode(), true
+	}
+	var hsc interface{ HTTPStatusCode() int }
+	if errors.As(err, &hsc) {
+		return hsc.HTTPStatusCode(), true
+	}
+	var gErr *googleapi.Error
+	if errors.As(err, &gErr) {
+		return gErr.Code, true
+	}
+	var azErr *azcore.ResponseError
+	if errors.As(err, &azErr) {
+		return azErr.StatusCode, true
+	}
+	var azStorageErr az.AzureStorageServiceError
+	if errors.As(err, &azStorageErr) {
+		return azStorageErr.StatusCode, true
+	}
+	var swiftErr *swift.Error
+	if errors.As(err, &swiftErr) {
+		return swiftErr.StatusCode, true
+	}
+	var b2Err *b2.Error
+	if errors.As(err, &b2Err) {
+		return b2Err.Status, true
+	}
+	var restErr *rest.Error
+	if errors.As(err, &restErr) {
+		return restErr.Status, true
+	}
+	return 0, false
+}
+
+func isCertificateError(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &verifyErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostErr) ||
+		errors.As(err, &invalidErr)
+}
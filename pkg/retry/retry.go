@@ -0,0 +1,227 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package retry retries the operations on a backend according to its
+// RetryPolicy. The blob package retries every operation with Do, after turning
+// the retries of the provider sdks off, and the osm package retries every stow
+// call the same way.
+package retry // import "kmodules.xyz/objectstore-api/pkg/retry"
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"gomodules.xyz/stow"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Defaults of the fields of api.RetryPolicy.
+const (
+	DefaultMaxAttempts = api.DefaultRetryMaxAttempts
+	DefaultBaseBackoff = api.DefaultRetryBaseBackoff
+	DefaultMaxBackoff  = api.DefaultRetryMaxBackoff
+)
+
+// Stow config keys of the retry policy. The durations use the format of
+// time.ParseDuration, ConfigRetryOn is a comma separated list of classes.
+const (
+	ConfigMaxAttempts    = "retry_max_attempts"
+	ConfigBaseBackoff    = "retry_base_backoff"
+	ConfigMaxBackoff     = "retry_max_backoff"
+	ConfigAttemptTimeout = "retry_attempt_timeout"
+	ConfigTimeout        = "retry_timeout"
+	ConfigRetryOn        = "retry_on"
+)
+
+// Policy is a RetryPolicy with its defaults applied. A nil Policy makes a
+// single attempt without timeouts.
+type Policy struct {
+	MaxAttempts    int
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+	AttemptTimeout time.Duration
+	Timeout        time.Duration
+	RetryOn        []api.RetryErrorClass
+}
+
+// FromSpec returns the Policy of the RetryPolicy of a backend, nil when spec
+// is nil.
+func FromSpec(spec *api.RetryPolicy) *Policy {
+	if spec == nil {
+		return nil
+	}
+	spec = spec.DeepCopy()
+	api.SetDefaults_RetryPolicy(spec)
+	return &Policy{
+		MaxAttempts:    int(spec.MaxAttempts),
+		BaseBackoff:    duration(spec.BaseBackoff),
+		MaxBackoff:     duration(spec.MaxBackoff),
+		AttemptTimeout: duration(spec.AttemptTimeout),
+		Timeout:        duration(spec.Timeout),
+		RetryOn:        spec.RetryOn,
+	}
+}
+
+func duration(d *metav1.Duration) time.Duration {
+	if d == nil {
+		return 0
+	}
+	return d.Duration
+}
+
+// FromStowConfig reads the Policy from a stow config, nil when the config has
+// no retry policy.
+func FromStowConfig(config stow.Config) (*Policy, error) {
+	v, ok := config.Config(ConfigMaxAttempts)
+	if !ok {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ConfigMaxAttempts, err)
+	}
+	spec := &api.RetryPolicy{MaxAttempts: int32(n)}
+	for key, d := range map[string]**metav1.Duration{
+		ConfigBaseBackoff:    &spec.BaseBackoff,
+		ConfigMaxBackoff:     &spec.MaxBackoff,
+		ConfigAttemptTimeout: &spec.AttemptTimeout,
+		ConfigTimeout:        &spec.Timeout,
+	} {
+		v, ok := config.Config(key)
+		if !ok || v == "" {
+			continue
+		}
+		dur, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		*d = &metav1.Duration{Duration: dur}
+	}
+	if v, ok := config.Config(ConfigRetryOn); ok && v != "" {
+		for _, c := range strings.Split(v, ",") {
+			spec.RetryOn = append(spec.RetryOn, api.RetryErrorClass(c))
+		}
+	}
+	return FromSpec(spec), nil
+}
+
+// SetStowConfig sets the stow config keys of p. A nil Policy sets none.
+func (p *Policy) SetStowConfig(cfg stow.ConfigMap) {
+	if p == nil {
+		return
+	}
+	cfg[ConfigMaxAttempts] = strconv.Itoa(p.MaxAttempts)
+	cfg[ConfigBaseBackoff] = p.BaseBackoff.String()
+	cfg[ConfigMaxBackoff] = p.MaxBackoff.String()
+	if p.AttemptTimeout > 0 {
+		cfg[ConfigAttemptTimeout] = p.AttemptTimeout.String()
+	}
+	if p.Timeout > 0 {
+		cfg[ConfigTimeout] = p.Timeout.String()
+	}
+	classes := make([]string, 0, len(p.RetryOn))
+	for _, c := range p.RetryOn {
+		classes = append(classes, string(c))
+	}
+	cfg[ConfigRetryOn] = strings.Join(classes, ",")
+}
+
+// WithTimeout limits ctx to the overall Timeout of p.
+func (p *Policy) WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p == nil || p.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, p.Timeout)
+}
+
+// attemptContext limits ctx to the AttemptTimeout of p.
+func (p *Policy) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p == nil || p.AttemptTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, p.AttemptTimeout)
+}
+
+// Backoff returns the wait before the given retry, counted from 1. The wait is
+// picked at random below BaseBackoff doubled with every retry and capped by
+// MaxBackoff.
+func (p *Policy) Backoff(retry int) time.Duration {
+	limit := p.BaseBackoff
+	for i := 1; i < retry && limit < p.MaxBackoff; i++ {
+		limit *= 2
+	}
+	limit = min(limit, p.MaxBackoff)
+	if limit <= 0 {
+		return 0
+	}
+	return rand.N(limit + 1)
+}
+
+// Retryable reports whether p retries err.
+func (p *Policy) Retryable(err error) bool {
+	if p == nil {
+		return false
+	}
+	class, ok := Classify(err)
+	return ok && slices.Contains(p.RetryOn, class)
+}
+
+// Do calls fn until it succeeds, returns an error that is not retried or the
+// attempts of p are used up, and returns the last error. Every call gets ctx
+// limited to the AttemptTimeout, an attempt that runs out of it is a Timeout.
+// The overall Timeout is left to the caller, see WithTimeout.
+func (p *Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p == nil {
+		return fn(ctx)
+	}
+	for attempt := 1; ; attempt++ {
+		actx, cancel := p.attemptContext(ctx)
+		err := fn(actx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		// the deadline of an attempt is a Timeout, the one of ctx is final
+		if attempt >= p.MaxAttempts || ctx.Err() != nil || !p.Retryable(err) {
+			return err
+		}
+		if sleep(ctx, p.Backoff(attempt)) != nil {
+			return err
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
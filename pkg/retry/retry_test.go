@@ -0,0 +1,186 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/b2"
+	"kmodules.xyz/objectstore-api/pkg/retry"
+
+	"github.com/stretchr/testify/assert"
+	"gomodules.xyz/stow"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func fastPolicy() *retry.Policy {
+	return retry.FromSpec(&api.RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: &metav1.Duration{Duration: time.Millisecond},
+		MaxBackoff:  &metav1.Duration{Duration: 5 * time.Millisecond},
+	})
+}
+
+func TestFromSpec(t *testing.T) {
+	assert.Nil(t, retry.FromSpec(nil))
+
+	p := retry.FromSpec(&api.RetryPolicy{})
+	assert.Equal(t, &retry.Policy{
+		MaxAttempts: retry.DefaultMaxAttempts,
+		BaseBackoff: retry.DefaultBaseBackoff,
+		MaxBackoff:  retry.DefaultMaxBackoff,
+		RetryOn:     api.DefaultRetryOn,
+	}, p)
+}
+
+func TestStowConfig(t *testing.T) {
+	p, err := retry.FromStowConfig(stow.ConfigMap{})
+	assert.Nil(t, err)
+	assert.Nil(t, p)
+
+	want := &retry.Policy{
+		MaxAttempts:    5,
+		BaseBackoff:    200 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		AttemptTimeout: time.Minute,
+		Timeout:        5 * time.Minute,
+		RetryOn:        []api.RetryErrorClass{api.RetryOnThrottling, api.RetryOnNetwork},
+	}
+	cfg := stow.ConfigMap{}
+	want.SetStowConfig(cfg)
+	got, err := retry.FromStowConfig(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = retry.FromStowConfig(stow.ConfigMap{retry.ConfigMaxAttempts: "3", retry.ConfigTimeout: "soon"})
+	assert.ErrorContains(t, err, "invalid retry_timeout")
+}
+
+func TestBackoff(t *testing.T) {
+	p := &retry.Policy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for i := 0; i < 100; i++ {
+		assert.LessOrEqual(t, p.Backoff(1), 100*time.Millisecond)
+		assert.LessOrEqual(t, p.Backoff(3), 400*time.Millisecond)
+		assert.LessOrEqual(t, p.Backoff(50), time.Second)
+		assert.GreaterOrEqual(t, p.Backoff(50), time.Duration(0))
+	}
+}
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		name  string
+		err   error
+		class api.RetryErrorClass
+		ok    bool
+	}{
+		{"throttled", &b2.Error{Status: http.StatusTooManyRequests}, api.RetryOnThrottling, true},
+		{"unavailable", fmt.Errorf("upload: %w", &b2.Error{Status: http.StatusServiceUnavailable}), api.RetryOnThrottling, true},
+		{"server error", &b2.Error{Status: http.StatusInternalServerError}, api.RetryOnServerError, true},
+		{"request timeout", &b2.Error{Status: http.StatusRequestTimeout}, api.RetryOnTimeout, true},
+		{"not found", &b2.Error{Status: http.StatusNotFound}, "", false},
+		{"deadline", &url.Error{Op: "Get", URL: "http://s3", Err: context.DeadlineExceeded}, api.RetryOnTimeout, true},
+		{"refused", &url.Error{Op: "Get", URL: "http://s3", Err: syscall.ECONNREFUSED}, api.RetryOnNetwork, true},
+		{"closed", &url.Error{Op: "Get", URL: "http://s3", Err: io.EOF}, api.RetryOnNetwork, true},
+		{"canceled", context.Canceled, "", false},
+		{"other", errors.New("invalid argument"), "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			class, ok := retry.Classify(tc.err)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.class, class)
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	p := fastPolicy()
+
+	var calls int
+	err := p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return &b2.Error{Status: http.StatusServiceUnavailable}
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return &b2.Error{Status: http.StatusInternalServerError}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return &b2.Error{Status: http.StatusForbidden}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls, "a denied request is not retried")
+
+	p.RetryOn = []api.RetryErrorClass{api.RetryOnNetwork}
+	calls = 0
+	err = p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return &b2.Error{Status: http.StatusServiceUnavailable}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls, "throttling is not retried")
+}
+
+func TestDoAttemptTimeout(t *testing.T) {
+	p := fastPolicy()
+	p.AttemptTimeout = 20 * time.Millisecond
+
+	var calls int
+	err := p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+
+	// the deadline of the caller is not retried
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	calls = 0
+	err = p.Do(ctx, func(ctx context.Context) error {
+		calls++
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, calls)
+}
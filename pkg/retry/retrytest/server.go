@@ -0,0 +1,125 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package retrytest provides a fault injecting http server in front of a
+// backend stand-in, eg. an s3test or resttest server, for testing the retry
+// policies.
+package retrytest // import "kmodules.xyz/objectstore-api/pkg/retry/retrytest"
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fault is what happens to a request instead of being forwarded as is.
+type Fault struct {
+	// Status answers the request with the status without forwarding it.
+	Status int
+	// RetryAfter sets the Retry-After header of the answer, in seconds.
+	RetryAfter int
+	// Delay holds the request before it is answered or forwarded.
+	Delay time.Duration
+	// Drop closes the connection without an answer.
+	Drop bool
+}
+
+// Server forwards the requests to a target server. The injected faults are
+// applied to the next requests, one fault per request.
+type Server struct {
+	*httptest.Server
+
+	proxy *httputil.ReverseProxy
+
+	mu       sync.Mutex
+	faults   []Fault
+	requests int
+}
+
+// NewServer returns a started Server in front of the target server. It is
+// closed by Close.
+func NewServer(target *url.URL) *Server {
+	s := &Server{proxy: httputil.NewSingleHostReverseProxy(target)}
+	// the client may give up on a delayed request
+	s.proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Inject queues faults for the next requests.
+func (s *Server) Inject(faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, faults...)
+}
+
+// Requests returns the number of requests received so far.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Pending returns the number of injected faults that were not applied yet.
+func (s *Server) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.faults)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	var f *Fault
+	if len(s.faults) > 0 {
+		f = &s.faults[0]
+		s.faults = s.faults[1:]
+	}
+	s.mu.Unlock()
+
+	if f == nil {
+		s.proxy.ServeHTTP(w, r)
+		return
+	}
+	if f.Delay > 0 {
+		select {
+		case <-time.After(f.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	switch {
+	case f.Drop:
+		conn, _, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			panic(err)
+		}
+		_ = conn.Close()
+	case f.Status != 0:
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(f.RetryAfter))
+		}
+		w.WriteHeader(f.Status)
+	default:
+		s.proxy.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package s3auth resolves the credentials of an S3 backend from its storage
+// secret, so that the blob driver and the osm config authenticate the same
+// way.
+package s3auth // import "kmodules.xyz/objectstore-api/pkg/s3auth"
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	awsconst "kmodules.xyz/constants/aws"
+	api "kmodules.xyz/objectstore-api/api/v1"
+)
+
+// file names of the shared credentials and config files in the directory
+// written by WriteSharedFiles
+const (
+	SharedCredentialsFileName = "credentials"
+	SharedConfigFileName      = "config"
+)
+
+// Credentials are the credentials of the storage secret of an S3 backend.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Expires is the expiry time of temporary access keys, zero when unknown
+	Expires time.Time
+
+	// SharedCredentials and SharedConfig are the content of shared
+	// credentials and config files, used instead of access keys
+	SharedCredentials []byte
+	SharedConfig      []byte
+	Profile           string
+}
+
+// FromSecret reads the Credentials of spec from the storage secret data. It
+// returns an ExpiredCredentialsError when the access keys are known to be
+// expired.
+func FromSecret(spec *api.S3Spec, data map[string][]byte) (*Credentials, error) {
+	c := &Credentials{
+		AccessKeyID:       string(data[awsconst.AWS_ACCESS_KEY_ID]),
+		SecretAccessKey:   string(data[awsconst.AWS_SECRET_ACCESS_KEY]),
+		SessionToken:      string(data[api.AWS_SESSION_TOKEN]),
+		SharedCredentials: data[api.AWS_SHARED_CREDENTIALS],
+		SharedConfig:      data[api.AWS_CONFIG],
+		Profile:           spec.Profile,
+	}
+	_, foundID := data[awsconst.AWS_ACCESS_KEY_ID]
+	_, foundKey := data[awsconst.AWS_SECRET_ACCESS_KEY]
+	switch {
+	case foundID && !foundKey:
+		return nil, fmt.Errorf("missing %s key", awsconst.AWS_SECRET_ACCESS_KEY)
+	case foundKey && !foundID:
+		return nil, fmt.Errorf("missing %s key", awsconst.AWS_ACCESS_KEY_ID)
+	case !foundID && c.SessionToken != "":
+		return nil, fmt.Errorf("%s requires %s and %s", api.AWS_SESSION_TOKEN, awsconst.AWS_ACCESS_KEY_ID, awsconst.AWS_SECRET_ACCESS_KEY)
+	}
+	if c.HasAccessKeys() && c.HasSharedFiles() {
+		return nil, fmt.Errorf("access keys may not be used together with %s or %s", api.AWS_SHARED_CREDENTIALS, api.AWS_CONFIG)
+	}
+	if c.Profile != "" && !c.HasSharedFiles() {
+		return nil, fmt.Errorf("profile %s requires %s or %s", c.Profile, api.AWS_SHARED_CREDENTIALS, api.AWS_CONFIG)
+	}
+
+	if v, ok := data[api.AWS_CREDENTIAL_EXPIRATION]; ok {
+		t, err := time.Parse(time.RFC3339, string(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", api.AWS_CREDENTIAL_EXPIRATION, err)
+		}
+		c.Expires = t
+	}
+	if err := c.CheckExpiry(time.Now()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// HasAccessKeys returns true if the storage secret has static access keys.
+func (c *Credentials) HasAccessKeys() bool {
+	return c.AccessKeyID != "" || c.SecretAccessKey != ""
+}
+
+// HasSharedFiles returns true if the storage secret has shared credentials
+// or config files.
+func (c *Credentials) HasSharedFiles() bool {
+	return len(c.SharedCredentials) > 0 || len(c.SharedConfig) > 0
+}
+
+// CheckExpiry returns an ExpiredCredentialsError if the access keys expired
+// before now.
+func (c *Credentials) CheckExpiry(now time.Time) error {
+	if c.Expires.IsZero() || now.Before(c.Expires) {
+		return nil
+	}
+	return &ExpiredCredentialsError{
+		Expires: c.Expires,
+		Err:     fmt.Errorf("%s is %s", api.AWS_CREDENTIAL_EXPIRATION, c.Expires.Format(time.RFC3339)),
+	}
+}
+
+// WriteSharedFiles writes the shared credentials and config files into a new
+// directory that only the current user can read. The caller must remove the
+// directory once the files are loaded.
+func (c *Credentials) WriteSharedFiles() (credentialsFile, configFile, dir string, err error) {
+	dir, err = os.MkdirTemp("", "s3auth-")
+	if err != nil {
+		return "", "", "", err
+	}
+	// the sdks expect both files, an empty file is the same as none
+	credentialsFile = filepath.Join(dir, SharedCredentialsFileName)
+	configFile = filepath.Join(dir, SharedConfigFileName)
+	if err = os.WriteFile(credentialsFile, c.SharedCredentials, 0o600); err == nil {
+		err = os.WriteFile(configFile, c.SharedConfig, 0o600)
+	}
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return "", "", "", err
+	}
+	return credentialsFile, configFile, dir, nil
+}
+
+// error codes of S3 and STS for expired session tokens
+var expiredCodes = map[string]bool{
+	"ExpiredToken":          true,
+	"ExpiredTokenException": true,
+	"TokenRefreshRequired":  true,
+}
+
+// IsExpiredCode returns true if code is an error code of S3 or STS for
+// expired credentials.
+func IsExpiredCode(code string) bool {
+	return expiredCodes[code]
+}
+
+// ExpiredCredentialsError is returned when the credentials of an S3 backend
+// are expired, either before they are used or when S3 rejects them.
+type ExpiredCredentialsError struct {
+	// Expires is the expiry time of the credentials, zero when unknown
+	Expires time.Time
+	Err     error
+}
+
+func (e *ExpiredCredentialsError) Error() string {
+	return "s3 credentials expired: " + e.Err.Error()
+}
+
+func (e *ExpiredCredentialsError) Unwrap() error {
+	return e.Err
+}
+
+// IsExpiredCredentials returns true if err is or wraps an
+// ExpiredCredentialsError.
+func IsExpiredCredentials(err error) bool {
+	var e *ExpiredCredentialsError
+	return errors.As(err, &e)
+}
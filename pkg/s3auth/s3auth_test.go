@@ -0,0 +1,146 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromSecret(t *testing.T) {
+	future := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	past := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	cases := []struct {
+		name    string
+		spec    api.S3Spec
+		data    map[string][]byte
+		want    *Credentials
+		wantErr string
+		expired bool
+	}{
+		{
+			name: "none",
+			want: &Credentials{},
+		},
+		{
+			name: "session token",
+			data: map[string][]byte{
+				api.AWS_ACCESS_KEY_ID:         []byte("id"),
+				api.AWS_SECRET_ACCESS_KEY:     []byte("key"),
+				api.AWS_SESSION_TOKEN:         []byte("token"),
+				api.AWS_CREDENTIAL_EXPIRATION: []byte(future.Format(time.RFC3339)),
+			},
+			want: &Credentials{AccessKeyID: "id", SecretAccessKey: "key", SessionToken: "token", Expires: future},
+		},
+		{
+			name: "profile",
+			spec: api.S3Spec{Profile: "backup"},
+			data: map[string][]byte{
+				api.AWS_SHARED_CREDENTIALS: []byte("[backup]"),
+			},
+			want: &Credentials{SharedCredentials: []byte("[backup]"), Profile: "backup"},
+		},
+		{
+			name: "missing secret key",
+			data: map[string][]byte{
+				api.AWS_ACCESS_KEY_ID: []byte("id"),
+			},
+			wantErr: "missing AWS_SECRET_ACCESS_KEY key",
+		},
+		{
+			name: "session token without keys",
+			data: map[string][]byte{
+				api.AWS_SESSION_TOKEN: []byte("token"),
+			},
+			wantErr: "AWS_SESSION_TOKEN requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY",
+		},
+		{
+			name: "keys and profile files",
+			data: map[string][]byte{
+				api.AWS_ACCESS_KEY_ID:     []byte("id"),
+				api.AWS_SECRET_ACCESS_KEY: []byte("key"),
+				api.AWS_CONFIG:            []byte("[default]"),
+			},
+			wantErr: "access keys may not be used together with AWS_SHARED_CREDENTIALS or AWS_CONFIG",
+		},
+		{
+			name:    "profile without files",
+			spec:    api.S3Spec{Profile: "backup"},
+			wantErr: "profile backup requires AWS_SHARED_CREDENTIALS or AWS_CONFIG",
+		},
+		{
+			name: "invalid expiration",
+			data: map[string][]byte{
+				api.AWS_ACCESS_KEY_ID:         []byte("id"),
+				api.AWS_SECRET_ACCESS_KEY:     []byte("key"),
+				api.AWS_CREDENTIAL_EXPIRATION: []byte("tomorrow"),
+			},
+			wantErr: `invalid AWS_CREDENTIAL_EXPIRATION: parsing time "tomorrow" as "2006-01-02T15:04:05Z07:00": cannot parse "tomorrow" as "2006"`,
+		},
+		{
+			name: "expired",
+			data: map[string][]byte{
+				api.AWS_ACCESS_KEY_ID:         []byte("id"),
+				api.AWS_SECRET_ACCESS_KEY:     []byte("key"),
+				api.AWS_SESSION_TOKEN:         []byte("token"),
+				api.AWS_CREDENTIAL_EXPIRATION: []byte(past.Format(time.RFC3339)),
+			},
+			wantErr: "s3 credentials expired: AWS_CREDENTIAL_EXPIRATION is " + past.Format(time.RFC3339),
+			expired: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := FromSecret(&tc.spec, tc.data)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				assert.Equal(t, tc.expired, IsExpiredCredentials(err))
+				return
+			}
+			if assert.Nil(t, err) {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestWriteSharedFiles(t *testing.T) {
+	c := &Credentials{SharedConfig: []byte("[default]\nregion = eu-west-1\n")}
+	credentialsFile, configFile, dir, err := c.WriteSharedFiles()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir) // nolint:errcheck
+
+	assert.Equal(t, filepath.Join(dir, SharedCredentialsFileName), credentialsFile)
+	data, err := os.ReadFile(configFile)
+	if assert.Nil(t, err) {
+		assert.Equal(t, c.SharedConfig, data)
+	}
+	for _, f := range []string{credentialsFile, configFile} {
+		info, err := os.Stat(f)
+		if assert.Nil(t, err) {
+			assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		}
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// sub-resources that are part of the string to sign
+//
+// ref: https://docs.aws.amazon.com/AmazonS3/latest/userguide/RESTAuthentication.html#ConstructingTheCanonicalizedResourceElement
+var sigV2SubResources = map[string]bool{
+	"acl":                          true,
+	"delete":                       true,
+	"lifecycle":                    true,
+	"location":                     true,
+	"logging":                      true,
+	"notification":                 true,
+	"partNumber":                   true,
+	"policy":                       true,
+	"requestPayment":               true,
+	"response-cache-control":       true,
+	"response-content-disposition": true,
+	"response-content-encoding":    true,
+	"response-content-language":    true,
+	"response-content-type":        true,
+	"response-expires":             true,
+	"tagging":                      true,
+	"torrent":                      true,
+	"uploadId":                     true,
+	"uploads":                      true,
+	"versionId":                    true,
+	"versioning":                   true,
+	"versions":                     true,
+	"website":                      true,
+}
+
+// SignV2 sets the Authorization header of req to a signature version 2 of
+// the access keys, for the S3 compatible services that do not support
+// version 4. req must have a Date header.
+func SignV2(req *http.Request, accessKeyID, secretAccessKey, sessionToken string) {
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+
+	var amzHeaders []string
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if strings.HasPrefix(k, "x-amz-") {
+			amzHeaders = append(amzHeaders, k+":"+strings.Join(v, ","))
+		}
+	}
+	sort.Strings(amzHeaders)
+
+	var subResources []string
+	for k, vals := range req.URL.Query() {
+		if !sigV2SubResources[k] {
+			continue
+		}
+		for _, v := range vals {
+			if v == "" {
+				subResources = append(subResources, k)
+			} else {
+				subResources = append(subResources, k+"="+v)
+			}
+		}
+	}
+	sort.Strings(subResources)
+	resource := req.URL.EscapedPath()
+	if len(subResources) > 0 {
+		resource += "?" + strings.Join(subResources, "&")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(req.Method + "\n")
+	sb.WriteString(req.Header.Get("Content-MD5") + "\n")
+	sb.WriteString(req.Header.Get("Content-Type") + "\n")
+	sb.WriteString(req.Header.Get("Date") + "\n")
+	for _, h := range amzHeaders {
+		sb.WriteString(h + "\n")
+	}
+	sb.WriteString(resource)
+
+	mac := hmac.New(sha1.New, []byte(secretAccessKey))
+	mac.Write([]byte(sb.String()))
+	req.Header.Set("Authorization", "AWS "+accessKeyID+":"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
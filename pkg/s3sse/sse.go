@@ -0,0 +1,110 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package s3sse resolves the server side encryption settings of an S3
+// backend, so that the blob driver and the osm config send the same
+// encryption headers.
+package s3sse // import "kmodules.xyz/objectstore-api/pkg/s3sse"
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+)
+
+// values of the x-amz-server-side-encryption and
+// x-amz-server-side-encryption-customer-algorithm headers
+const (
+	AlgorithmAES256 = "AES256"
+	AlgorithmKMS    = "aws:kms"
+)
+
+// Config is the resolved server side encryption of a backend.
+type Config struct {
+	Type api.S3EncryptionType
+	// ServerSideEncryption is sent as x-amz-server-side-encryption.
+	// It is empty for SSE-C.
+	ServerSideEncryption string
+	KMSKeyID             string
+	// KMSEncryptionContext is the base64 encoded json of the spec's
+	// encryption context, as expected by S3.
+	KMSEncryptionContext string
+	// CustomerKey is the 256 bit SSE-C key.
+	CustomerKey []byte
+}
+
+// ConfigFromSpec resolves enc. The SSE-C key is read from the storage secret
+// data. It returns nil when enc is nil.
+func ConfigFromSpec(enc *api.S3Encryption, data map[string][]byte) (*Config, error) {
+	if enc == nil {
+		return nil, nil
+	}
+	cfg := &Config{Type: enc.Type}
+	switch enc.Type {
+	case api.S3EncryptionSSES3:
+		cfg.ServerSideEncryption = AlgorithmAES256
+	case api.S3EncryptionSSEKMS:
+		cfg.ServerSideEncryption = AlgorithmKMS
+		cfg.KMSKeyID = enc.KMSKeyID
+		if len(enc.KMSEncryptionContext) > 0 {
+			js, err := json.Marshal(enc.KMSEncryptionContext)
+			if err != nil {
+				return nil, err
+			}
+			cfg.KMSEncryptionContext = base64.StdEncoding.EncodeToString(js)
+		}
+	case api.S3EncryptionSSEC:
+		key, err := customerKey(data[api.AWS_SSE_CUSTOMER_KEY])
+		if err != nil {
+			return nil, err
+		}
+		cfg.CustomerKey = key
+	default:
+		return nil, fmt.Errorf("unknown s3 encryption type %q", enc.Type)
+	}
+	return cfg, nil
+}
+
+// customerKey accepts the key as 32 raw bytes or as base64.
+func customerKey(val []byte) ([]byte, error) {
+	if len(val) == 0 {
+		return nil, fmt.Errorf("storage secret missing %s key required for %s", api.AWS_SSE_CUSTOMER_KEY, api.S3EncryptionSSEC)
+	}
+	if len(val) == 32 {
+		return val, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(string(val))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%s must be a 256 bit key, either raw or base64 encoded", api.AWS_SSE_CUSTOMER_KEY)
+	}
+	return key, nil
+}
+
+// CustomerKeyHeaders returns the values of the
+// x-amz-server-side-encryption-customer-{algorithm,key,key-MD5} headers.
+// They are empty unless the backend uses SSE-C.
+func (c *Config) CustomerKeyHeaders() (algorithm, key, keyMD5 string) {
+	if c == nil || len(c.CustomerKey) == 0 {
+		return "", "", ""
+	}
+	sum := md5.Sum(c.CustomerKey)
+	return AlgorithmAES256,
+		base64.StdEncoding.EncodeToString(c.CustomerKey),
+		base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,132 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// upload is a multipart upload in progress.
+type upload struct {
+	bucket string
+	key    string
+	header http.Header
+	parts  map[int][]byte
+}
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+type completeMultipartUpload struct {
+	Parts []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+	Bucket  string   `xml:"Bucket"`
+	Key     string   `xml:"Key"`
+	ETag    string   `xml:"ETag"`
+}
+
+// Uploads returns the number of multipart uploads that were neither
+// completed nor aborted.
+func (s *Server) Uploads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.uploads)
+}
+
+// serveMultipart serves the requests of multipart uploads, the bucket is
+// known to exist.
+func (s *Server) serveMultipart(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	q := r.URL.Query()
+	if r.Method == http.MethodPost && q.Has("uploads") {
+		s.lastID++
+		id := strconv.Itoa(s.lastID)
+		s.uploads[id] = &upload{bucket: bucket, key: key, header: storedHeader(r.Header), parts: map[int][]byte{}}
+		writeXML(w, initiateMultipartUploadResult{Bucket: bucket, Key: key, UploadID: id})
+		return
+	}
+	id := q.Get("uploadId")
+	up, ok := s.uploads[id]
+	if !ok || up.bucket != bucket || up.key != key {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist.")
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		if r.Header.Get("X-Amz-Copy-Source") != "" {
+			writeError(w, http.StatusNotImplemented, "NotImplemented", "copy is not supported")
+			return
+		}
+		n, err := strconv.Atoi(q.Get("partNumber"))
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "InvalidArgument", "Part number must be an integer between 1 and 10000.")
+			return
+		}
+		data, err := readBody(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+			return
+		}
+		up.parts[n] = data
+		sum := md5.Sum(data)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodPost:
+		var in completeMultipartUpload
+		if err := xml.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeError(w, http.StatusBadRequest, "MalformedXML", err.Error())
+			return
+		}
+		var data []byte
+		sums := md5.New()
+		for _, p := range in.Parts {
+			part, ok := up.parts[p.PartNumber]
+			if !ok {
+				writeError(w, http.StatusBadRequest, "InvalidPart", fmt.Sprintf("Part %d was not uploaded.", p.PartNumber))
+				return
+			}
+			data = append(data, part...)
+			sum := md5.Sum(part)
+			sums.Write(sum[:])
+		}
+		delete(s.uploads, id)
+		s.buckets[bucket][key] = &Object{Data: data, Header: up.header, ModTime: time.Now().UTC().Truncate(time.Second)}
+		// the etag of a multipart object is the md5 of the md5s of its parts
+		etag := fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(sums.Sum(nil)), len(in.Parts))
+		writeXML(w, completeMultipartUploadResult{Bucket: bucket, Key: key, ETag: etag})
+	case http.MethodDelete:
+		delete(s.uploads, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource.")
+	}
+}
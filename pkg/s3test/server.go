@@ -0,0 +1,461 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package s3test provides an in-memory stand-in for the S3 api that records
+// the requests it receives, so that S3 backends can be tested without
+// network access.
+package s3test // import "kmodules.xyz/objectstore-api/pkg/s3test"
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/pem"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// encryption headers stored with an object and returned on reads
+var encryptionHeaders = []string{
+	"X-Amz-Server-Side-Encryption",
+	"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id",
+	"X-Amz-Server-Side-Encryption-Customer-Algorithm",
+	"X-Amz-Server-Side-Encryption-Customer-Key-Md5",
+}
+
+// checksum headers stored with an object and returned when a read asks for
+// them with x-amz-checksum-mode
+var checksumHeaders = []string{
+	"X-Amz-Checksum-Crc32",
+	"X-Amz-Checksum-Crc32c",
+	"X-Amz-Checksum-Crc64nvme",
+	"X-Amz-Checksum-Sha1",
+	"X-Amz-Checksum-Sha256",
+}
+
+const (
+	headerCustomerKey    = "X-Amz-Server-Side-Encryption-Customer-Key"
+	headerCustomerKeyMD5 = "X-Amz-Server-Side-Encryption-Customer-Key-Md5"
+)
+
+// Request is a request received by the Server.
+type Request struct {
+	Method string
+	Bucket string
+	Key    string
+	Query  url.Values
+	Header http.Header
+}
+
+// Object is an object stored by the Server. Header holds the content type
+// and the server side encryption headers of the upload.
+type Object struct {
+	Data    []byte
+	Header  http.Header
+	ModTime time.Time
+}
+
+// Server is an S3 stand-in backed by memory. It serves path style requests,
+// does not verify signatures and, like S3, rejects reads of SSE-C objects
+// that do not carry the key they were written with.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	buckets  map[string]map[string]*Object
+	uploads  map[string]*upload
+	lastID   int
+	requests []Request
+	expired  map[string]bool
+}
+
+// NewServer starts a plain http Server.
+func NewServer() *Server {
+	s := &Server{buckets: map[string]map[string]*Object{}, uploads: map[string]*upload{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// NewTLSServer starts a Server with a self signed certificate, see CACert.
+// S3 clients only send SSE-C keys over https.
+func NewTLSServer() *Server {
+	s := &Server{buckets: map[string]map[string]*Object{}, uploads: map[string]*upload{}}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// CACert returns the PEM encoded certificate of a TLS server.
+func (s *Server) CACert() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+}
+
+// CreateBucket creates an empty bucket.
+func (s *Server) CreateBucket(bucket string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.buckets[bucket]; !ok {
+		s.buckets[bucket] = map[string]*Object{}
+	}
+}
+
+// Object returns an object of a bucket.
+func (s *Server) Object(bucket, key string) (*Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.buckets[bucket][key]
+	return obj, ok
+}
+
+// Keys returns the sorted keys of a bucket.
+func (s *Server) Keys(bucket string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0)
+	for k := range s.buckets[bucket] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ExpireSessionToken makes the Server reject requests signed with the
+// session token with an ExpiredToken error, like S3 does once the token
+// expired.
+func (s *Server) ExpireSessionToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.expired == nil {
+		s.expired = map[string]bool{}
+	}
+	s.expired[token] = true
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Reset forgets the recorded requests.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Bucket: bucket,
+		Key:    key,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+	})
+
+	if s.expired[r.Header.Get("X-Amz-Security-Token")] {
+		writeError(w, http.StatusBadRequest, "ExpiredToken", "The provided token has expired.")
+		return
+	}
+	if bucket == "" {
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "bucket listing is not supported")
+		return
+	}
+	if key == "" {
+		s.serveBucket(w, r, bucket)
+		return
+	}
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+
+	if q := r.URL.Query(); q.Has("uploads") || q.Has("uploadId") {
+		s.serveMultipart(w, r, bucket, key)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		if r.Header.Get("X-Amz-Copy-Source") != "" {
+			writeError(w, http.StatusNotImplemented, "NotImplemented", "copy is not supported")
+			return
+		}
+		data, err := readBody(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+			return
+		}
+		if ck := r.Header.Get(headerCustomerKey); ck != "" && r.Header.Get(headerCustomerKeyMD5) == "" {
+			writeError(w, http.StatusBadRequest, "InvalidArgument", "The customer key MD5 is missing")
+			return
+		}
+		obj := &Object{Data: data, Header: storedHeader(r.Header), ModTime: time.Now().UTC().Truncate(time.Second)}
+		objects[key] = obj
+		sum := md5.Sum(data)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		writeEncryptionHeaders(w, obj)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		obj, ok := objects[key]
+		if !ok {
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			writeError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+			return
+		}
+		if want := obj.Header.Get(headerCustomerKeyMD5); want != "" && r.Header.Get(headerCustomerKeyMD5) != want {
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			writeError(w, http.StatusBadRequest, "InvalidRequest", "The object was stored using a form of Server Side Encryption. The correct parameters must be provided to retrieve the object.")
+			return
+		}
+		sum := md5.Sum(obj.Data)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		w.Header().Set("Last-Modified", obj.ModTime.Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.Data)))
+		if ct := obj.Header.Get("Content-Type"); ct != "" {
+			w.Header().Set("Content-Type", ct)
+		}
+		writeEncryptionHeaders(w, obj)
+		if r.Header.Get("X-Amz-Checksum-Mode") == "ENABLED" {
+			for _, h := range checksumHeaders {
+				if v := obj.Header.Get(h); v != "" {
+					w.Header().Set(h, v)
+				}
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(obj.Data)
+		}
+	case http.MethodDelete:
+		delete(objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource.")
+	}
+}
+
+// storedHeader returns the headers of an upload that are stored with the
+// object.
+func storedHeader(h http.Header) http.Header {
+	out := http.Header{}
+	stored := append([]string{"Content-Type", "X-Amz-Server-Side-Encryption-Context"}, encryptionHeaders...)
+	for _, k := range append(stored, checksumHeaders...) {
+		if v := h.Get(k); v != "" {
+			out.Set(k, v)
+		}
+	}
+	return out
+}
+
+func writeEncryptionHeaders(w http.ResponseWriter, obj *Object) {
+	for _, h := range encryptionHeaders {
+		if v := obj.Header.Get(h); v != "" {
+			w.Header().Set(h, v)
+		}
+	}
+}
+
+func (s *Server) serveBucket(w http.ResponseWriter, r *http.Request, bucket string) {
+	objects, ok := s.buckets[bucket]
+	switch {
+	case r.Method == http.MethodPut:
+		if !ok {
+			s.buckets[bucket] = map[string]*Object{}
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	case !ok:
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	case r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+		return
+	case r.Method == http.MethodGet && r.URL.Query().Has("location"):
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"LocationConstraint"`
+		}{})
+		return
+	case r.Method == http.MethodGet:
+		s.listObjects(w, r, bucket, objects)
+		return
+	}
+	writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource.")
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name       `xml:"ListBucketResult"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	Delimiter             string         `xml:"Delimiter,omitempty"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	KeyCount              int            `xml:"KeyCount"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	Contents              []listObject   `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type listObject struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int    `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+func (s *Server) listObjects(w http.ResponseWriter, r *http.Request, bucket string, objects map[string]*Object) {
+	q := r.URL.Query()
+	prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+	// continuation tokens are the last returned key or common prefix
+	after := q.Get("start-after")
+	token := q.Get("continuation-token")
+	if p, ok := strings.CutPrefix(token, "p:"); ok {
+		after = p + "\xff"
+	} else if k, ok := strings.CutPrefix(token, "k:"); ok {
+		after = k
+	}
+	maxKeys := 1000
+	if v, err := strconv.Atoi(q.Get("max-keys")); err == nil && v > 0 && v < maxKeys {
+		maxKeys = v
+	}
+
+	keys := make([]string, 0, len(objects))
+	for k := range objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := listBucketResult{
+		Name:      bucket,
+		Prefix:    prefix,
+		Delimiter: delimiter,
+		MaxKeys:   maxKeys,
+	}
+	seen := map[string]bool{}
+	for _, k := range keys {
+		if !strings.HasPrefix(k, prefix) || k <= after {
+			continue
+		}
+		if out.KeyCount == maxKeys {
+			out.IsTruncated = true
+			break
+		}
+		if delimiter != "" {
+			if i := strings.Index(k[len(prefix):], delimiter); i >= 0 {
+				p := k[:len(prefix)+i+len(delimiter)]
+				if !seen[p] {
+					seen[p] = true
+					out.CommonPrefixes = append(out.CommonPrefixes, commonPrefix{Prefix: p})
+					out.KeyCount++
+					out.NextContinuationToken = "p:" + p
+				}
+				continue
+			}
+		}
+		sum := md5.Sum(objects[k].Data)
+		out.Contents = append(out.Contents, listObject{
+			Key:          k,
+			LastModified: objects[k].ModTime.Format(time.RFC3339),
+			ETag:         `"` + hex.EncodeToString(sum[:]) + `"`,
+			Size:         len(objects[k].Data),
+			StorageClass: "STANDARD",
+		})
+		out.KeyCount++
+		out.NextContinuationToken = "k:" + k
+	}
+	if !out.IsTruncated {
+		out.NextContinuationToken = ""
+	}
+	writeXML(w, out)
+}
+
+// readBody returns the request body, decoding the aws-chunked encoding used
+// by streaming signatures.
+func readBody(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+	var out bytes.Buffer
+	br := bufio.NewReader(r.Body)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chunk size %q", sizeHex)
+		}
+		if size == 0 {
+			// trailers follow the last chunk
+			return out.Bytes(), nil
+		}
+		if _, err := io.CopyN(&out, br, size); err != nil {
+			return nil, err
+		}
+		if _, err := br.ReadString('\n'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func writeXML(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}{Code: code, Message: message})
+}
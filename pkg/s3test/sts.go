@@ -0,0 +1,196 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// STSRequest is an AssumeRole or AssumeRoleWithWebIdentity call received by
+// the STSServer.
+type STSRequest struct {
+	Action string
+	Form   url.Values
+	Header http.Header
+}
+
+// Credentials are temporary credentials issued by the STSServer.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// STSServer is a stand-in for the AssumeRole and AssumeRoleWithWebIdentity
+// actions of STS. It issues new credentials on every call and, like STS,
+// rejects calls whose external id or web identity token does not match.
+// Signatures of AssumeRole calls are not verified.
+type STSServer struct {
+	*httptest.Server
+
+	// ExternalID is required by AssumeRole when it is not empty
+	ExternalID string
+	// WebIdentityToken is the only token accepted by AssumeRoleWithWebIdentity
+	WebIdentityToken string
+	// Duration is the lifetime of the issued credentials, 1h by default
+	Duration time.Duration
+
+	mu       sync.Mutex
+	issued   []Credentials
+	requests []STSRequest
+}
+
+// NewSTSServer starts a plain http STSServer.
+func NewSTSServer() *STSServer {
+	s := &STSServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Issued returns the credentials issued so far, in order.
+func (s *STSServer) Issued() []Credentials {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Credentials(nil), s.issued...)
+}
+
+// Requests returns the calls received so far, in order.
+func (s *STSServer) Requests() []STSRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]STSRequest(nil), s.requests...)
+}
+
+type stsCredentials struct {
+	AccessKeyID     string `xml:"AccessKeyId"`
+	SecretAccessKey string `xml:"SecretAccessKey"`
+	SessionToken    string `xml:"SessionToken"`
+	Expiration      string `xml:"Expiration"`
+}
+
+type stsAssumedRoleUser struct {
+	Arn           string `xml:"Arn"`
+	AssumedRoleID string `xml:"AssumedRoleId"`
+}
+
+type stsResult struct {
+	XMLName         xml.Name
+	Credentials     stsCredentials     `xml:"Credentials"`
+	AssumedRoleUser stsAssumedRoleUser `xml:"AssumedRoleUser"`
+}
+
+func (s *STSServer) serve(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeSTSError(w, http.StatusBadRequest, "InvalidParameterValue", err.Error())
+		return
+	}
+	action := r.Form.Get("Action")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, STSRequest{
+		Action: action,
+		Form:   r.Form,
+		Header: r.Header.Clone(),
+	})
+
+	roleARN := r.Form.Get("RoleArn")
+	if roleARN == "" {
+		writeSTSError(w, http.StatusBadRequest, "MissingParameter", "RoleArn is required")
+		return
+	}
+	switch action {
+	case "AssumeRole":
+		if r.Header.Get("Authorization") == "" {
+			writeSTSError(w, http.StatusForbidden, "MissingAuthenticationToken", "AssumeRole requires signed requests")
+			return
+		}
+		if s.ExternalID != "" && r.Form.Get("ExternalId") != s.ExternalID {
+			writeSTSError(w, http.StatusForbidden, "AccessDenied", "external id does not match the trust policy of "+roleARN)
+			return
+		}
+	case "AssumeRoleWithWebIdentity":
+		token := r.Form.Get("WebIdentityToken")
+		if token == "" || (s.WebIdentityToken != "" && token != s.WebIdentityToken) {
+			writeSTSError(w, http.StatusBadRequest, "InvalidIdentityToken", "the web identity token is not valid")
+			return
+		}
+	default:
+		writeSTSError(w, http.StatusBadRequest, "InvalidAction", fmt.Sprintf("action %q is not supported", action))
+		return
+	}
+
+	n := len(s.issued) + 1
+	creds := Credentials{
+		AccessKeyID:     fmt.Sprintf("ASIATEST%08d", n),
+		SecretAccessKey: fmt.Sprintf("secret-%d", n),
+		SessionToken:    fmt.Sprintf("session-token-%d", n),
+	}
+	s.issued = append(s.issued, creds)
+
+	duration := s.Duration
+	if duration == 0 {
+		duration = time.Hour
+	}
+	result := stsResult{
+		XMLName: xml.Name{Local: action + "Result"},
+		Credentials: stsCredentials{
+			AccessKeyID:     creds.AccessKeyID,
+			SecretAccessKey: creds.SecretAccessKey,
+			SessionToken:    creds.SessionToken,
+			Expiration:      time.Now().Add(duration).UTC().Format(time.RFC3339),
+		},
+		AssumedRoleUser: stsAssumedRoleUser{
+			Arn:           roleARN + "/" + r.Form.Get("RoleSessionName"),
+			AssumedRoleID: fmt.Sprintf("AROATEST:%d", n),
+		},
+	}
+	writeXML(w, struct {
+		XMLName  xml.Name
+		Xmlns    string `xml:"xmlns,attr"`
+		Result   stsResult
+		Metadata struct {
+			RequestID string `xml:"RequestId"`
+		} `xml:"ResponseMetadata"`
+	}{
+		XMLName: xml.Name{Local: action + "Response"},
+		Xmlns:   "https://sts.amazonaws.com/doc/2011-06-15/",
+		Result:  result,
+	})
+}
+
+type stsError struct {
+	Type    string `xml:"Type"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func writeSTSError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"ErrorResponse"`
+		Error   stsError `xml:"Error"`
+	}{Error: stsError{Type: "Sender", Code: code, Message: message}})
+}
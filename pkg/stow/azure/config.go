@@ -0,0 +1,139 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the azure location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+// Package azure provides the stow location of azure backends that the osm
+// package dials. Unlike gomodules.xyz/stow/azure, it authenticates with a
+// SAS token or a workload or managed identity as well as with an account
+// key, talks to the endpoint of sovereign clouds and emulators, and sends its
+// requests with the TLS, proxy and connection limit settings of the backend.
+// It registers the azure kind with stow in place of gomodules.xyz/stow/azure,
+// as the first registration of a kind wins, importing both packages panics.
+package azure // import "kmodules.xyz/objectstore-api/pkg/stow/azure"
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+
+	"kmodules.xyz/objectstore-api/pkg/azureauth"
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+	"kmodules.xyz/objectstore-api/pkg/retry"
+	"kmodules.xyz/objectstore-api/pkg/stow/internal/stowhttp"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+// Kind represents the name of the location/storage type.
+const Kind = "azure"
+
+const (
+	// ConfigAccount is the name of the storage account.
+	ConfigAccount = "account"
+
+	// ConfigKey is an optional account key.
+	ConfigKey = "key"
+
+	// ConfigEndpoint is the optional url of the blob service, it defaults to
+	// the one of the account in the public cloud.
+	ConfigEndpoint = "endpoint"
+
+	// ConfigSASToken is an optional shared access signature, used when there
+	// is no account key.
+	ConfigSASToken = "sas_token"
+
+	// ConfigClientID selects the workload or managed identity that is used
+	// when there is neither an account key nor a SAS token.
+	ConfigClientID = "client_id"
+)
+
+// The TLS settings are read from the keys of package tlsconfig, and the proxy
+// from those of package httpproxy.
+
+func init() {
+	for _, kind := range stow.Kinds() {
+		if kind == Kind {
+			panic("stow kind azure is registered already, gomodules.xyz/stow/azure must not be imported together with kmodules.xyz/objectstore-api/pkg/stow/azure")
+		}
+	}
+	validatefn := func(config stow.Config) error {
+		if v, ok := config.Config(ConfigAccount); !ok || v == "" {
+			return errors.New("missing account id")
+		}
+		if v, ok := config.Config(ConfigEndpoint); ok && v != "" {
+			if u, err := url.Parse(v); err != nil || u.Host == "" {
+				return errors.New("invalid endpoint")
+			}
+		}
+		return nil
+	}
+	makefn := func(config stow.Config) (stow.Location, error) {
+		if err := validatefn(config); err != nil {
+			return nil, err
+		}
+		limiter, err := stowhttp.Limiter(config)
+		if err != nil {
+			return nil, err
+		}
+		cfg := authConfig(config)
+		client, err := newClient(cfg, config, limiter)
+		if err != nil {
+			limiter.Release()
+			return nil, err
+		}
+		endpoint, _ := url.Parse(cfg.Endpoint)
+		return &location{client: client, endpoint: endpoint, release: sync.OnceFunc(limiter.Release)}, nil
+	}
+	kindfn := func(u *url.URL) bool {
+		return u.Scheme == Kind
+	}
+	stow.Register(Kind, makefn, kindfn, validatefn)
+}
+
+// authConfig returns the azureauth.Config of config.
+func authConfig(config stow.Config) *azureauth.Config {
+	cfg := &azureauth.Config{}
+	cfg.AccountName, _ = config.Config(ConfigAccount)
+	cfg.AccountKey, _ = config.Config(ConfigKey)
+	cfg.SASToken, _ = config.Config(ConfigSASToken)
+	cfg.ClientID, _ = config.Config(ConfigClientID)
+	cfg.Endpoint, _ = config.Config(ConfigEndpoint)
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://" + cfg.AccountName + "." + azureauth.DefaultDomain
+	}
+	return cfg
+}
+
+func newClient(cfg *azureauth.Config, config stow.Config, limiter *ratelimit.Limiter) (*service.Client, error) {
+	hc, err := stowhttp.NewClient(config, limiter)
+	if err != nil {
+		return nil, err
+	}
+	cfg.HTTPClient = hc
+	p, err := retry.FromStowConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	// the calls are retried by the osm package
+	cfg.DisableRetries = p != nil
+	return cfg.NewServiceClient()
+}
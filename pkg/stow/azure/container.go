@@ -0,0 +1,218 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the azure location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+package azure
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	azcontainer "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gomodules.xyz/pointer"
+	"gomodules.xyz/stow"
+)
+
+type container struct {
+	id     string
+	client *azcontainer.Client
+	// endpoint is the url of the blob service, without the SAS token
+	endpoint *url.URL
+}
+
+var _ stow.Container = &container{}
+
+// ID returns the name of the container.
+func (c *container) ID() string {
+	return c.id
+}
+
+// Name returns the name of the container.
+func (c *container) Name() string {
+	return c.id
+}
+
+// Item returns the blob with the given name.
+func (c *container) Item(id string) (stow.Item, error) {
+	props, err := c.client.NewBlobClient(id).GetProperties(context.Background(), nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return nil, stow.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Item, getting the blob properties")
+	}
+	i := &item{
+		container: c,
+		name:      id,
+		size:      pointer.Int64(props.ContentLength),
+		lastMod:   pointer.Time(props.LastModified),
+		metadata:  parseMetadata(props.Metadata),
+	}
+	if props.ETag != nil {
+		i.etag = cleanEtag(string(*props.ETag))
+	}
+	return i, nil
+}
+
+// Browse lists the blobs that start with prefix. With a delimiter, the
+// common prefixes are returned separately.
+func (c *container) Browse(prefix, delimiter, cursor string, count int) (*stow.ItemPage, error) {
+	var marker, maxResults = pointer.StringP(cursor), pointer.Int32P(int32(count))
+	if cursor == "" {
+		marker = nil
+	}
+	if count <= 0 {
+		maxResults = nil
+	}
+	var (
+		blobs    []*azcontainer.BlobItem
+		prefixes []string
+		next     *string
+	)
+	if delimiter == "" {
+		page, err := c.client.NewListBlobsFlatPager(&azcontainer.ListBlobsFlatOptions{
+			Prefix:     pointer.StringP(prefix),
+			Marker:     marker,
+			MaxResults: maxResults,
+		}).NextPage(context.Background())
+		if err != nil {
+			return nil, errors.Wrap(err, "Browse, listing blobs")
+		}
+		if page.Segment != nil {
+			blobs = page.Segment.BlobItems
+		}
+		next = page.NextMarker
+	} else {
+		page, err := c.client.NewListBlobsHierarchyPager(delimiter, &azcontainer.ListBlobsHierarchyOptions{
+			Prefix:     pointer.StringP(prefix),
+			Marker:     marker,
+			MaxResults: maxResults,
+		}).NextPage(context.Background())
+		if err != nil {
+			return nil, errors.Wrap(err, "Browse, listing blobs")
+		}
+		if page.Segment != nil {
+			blobs = page.Segment.BlobItems
+			for _, p := range page.Segment.BlobPrefixes {
+				prefixes = append(prefixes, pointer.String(p.Name))
+			}
+		}
+		next = page.NextMarker
+	}
+
+	items := make([]stow.Item, 0, len(blobs))
+	for _, b := range blobs {
+		i := &item{container: c, name: pointer.String(b.Name)}
+		if b.Properties != nil {
+			i.size = pointer.Int64(b.Properties.ContentLength)
+			i.lastMod = pointer.Time(b.Properties.LastModified)
+			if b.Properties.ETag != nil {
+				i.etag = cleanEtag(string(*b.Properties.ETag))
+			}
+		}
+		items = append(items, i)
+	}
+	return &stow.ItemPage{Prefixes: prefixes, Items: items, Cursor: pointer.String(next)}, nil
+}
+
+// Items lists the blobs that start with prefix.
+func (c *container) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	page, err := c.Browse(prefix, "", cursor, count)
+	if err != nil {
+		return nil, "", err
+	}
+	return page.Items, page.Cursor, nil
+}
+
+// Put uploads the content of r as a block blob. The values of metadata must
+// be strings.
+func (c *container) Put(name string, r io.Reader, size int64, metadata map[string]any) (stow.Item, error) {
+	md, err := prepMetadata(metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "Put, preparing metadata")
+	}
+	resp, err := c.client.NewBlockBlobClient(name).UploadStream(context.Background(), r, &blockblob.UploadStreamOptions{
+		Metadata: md,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Put, uploading the blob")
+	}
+	i := &item{
+		container: c,
+		name:      name,
+		size:      size,
+		lastMod:   pointer.Time(resp.LastModified),
+		metadata:  parseMetadata(md),
+	}
+	if resp.ETag != nil {
+		i.etag = cleanEtag(string(*resp.ETag))
+	}
+	return i, nil
+}
+
+// RemoveItem deletes the blob with the given name.
+func (c *container) RemoveItem(id string) error {
+	_, err := c.client.NewBlobClient(id).Delete(context.Background(), nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return stow.ErrNotFound
+	}
+	return errors.Wrapf(err, "RemoveItem, deleting blob %s", id)
+}
+
+// HasWriteAccess stores and removes a blob in the .trash directory.
+func (c *container) HasWriteAccess() error {
+	r := bytes.NewReader([]byte("CheckBucketAccess"))
+	item, err := c.Put(".trash/"+uuid.New().String(), r, r.Size(), nil)
+	if err != nil {
+		return err
+	}
+	return c.RemoveItem(item.ID())
+}
+
+func prepMetadata(md map[string]any) (map[string]*string, error) {
+	out := make(map[string]*string, len(md))
+	for k, v := range md {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.Errorf("value of key '%s' in metadata must be of type string", k)
+		}
+		out[k] = pointer.StringP(s)
+	}
+	return out, nil
+}
+
+func parseMetadata(md map[string]*string) map[string]any {
+	out := make(map[string]any, len(md))
+	for k, v := range md {
+		out[strings.ToLower(k)] = pointer.String(v)
+	}
+	return out
+}
+
+// cleanEtag strips the quotes and the weak prefix of an ETag.
+func cleanEtag(etag string) string {
+	return strings.Trim(strings.TrimPrefix(etag, "W/"), `"`)
+}
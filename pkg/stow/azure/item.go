@@ -0,0 +1,121 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the azure location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+package azure
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+type item struct {
+	container *container
+	name      string
+	size      int64
+	etag      string
+	lastMod   time.Time
+	// metadata is nil for the items of a listing, it is then read once
+	// with the properties of the blob
+	metadata map[string]any
+	infoOnce sync.Once
+	infoErr  error
+}
+
+var (
+	_ stow.Item       = &item{}
+	_ stow.ItemRanger = &item{}
+)
+
+// ID returns the name of the blob.
+func (i *item) ID() string {
+	return i.name
+}
+
+// Name returns the name of the blob.
+func (i *item) Name() string {
+	return i.name
+}
+
+// URL returns the url of the blob with the azure scheme, see
+// location.ItemByURL.
+func (i *item) URL() *url.URL {
+	u := i.container.endpoint.JoinPath(i.container.id, i.name)
+	u.Scheme = Kind
+	return u
+}
+
+// Size returns the size of the blob in bytes.
+func (i *item) Size() (int64, error) {
+	return i.size, nil
+}
+
+// Open downloads the blob.
+func (i *item) Open() (io.ReadCloser, error) {
+	return i.download(blob.HTTPRange{})
+}
+
+// OpenRange opens the blob for reading starting at byte start and ending at
+// byte end.
+func (i *item) OpenRange(start, end uint64) (io.ReadCloser, error) {
+	return i.download(blob.HTTPRange{Offset: int64(start), Count: int64(end - start + 1)})
+}
+
+func (i *item) download(r blob.HTTPRange) (io.ReadCloser, error) {
+	resp, err := i.container.client.NewBlobClient(i.name).DownloadStream(context.Background(), &blob.DownloadStreamOptions{
+		Range: r,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Open, downloading the blob")
+	}
+	return resp.Body, nil
+}
+
+// ETag returns the ETag of the blob without quotes.
+func (i *item) ETag() (string, error) {
+	return i.etag, nil
+}
+
+// LastMod returns the last modified date of the blob.
+func (i *item) LastMod() (time.Time, error) {
+	return i.lastMod, nil
+}
+
+// Metadata returns the metadata of the blob.
+func (i *item) Metadata() (map[string]any, error) {
+	i.infoOnce.Do(func() {
+		if i.metadata != nil {
+			return
+		}
+		props, err := i.container.client.NewBlobClient(i.name).GetProperties(context.Background(), nil)
+		if err != nil {
+			i.infoErr = errors.Wrap(err, "retrieving metadata")
+			return
+		}
+		i.metadata = parseMetadata(props.Metadata)
+	})
+	return i.metadata, i.infoErr
+}
@@ -0,0 +1,131 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the azure location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/pkg/errors"
+	"gomodules.xyz/pointer"
+	"gomodules.xyz/stow"
+)
+
+// A location holds the containers of a storage account.
+type location struct {
+	client *service.Client
+	// endpoint is the url of the blob service, without the SAS token
+	endpoint *url.URL
+	// release releases the limiter of the backend once
+	release func()
+}
+
+func (l *location) container(name string) *container {
+	return &container{id: name, client: l.client.NewContainerClient(name), endpoint: l.endpoint}
+}
+
+// CreateContainer creates a private container, or returns the container
+// when it exists.
+func (l *location) CreateContainer(name string) (stow.Container, error) {
+	_, err := l.client.CreateContainer(context.Background(), name, nil)
+	if bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		return l.Container(name)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "CreateContainer, creating the container")
+	}
+	return l.container(name), nil
+}
+
+// Containers lists the containers whose name starts with prefix.
+func (l *location) Containers(prefix, cursor string, count int) ([]stow.Container, string, error) {
+	opts := &service.ListContainersOptions{Prefix: pointer.StringP(prefix)}
+	if count > 0 {
+		opts.MaxResults = pointer.Int32P(int32(count))
+	}
+	if cursor != stow.CursorStart {
+		opts.Marker = pointer.StringP(cursor)
+	}
+	page, err := l.client.NewListContainersPager(opts).NextPage(context.Background())
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Containers, listing the containers")
+	}
+	containers := make([]stow.Container, 0, len(page.ContainerItems))
+	for _, c := range page.ContainerItems {
+		containers = append(containers, l.container(*c.Name))
+	}
+	return containers, pointer.String(page.NextMarker), nil
+}
+
+// Container returns the container with the given name.
+func (l *location) Container(id string) (stow.Container, error) {
+	c := l.container(id)
+	_, err := c.client.GetProperties(context.Background(), nil)
+	if bloberror.HasCode(err, bloberror.ContainerNotFound) {
+		return nil, stow.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Container, getting the container")
+	}
+	return c, nil
+}
+
+// RemoveContainer deletes the container with the given name.
+func (l *location) RemoveContainer(id string) error {
+	_, err := l.client.DeleteContainer(context.Background(), id, nil)
+	if bloberror.HasCode(err, bloberror.ContainerNotFound) {
+		return stow.ErrNotFound
+	}
+	return errors.Wrapf(err, "RemoveContainer, deleting container %s", id)
+}
+
+// ItemByURL retrieves an item from the url of the blob with the azure
+// scheme, see item.URL.
+func (l *location) ItemByURL(u *url.URL) (stow.Item, error) {
+	if u.Scheme != Kind {
+		return nil, errors.New("not valid azure URL")
+	}
+	if u.Host != l.endpoint.Host {
+		return nil, errors.New("wrong azure URL")
+	}
+	path, ok := strings.CutPrefix(u.Path, l.endpoint.Path+"/")
+	if !ok {
+		return nil, errors.New("wrong azure URL")
+	}
+	params := strings.SplitN(path, "/", 2)
+	if len(params) != 2 {
+		return nil, errors.New("wrong path")
+	}
+	c, err := l.Container(params[0])
+	if err != nil {
+		return nil, err
+	}
+	return c.Item(params[1])
+}
+
+// Close releases the limiter of the backend.
+func (l *location) Close() error {
+	l.release()
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package b2 registers a stow location kind for Backblaze B2.
+package b2 // import "kmodules.xyz/objectstore-api/pkg/stow/b2"
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"kmodules.xyz/objectstore-api/pkg/b2"
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+	"kmodules.xyz/objectstore-api/pkg/stow/internal/stowhttp"
+
+	"gomodules.xyz/stow"
+)
+
+// Kind represents the name of the location/storage type.
+const Kind = "b2"
+
+const (
+	// ConfigAccountID is the account ID or application key ID.
+	ConfigAccountID = "account_id"
+
+	// ConfigApplicationKey is the application key.
+	ConfigApplicationKey = "application_key"
+
+	// ConfigAuthURL is an optional config value to override the
+	// b2_authorize_account endpoint.
+	ConfigAuthURL = "auth_url"
+
+	// ConfigMaxConnections is an optional config value that limits the
+	// number of parts of a large file that are uploaded concurrently. The
+	// requests are limited by the ratelimit.ConfigMaxConnections key.
+	ConfigMaxConnections = "max_connections"
+)
+
+// The TLS settings are read from the keys of package tlsconfig, and the proxy
+// from those of package httpproxy.
+
+func init() {
+	validatefn := func(config stow.Config) error {
+		if v, ok := config.Config(ConfigAccountID); !ok || v == "" {
+			return errors.New("missing account id")
+		}
+		if v, ok := config.Config(ConfigApplicationKey); !ok || v == "" {
+			return errors.New("missing application key")
+		}
+		if v, ok := config.Config(ConfigMaxConnections); ok && v != "" {
+			if _, err := strconv.Atoi(v); err != nil {
+				return errors.New("invalid max_connections")
+			}
+		}
+		return nil
+	}
+	makefn := func(config stow.Config) (stow.Location, error) {
+		if err := validatefn(config); err != nil {
+			return nil, err
+		}
+		limiter, err := stowhttp.Limiter(config)
+		if err != nil {
+			return nil, err
+		}
+		client, err := newClient(config, limiter)
+		if err != nil {
+			limiter.Release()
+			return nil, err
+		}
+		return &location{client: client, release: sync.OnceFunc(limiter.Release)}, nil
+	}
+	kindfn := func(u *url.URL) bool {
+		return u.Scheme == Kind
+	}
+	stow.Register(Kind, makefn, kindfn, validatefn)
+}
+
+func newClient(config stow.Config, limiter *ratelimit.Limiter) (*b2.Client, error) {
+	opts := b2.Options{}
+	opts.AccountID, _ = config.Config(ConfigAccountID)
+	opts.ApplicationKey, _ = config.Config(ConfigApplicationKey)
+	opts.AuthURL, _ = config.Config(ConfigAuthURL)
+	if v, ok := config.Config(ConfigMaxConnections); ok && v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		opts.MaxConnections = n
+	}
+	hc, err := stowhttp.NewClient(config, limiter)
+	if err != nil {
+		return nil, err
+	}
+	opts.HTTPClient = hc
+	return b2.NewClient(context.Background(), opts)
+}
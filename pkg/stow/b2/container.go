@@ -0,0 +1,137 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package b2
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"kmodules.xyz/objectstore-api/pkg/b2"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+type container struct {
+	bucket *b2.Bucket
+}
+
+// ID returns the name of the bucket.
+func (c *container) ID() string {
+	return c.bucket.Name()
+}
+
+// Name returns the name of the bucket.
+func (c *container) Name() string {
+	return c.bucket.Name()
+}
+
+// Item returns the latest version of the named file.
+func (c *container) Item(id string) (stow.Item, error) {
+	info, err := c.bucket.Head(context.Background(), id)
+	if err != nil {
+		if b2.IsNotFound(err) {
+			return nil, stow.ErrNotFound
+		}
+		return nil, errors.Wrap(err, "Item, getting the file")
+	}
+	return &item{container: c, info: *info}, nil
+}
+
+// Browse lists the files that start with prefix. With a delimiter, the
+// common prefixes are returned separately. The cursor is the name of the
+// first file of the page.
+func (c *container) Browse(prefix, delimiter, cursor string, count int) (*stow.ItemPage, error) {
+	out, err := c.bucket.ListFileNames(context.Background(), b2.ListOptions{
+		Prefix:        prefix,
+		Delimiter:     delimiter,
+		StartFileName: cursor,
+		MaxFileCount:  count,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Browse, listing files")
+	}
+	page := &stow.ItemPage{Cursor: out.NextFileName}
+	for _, f := range out.Files {
+		if f.Action == b2.ActionFolder {
+			page.Prefixes = append(page.Prefixes, f.FileName)
+			continue
+		}
+		page.Items = append(page.Items, &item{container: c, info: f})
+	}
+	return page, nil
+}
+
+// Items lists the files that start with prefix.
+func (c *container) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	page, err := c.Browse(prefix, "", cursor, count)
+	if err != nil {
+		return nil, "", err
+	}
+	return page.Items, page.Cursor, nil
+}
+
+// RemoveItem deletes every version of the named file.
+func (c *container) RemoveItem(id string) error {
+	if err := c.bucket.DeleteFile(context.Background(), id); err != nil {
+		if b2.IsNotFound(err) {
+			return stow.ErrNotFound
+		}
+		return errors.Wrapf(err, "RemoveItem, deleting file %s", id)
+	}
+	return nil
+}
+
+// Put uploads the content of r. Content larger than the part size is
+// uploaded as a large file.
+func (c *container) Put(name string, r io.Reader, size int64, metadata map[string]any) (stow.Item, error) {
+	info, err := prepMetadata(metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create or update item, preparing metadata")
+	}
+	w := c.bucket.NewWriter(context.Background(), name, b2.UploadOptions{Info: info})
+	if _, err := io.Copy(w, r); err != nil {
+		return nil, errors.Wrap(w.Abort(err), "Put, uploading file")
+	}
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrap(err, "Put, uploading file")
+	}
+	return &item{container: c, info: *w.Info()}, nil
+}
+
+func (c *container) HasWriteAccess() error {
+	r := bytes.NewReader([]byte("CheckBucketAccess"))
+	item, err := c.Put(".trash/"+uuid.New().String(), r, r.Size(), nil)
+	if err != nil {
+		return err
+	}
+	return c.RemoveItem(item.ID())
+}
+
+func prepMetadata(md map[string]any) (map[string]string, error) {
+	m := make(map[string]string, len(md))
+	for key, value := range md {
+		str, ok := value.(string)
+		if !ok {
+			return nil, errors.Errorf(`value of key '%s' in metadata must be of type string`, key)
+		}
+		m[key] = str
+	}
+	return m, nil
+}
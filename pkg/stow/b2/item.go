@@ -0,0 +1,89 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package b2
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"time"
+
+	"kmodules.xyz/objectstore-api/pkg/b2"
+
+	"github.com/pkg/errors"
+)
+
+type item struct {
+	container *container
+	info      b2.FileInfo
+}
+
+// ID returns the name of the file.
+func (i *item) ID() string {
+	return i.info.FileName
+}
+
+// Name returns the name of the file.
+func (i *item) Name() string {
+	return i.info.FileName
+}
+
+// URL returns b2://<bucket>/<file>.
+func (i *item) URL() *url.URL {
+	return &url.URL{
+		Scheme: Kind,
+		Host:   i.container.Name(),
+		Path:   "/" + i.info.FileName,
+	}
+}
+
+// Size returns the size of the file in bytes.
+func (i *item) Size() (int64, error) {
+	return i.info.ContentLength, nil
+}
+
+// Open downloads the file.
+func (i *item) Open() (io.ReadCloser, error) {
+	r, _, err := i.container.bucket.Download(context.Background(), i.info.FileName, 0, -1)
+	if err != nil {
+		return nil, errors.Wrap(err, "Open, downloading the file")
+	}
+	return r, nil
+}
+
+// ETag returns the SHA1 of the content, or the file id for large files
+// which have no SHA1 of their own.
+func (i *item) ETag() (string, error) {
+	if i.info.ContentSHA1 != "" && i.info.ContentSHA1 != "none" {
+		return i.info.ContentSHA1, nil
+	}
+	return i.info.FileID, nil
+}
+
+// LastMod returns the upload time of the file.
+func (i *item) LastMod() (time.Time, error) {
+	return i.info.ModTime(), nil
+}
+
+// Metadata returns the custom file info.
+func (i *item) Metadata() (map[string]any, error) {
+	md := make(map[string]any, len(i.info.Info))
+	for k, v := range i.info.Info {
+		md[k] = v
+	}
+	return md, nil
+}
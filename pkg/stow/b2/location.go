@@ -0,0 +1,126 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package b2
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"kmodules.xyz/objectstore-api/pkg/b2"
+
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+// A location contains a client for a single B2 account.
+type location struct {
+	client *b2.Client
+	// release releases the limiter of the backend once
+	release func()
+}
+
+// CreateContainer creates a new private bucket.
+func (l *location) CreateContainer(name string) (stow.Container, error) {
+	b, err := l.client.CreateBucket(context.Background(), name, b2.BucketTypePrivate)
+	if err != nil {
+		return nil, errors.Wrap(err, "CreateContainer, creating the bucket")
+	}
+	return &container{bucket: b}, nil
+}
+
+// Containers lists the buckets of the account whose name starts with prefix.
+// The cursor is the name of the first bucket of the page.
+func (l *location) Containers(prefix, cursor string, count int) ([]stow.Container, string, error) {
+	buckets, err := l.client.ListBuckets(context.Background(), "")
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Containers, listing the buckets")
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Name() < buckets[j].Name()
+	})
+
+	if cursor != stow.CursorStart {
+		i := sort.Search(len(buckets), func(i int) bool {
+			return buckets[i].Name() >= cursor
+		})
+		if i == len(buckets) || buckets[i].Name() != cursor {
+			return nil, "", stow.ErrBadCursor
+		}
+		buckets = buckets[i:]
+	}
+
+	var containers []stow.Container
+	cursor = ""
+	for _, b := range buckets {
+		if !strings.HasPrefix(b.Name(), prefix) {
+			continue
+		}
+		if len(containers) == count {
+			cursor = b.Name()
+			break
+		}
+		containers = append(containers, &container{bucket: b})
+	}
+	return containers, cursor, nil
+}
+
+// Container retrieves a bucket by its name.
+func (l *location) Container(id string) (stow.Container, error) {
+	b, err := l.client.Bucket(context.Background(), id)
+	if err != nil {
+		if b2.IsNotFound(err) {
+			return nil, stow.ErrNotFound
+		}
+		return nil, errors.Wrap(err, "Container, getting the bucket")
+	}
+	return &container{bucket: b}, nil
+}
+
+// RemoveContainer removes an empty bucket by name.
+func (l *location) RemoveContainer(id string) error {
+	b, err := l.client.Bucket(context.Background(), id)
+	if err != nil {
+		if b2.IsNotFound(err) {
+			return stow.ErrNotFound
+		}
+		return errors.Wrap(err, "RemoveContainer, getting the bucket")
+	}
+	if err := b.Delete(context.Background()); err != nil {
+		return errors.Wrap(err, "RemoveContainer, deleting the bucket")
+	}
+	return nil
+}
+
+// ItemByURL retrieves an item from a URL of the form b2://<bucket>/<file>.
+func (l *location) ItemByURL(u *url.URL) (stow.Item, error) {
+	if u.Scheme != Kind {
+		return nil, errors.New("not valid b2 URL")
+	}
+	c, err := l.Container(u.Host)
+	if err != nil {
+		return nil, err
+	}
+	return c.Item(strings.TrimPrefix(u.Path, "/"))
+}
+
+// Close releases the limiter of the backend.
+func (l *location) Close() error {
+	l.release()
+	return nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the google location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+// Package gcs provides the stow location of gcs backends that the osm
+// package dials. Unlike gomodules.xyz/stow/google, it reads every credential
+// file of package gcsauth, eg. of workload identity federation, impersonates
+// service accounts and sends its requests with the TLS, proxy and connection
+// limit settings of the backend. Like the clients of
+// cloud.google.com/go/storage, it talks to the emulator at
+// STORAGE_EMULATOR_HOST when that is set. It registers the google kind with
+// stow in place of gomodules.xyz/stow/google, as the first registration of a
+// kind wins, importing both packages panics.
+package gcs // import "kmodules.xyz/objectstore-api/pkg/stow/gcs"
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/gcsauth"
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+	"kmodules.xyz/objectstore-api/pkg/stow/internal/stowhttp"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"gomodules.xyz/stow"
+	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
+)
+
+// Kind represents the name of the location/storage type.
+const Kind = "google"
+
+// The keys of gomodules.xyz/stow/google.
+const (
+	// ConfigJSON is an optional credential file, eg. a service account key.
+	// The application default credentials are used when there is none.
+	ConfigJSON = "json"
+
+	// ConfigProjectId is the project of the buckets that are listed and
+	// created.
+	ConfigProjectId = "project_id"
+
+	// ConfigScopes is an optional comma separated list of the scopes of the
+	// access tokens.
+	ConfigScopes = "scopes"
+)
+
+const (
+	// ConfigCredentialsData and ConfigCredentialsFile are an optional
+	// credential file of any type of package gcsauth, used when there is no
+	// ConfigJSON.
+	ConfigCredentialsData = "credentials_data"
+	ConfigCredentialsFile = "credentials_file"
+
+	// ConfigImpersonateServiceAccount is an optional service account that the
+	// credentials impersonate.
+	ConfigImpersonateServiceAccount = "impersonate_service_account"
+)
+
+// The TLS settings are read from the keys of package tlsconfig, and the proxy
+// from those of package httpproxy.
+
+func init() {
+	for _, kind := range stow.Kinds() {
+		if kind == Kind {
+			panic("stow kind google is registered already, gomodules.xyz/stow/google must not be imported together with kmodules.xyz/objectstore-api/pkg/stow/gcs")
+		}
+	}
+	validatefn := func(config stow.Config) error {
+		_, err := credentials(config)
+		return err
+	}
+	makefn := func(config stow.Config) (stow.Location, error) {
+		limiter, err := stowhttp.Limiter(config)
+		if err != nil {
+			return nil, err
+		}
+		client, err := newClient(config, limiter)
+		if err != nil {
+			limiter.Release()
+			return nil, err
+		}
+		projectID, _ := config.Config(ConfigProjectId)
+		return &location{client: client, projectID: projectID, release: sync.OnceFunc(limiter.Release)}, nil
+	}
+	kindfn := func(u *url.URL) bool {
+		return u.Scheme == Kind
+	}
+	stow.Register(Kind, makefn, kindfn, validatefn)
+}
+
+func newClient(config stow.Config, limiter *ratelimit.Limiter) (*storage.Service, error) {
+	data, err := credentials(config)
+	if err != nil {
+		return nil, err
+	}
+	spec := &api.GCSSpec{}
+	if s, ok := config.Config(ConfigScopes); ok && s != "" {
+		spec.Scopes = strings.Split(s, ",")
+	}
+	spec.ImpersonateServiceAccount, _ = config.Config(ConfigImpersonateServiceAccount)
+
+	hc, err := stowhttp.NewClient(config, limiter)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if hc != nil {
+		// the tokens are fetched with the TLS and proxy settings as well
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, hc)
+	} else {
+		hc = &http.Client{}
+	}
+	ts, err := gcsauth.TokenSource(ctx, spec, data)
+	if err != nil {
+		return nil, err
+	}
+	opts := []option.ClientOption{
+		option.WithHTTPClient(&http.Client{
+			Transport: &oauth2.Transport{Source: ts, Base: hc.Transport},
+			Timeout:   hc.Timeout,
+		}),
+	}
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		if !strings.Contains(host, "://") {
+			host = "http://" + host
+		}
+		opts = append(opts, option.WithEndpoint(strings.TrimSuffix(host, "/")+"/storage/v1/"))
+	}
+	return storage.NewService(context.Background(), opts...)
+}
+
+// credentials returns the credential file of config, nil when there is none.
+func credentials(config stow.Config) ([]byte, error) {
+	if v, ok := config.Config(ConfigJSON); ok && v != "" {
+		return []byte(v), nil
+	}
+	if v, ok := config.Config(ConfigCredentialsData); ok && v != "" {
+		return []byte(v), nil
+	}
+	if v, ok := config.Config(ConfigCredentialsFile); ok && v != "" {
+		data, err := os.ReadFile(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading the credentials file")
+		}
+		return data, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the google location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+package gcs
+
+import (
+	"io"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+	storage "google.golang.org/api/storage/v1"
+)
+
+// permissions that HasWriteAccess requires
+var writePermissions = []string{
+	"storage.objects.create",
+	"storage.objects.delete",
+	"storage.objects.get",
+	"storage.objects.update",
+}
+
+type container struct {
+	name   string
+	client *storage.Service
+}
+
+var _ stow.Container = &container{}
+
+// ID returns the name of the bucket.
+func (c *container) ID() string {
+	return c.name
+}
+
+// Name returns the name of the bucket.
+func (c *container) Name() string {
+	return c.name
+}
+
+// Item returns the object with the given name.
+func (c *container) Item(id string) (stow.Item, error) {
+	obj, err := c.client.Objects.Get(c.name, id).Do()
+	if isNotFound(err) {
+		return nil, stow.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Item, getting the object")
+	}
+	return c.newItem(obj, int64(obj.Size))
+}
+
+// Browse lists the objects that start with prefix. With a delimiter, the
+// common prefixes are returned separately.
+func (c *container) Browse(prefix, delimiter, cursor string, count int) (*stow.ItemPage, error) {
+	call := c.client.Objects.List(c.name).Prefix(prefix).Delimiter(delimiter)
+	if count > 0 {
+		call.MaxResults(int64(count))
+	}
+	if cursor != "" {
+		call.PageToken(cursor)
+	}
+	res, err := call.Do()
+	if err != nil {
+		return nil, errors.Wrap(err, "Browse, listing objects")
+	}
+	items := make([]stow.Item, 0, len(res.Items))
+	for _, obj := range res.Items {
+		i, err := c.newItem(obj, int64(obj.Size))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return &stow.ItemPage{Prefixes: res.Prefixes, Items: items, Cursor: res.NextPageToken}, nil
+}
+
+// Items lists the objects that start with prefix.
+func (c *container) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	page, err := c.Browse(prefix, "", cursor, count)
+	if err != nil {
+		return nil, "", err
+	}
+	return page.Items, page.Cursor, nil
+}
+
+// Put uploads the content of r. The values of metadata must be strings.
+func (c *container) Put(name string, r io.Reader, size int64, metadata map[string]any) (stow.Item, error) {
+	md, err := prepMetadata(metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "Put, preparing metadata")
+	}
+	obj, err := c.client.Objects.Insert(c.name, &storage.Object{Name: name, Metadata: md}).Media(r).Do()
+	if err != nil {
+		return nil, errors.Wrap(err, "Put, uploading the object")
+	}
+	return c.newItem(obj, size)
+}
+
+// RemoveItem deletes the object with the given name.
+func (c *container) RemoveItem(id string) error {
+	err := c.client.Objects.Delete(c.name, id).Do()
+	if isNotFound(err) {
+		return stow.ErrNotFound
+	}
+	return errors.Wrapf(err, "RemoveItem, deleting object %s", id)
+}
+
+// HasWriteAccess checks that the caller holds the permissions to write and
+// delete the objects of the bucket.
+func (c *container) HasWriteAccess() error {
+	res, err := c.client.Buckets.TestIamPermissions(c.name, writePermissions).Do()
+	if err != nil {
+		return errors.Wrap(err, "HasWriteAccess, testing the permissions")
+	}
+	for _, p := range writePermissions {
+		if !slices.Contains(res.Permissions, p) {
+			return errors.Errorf("HasWriteAccess, missing permission %s on bucket %s", p, c.name)
+		}
+	}
+	return nil
+}
+
+func (c *container) newItem(obj *storage.Object, size int64) (*item, error) {
+	lastMod, err := time.Parse(time.RFC3339, obj.Updated)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing the update time")
+	}
+	u, err := prepURL(obj.MediaLink)
+	if err != nil {
+		return nil, err
+	}
+	return &item{
+		container: c,
+		name:      obj.Name,
+		size:      size,
+		etag:      obj.Etag,
+		lastMod:   lastMod,
+		url:       u,
+		metadata:  parseMetadata(obj.Metadata),
+	}, nil
+}
+
+func prepMetadata(md map[string]any) (map[string]string, error) {
+	out := make(map[string]string, len(md))
+	for k, v := range md {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.Errorf("value of key '%s' in metadata must be of type string", k)
+		}
+		out[k] = s
+	}
+	return out, nil
+}
+
+func parseMetadata(md map[string]string) map[string]any {
+	out := make(map[string]any, len(md))
+	for k, v := range md {
+		out[k] = v
+	}
+	return out
+}
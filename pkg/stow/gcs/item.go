@@ -0,0 +1,113 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the google location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+package gcs
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+type item struct {
+	container *container
+	name      string
+	size      int64
+	etag      string
+	lastMod   time.Time
+	url       *url.URL
+	metadata  map[string]any
+}
+
+var (
+	_ stow.Item       = &item{}
+	_ stow.ItemRanger = &item{}
+)
+
+// ID returns the name of the object.
+func (i *item) ID() string {
+	return i.name
+}
+
+// Name returns the name of the object.
+func (i *item) Name() string {
+	return i.name
+}
+
+// URL returns the media link of the object with the google scheme, see
+// location.ItemByURL.
+func (i *item) URL() *url.URL {
+	return i.url
+}
+
+// Size returns the size of the object in bytes.
+func (i *item) Size() (int64, error) {
+	return i.size, nil
+}
+
+// Open downloads the object.
+func (i *item) Open() (io.ReadCloser, error) {
+	res, err := i.container.client.Objects.Get(i.container.name, i.name).Download()
+	if err != nil {
+		return nil, errors.Wrap(err, "Open, downloading the object")
+	}
+	return res.Body, nil
+}
+
+// OpenRange opens the object for reading starting at byte start and ending
+// at byte end.
+func (i *item) OpenRange(start, end uint64) (io.ReadCloser, error) {
+	call := i.container.client.Objects.Get(i.container.name, i.name)
+	call.Header().Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	res, err := call.Download()
+	if err != nil {
+		return nil, errors.Wrap(err, "Open, downloading the object")
+	}
+	return res.Body, nil
+}
+
+// ETag returns the ETag of the object.
+func (i *item) ETag() (string, error) {
+	return i.etag, nil
+}
+
+// LastMod returns the last update of the object.
+func (i *item) LastMod() (time.Time, error) {
+	return i.lastMod, nil
+}
+
+// Metadata returns the custom metadata of the object.
+func (i *item) Metadata() (map[string]any, error) {
+	return i.metadata, nil
+}
+
+func prepURL(link string) (*url.URL, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing the media link")
+	}
+	u.Scheme = Kind
+	u.RawQuery = ""
+	return u, nil
+}
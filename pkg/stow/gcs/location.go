@@ -0,0 +1,118 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the google location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+package gcs
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+	"google.golang.org/api/googleapi"
+	storage "google.golang.org/api/storage/v1"
+)
+
+// A location holds the buckets of a project.
+type location struct {
+	client    *storage.Service
+	projectID string
+	// release releases the limiter of the backend once
+	release func()
+}
+
+// CreateContainer creates a bucket in the project.
+func (l *location) CreateContainer(name string) (stow.Container, error) {
+	if _, err := l.client.Buckets.Insert(l.projectID, &storage.Bucket{Name: name}).Do(); err != nil {
+		return nil, errors.Wrap(err, "CreateContainer, creating the bucket")
+	}
+	return &container{name: name, client: l.client}, nil
+}
+
+// Containers lists the buckets of the project whose name starts with prefix.
+func (l *location) Containers(prefix, cursor string, count int) ([]stow.Container, string, error) {
+	call := l.client.Buckets.List(l.projectID).Prefix(prefix)
+	if count > 0 {
+		call.MaxResults(int64(count))
+	}
+	if cursor != stow.CursorStart {
+		call.PageToken(cursor)
+	}
+	res, err := call.Do()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Containers, listing the buckets")
+	}
+	containers := make([]stow.Container, 0, len(res.Items))
+	for _, b := range res.Items {
+		containers = append(containers, &container{name: b.Name, client: l.client})
+	}
+	return containers, res.NextPageToken, nil
+}
+
+// Container returns the bucket with the given name.
+func (l *location) Container(id string) (stow.Container, error) {
+	_, err := l.client.Buckets.Get(id).Do()
+	if isNotFound(err) {
+		return nil, stow.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Container, getting the bucket")
+	}
+	return &container{name: id, client: l.client}, nil
+}
+
+// RemoveContainer deletes the bucket with the given name.
+func (l *location) RemoveContainer(id string) error {
+	err := l.client.Buckets.Delete(id).Do()
+	if isNotFound(err) {
+		return stow.ErrNotFound
+	}
+	return errors.Wrapf(err, "RemoveContainer, deleting bucket %s", id)
+}
+
+// ItemByURL retrieves an item from the media link of an object with the
+// google scheme, see item.URL.
+func (l *location) ItemByURL(u *url.URL) (stow.Item, error) {
+	if u.Scheme != Kind {
+		return nil, errors.New("not valid google storage URL")
+	}
+	// /download/storage/v1/b/<bucket>/o/<object>
+	pieces := strings.SplitN(u.Path, "/", 8)
+	if len(pieces) != 8 || pieces[4] != "b" || pieces[6] != "o" {
+		return nil, errors.New("wrong google storage URL")
+	}
+	c, err := l.Container(pieces[5])
+	if err != nil {
+		return nil, err
+	}
+	return c.Item(pieces[7])
+}
+
+// Close releases the limiter of the backend.
+func (l *location) Close() error {
+	l.release()
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var gErr *googleapi.Error
+	return errors.As(err, &gErr) && gErr.Code == http.StatusNotFound
+}
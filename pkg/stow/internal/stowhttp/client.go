@@ -0,0 +1,95 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package stowhttp builds the http client of the stow locations of this
+// module from the TLS, proxy, retry and connection limit keys of their config.
+package stowhttp // import "kmodules.xyz/objectstore-api/pkg/stow/internal/stowhttp"
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"kmodules.xyz/objectstore-api/pkg/httpproxy"
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+	"kmodules.xyz/objectstore-api/pkg/retry"
+	"kmodules.xyz/objectstore-api/pkg/tlsconfig"
+
+	"gomodules.xyz/stow"
+)
+
+// NewClient returns a client with the TLS and proxy settings of config, or
+// nil when there are none, so that the default client is used. The
+// AttemptTimeout of the retry policy limits every request, the retries
+// themselves are made by the osm package. The requests hold a connection slot
+// of limiter, its rate limits are applied by the osm package as well.
+func NewClient(config stow.Config, limiter *ratelimit.Limiter) (*http.Client, error) {
+	return newClient(config, limiter, false)
+}
+
+// NewInsecureClient returns the client of NewClient that does not verify the
+// certificate of the server.
+func NewInsecureClient(config stow.Config, limiter *ratelimit.Limiter) (*http.Client, error) {
+	return newClient(config, limiter, true)
+}
+
+// Limiter returns the limiter of the backend of config for a new owner, the
+// location that releases it when it is closed.
+func Limiter(config stow.Config) (*ratelimit.Limiter, error) {
+	limits, err := ratelimit.FromStowConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return limits.Limiter(), nil
+}
+
+func newClient(config stow.Config, limiter *ratelimit.Limiter, insecureTLS bool) (*http.Client, error) {
+	tlsOpts, err := tlsconfig.FromStowConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg, err := tlsOpts.Config()
+	if err != nil {
+		return nil, err
+	}
+	if insecureTLS {
+		if tlsCfg == nil {
+			tlsCfg = &tls.Config{}
+		}
+		tlsCfg.InsecureSkipVerify = true
+	}
+	proxy, err := httpproxy.FromStowConfig(config).Func()
+	if err != nil {
+		return nil, err
+	}
+	policy, err := retry.FromStowConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	var timeout time.Duration
+	if policy != nil {
+		timeout = policy.AttemptTimeout
+	}
+	if tlsCfg == nil && proxy == nil && timeout == 0 && !limiter.LimitsConnections() {
+		return nil, nil
+	}
+	tr := tlsconfig.Transport(tlsCfg)
+	if proxy != nil {
+		tr.Proxy = proxy
+	}
+	limiter.SizePool(tr)
+	return &http.Client{Transport: limiter.ConnTransport(tr), Timeout: timeout}, nil
+}
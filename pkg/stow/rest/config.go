@@ -0,0 +1,105 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rest registers a stow location kind for a restic repository
+// hosted by a rest-server.
+package rest // import "kmodules.xyz/objectstore-api/pkg/stow/rest"
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+	"kmodules.xyz/objectstore-api/pkg/rest"
+	"kmodules.xyz/objectstore-api/pkg/stow/internal/stowhttp"
+	"kmodules.xyz/objectstore-api/pkg/tlsconfig"
+
+	"gomodules.xyz/stow"
+)
+
+// Kind represents the name of the location/storage type.
+const Kind = "rest"
+
+const (
+	// ConfigURL is the url of the repository.
+	ConfigURL = "url"
+
+	// ConfigUsername is an optional basic auth username.
+	ConfigUsername = "username"
+
+	// ConfigPassword is an optional basic auth password.
+	ConfigPassword = "password"
+
+	// ConfigCACertData is optional PEM encoded CA certificate data. It has
+	// the same name as the s3 key so that osm projects it into a file the
+	// same way.
+	ConfigCACertData = tlsconfig.ConfigCACertData
+
+	// ConfigCACertFile is an optional path to a PEM encoded CA certificate.
+	ConfigCACertFile = tlsconfig.ConfigCACertFile
+)
+
+// The client certificate, server name and minimum version are read from the
+// tls_* keys of package tlsconfig, and the proxy from the keys of package
+// httpproxy.
+
+func init() {
+	validatefn := func(config stow.Config) error {
+		v, ok := config.Config(ConfigURL)
+		if !ok || v == "" {
+			return errors.New("missing url")
+		}
+		if _, err := url.Parse(v); err != nil {
+			return errors.New("invalid url")
+		}
+		return nil
+	}
+	makefn := func(config stow.Config) (stow.Location, error) {
+		if err := validatefn(config); err != nil {
+			return nil, err
+		}
+		limiter, err := stowhttp.Limiter(config)
+		if err != nil {
+			return nil, err
+		}
+		client, err := newClient(config, limiter)
+		if err != nil {
+			limiter.Release()
+			return nil, err
+		}
+		u, _ := config.Config(ConfigURL)
+		repo, _ := url.Parse(u)
+		return &location{client: client, host: repo.Host, release: sync.OnceFunc(limiter.Release)}, nil
+	}
+	kindfn := func(u *url.URL) bool {
+		return u.Scheme == Kind
+	}
+	stow.Register(Kind, makefn, kindfn, validatefn)
+}
+
+func newClient(config stow.Config, limiter *ratelimit.Limiter) (*rest.Client, error) {
+	opts := rest.Options{}
+	opts.URL, _ = config.Config(ConfigURL)
+	opts.Username, _ = config.Config(ConfigUsername)
+	opts.Password, _ = config.Config(ConfigPassword)
+	hc, err := stowhttp.NewClient(config, limiter)
+	if err != nil {
+		return nil, err
+	}
+	opts.HTTPClient = hc
+	return rest.NewClient(opts)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strings"
+
+	"kmodules.xyz/objectstore-api/pkg/rest"
+
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+type container struct {
+	id     string
+	client *rest.Client
+}
+
+// ID returns the host of the repository url.
+func (c *container) ID() string {
+	return c.id
+}
+
+// Name returns the host of the repository url.
+func (c *container) Name() string {
+	return c.id
+}
+
+// Item returns the file with the given key.
+func (c *container) Item(id string) (stow.Item, error) {
+	h, err := rest.ParseHandle(id)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := c.client.Stat(context.Background(), h)
+	if err != nil {
+		if rest.IsNotFound(err) {
+			return nil, stow.ErrNotFound
+		}
+		return nil, errors.Wrap(err, "Item, getting the file")
+	}
+	return &item{container: c, info: fi}, nil
+}
+
+// Browse lists the files that start with prefix. With a delimiter, the
+// common prefixes are returned separately. The cursor is the key of the
+// first file of the page.
+func (c *container) Browse(prefix, delimiter, cursor string, count int) (*stow.ItemPage, error) {
+	files, err := c.client.ListKeys(context.Background(), prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "Browse, listing files")
+	}
+	i := sort.Search(len(files), func(i int) bool {
+		return files[i].Name >= cursor
+	})
+
+	page := &stow.ItemPage{}
+	seen := map[string]bool{}
+	for ; i < len(files); i++ {
+		f := files[i]
+		if delimiter != "" {
+			rel := strings.TrimPrefix(f.Name, prefix)
+			if j := strings.Index(rel, delimiter); j >= 0 {
+				dir := prefix + rel[:j+len(delimiter)]
+				if !seen[dir] {
+					seen[dir] = true
+					page.Prefixes = append(page.Prefixes, dir)
+				}
+				continue
+			}
+		}
+		if len(page.Items) == count {
+			page.Cursor = f.Name
+			break
+		}
+		page.Items = append(page.Items, &item{container: c, info: f})
+	}
+	return page, nil
+}
+
+// Items lists the files that start with prefix.
+func (c *container) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	page, err := c.Browse(prefix, "", cursor, count)
+	if err != nil {
+		return nil, "", err
+	}
+	return page.Items, page.Cursor, nil
+}
+
+// RemoveItem deletes the file with the given key.
+func (c *container) RemoveItem(id string) error {
+	h, err := rest.ParseHandle(id)
+	if err != nil {
+		return err
+	}
+	if err := c.client.Remove(context.Background(), h); err != nil {
+		if rest.IsNotFound(err) {
+			return stow.ErrNotFound
+		}
+		return errors.Wrapf(err, "RemoveItem, deleting file %s", id)
+	}
+	return nil
+}
+
+// Put stores the content of r. Metadata is not supported by rest-server.
+func (c *container) Put(name string, r io.Reader, size int64, metadata map[string]any) (stow.Item, error) {
+	if len(metadata) > 0 {
+		return nil, errors.New("Put, metadata is not supported by rest-server")
+	}
+	h, err := rest.ParseHandle(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.client.Save(context.Background(), h, r, size); err != nil {
+		return nil, errors.Wrap(err, "Put, uploading file")
+	}
+	return &item{container: c, info: rest.FileInfo{Name: h.String(), Size: size}}, nil
+}
+
+// HasWriteAccess stores and removes a lock file. A rest-server only accepts
+// files of the repository layout that are named after the sha256 of their
+// content.
+func (c *container) HasWriteAccess() error {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	name := string(rest.LockFile) + "/" + hex.EncodeToString(sum[:])
+	item, err := c.Put(name, bytes.NewReader(data), int64(len(data)), nil)
+	if err != nil {
+		return err
+	}
+	return c.RemoveItem(item.ID())
+}
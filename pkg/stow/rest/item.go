@@ -0,0 +1,86 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"time"
+
+	"kmodules.xyz/objectstore-api/pkg/rest"
+
+	"github.com/pkg/errors"
+)
+
+type item struct {
+	container *container
+	info      rest.FileInfo
+}
+
+// ID returns the key of the file.
+func (i *item) ID() string {
+	return i.info.Name
+}
+
+// Name returns the key of the file.
+func (i *item) Name() string {
+	return i.info.Name
+}
+
+// URL returns rest://<host>/<key>.
+func (i *item) URL() *url.URL {
+	return &url.URL{
+		Scheme: Kind,
+		Host:   i.container.Name(),
+		Path:   "/" + i.info.Name,
+	}
+}
+
+// Size returns the size of the file in bytes.
+func (i *item) Size() (int64, error) {
+	return i.info.Size, nil
+}
+
+// Open downloads the file.
+func (i *item) Open() (io.ReadCloser, error) {
+	h, err := rest.ParseHandle(i.info.Name)
+	if err != nil {
+		return nil, err
+	}
+	r, _, err := i.container.client.Load(context.Background(), h, 0, -1)
+	if err != nil {
+		return nil, errors.Wrap(err, "Open, downloading the file")
+	}
+	return r, nil
+}
+
+// ETag returns the key, files of a restic repository are named after the
+// hash of their content.
+func (i *item) ETag() (string, error) {
+	return i.info.Name, nil
+}
+
+// LastMod is not reported by rest-server.
+func (i *item) LastMod() (time.Time, error) {
+	return time.Time{}, nil
+}
+
+// Metadata is not supported by rest-server.
+func (i *item) Metadata() (map[string]any, error) {
+	return map[string]any{}, nil
+}
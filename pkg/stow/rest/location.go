@@ -0,0 +1,90 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"kmodules.xyz/objectstore-api/pkg/rest"
+
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+// A location holds the single repository of the configured url. The
+// repository is the only container, named after the host of the url.
+type location struct {
+	client *rest.Client
+	host   string
+	// release releases the limiter of the backend once
+	release func()
+}
+
+// CreateContainer creates the repository.
+func (l *location) CreateContainer(name string) (stow.Container, error) {
+	if name != l.host {
+		return nil, errors.Errorf("CreateContainer, container must be named %s", l.host)
+	}
+	if err := l.client.CreateRepository(context.Background()); err != nil {
+		return nil, errors.Wrap(err, "CreateContainer, creating the repository")
+	}
+	return &container{id: l.host, client: l.client}, nil
+}
+
+// Containers returns the repository if its name starts with prefix.
+func (l *location) Containers(prefix, cursor string, count int) ([]stow.Container, string, error) {
+	if cursor != stow.CursorStart && cursor != l.host {
+		return nil, "", stow.ErrBadCursor
+	}
+	if !strings.HasPrefix(l.host, prefix) || count < 1 {
+		return nil, "", nil
+	}
+	return []stow.Container{&container{id: l.host, client: l.client}}, "", nil
+}
+
+// Container returns the repository.
+func (l *location) Container(id string) (stow.Container, error) {
+	if id != l.host {
+		return nil, stow.ErrNotFound
+	}
+	return &container{id: l.host, client: l.client}, nil
+}
+
+// RemoveContainer is not supported, repositories are removed on the server.
+func (l *location) RemoveContainer(string) error {
+	return errors.New("RemoveContainer, not supported by rest-server")
+}
+
+// ItemByURL retrieves an item from a URL of the form rest://<host>/<key>.
+func (l *location) ItemByURL(u *url.URL) (stow.Item, error) {
+	if u.Scheme != Kind {
+		return nil, errors.New("not valid rest URL")
+	}
+	c, err := l.Container(u.Host)
+	if err != nil {
+		return nil, err
+	}
+	return c.Item(strings.TrimPrefix(u.Path, "/"))
+}
+
+// Close releases the limiter of the backend.
+func (l *location) Close() error {
+	l.release()
+	return nil
+}
@@ -0,0 +1,302 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the s3 location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+// Package s3 provides the stow location of s3 backends that the osm package
+// dials. It reads the config of gomodules.xyz/stow/s3 and also the keys of
+// the osm config that gomodules.xyz/stow/s3 ignores. It registers the s3
+// kind with stow in place of gomodules.xyz/stow/s3, so stow.Dial never
+// returns a location that silently drops these keys, eg. writes plaintext
+// objects of a backend with server side encryption. As the first
+// registration of a kind wins, importing both packages panics.
+package s3 // import "kmodules.xyz/objectstore-api/pkg/stow/s3"
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+	"kmodules.xyz/objectstore-api/pkg/retry"
+	"kmodules.xyz/objectstore-api/pkg/s3auth"
+	"kmodules.xyz/objectstore-api/pkg/stow/internal/stowhttp"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/corehandlers"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+// Kind represents the name of the location/storage type.
+const Kind = "s3"
+
+const (
+	authTypeAccessKey = "accesskey"
+	authTypeIAM       = "iam"
+)
+
+// The keys of gomodules.xyz/stow/s3.
+const (
+	// ConfigAuthType is an optional argument that defines whether to use an
+	// IAM role or access key based auth.
+	ConfigAuthType = "auth_type"
+
+	// ConfigAccessKeyID is one key of a pair of AWS credentials.
+	ConfigAccessKeyID = "access_key_id"
+
+	// ConfigSecretKey is one key of a pair of AWS credentials.
+	ConfigSecretKey = "secret_key"
+
+	// ConfigRegion represents the region/availability zone of the session.
+	ConfigRegion = "region"
+
+	// ConfigEndpoint is an optional config value for an s3 compatible
+	// endpoint, e.g. minio.
+	ConfigEndpoint = "endpoint"
+
+	// ConfigCACertFile and ConfigCACertData are optional config values for
+	// the CA bundle of a custom endpoint. They are the keys of package
+	// tlsconfig.
+	ConfigCACertFile = "cacert_file"
+	ConfigCACertData = "cacert_data"
+
+	// ConfigDisableSSL is an optional config value for disabling SSL support
+	// on custom endpoints.
+	ConfigDisableSSL = "disable_ssl"
+
+	// ConfigV2Signing is an optional config value for signing requests with
+	// the v2 signature, for s3 compatible services that do not support v4.
+	ConfigV2Signing = "v2_signing"
+)
+
+// Addressing and endpoint modes. Custom endpoints use path-style urls unless
+// ConfigForcePathStyle is "false".
+const (
+	ConfigForcePathStyle = "force_path_style"
+	ConfigUseDualStack   = "use_dualstack"
+	ConfigUseFIPS        = "use_fips"
+	ConfigUseAccelerate  = "use_accelerate"
+	ConfigInsecureTLS    = "insecure_tls"
+)
+
+// Server side encryption. These keys carry the values of the matching
+// x-amz-server-side-encryption-* headers, the customer key is base64 encoded.
+const (
+	ConfigServerSideEncryption    = "server_side_encryption"
+	ConfigSSEKMSKeyID             = "sse_kms_key_id"
+	ConfigSSEKMSEncryptionContext = "sse_kms_encryption_context"
+	ConfigSSECustomerAlgorithm    = "sse_customer_algorithm"
+	ConfigSSECustomerKey          = "sse_customer_key"
+	ConfigSSECustomerKeyMD5       = "sse_customer_key_md5"
+)
+
+// The TLS settings are read from the keys of package tlsconfig, and the proxy
+// from those of package httpproxy.
+
+func init() {
+	for _, kind := range stow.Kinds() {
+		if kind == Kind {
+			panic("stow kind s3 is registered already, gomodules.xyz/stow/s3 must not be imported together with kmodules.xyz/objectstore-api/pkg/stow/s3")
+		}
+	}
+	kindfn := func(u *url.URL) bool {
+		return u.Scheme == Kind
+	}
+	stow.Register(Kind, NewLocation, kindfn, validateConfig)
+}
+
+func validateConfig(config stow.Config) error {
+	authType, ok := config.Config(ConfigAuthType)
+	if !ok || authType == "" {
+		authType = authTypeAccessKey
+	}
+	if authType != authTypeAccessKey && authType != authTypeIAM {
+		return errors.New("invalid auth_type")
+	}
+	if authType == authTypeAccessKey {
+		if _, ok := config.Config(ConfigAccessKeyID); !ok {
+			return errors.New("missing Access Key ID")
+		}
+		if _, ok := config.Config(ConfigSecretKey); !ok {
+			return errors.New("missing Secret Key")
+		}
+	}
+	return nil
+}
+
+// NewLocation dials the s3 location of config, like stow.Dial does for the
+// s3 kind.
+func NewLocation(config stow.Config) (stow.Location, error) {
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+	if err := checkExpiry(config); err != nil {
+		return nil, err
+	}
+
+	limiter, err := stowhttp.Limiter(config)
+	if err != nil {
+		return nil, err
+	}
+	client, endpoint, err := newS3Client(config, "", limiter)
+	if err != nil {
+		limiter.Release()
+		return nil, err
+	}
+	return &location{
+		config:         config,
+		client:         client,
+		customEndpoint: endpoint,
+		limiter:        limiter,
+		release:        sync.OnceFunc(limiter.Release),
+	}, nil
+}
+
+// newS3Client returns a client of config for region, the region of config
+// when it is empty. Its requests hold the connection slots of limiter.
+func newS3Client(config stow.Config, region string, limiter *ratelimit.Limiter) (client *s3.S3, endpoint string, err error) {
+	newClient := stowhttp.NewClient
+	if v, _ := config.Config(ConfigInsecureTLS); v == "true" {
+		newClient = stowhttp.NewInsecureClient
+	}
+	hc, err := newClient(config, limiter)
+	if err != nil {
+		return nil, "", err
+	}
+	awsConfig := aws.NewConfig().
+		WithMaxRetries(aws.UseServiceDefaultRetries).
+		WithLogger(aws.NewDefaultLogger()).
+		WithLogLevel(aws.LogOff).
+		WithSleepDelay(time.Sleep)
+
+	p, err := retry.FromStowConfig(config)
+	if err != nil {
+		return nil, "", err
+	}
+	if p != nil {
+		// the calls are retried by the osm package
+		awsConfig.WithMaxRetries(0)
+	}
+
+	if region == "" {
+		region, _ = config.Config(ConfigRegion)
+	}
+	if region != "" {
+		awsConfig.WithRegion(region)
+	} else {
+		awsConfig.WithRegion("us-east-1")
+	}
+
+	opts := session.Options{}
+	if authType, _ := config.Config(ConfigAuthType); authType != authTypeIAM {
+		accessKeyID, _ := config.Config(ConfigAccessKeyID)
+		secretKey, _ := config.Config(ConfigSecretKey)
+		token, _ := config.Config(ConfigToken)
+		awsConfig.WithCredentials(credentials.NewStaticCredentials(accessKeyID, secretKey, token))
+	} else {
+		files, dir, err := sharedConfigFiles(config)
+		if err != nil {
+			return nil, "", err
+		}
+		if dir != "" {
+			// the files are read while the session is created
+			defer os.RemoveAll(dir) // nolint:errcheck
+		}
+		opts.SharedConfigFiles = files
+		opts.Profile, _ = config.Config(ConfigProfile)
+		if len(files) > 0 || opts.Profile != "" {
+			opts.SharedConfigState = session.SharedConfigEnable
+		}
+	}
+
+	endpoint, ok := config.Config(ConfigEndpoint)
+	if ok {
+		awsConfig.WithEndpoint(endpoint).
+			WithS3ForcePathStyle(true)
+	}
+	if v, _ := config.Config(ConfigDisableSSL); v == "true" {
+		awsConfig.WithDisableSSL(true)
+	}
+	if v, ok := config.Config(ConfigForcePathStyle); ok {
+		awsConfig.WithS3ForcePathStyle(v == "true")
+	}
+	if v, _ := config.Config(ConfigUseDualStack); v == "true" {
+		awsConfig.UseDualStackEndpoint = endpoints.DualStackEndpointStateEnabled
+	}
+	if v, _ := config.Config(ConfigUseFIPS); v == "true" {
+		awsConfig.UseFIPSEndpoint = endpoints.FIPSEndpointStateEnabled
+	}
+	if v, _ := config.Config(ConfigUseAccelerate); v == "true" {
+		awsConfig.WithS3UseAccelerate(true)
+	}
+
+	opts.Config = *awsConfig
+	sess, err := session.NewSessionWithOptions(opts)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create S3 session")
+	}
+	if hc != nil {
+		// set after the session is created, as the session replaces the CA
+		// bundle of the transport with the one of AWS_CA_BUNDLE
+		sess.Config.HTTPClient = hc
+	}
+	if role := roleFromConfig(config); role != nil {
+		// the AssumeRole calls use the client of the location as well
+		sess.Config.Credentials = AssumeRoleCredentials(sess, *role)
+	}
+	client = s3.New(sess)
+
+	if v, _ := config.Config(ConfigV2Signing); v == "true" {
+		useSigV2(client)
+	}
+	sse, err := encryptionFromConfig(config)
+	if err != nil {
+		return nil, "", err
+	}
+	if sse != nil {
+		sse.setHandlers(client)
+	}
+	return client, endpoint, nil
+}
+
+// useSigV2 replaces the v4 signer of client with a v2 signer.
+func useSigV2(client *s3.S3) {
+	client.Handlers.Sign.Clear()
+	client.Handlers.Sign.PushBack(func(r *request.Request) {
+		if r.Config.Credentials == credentials.AnonymousCredentials {
+			return
+		}
+		creds, err := r.Config.Credentials.GetWithContext(r.Context())
+		if err != nil {
+			r.Error = err
+			return
+		}
+		r.HTTPRequest.Header.Del("X-Amz-Date")
+		r.HTTPRequest.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+		s3auth.SignV2(r.HTTPRequest, creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken)
+	})
+	client.Handlers.Sign.PushBackNamed(corehandlers.BuildContentLengthHandler)
+}
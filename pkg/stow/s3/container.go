@@ -0,0 +1,263 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the s3 location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+package s3
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+// A container is an S3 bucket.
+type container struct {
+	// name is needed to retrieve items.
+	name string
+	// client is responsible for performing the requests.
+	client *s3.S3
+	// region describes the AWS Availability Zone of the S3 Bucket.
+	region         string
+	customEndpoint string
+}
+
+var _ stow.Container = &container{}
+
+// ID returns a string value which represents the name of the container.
+func (c *container) ID() string {
+	return c.name
+}
+
+// Name returns a string value which represents the name of the container.
+func (c *container) Name() string {
+	return c.name
+}
+
+// Region returns a string representing the region/availability zone of the
+// container.
+func (c *container) Region() string {
+	return c.region
+}
+
+// Item returns the item of the object named id. The item only holds the
+// metadata of the object, Open reads its content.
+func (c *container) Item(id string) (stow.Item, error) {
+	return c.getItem(id)
+}
+
+// Browse returns a page of the items whose name starts with prefix, and the
+// common prefixes up to delimiter. The cursor is the name of the last item of
+// the previous page.
+func (c *container) Browse(prefix, delimiter, cursor string, count int) (*stow.ItemPage, error) {
+	params := &s3.ListObjectsV2Input{
+		Bucket:     aws.String(c.name),
+		StartAfter: aws.String(cursor),
+		MaxKeys:    aws.Int64(int64(count)),
+		Prefix:     aws.String(prefix),
+	}
+	if delimiter != "" {
+		params.Delimiter = aws.String(delimiter)
+	}
+	response, err := c.client.ListObjectsV2(params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Items, listing objects")
+	}
+
+	prefixes := make([]string, len(response.CommonPrefixes))
+	for i, prefix := range response.CommonPrefixes {
+		prefixes[i] = aws.StringValue(prefix.Prefix)
+	}
+
+	items := make([]stow.Item, 0, len(response.Contents))
+	for _, object := range response.Contents {
+		if aws.StringValue(object.StorageClass) == s3.ObjectStorageClassGlacier {
+			continue
+		}
+		etag := cleanEtag(aws.StringValue(object.ETag))
+		items = append(items, &item{
+			container: c,
+			client:    c.client,
+			properties: properties{
+				ETag:         &etag,
+				Key:          object.Key,
+				LastModified: object.LastModified,
+				Owner:        object.Owner,
+				Size:         object.Size,
+				StorageClass: object.StorageClass,
+			},
+		})
+	}
+
+	var startAfter string
+	if aws.BoolValue(response.IsTruncated) && len(items) > 0 {
+		startAfter = items[len(items)-1].Name()
+	}
+	return &stow.ItemPage{Prefixes: prefixes, Items: items, Cursor: startAfter}, nil
+}
+
+// Items returns a page of the items whose name starts with prefix.
+func (c *container) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	page, err := c.Browse(prefix, "", cursor, count)
+	if err != nil {
+		return nil, "", err
+	}
+	return page.Items, page.Cursor, nil
+}
+
+func (c *container) RemoveItem(id string) error {
+	params := &s3.DeleteObjectInput{
+		Bucket: aws.String(c.name),
+		Key:    aws.String(id),
+	}
+	if _, err := c.client.DeleteObject(params); err != nil {
+		return errors.Wrapf(err, "RemoveItem, deleting object %+v", params)
+	}
+	return nil
+}
+
+// Put uploads the content of r as the object name, with metadata. Large
+// content is uploaded in parts.
+func (c *container) Put(name string, r io.Reader, size int64, metadata map[string]interface{}) (stow.Item, error) {
+	md, err := prepMetadata(metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create or update item, preparing metadata")
+	}
+
+	uploader := s3manager.NewUploaderWithClient(c.client)
+	_, err = uploader.Upload(&s3manager.UploadInput{
+		Bucket:   aws.String(c.name),
+		Key:      aws.String(name),
+		Body:     r,
+		Metadata: md,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "PutObject, putting object")
+	}
+
+	var etag string
+	head, err := c.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(c.name),
+		Key:    aws.String(name),
+	})
+	if err == nil && head.ETag != nil {
+		etag = cleanEtag(*head.ETag)
+	}
+	// the last modified time and the metadata are read by the item when they
+	// are asked for
+	return &item{
+		container: c,
+		client:    c.client,
+		properties: properties{
+			ETag: &etag,
+			Key:  &name,
+			Size: &size,
+		},
+	}, nil
+}
+
+// getItem returns the item of the object named id with the information of a
+// HeadObject call.
+func (c *container) getItem(id string) (*item, error) {
+	res, err := c.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(c.name),
+		Key:    aws.String(id),
+	})
+	if err != nil {
+		// the response of a HEAD request has no body, so the error has no
+		// S3 error code
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+			return nil, stow.ErrNotFound
+		}
+		return nil, errors.Wrap(err, "getItem, getting the object")
+	}
+
+	etag := cleanEtag(aws.StringValue(res.ETag))
+	return &item{
+		container: c,
+		client:    c.client,
+		properties: properties{
+			ETag:         &etag,
+			Key:          &id,
+			LastModified: res.LastModified,
+			Size:         res.ContentLength,
+			StorageClass: res.StorageClass,
+			Metadata:     parseMetadata(res.Metadata),
+		},
+	}, nil
+}
+
+// cleanEtag removes the quotes, escaped or not, and the W/ prefix of weak
+// etags from etag.
+//
+// ref: https://tools.ietf.org/html/rfc7232#section-2.3
+func cleanEtag(etag string) string {
+	for {
+		switch {
+		case strings.HasPrefix(etag, `\"`):
+			etag = strings.Trim(etag, `\"`)
+		case strings.HasPrefix(etag, `"`):
+			etag = strings.Trim(etag, `"`)
+		case strings.HasPrefix(etag, `W/`):
+			etag = strings.Replace(etag, `W/`, "", 1)
+		default:
+			return etag
+		}
+	}
+}
+
+// prepMetadata converts the metadata of stow into the metadata of S3, its
+// values must be strings.
+func prepMetadata(md map[string]interface{}) (map[string]*string, error) {
+	m := make(map[string]*string, len(md))
+	for key, value := range md {
+		strValue, valid := value.(string)
+		if !valid {
+			return nil, errors.Errorf(`value of key '%s' in metadata must be of type string`, key)
+		}
+		m[key] = aws.String(strValue)
+	}
+	return m, nil
+}
+
+// parseMetadata converts the metadata of S3 into the metadata of stow. S3
+// capitalizes the keys, they are lower cased like in the other locations.
+func parseMetadata(md map[string]*string) map[string]interface{} {
+	m := make(map[string]interface{}, len(md))
+	for key, value := range md {
+		m[strings.ToLower(key)] = aws.StringValue(value)
+	}
+	return m
+}
+
+func (c *container) HasWriteAccess() error {
+	r := bytes.NewReader([]byte("CheckBucketAccess"))
+	item, err := c.Put(".trash/"+uuid.New().String(), r, r.Size(), nil)
+	if err != nil {
+		return err
+	}
+	return c.RemoveItem(item.ID())
+}
@@ -0,0 +1,87 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"time"
+
+	"kmodules.xyz/objectstore-api/pkg/s3auth"
+
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+// Temporary credentials and profiles. The token is sent with the access keys
+// of auth_type accesskey. The shared credentials and config files, and their
+// profile, are read when auth_type is iam. The data keys carry the content of
+// the files, the file keys their paths.
+const (
+	ConfigToken                 = "token"
+	ConfigCredentialExpiration  = "credential_expiration"
+	ConfigSharedCredentialsData = "shared_credentials_data"
+	ConfigSharedCredentialsFile = "shared_credentials_file"
+	ConfigSharedConfigData      = "shared_config_data"
+	ConfigSharedConfigFile      = "shared_config_file"
+	ConfigProfile               = "profile"
+)
+
+// checkExpiry returns an s3auth.ExpiredCredentialsError if the credential
+// expiration of config has passed.
+func checkExpiry(config stow.Config) error {
+	v, _ := config.Config(ConfigCredentialExpiration)
+	if v == "" {
+		return nil
+	}
+	expires, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s", ConfigCredentialExpiration)
+	}
+	return (&s3auth.Credentials{Expires: expires}).CheckExpiry(time.Now())
+}
+
+// sharedConfigFiles returns the shared credentials and config files of
+// config. The files of the data keys are written into dir, which the caller
+// must remove once the session is created.
+func sharedConfigFiles(config stow.Config) (files []string, dir string, err error) {
+	credentialsData, _ := config.Config(ConfigSharedCredentialsData)
+	configData, _ := config.Config(ConfigSharedConfigData)
+	credentialsFile, _ := config.Config(ConfigSharedCredentialsFile)
+	configFile, _ := config.Config(ConfigSharedConfigFile)
+	if credentialsData != "" || configData != "" {
+		creds := &s3auth.Credentials{
+			SharedCredentials: []byte(credentialsData),
+			SharedConfig:      []byte(configData),
+		}
+		var writtenCredentials, writtenConfig string
+		writtenCredentials, writtenConfig, dir, err = creds.WriteSharedFiles()
+		if err != nil {
+			return nil, "", err
+		}
+		if credentialsData != "" {
+			credentialsFile = writtenCredentials
+		}
+		if configData != "" {
+			configFile = writtenConfig
+		}
+	}
+	for _, f := range []string{credentialsFile, configFile} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, dir, nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the s3 location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+package s3
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+// An item is an S3 object. Its properties are filled from the listing or the
+// HeadObject call that found it.
+type item struct {
+	container  *container
+	client     *s3.S3
+	properties properties
+	infoOnce   sync.Once
+	infoErr    error
+	tags       map[string]interface{}
+	tagsOnce   sync.Once
+	tagsErr    error
+}
+
+var (
+	_ stow.Item       = &item{}
+	_ stow.ItemRanger = &item{}
+	_ stow.Taggable   = &item{}
+)
+
+type properties struct {
+	ETag         *string
+	Key          *string
+	LastModified *time.Time
+	Owner        *s3.Owner
+	Size         *int64
+	StorageClass *string
+	Metadata     map[string]interface{}
+}
+
+// ID returns a string value that represents the name of a file.
+func (i *item) ID() string {
+	return *i.properties.Key
+}
+
+// Name returns a string value that represents the name of the file.
+func (i *item) Name() string {
+	return *i.properties.Key
+}
+
+// Size returns the size of an item in bytes.
+func (i *item) Size() (int64, error) {
+	return aws.Int64Value(i.properties.Size), nil
+}
+
+// URL returns the url of the item, see location.ItemByURL.
+func (i *item) URL() *url.URL {
+	if i.container.customEndpoint == "" {
+		return &url.URL{
+			Scheme: Kind,
+			Path:   fmt.Sprintf("https://s3.dualstack.%s.amazonaws.com/%s/%s", i.container.Region(), i.container.Name(), i.Name()),
+		}
+	}
+	return &url.URL{
+		Scheme: Kind,
+		Path:   fmt.Sprintf("%s/%s", i.container.Name(), i.Name()),
+	}
+}
+
+// Open returns the content of the item.
+func (i *item) Open() (io.ReadCloser, error) {
+	response, err := i.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(i.container.Name()),
+		Key:    aws.String(i.ID()),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Open, getting the object")
+	}
+	return response.Body, nil
+}
+
+// OpenRange opens the item for reading starting at byte start and ending
+// at byte end.
+func (i *item) OpenRange(start, end uint64) (io.ReadCloser, error) {
+	response, err := i.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(i.container.Name()),
+		Key:    aws.String(i.ID()),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Open, getting the object")
+	}
+	return response.Body, nil
+}
+
+// LastMod returns the last modified date of the item. Items that were put
+// do not know it, it is then read once with a HeadObject call.
+func (i *item) LastMod() (time.Time, error) {
+	if err := i.ensureInfo(); err != nil {
+		return time.Time{}, errors.Wrap(err, "retrieving Last Modified information of Item")
+	}
+	return aws.TimeValue(i.properties.LastModified), nil
+}
+
+// ETag returns the ETag value from the properties field of an item.
+func (i *item) ETag() (string, error) {
+	return aws.StringValue(i.properties.ETag), nil
+}
+
+func (i *item) Metadata() (map[string]interface{}, error) {
+	if err := i.ensureInfo(); err != nil {
+		return nil, errors.Wrap(err, "retrieving metadata")
+	}
+	return i.properties.Metadata, nil
+}
+
+func (i *item) ensureInfo() error {
+	if i.properties.Metadata != nil && i.properties.LastModified != nil {
+		return nil
+	}
+	i.infoOnce.Do(func() {
+		info, err := i.container.getItem(i.ID())
+		if err != nil {
+			i.infoErr = err
+			return
+		}
+		i.properties.Metadata = info.properties.Metadata
+		i.properties.LastModified = info.properties.LastModified
+	})
+	return i.infoErr
+}
+
+// Tags returns the tags of the object.
+func (i *item) Tags() (map[string]interface{}, error) {
+	i.tagsOnce.Do(func() {
+		res, err := i.client.GetObjectTagging(&s3.GetObjectTaggingInput{
+			Bucket: aws.String(i.container.Name()),
+			Key:    aws.String(i.ID()),
+		})
+		if err != nil {
+			if strings.Contains(err.Error(), s3.ErrCodeNoSuchKey) {
+				i.tagsErr = stow.ErrNotFound
+				return
+			}
+			i.tagsErr = errors.Wrap(err, "getObjectTagging")
+			return
+		}
+
+		i.tags = make(map[string]interface{}, len(res.TagSet))
+		for _, t := range res.TagSet {
+			i.tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+		}
+	})
+	return i.tags, i.tagsErr
+}
@@ -0,0 +1,226 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the s3 location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+package s3
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+// bucketRegionTimeout bounds the lookup of the region of a bucket on AWS.
+const bucketRegionTimeout = 5 * time.Second
+
+// A location contains a client and the config used to create the client.
+type location struct {
+	config         stow.Config
+	customEndpoint string
+	client         *s3.S3
+	limiter        *ratelimit.Limiter
+	// release releases limiter once
+	release func()
+}
+
+var _ stow.Location = &location{}
+
+// CreateContainer creates a new container, in this case an S3 bucket.
+func (l *location) CreateContainer(containerName string) (stow.Container, error) {
+	_, err := l.client.CreateBucket(&s3.CreateBucketInput{
+		Bucket: aws.String(containerName),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "CreateContainer, creating the bucket")
+	}
+
+	region, _ := l.config.Config(ConfigRegion)
+	return &container{
+		name:           containerName,
+		client:         l.client,
+		region:         region,
+		customEndpoint: l.customEndpoint,
+	}, nil
+}
+
+// Containers returns the buckets of the account whose name starts with
+// prefix. On AWS, only the buckets in the configured region are returned, as
+// the credentials may be tied to it.
+func (l *location) Containers(prefix, cursor string, count int) ([]stow.Container, string, error) {
+	bucketList, err := l.client.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Containers, listing the buckets")
+	}
+
+	// Seek to the current bucket, according to cursor.
+	if cursor != stow.CursorStart {
+		ok := false
+		for i, b := range bucketList.Buckets {
+			if *b.Name == cursor {
+				ok = true
+				bucketList.Buckets = bucketList.Buckets[i:]
+				break
+			}
+		}
+		if !ok {
+			return nil, "", stow.ErrBadCursor
+		}
+	}
+	cursor = ""
+
+	region, _ := l.config.Config(ConfigRegion)
+	var containers []stow.Container
+	for _, bucket := range bucketList.Buckets {
+		if len(containers) == count {
+			cursor = *bucket.Name
+			break
+		}
+		if !strings.HasPrefix(*bucket.Name, prefix) {
+			continue
+		}
+
+		client := l.client
+		bucketRegion := region
+		// s3 compatible services do not support the region lookup
+		if l.customEndpoint == "" {
+			ctx, cancel := context.WithTimeout(context.Background(), bucketRegionTimeout)
+			bucketRegion, err = s3manager.GetBucketRegionWithClient(ctx, l.client, *bucket.Name)
+			cancel()
+			if err != nil {
+				if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+					// deleted buckets may still be listed for a while, but
+					// their region is not found
+					continue
+				}
+				return nil, "", errors.Wrapf(err, "Containers, getting bucket region for: %s", *bucket.Name)
+			}
+			if region != "" && bucketRegion != region {
+				continue
+			}
+			client, _, err = newS3Client(l.config, bucketRegion, l.limiter)
+			if err != nil {
+				return nil, "", errors.Wrapf(err, "Containers, creating new client for region: %s", bucketRegion)
+			}
+		}
+
+		containers = append(containers, &container{
+			name:           *bucket.Name,
+			client:         client,
+			region:         bucketRegion,
+			customEndpoint: l.customEndpoint,
+		})
+	}
+	return containers, cursor, nil
+}
+
+// Close releases the limiter of the backend.
+func (l *location) Close() error {
+	l.release()
+	return nil
+}
+
+// Container retrieves a stow.Container based on its name which must be
+// exact.
+func (l *location) Container(id string) (stow.Container, error) {
+	client := l.client
+	bucketRegion, _ := l.config.Config(ConfigRegion)
+
+	// s3 compatible services do not support the region lookup
+	if l.customEndpoint == "" {
+		ctx, cancel := context.WithTimeout(context.Background(), bucketRegionTimeout)
+		bucketRegion, _ = s3manager.GetBucketRegionWithClient(ctx, l.client, id)
+		cancel()
+
+		var err error
+		client, _, err = newS3Client(l.config, bucketRegion, l.limiter)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Container, creating new client for region: %s", bucketRegion)
+		}
+	}
+
+	_, err := client.GetBucketLocation(&s3.GetBucketLocationInput{
+		Bucket: aws.String(id),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NoSuchBucket" {
+			return nil, stow.ErrNotFound
+		}
+		return nil, errors.Wrap(err, "GetBucketLocation")
+	}
+
+	return &container{
+		name:           id,
+		client:         client,
+		region:         bucketRegion,
+		customEndpoint: l.customEndpoint,
+	}, nil
+}
+
+// RemoveContainer removes a container simply by name.
+func (l *location) RemoveContainer(id string) error {
+	_, err := l.client.DeleteBucket(&s3.DeleteBucketInput{
+		Bucket: aws.String(id),
+	})
+	if err != nil {
+		return errors.Wrap(err, "RemoveContainer, deleting the bucket")
+	}
+	return nil
+}
+
+// ItemByURL retrieves a stow.Item by parsing the URL, in this case an item is
+// an object. The url is the one returned by the URL of the item.
+func (l *location) ItemByURL(u *url.URL) (stow.Item, error) {
+	var containerName, itemName string
+	if l.customEndpoint == "" {
+		// url looks like this: s3:https://s3.dualstack.<region>.amazonaws.com/<containerName>/<itemName>
+		path := u.Path
+		if i := strings.Index(path, ".amazonaws.com/"); i >= 0 {
+			path = path[i+len(".amazonaws.com/"):]
+		}
+		containerName, itemName, _ = strings.Cut(path, "/")
+	} else {
+		// url looks like this: s3://<containerName>/<itemName>
+		containerName = u.Host
+		itemName = strings.TrimPrefix(u.Path, "/")
+		if containerName == "" {
+			// or like the url of an item: s3:<containerName>/<itemName>
+			containerName, itemName, _ = strings.Cut(u.Path, "/")
+		}
+	}
+
+	c, err := l.Container(containerName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "ItemByURL, getting container by the bucketname %s", containerName)
+	}
+	i, err := c.Item(itemName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "ItemByURL, getting item by object name %s", itemName)
+	}
+	return i, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"gomodules.xyz/stow"
+)
+
+// Roles that are assumed through STS. The credentials of auth_type sign the
+// AssumeRole calls, the web identity token replaces them when it is set.
+const (
+	ConfigRoleARN              = "role_arn"
+	ConfigExternalID           = "external_id"
+	ConfigRoleSessionName      = "role_session_name"
+	ConfigWebIdentityTokenFile = "web_identity_token_file"
+	ConfigSTSEndpoint          = "sts_endpoint"
+)
+
+// Role is a role that is assumed through STS.
+type Role struct {
+	ARN                  string
+	ExternalID           string
+	SessionName          string
+	WebIdentityTokenFile string
+	STSEndpoint          string
+}
+
+// roleFromConfig returns the role of config, nil if it has none.
+func roleFromConfig(config stow.Config) *Role {
+	arn, _ := config.Config(ConfigRoleARN)
+	if arn == "" {
+		return nil
+	}
+	r := &Role{ARN: arn}
+	r.ExternalID, _ = config.Config(ConfigExternalID)
+	r.SessionName, _ = config.Config(ConfigRoleSessionName)
+	r.WebIdentityTokenFile, _ = config.Config(ConfigWebIdentityTokenFile)
+	r.STSEndpoint, _ = config.Config(ConfigSTSEndpoint)
+	return r
+}
+
+// AssumeRoleCredentials returns the credentials of role. The credentials of
+// sess sign the AssumeRole calls.
+func AssumeRoleCredentials(sess *session.Session, role Role) *credentials.Credentials {
+	c := aws.NewConfig()
+	if role.STSEndpoint != "" {
+		c.WithEndpoint(role.STSEndpoint)
+	}
+	if aws.StringValue(sess.Config.Region) == "" {
+		c.WithRegion(endpoints.UsEast1RegionID)
+	}
+	svc := sts.New(sess, c)
+	if role.WebIdentityTokenFile != "" {
+		return credentials.NewCredentials(stscreds.NewWebIdentityRoleProviderWithOptions(svc, role.ARN, role.SessionName,
+			stscreds.FetchTokenPath(role.WebIdentityTokenFile)))
+	}
+	return stscreds.NewCredentialsWithClient(svc, role.ARN, func(p *stscreds.AssumeRoleProvider) {
+		if role.ExternalID != "" {
+			p.ExternalID = aws.String(role.ExternalID)
+		}
+		if role.SessionName != "" {
+			p.RoleSessionName = role.SessionName
+		}
+	})
+}
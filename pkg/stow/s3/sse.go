@@ -0,0 +1,108 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"encoding/base64"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+// encryption is the server side encryption of the objects of a location.
+type encryption struct {
+	serverSideEncryption string
+	kmsKeyID             string
+	kmsEncryptionContext string
+	customerAlgorithm    string
+	// customerKey is the raw key, the sdk encodes it
+	customerKey    string
+	customerKeyMD5 string
+}
+
+// encryptionFromConfig returns the encryption of config, nil when there is
+// none.
+func encryptionFromConfig(config stow.Config) (*encryption, error) {
+	e := &encryption{}
+	e.serverSideEncryption, _ = config.Config(ConfigServerSideEncryption)
+	e.kmsKeyID, _ = config.Config(ConfigSSEKMSKeyID)
+	e.kmsEncryptionContext, _ = config.Config(ConfigSSEKMSEncryptionContext)
+	if v, _ := config.Config(ConfigSSECustomerKey); v != "" {
+		key, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, errors.Errorf("invalid %s: %v", ConfigSSECustomerKey, err)
+		}
+		e.customerKey = string(key)
+		e.customerAlgorithm, _ = config.Config(ConfigSSECustomerAlgorithm)
+		e.customerKeyMD5, _ = config.Config(ConfigSSECustomerKeyMD5)
+	}
+	if *e == (encryption{}) {
+		return nil, nil
+	}
+	return e, nil
+}
+
+// setHandlers sets the server side encryption of every object written by
+// client, and sends the SSE-C key with every read of an object. The
+// encryption is set before the parameters are validated, as the sdk only
+// sends SSE-C keys over https.
+func (e *encryption) setHandlers(client *s3.S3) {
+	client.Handlers.Validate.PushFront(func(r *request.Request) {
+		switch p := r.Params.(type) {
+		case *s3.PutObjectInput:
+			e.setEncryption(&p.ServerSideEncryption, &p.SSEKMSKeyId, &p.SSEKMSEncryptionContext)
+			e.setCustomerKey(&p.SSECustomerAlgorithm, &p.SSECustomerKey, &p.SSECustomerKeyMD5)
+		case *s3.CreateMultipartUploadInput:
+			e.setEncryption(&p.ServerSideEncryption, &p.SSEKMSKeyId, &p.SSEKMSEncryptionContext)
+			e.setCustomerKey(&p.SSECustomerAlgorithm, &p.SSECustomerKey, &p.SSECustomerKeyMD5)
+		case *s3.UploadPartInput:
+			e.setCustomerKey(&p.SSECustomerAlgorithm, &p.SSECustomerKey, &p.SSECustomerKeyMD5)
+		case *s3.GetObjectInput:
+			e.setCustomerKey(&p.SSECustomerAlgorithm, &p.SSECustomerKey, &p.SSECustomerKeyMD5)
+		case *s3.HeadObjectInput:
+			e.setCustomerKey(&p.SSECustomerAlgorithm, &p.SSECustomerKey, &p.SSECustomerKeyMD5)
+		}
+	})
+}
+
+// setEncryption applies SSE-S3 or SSE-KMS unless the request already asks
+// for an encryption.
+func (e *encryption) setEncryption(typ, keyID, kmsContext **string) {
+	if e.serverSideEncryption == "" || *typ != nil {
+		return
+	}
+	*typ = aws.String(e.serverSideEncryption)
+	if e.kmsKeyID != "" {
+		*keyID = aws.String(e.kmsKeyID)
+	}
+	if e.kmsEncryptionContext != "" {
+		*kmsContext = aws.String(e.kmsEncryptionContext)
+	}
+}
+
+func (e *encryption) setCustomerKey(algorithm, key, keyMD5 **string) {
+	if e.customerKey == "" || *key != nil {
+		return
+	}
+	*algorithm, *key = aws.String(e.customerAlgorithm), aws.String(e.customerKey)
+	if e.customerKeyMD5 != "" {
+		*keyMD5 = aws.String(e.customerKeyMD5)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the swift location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+// Package swift provides the stow location of swift backends that the osm
+// package dials. It reads the config of gomodules.xyz/stow/swift, but sends
+// its requests with the TLS, proxy and connection limit settings of the
+// backend. It registers the swift kind with stow in place of
+// gomodules.xyz/stow/swift, as the first registration of a kind wins,
+// importing both packages panics.
+package swift // import "kmodules.xyz/objectstore-api/pkg/stow/swift"
+
+import (
+	"net/url"
+	"sync"
+
+	"kmodules.xyz/objectstore-api/pkg/openstack"
+	"kmodules.xyz/objectstore-api/pkg/ratelimit"
+	"kmodules.xyz/objectstore-api/pkg/stow/internal/stowhttp"
+
+	"github.com/ncw/swift"
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+// Kind represents the name of the location/storage type.
+const Kind = "swift"
+
+// The keys of gomodules.xyz/stow/swift, see openstack.SwiftConfig.
+const (
+	ConfigUsername      = "username"
+	ConfigKey           = "key"
+	ConfigTenantName    = "tenant_name"
+	ConfigTenantAuthURL = "tenant_auth_url"
+	ConfigDomain        = "domain"
+	ConfigRegion        = "region"
+	ConfigTenantId      = "tenant_id"
+	ConfigTenantDomain  = "tenant_domain"
+	ConfigTrustId       = "trust_id"
+	ConfigStorageURL    = "storage_url"
+	ConfigAuthToken     = "auth_token"
+)
+
+// The TLS settings are read from the keys of package tlsconfig, and the proxy
+// from those of package httpproxy.
+
+func init() {
+	for _, kind := range stow.Kinds() {
+		if kind == Kind {
+			panic("stow kind swift is registered already, gomodules.xyz/stow/swift must not be imported together with kmodules.xyz/objectstore-api/pkg/stow/swift")
+		}
+	}
+	validatefn := func(config stow.Config) error {
+		cfg := swiftConfig(config)
+		if cfg.ManualAuth() {
+			return nil
+		}
+		if cfg.Username == "" {
+			return errors.New("missing account username")
+		}
+		if cfg.AuthURL == "" {
+			return errors.New("missing tenant auth url")
+		}
+		return nil
+	}
+	makefn := func(config stow.Config) (stow.Location, error) {
+		if err := validatefn(config); err != nil {
+			return nil, err
+		}
+		limiter, err := stowhttp.Limiter(config)
+		if err != nil {
+			return nil, err
+		}
+		client, err := newClient(config, limiter)
+		if err != nil {
+			limiter.Release()
+			return nil, err
+		}
+		return &location{client: client, release: sync.OnceFunc(limiter.Release)}, nil
+	}
+	kindfn := func(u *url.URL) bool {
+		return u.Scheme == Kind
+	}
+	stow.Register(Kind, makefn, kindfn, validatefn)
+}
+
+func swiftConfig(config stow.Config) openstack.SwiftConfig {
+	var cfg openstack.SwiftConfig
+	cfg.Username, _ = config.Config(ConfigUsername)
+	cfg.Key, _ = config.Config(ConfigKey)
+	cfg.Region, _ = config.Config(ConfigRegion)
+	cfg.AuthURL, _ = config.Config(ConfigTenantAuthURL)
+	cfg.Domain, _ = config.Config(ConfigDomain)
+	cfg.TenantName, _ = config.Config(ConfigTenantName)
+	cfg.TenantDomain, _ = config.Config(ConfigTenantDomain)
+	cfg.TenantID, _ = config.Config(ConfigTenantId)
+	cfg.StorageURL, _ = config.Config(ConfigStorageURL)
+	cfg.AuthToken, _ = config.Config(ConfigAuthToken)
+	return cfg
+}
+
+func newClient(config stow.Config, limiter *ratelimit.Limiter) (*swift.Connection, error) {
+	hc, err := stowhttp.NewClient(config, limiter)
+	if err != nil {
+		return nil, err
+	}
+	var conn *swift.Connection
+	if hc != nil {
+		conn = swiftConfig(config).Connection(hc.Transport)
+		if hc.Timeout > 0 {
+			conn.Timeout = hc.Timeout
+		}
+	} else {
+		conn = swiftConfig(config).Connection(nil)
+	}
+	conn.TrustId, _ = config.Config(ConfigTrustId)
+	if err := conn.Authenticate(); err != nil {
+		return nil, errors.Wrap(err, "unable to authenticate")
+	}
+	return conn, nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the swift location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+package swift
+
+import (
+	"bytes"
+	"io"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/ncw/swift"
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+type container struct {
+	id     string
+	client *swift.Connection
+}
+
+var _ stow.Container = &container{}
+
+// ID returns the name of the container.
+func (c *container) ID() string {
+	return c.id
+}
+
+// Name returns the name of the container.
+func (c *container) Name() string {
+	return c.id
+}
+
+// Item returns the object with the given name.
+func (c *container) Item(id string) (stow.Item, error) {
+	info, headers, err := c.client.Object(c.id, id)
+	if errors.Is(err, swift.ObjectNotFound) {
+		return nil, stow.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Item, getting the object")
+	}
+	return &item{
+		container: c,
+		name:      id,
+		hash:      info.Hash,
+		size:      info.Bytes,
+		lastMod:   info.LastModified,
+		metadata:  parseMetadata(headers),
+	}, nil
+}
+
+// Browse lists the objects that start with prefix. With a delimiter, the
+// pseudo directories are returned as prefixes. The cursor is the name of the
+// last object of the previous page.
+func (c *container) Browse(prefix, delimiter, cursor string, count int) (*stow.ItemPage, error) {
+	opts := &swift.ObjectsOpts{
+		Limit:  count,
+		Marker: cursor,
+		Prefix: prefix,
+	}
+	if delimiter != "" {
+		r, size := utf8.DecodeRuneInString(delimiter)
+		if r == utf8.RuneError || size != len(delimiter) {
+			return nil, errors.Errorf("Browse, bad delimiter %q", delimiter)
+		}
+		opts.Delimiter = r
+	}
+	objects, err := c.client.Objects(c.id, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "Browse, listing objects")
+	}
+
+	page := &stow.ItemPage{}
+	for _, obj := range objects {
+		if obj.PseudoDirectory {
+			page.Prefixes = append(page.Prefixes, obj.Name)
+			continue
+		}
+		page.Items = append(page.Items, &item{
+			container: c,
+			name:      obj.Name,
+			hash:      obj.Hash,
+			size:      obj.Bytes,
+			lastMod:   obj.LastModified,
+		})
+	}
+	if len(objects) > 0 && len(objects) == count {
+		page.Cursor = objects[len(objects)-1].Name
+	}
+	return page, nil
+}
+
+// Items lists the objects that start with prefix.
+func (c *container) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	page, err := c.Browse(prefix, "", cursor, count)
+	if err != nil {
+		return nil, "", err
+	}
+	return page.Items, page.Cursor, nil
+}
+
+// Put uploads the content of r. The values of metadata must be strings.
+func (c *container) Put(name string, r io.Reader, size int64, metadata map[string]any) (stow.Item, error) {
+	headers, err := prepMetadata(metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "Put, preparing metadata")
+	}
+	headers, err = c.client.ObjectPut(c.id, name, r, false, "", "", headers)
+	if err != nil {
+		return nil, errors.Wrap(err, "Put, uploading the object")
+	}
+	return &item{
+		container: c,
+		name:      name,
+		hash:      headers["Etag"],
+		size:      size,
+		metadata:  parseMetadata(metadataHeaders(metadata)),
+	}, nil
+}
+
+// RemoveItem deletes the object with the given name.
+func (c *container) RemoveItem(id string) error {
+	err := c.client.ObjectDelete(c.id, id)
+	if errors.Is(err, swift.ObjectNotFound) {
+		return stow.ErrNotFound
+	}
+	return errors.Wrapf(err, "RemoveItem, deleting object %s", id)
+}
+
+// HasWriteAccess stores and removes an object in the .trash directory.
+func (c *container) HasWriteAccess() error {
+	r := bytes.NewReader([]byte("CheckBucketAccess"))
+	item, err := c.Put(".trash/"+uuid.New().String(), r, r.Size(), nil)
+	if err != nil {
+		return err
+	}
+	return c.RemoveItem(item.ID())
+}
+
+func prepMetadata(md map[string]any) (swift.Headers, error) {
+	for k, v := range md {
+		if _, ok := v.(string); !ok {
+			return nil, errors.Errorf("value of key '%s' in metadata must be of type string", k)
+		}
+	}
+	return metadataHeaders(md), nil
+}
+
+// metadataHeaders returns the X-Object-Meta-* headers of md.
+func metadataHeaders(md map[string]any) swift.Headers {
+	h := swift.Headers{}
+	for k, v := range md {
+		s, _ := v.(string)
+		h["X-Object-Meta-"+k] = s
+	}
+	return h
+}
+
+func parseMetadata(h swift.Headers) map[string]any {
+	out := map[string]any{}
+	for k, v := range h.ObjectMetadata() {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the swift location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+package swift
+
+import (
+	"io"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+type item struct {
+	container *container
+	name      string
+	hash      string
+	size      int64
+	lastMod   time.Time
+	// metadata is nil for the items of a listing and of Put, the hash, last
+	// modification and metadata are then read once with a HEAD request
+	metadata map[string]any
+	infoOnce sync.Once
+	infoErr  error
+}
+
+var _ stow.Item = &item{}
+
+// ID returns the name of the object.
+func (i *item) ID() string {
+	return i.name
+}
+
+// Name returns the name of the object.
+func (i *item) Name() string {
+	return i.name
+}
+
+// URL returns the url of the object with the swift scheme, see
+// location.ItemByURL.
+func (i *item) URL() *url.URL {
+	u, _ := url.Parse(i.container.client.StorageUrl)
+	u.Scheme = Kind
+	u.Path = path.Join(u.Path, i.container.id, i.name)
+	return u
+}
+
+// Size returns the size of the object in bytes.
+func (i *item) Size() (int64, error) {
+	return i.size, nil
+}
+
+// Open downloads the object.
+func (i *item) Open() (io.ReadCloser, error) {
+	r, _, err := i.container.client.ObjectOpen(i.container.id, i.name, false, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Open, downloading the object")
+	}
+	return r, nil
+}
+
+// ETag returns the md5 hash of the object.
+func (i *item) ETag() (string, error) {
+	if err := i.ensureInfo(); err != nil {
+		return "", err
+	}
+	return i.hash, nil
+}
+
+// LastMod returns the last modification of the object.
+func (i *item) LastMod() (time.Time, error) {
+	if err := i.ensureInfo(); err != nil {
+		return time.Time{}, err
+	}
+	return i.lastMod, nil
+}
+
+// Metadata returns the X-Object-Meta-* headers of the object.
+func (i *item) Metadata() (map[string]any, error) {
+	if err := i.ensureInfo(); err != nil {
+		return nil, err
+	}
+	return i.metadata, nil
+}
+
+func (i *item) ensureInfo() error {
+	i.infoOnce.Do(func() {
+		if i.metadata != nil && i.hash != "" && !i.lastMod.IsZero() {
+			return
+		}
+		info, headers, err := i.container.client.Object(i.container.id, i.name)
+		if err != nil {
+			i.infoErr = errors.Wrap(err, "retrieving the object info")
+			return
+		}
+		i.hash, i.lastMod, i.metadata = info.Hash, info.LastModified, parseMetadata(headers)
+	})
+	return i.infoErr
+}
@@ -0,0 +1,119 @@
+/*
+Copyright AppsCode Inc. and Contributors
+Copyright the stow authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+This file is derived from the swift location of gomodules.xyz/stow, a fork of
+github.com/graymeta/stow.
+*/
+
+package swift
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/ncw/swift"
+	"github.com/pkg/errors"
+	"gomodules.xyz/stow"
+)
+
+// A location holds the containers of a swift account.
+type location struct {
+	client *swift.Connection
+	// release releases the limiter of the backend once
+	release func()
+}
+
+// CreateContainer creates a container, or updates it when it exists.
+func (l *location) CreateContainer(name string) (stow.Container, error) {
+	if err := l.client.ContainerCreate(name, nil); err != nil {
+		return nil, errors.Wrap(err, "CreateContainer, creating the container")
+	}
+	return &container{id: name, client: l.client}, nil
+}
+
+// Containers lists the containers whose name starts with prefix. The cursor
+// is the name of the last container of the previous page.
+func (l *location) Containers(prefix, cursor string, count int) ([]stow.Container, string, error) {
+	response, err := l.client.Containers(&swift.ContainersOpts{
+		Limit:  count,
+		Prefix: prefix,
+		Marker: cursor,
+	})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Containers, listing the containers")
+	}
+	containers := make([]stow.Container, 0, len(response))
+	for _, c := range response {
+		containers = append(containers, &container{id: c.Name, client: l.client})
+	}
+	marker := ""
+	if len(response) > 0 && len(response) == count {
+		marker = response[len(response)-1].Name
+	}
+	return containers, marker, nil
+}
+
+// Container returns the container with the given name.
+func (l *location) Container(id string) (stow.Container, error) {
+	_, _, err := l.client.Container(id)
+	if errors.Is(err, swift.ContainerNotFound) {
+		return nil, stow.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Container, getting the container")
+	}
+	return &container{id: id, client: l.client}, nil
+}
+
+// RemoveContainer deletes the container with the given name.
+func (l *location) RemoveContainer(id string) error {
+	err := l.client.ContainerDelete(id)
+	if errors.Is(err, swift.ContainerNotFound) {
+		return stow.ErrNotFound
+	}
+	return errors.Wrapf(err, "RemoveContainer, deleting container %s", id)
+}
+
+// ItemByURL retrieves an item from the url of an object with the swift
+// scheme, see item.URL.
+func (l *location) ItemByURL(u *url.URL) (stow.Item, error) {
+	if u.Scheme != Kind {
+		return nil, errors.New("not valid swift URL")
+	}
+	storage, err := url.Parse(l.client.StorageUrl)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing the storage url")
+	}
+	path, ok := strings.CutPrefix(u.Path, strings.TrimSuffix(storage.Path, "/")+"/")
+	if u.Host != storage.Host || !ok {
+		return nil, errors.New("wrong swift URL")
+	}
+	pieces := strings.SplitN(path, "/", 2)
+	if len(pieces) != 2 {
+		return nil, errors.New("wrong path")
+	}
+	c, err := l.Container(pieces[0])
+	if err != nil {
+		return nil, err
+	}
+	return c.Item(pieces[1])
+}
+
+// Close releases the limiter of the backend.
+func (l *location) Close() error {
+	l.release()
+	return nil
+}
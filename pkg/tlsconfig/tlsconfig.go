@@ -0,0 +1,183 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tlsconfig builds the client TLS configuration of a backend from its
+// TLS section and the CA bundle and client certificate of the storage secret,
+// so that the clients of every provider connect the same way.
+package tlsconfig // import "kmodules.xyz/objectstore-api/pkg/tlsconfig"
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+
+	"gomodules.xyz/stow"
+)
+
+// Stow config keys of the TLS settings. The CA keys have the same names as
+// those of gomodules.xyz/stow/s3, so that osm projects them into files the
+// same way.
+const (
+	ConfigCACertData     = "cacert_data"
+	ConfigCACertFile     = "cacert_file"
+	ConfigClientCertData = "tls_client_cert_data"
+	ConfigClientCertFile = "tls_client_cert_file"
+	ConfigClientKeyData  = "tls_client_key_data"
+	ConfigClientKeyFile  = "tls_client_key_file"
+	ConfigServerName     = "tls_server_name"
+	ConfigMinVersion     = "tls_min_version"
+)
+
+var versions = map[api.TLSVersion]uint16{
+	api.TLSVersion10: tls.VersionTLS10,
+	api.TLSVersion11: tls.VersionTLS11,
+	api.TLSVersion12: tls.VersionTLS12,
+	api.TLSVersion13: tls.VersionTLS13,
+}
+
+// Options are the TLS settings of a backend.
+type Options struct {
+	// CACertData holds PEM encoded certificates trusted in addition to the
+	// system roots.
+	CACertData []byte
+	// ClientCertData and ClientKeyData hold the PEM encoded client
+	// certificate and key used for mutual TLS.
+	ClientCertData []byte
+	ClientKeyData  []byte
+	ServerName     string
+	MinVersion     api.TLSVersion
+}
+
+// FromSecret reads the Options of a backend from its TLS section and the
+// storage secret data. spec may be nil.
+func FromSecret(spec *api.TLSConfig, data map[string][]byte) Options {
+	o := Options{
+		CACertData:     data[api.CA_CERT_DATA],
+		ClientCertData: data[api.TLS_CLIENT_CERT_DATA],
+		ClientKeyData:  data[api.TLS_CLIENT_KEY_DATA],
+	}
+	if spec != nil {
+		o.ServerName = spec.ServerName
+		o.MinVersion = spec.MinVersion
+	}
+	return o
+}
+
+// FromStowConfig reads the Options from a stow config. The data keys win over
+// the file keys.
+func FromStowConfig(config stow.Config) (Options, error) {
+	var o Options
+	var err error
+	if o.CACertData, err = readConfig(config, ConfigCACertData, ConfigCACertFile); err != nil {
+		return Options{}, err
+	}
+	if o.ClientCertData, err = readConfig(config, ConfigClientCertData, ConfigClientCertFile); err != nil {
+		return Options{}, err
+	}
+	if o.ClientKeyData, err = readConfig(config, ConfigClientKeyData, ConfigClientKeyFile); err != nil {
+		return Options{}, err
+	}
+	o.ServerName, _ = config.Config(ConfigServerName)
+	v, _ := config.Config(ConfigMinVersion)
+	o.MinVersion = api.TLSVersion(v)
+	return o, nil
+}
+
+func readConfig(config stow.Config, dataKey, fileKey string) ([]byte, error) {
+	if v, ok := config.Config(dataKey); ok && v != "" {
+		return []byte(v), nil
+	}
+	if f, ok := config.Config(fileKey); ok && f != "" {
+		return os.ReadFile(f)
+	}
+	return nil, nil
+}
+
+// SetStowConfig sets the stow config keys of o. The CA bundle is left to the
+// caller, as gomodules.xyz/stow/s3 only trusts it for custom endpoints.
+func (o Options) SetStowConfig(cfg stow.ConfigMap) {
+	if len(o.ClientCertData) > 0 {
+		cfg[ConfigClientCertData] = string(o.ClientCertData)
+	}
+	if len(o.ClientKeyData) > 0 {
+		cfg[ConfigClientKeyData] = string(o.ClientKeyData)
+	}
+	if o.ServerName != "" {
+		cfg[ConfigServerName] = o.ServerName
+	}
+	if o.MinVersion != "" {
+		cfg[ConfigMinVersion] = string(o.MinVersion)
+	}
+}
+
+// IsZero reports whether no TLS setting is configured.
+func (o Options) IsZero() bool {
+	return len(o.CACertData) == 0 && len(o.ClientCertData) == 0 && len(o.ClientKeyData) == 0 &&
+		o.ServerName == "" && o.MinVersion == ""
+}
+
+// Config returns the client TLS configuration of o, or nil when no setting is
+// configured, so that the defaults of the provider sdk stay in place.
+func (o Options) Config() (*tls.Config, error) {
+	if o.IsZero() {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		ServerName: o.ServerName,
+	}
+	if o.MinVersion != "" {
+		v, ok := versions[o.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported tls version %q", o.MinVersion)
+		}
+		cfg.MinVersion = v
+	}
+	if len(o.CACertData) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(o.CACertData) {
+			return nil, errors.New("failed to parse CA certificate")
+		}
+		cfg.RootCAs = pool
+	}
+	switch {
+	case len(o.ClientCertData) > 0 && len(o.ClientKeyData) > 0:
+		cert, err := tls.X509KeyPair(o.ClientCertData, o.ClientKeyData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	case len(o.ClientCertData) > 0:
+		return nil, fmt.Errorf("tls client certificate without %s", api.TLS_CLIENT_KEY_DATA)
+	case len(o.ClientKeyData) > 0:
+		return nil, fmt.Errorf("tls client key without %s", api.TLS_CLIENT_CERT_DATA)
+	}
+	return cfg, nil
+}
+
+// Transport returns a clone of http.DefaultTransport that connects with cfg.
+func Transport(cfg *tls.Config) *http.Transport {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.TLSClientConfig = cfg
+	return tr
+}
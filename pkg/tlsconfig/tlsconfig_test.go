@@ -0,0 +1,185 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "kmodules.xyz/objectstore-api/api/v1"
+	"kmodules.xyz/objectstore-api/pkg/rest/resttest"
+
+	"github.com/stretchr/testify/assert"
+	"gomodules.xyz/stow"
+)
+
+func TestConfig(t *testing.T) {
+	srv := resttest.NewMutualTLSServer()
+	defer srv.Close()
+	cert, key := srv.ClientCert()
+
+	cases := []struct {
+		name    string
+		opts    Options
+		check   func(t *testing.T, cfg *tls.Config)
+		wantErr string
+	}{
+		{
+			name: "none",
+			check: func(t *testing.T, cfg *tls.Config) {
+				assert.Nil(t, cfg)
+			},
+		},
+		{
+			name: "server name and version",
+			opts: Options{ServerName: "minio.local", MinVersion: api.TLSVersion12},
+			check: func(t *testing.T, cfg *tls.Config) {
+				assert.Equal(t, "minio.local", cfg.ServerName)
+				assert.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+				assert.Nil(t, cfg.RootCAs)
+			},
+		},
+		{
+			name: "client certificate",
+			opts: Options{CACertData: srv.CACert(), ClientCertData: cert, ClientKeyData: key},
+			check: func(t *testing.T, cfg *tls.Config) {
+				assert.NotNil(t, cfg.RootCAs)
+				assert.Len(t, cfg.Certificates, 1)
+			},
+		},
+		{
+			name:    "unsupported version",
+			opts:    Options{MinVersion: "1.4"},
+			wantErr: `unsupported tls version "1.4"`,
+		},
+		{
+			name:    "invalid CA",
+			opts:    Options{CACertData: []byte("not a certificate")},
+			wantErr: "failed to parse CA certificate",
+		},
+		{
+			name:    "certificate without key",
+			opts:    Options{ClientCertData: cert},
+			wantErr: "tls client certificate without TLS_CLIENT_KEY_DATA",
+		},
+		{
+			name:    "key without certificate",
+			opts:    Options{ClientKeyData: key},
+			wantErr: "tls client key without TLS_CLIENT_CERT_DATA",
+		},
+		{
+			name:    "mismatched key",
+			opts:    Options{ClientCertData: cert, ClientKeyData: cert},
+			wantErr: "invalid tls client certificate",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := tc.opts.Config()
+			if tc.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.wantErr)
+				}
+				return
+			}
+			if assert.Nil(t, err) {
+				tc.check(t, cfg)
+			}
+		})
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	srv := resttest.NewMutualTLSServer()
+	defer srv.Close()
+	srv.CreateRepository("/demo")
+	cert, key := srv.ClientCert()
+
+	get := func(opts Options) error {
+		cfg, err := opts.Config()
+		if err != nil {
+			return err
+		}
+		hc := &http.Client{Transport: Transport(cfg)}
+		resp, err := hc.Get(srv.URL + "/demo/")
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	assert.Nil(t, get(Options{CACertData: srv.CACert(), ClientCertData: cert, ClientKeyData: key}))
+	// the certificate of httptest servers is valid for example.com
+	assert.Nil(t, get(Options{CACertData: srv.CACert(), ClientCertData: cert, ClientKeyData: key, ServerName: "example.com"}))
+	assert.Error(t, get(Options{CACertData: srv.CACert(), ClientCertData: cert, ClientKeyData: key, ServerName: "minio.local"}))
+	assert.Error(t, get(Options{CACertData: srv.CACert()}))
+}
+
+func TestStowConfig(t *testing.T) {
+	srv := resttest.NewMutualTLSServer()
+	defer srv.Close()
+	cert, key := srv.ClientCert()
+
+	opts := Options{
+		ClientCertData: cert,
+		ClientKeyData:  key,
+		ServerName:     "minio.local",
+		MinVersion:     api.TLSVersion13,
+	}
+	cfg := stow.ConfigMap{}
+	opts.SetStowConfig(cfg)
+	assert.Equal(t, stow.ConfigMap{
+		ConfigClientCertData: string(cert),
+		ConfigClientKeyData:  string(key),
+		ConfigServerName:     "minio.local",
+		ConfigMinVersion:     "1.3",
+	}, cfg)
+
+	got, err := FromStowConfig(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, opts, got)
+
+	// files are read when there is no data
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	assert.Nil(t, os.WriteFile(caFile, srv.CACert(), 0o600))
+	assert.Nil(t, os.WriteFile(keyFile, key, 0o600))
+	cfg[ConfigCACertFile] = caFile
+	cfg[ConfigClientKeyFile] = keyFile
+	delete(cfg, ConfigClientKeyData)
+	got, err = FromStowConfig(cfg)
+	assert.Nil(t, err)
+	opts.CACertData = srv.CACert()
+	assert.Equal(t, opts, got)
+
+	cfg[ConfigClientCertFile] = filepath.Join(dir, "missing.crt")
+	delete(cfg, ConfigClientCertData)
+	_, err = FromStowConfig(cfg)
+	assert.Error(t, err)
+}
+
+func TestTransport(t *testing.T) {
+	cfg := &tls.Config{ServerName: "minio.local"}
+	tr := Transport(cfg)
+	assert.Same(t, cfg, tr.TLSClientConfig)
+	assert.NotSame(t, http.DefaultTransport, tr)
+	assert.NotSame(t, cfg, http.DefaultTransport.(*http.Transport).TLSClientConfig)
+}